@@ -28,6 +28,8 @@ import (
 	"github.com/Nigel2392/go-django/src/core/filesystem/staticfiles"
 	"github.com/Nigel2392/go-django/src/core/filesystem/tpl"
 	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-django/src/core/ratelimit"
+	"github.com/Nigel2392/go-django/src/core/trace"
 	"github.com/Nigel2392/go-django/src/core/trans"
 	"github.com/Nigel2392/go-django/src/forms/media"
 	"github.com/Nigel2392/go-django/src/models"
@@ -262,7 +264,7 @@ func NewAppConfig() django.AppConfig {
 			"login", // admin:login
 		)
 		AdminSite.Route.Post(
-			"login/", mux.NewHandler(loginHandler),
+			"login/", ratelimit.ProtectLogin(mux.NewHandler(loginHandler)),
 			"login", // admin:login
 		)
 
@@ -434,7 +436,7 @@ func NewAppConfig() django.AppConfig {
 				}
 				m.Items = menuItems.All()
 				var buf = new(bytes.Buffer)
-				m.Component().Render(r.Context(), buf)
+				renderComponent(r.Context(), "menu", buf, m.Component().Render)
 				return template.HTML(buf.String())
 			},
 			"footer_menu": func(r *http.Request) template.HTML {
@@ -448,7 +450,7 @@ func NewAppConfig() django.AppConfig {
 				}
 				m.Items = menuItems.All()
 				var buf = new(bytes.Buffer)
-				m.Component().Render(r.Context(), buf)
+				renderComponent(r.Context(), "footer_menu", buf, m.Component().Render)
 				return template.HTML(buf.String())
 			},
 			"script_hook_output": func() media.Media {
@@ -478,9 +480,9 @@ func NewAppConfig() django.AppConfig {
 				var buf = new(bytes.Buffer)
 				switch v := obj.(type) {
 				case components.Component:
-					err = v.Render(r.Context(), buf)
+					err = renderComponent(r.Context(), fmt.Sprintf("%T", v), buf, v.Render)
 				case func(ctx context.Context, w io.Writer) error:
-					err = v(r.Context(), buf)
+					err = renderComponent(r.Context(), "func", buf, v)
 				}
 				return template.HTML(buf.String()), err
 			},
@@ -490,6 +492,16 @@ func NewAppConfig() django.AppConfig {
 	return AdminSite
 }
 
+// renderComponent renders a templ component within a "templ.render" span, see [trace.Start].
+func renderComponent(ctx context.Context, name string, w io.Writer, render func(ctx context.Context, w io.Writer) error) error {
+	var spanCtx, span = trace.Start(ctx, "templ.render", "component", name)
+	defer span.End()
+
+	var err = render(spanCtx, w)
+	span.SetError(err)
+	return err
+}
+
 func (a *AdminApplication) Check(ctx context.Context, settings django.Settings) []checks.Message {
 	var messages = a.AppConfig.Check(ctx, settings)
 
@@ -202,6 +202,10 @@ func (c *adminContext) CsrfToken() string {
 	return nosurf.Token(c.request)
 }
 
+func (c *adminContext) CSPNonce() string {
+	return ctx.CSPNonce(c.request.Context())
+}
+
 func (c *adminContext) Clone(values ...interface{}) (ctx.Context, error) {
 	var subCopy, _ = c.Context.Clone()
 	var copy = &adminContext{
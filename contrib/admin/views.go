@@ -20,6 +20,7 @@ import (
 	"github.com/Nigel2392/go-django/src/core/except"
 	"github.com/Nigel2392/go-django/src/core/filesystem/tpl"
 	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-django/src/core/metrics"
 	"github.com/Nigel2392/go-django/src/core/pagination"
 	"github.com/Nigel2392/go-django/src/core/trans"
 	"github.com/Nigel2392/go-django/src/forms/media"
@@ -31,6 +32,12 @@ import (
 	"github.com/Nigel2392/goldcrest"
 )
 
+var actionsTotal = metrics.Default.Counter(metrics.Opts{
+	Name:   "django_admin_actions_total",
+	Help:   "The number of objects added, edited and deleted through the admin, by app, model and action.",
+	Labels: []string{"app", "model", "action"},
+})
+
 var AppHandler = func(w http.ResponseWriter, r *http.Request, adminSite *AdminApplication, app *AppDefinition) {
 	if !app.Options.EnableIndexView {
 		except.RaiseNotFound(
@@ -564,6 +571,7 @@ func (v *AdminDeleteView) ServePOST(w http.ResponseWriter, r *http.Request) {
 			),
 		)
 	} else {
+		actionsTotal.Add(float64(len(v.Instances)), v.App.Name, v.Model.GetName(), "delete")
 		messages.Warning(r,
 			trans.T(r.Context(),
 				"Successfully deleted %s (%v)",
@@ -833,6 +841,7 @@ func newInstanceView(tpl string, instance attrs.Definer, opts FormViewOptions, a
 		},
 		SuccessFn: func(w http.ResponseWriter, req *http.Request, form *AdminForm[modelforms.ModelForm[attrs.Definer], attrs.Definer]) {
 			var instance = form.Instance()
+			actionsTotal.Inc(app.Name, model.GetName(), tpl)
 
 			var hooks = goldcrest.Get[AdminModelHookFunc](
 				fmt.Sprintf("admin:model:%s", tpl),
@@ -26,6 +26,7 @@ import (
 	"github.com/Nigel2392/go-django/src/core/ctx"
 	"github.com/Nigel2392/go-django/src/core/filesystem"
 	"github.com/Nigel2392/go-django/src/core/filesystem/tpl"
+	"github.com/Nigel2392/go-django/src/core/ratelimit"
 	"github.com/Nigel2392/go-django/src/core/trans"
 	"github.com/Nigel2392/go-django/src/forms"
 	"github.com/Nigel2392/go-django/src/forms/fields"
@@ -75,7 +76,7 @@ func NewAppConfig() django.AppConfig {
 		if django.ConfigGet(django.Global.Settings, APPVAR_REGISTER_AUTH_URLS, true) {
 			var g = m.Any("/auth", nil, "auth")
 			g.Handle(mux.GET, "/login", mux.NewHandler(viewUserLogin), "login")
-			g.Handle(mux.POST, "/login", mux.NewHandler(viewUserLogin))
+			g.Handle(mux.POST, "/login", ratelimit.ProtectLogin(mux.NewHandler(viewUserLogin)))
 
 			if django.ConfigGet(django.Global.Settings, APPVAR_ALLOW_USER_REGISTER, true) {
 				g.Handle(mux.GET, "/register", mux.NewHandler(viewUserRegister), "register")
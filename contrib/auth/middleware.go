@@ -65,13 +65,14 @@ func loadUserFromRequest(r *http.Request) *User {
 		Get()
 	if err != nil {
 		if !errors.Is(err, errors.NoRows) {
-			logger.Errorf("Failed to get user from session: %v", err)
+			logger.Ctx(r.Context()).Errorf("Failed to get user from session: %v", err)
 		}
 		return UnAuthenticatedUser()
 	}
 
 	user := userRow.Object
 	user.IsLoggedIn = true
+	logger.AddContextAttrs(r.Context(), "user_id", uidInt)
 	return user
 }
 
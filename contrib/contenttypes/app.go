@@ -0,0 +1,92 @@
+package contenttypes
+
+import (
+	"context"
+	"embed"
+	"fmt"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	django "github.com/Nigel2392/go-django/src"
+	"github.com/Nigel2392/go-django/src/apps"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	ctypes "github.com/Nigel2392/go-django/src/core/contenttypes"
+	"github.com/Nigel2392/go-django/src/core/filesystem"
+	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-signals"
+)
+
+//go:embed migrations/*
+var migrationFS embed.FS
+
+// NewAppConfig returns the contenttypes app.
+//
+// The app persists the content types of the in-memory registry in the database,
+// giving each content type a stable integer ID which can be resolved with
+// [ctypes.IDForType] and [ctypes.TypeForID].
+//
+// The content types are synchronized with the registry when the app is ready
+// and after the `migrate` command has run.
+func NewAppConfig() django.AppConfig {
+	var app = apps.NewDBAppConfig("contenttypes")
+
+	app.ModelObjects = []attrs.Definer{
+		&ContentType{},
+	}
+
+	app.Init = func(settings django.Settings, db drivers.Database) error {
+		ctypes.SetIDStore(store)
+
+		if !django.AppInstalled("migrator") {
+			var schemaEditor, err = migrator.GetSchemaEditor(db.Driver())
+			if err != nil {
+				return fmt.Errorf("failed to get schema editor: %w", err)
+			}
+
+			var table = migrator.NewModelTable(&ContentType{})
+			if err := schemaEditor.CreateTable(context.Background(), table, true); err != nil {
+				return fmt.Errorf("failed to create contenttypes table: %w", err)
+			}
+
+			for _, index := range table.Indexes() {
+				if err := schemaEditor.AddIndex(context.Background(), table, index, true); err != nil {
+					return fmt.Errorf("failed to create index %s: %w", index.Name(), err)
+				}
+			}
+
+			return nil
+		}
+
+		// commands are executed before the apps are ready,
+		// the listener must be registered during initialization.
+		var _, err = migrator.OnMigrated.Listen(context.Background(), func(ctx context.Context, s signals.Signal[*migrator.MigrationEngine], engine *migrator.MigrationEngine) error {
+			var created, err = Sync(ctx)
+			if err != nil {
+				return err
+			}
+			if created > 0 {
+				logger.Infof("Created %d content types", created)
+			}
+			return nil
+		})
+		return err
+	}
+
+	// All registered content types are stored and cached when the app is ready,
+	// queries which filter on content type IDs are compiled with the cached IDs.
+	app.Ready = func() error {
+		var _, err = Sync(context.Background())
+		if err != nil && django.AppInstalled("migrator") {
+			logger.Warnf("Failed to synchronize content types, have the migrations been applied? %v", err)
+			return nil
+		}
+		return err
+	}
+
+	return &migrator.MigratorAppConfig{
+		AppConfig: app,
+		MigrationFS: filesystem.Sub(
+			migrationFS, "migrations/contenttypes",
+		),
+	}
+}
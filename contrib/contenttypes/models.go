@@ -0,0 +1,63 @@
+package contenttypes
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	"github.com/Nigel2392/go-django/queries/src/models"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/trans"
+)
+
+// ContentType is the persisted version of a content type from the in-memory registry.
+//
+// The ID is stable for the lifetime of the database, when a model is moved or renamed
+// the TypeName is updated with [Rename] and the ID stays the same.
+type ContentType struct {
+	models.Model `table:"contenttypes_contenttype"`
+	ID           int64
+	AppLabel     string
+	ModelName    string
+	TypeName     string
+}
+
+func (c *ContentType) String() string {
+	return c.AppLabel + "." + c.ModelName
+}
+
+func (c *ContentType) DatabaseIndexes(obj attrs.Definer) []migrator.Index {
+	if reflect.TypeOf(obj) != reflect.TypeOf(c) {
+		return nil
+	}
+
+	return []migrator.Index{
+		{Fields: []string{"TypeName"}, Unique: true},
+	}
+}
+
+func (c *ContentType) FieldDefs(ctx context.Context) attrs.Definitions {
+	return c.Model.Define(ctx, c,
+		attrs.NewField(c, "ID", &attrs.FieldConfig{
+			HelpText: trans.S("The unique identifier for the content type."),
+			Primary:  true,
+			Column:   "id",
+			ReadOnly: true,
+		}),
+		attrs.NewField(c, "AppLabel", &attrs.FieldConfig{
+			HelpText:  trans.S("The label of the app the model belongs to, e.g. auth."),
+			Column:    "app_label",
+			MaxLength: 100,
+		}),
+		attrs.NewField(c, "ModelName", &attrs.FieldConfig{
+			HelpText:  trans.S("The name of the model, e.g. User."),
+			Column:    "model",
+			MaxLength: 100,
+		}),
+		attrs.NewField(c, "TypeName", &attrs.FieldConfig{
+			HelpText:  trans.S("The full type name of the model, including the package path."),
+			Column:    "type_name",
+			MaxLength: 255,
+		}),
+	)
+}
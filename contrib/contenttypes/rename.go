@@ -0,0 +1,131 @@
+package contenttypes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/queries/src/expr"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	ctypes "github.com/Nigel2392/go-django/src/core/contenttypes"
+)
+
+// Rename renames the persisted content type from the old type name to the new type name,
+// and rewrites the stored type names of all fields registered with [ctypes.RegisterReference].
+//
+// The ID of the content type stays the same, so fields which store the ID do not need to be updated.
+func Rename(ctx context.Context, oldTypeName, newTypeName string) error {
+	if oldTypeName == "" || newTypeName == "" {
+		return errors.ValueError.Wrap("old and new type names must not be empty")
+	}
+
+	if oldTypeName == newTypeName {
+		return nil
+	}
+
+	ctx, transaction, err := queries.StartTransaction(ctx)
+	if err != nil {
+		return errors.FailedStartTransaction.WithCause(err)
+	}
+	defer transaction.Rollback(ctx)
+
+	if err := rename(ctx, oldTypeName, newTypeName); err != nil {
+		return err
+	}
+
+	// registered before committing, the hook runs when the transaction
+	// in the context is committed, which might not be ours.
+	queries.OnCommit(ctx, func(context.Context) {
+		store.rename(oldTypeName, newTypeName)
+	})
+
+	if err := transaction.Commit(ctx); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// RenameMigration returns a function which renames a content type when a migration is applied,
+// it should be registered with [migrator.RegisterMigrateFunc] for a migration file with an `exec` action.
+//
+// The content type is renamed in the transaction of the migration, it is rolled back if the migration fails.
+// The cached content types are reloaded by [Sync] after the migrations have run.
+//
+//	func init() {
+//		migrator.RegisterMigrateFunc(&Article{}, "0002_rename_blog_post.mig", contenttypes.RenameMigration(
+//			"github.com/me/blog.Post", "github.com/me/news.Article",
+//		))
+//	}
+func RenameMigration(oldTypeName, newTypeName string) func(context.Context, *migrator.MigrationEngine, *migrator.ModelTable) error {
+	return func(ctx context.Context, engine *migrator.MigrationEngine, table *migrator.ModelTable) error {
+		if oldTypeName == "" || newTypeName == "" {
+			return errors.ValueError.Wrap("old and new type names must not be empty")
+		}
+
+		if oldTypeName == newTypeName {
+			return nil
+		}
+
+		if tx, ok := migrator.DbFromContext(ctx, nil).(drivers.Transaction); ok {
+			ctx = queries.ContextWithTransaction(ctx, tx)
+		}
+
+		if err := rename(ctx, oldTypeName, newTypeName); err != nil {
+			return fmt.Errorf("migration for %T: %w", table.Object, err)
+		}
+		return nil
+	}
+}
+
+// rename updates the content type and the stored references in the database,
+// the transaction must be managed by the caller.
+func rename(ctx context.Context, oldTypeName, newTypeName string) error {
+	var appLabel, modelName = splitTypeName(newTypeName)
+	var _, err = queries.GetQuerySetWithContext(ctx, &ContentType{}).
+		Select("AppLabel", "ModelName", "TypeName").
+		Filter("TypeName", oldTypeName).
+		ExplicitSave().
+		Update(&ContentType{
+			AppLabel:  appLabel,
+			ModelName: modelName,
+			TypeName:  newTypeName,
+		})
+	if err != nil && !errors.Is(err, errors.NoChanges) {
+		return errors.Wrapf(
+			err, "failed to rename content type %q to %q", oldTypeName, newTypeName,
+		)
+	}
+
+	for _, ref := range ctypes.References() {
+		var obj = attrs.NewObject[attrs.Definer](ctx, ref.Model)
+		_, err = queries.GetQuerySetWithContext(ctx, obj).
+			Select(ref.Field).
+			Filter(ref.Field, oldTypeName).
+			ExplicitSave().
+			Update(obj, expr.As(ref.Field, expr.Value(newTypeName)))
+		if err != nil && !errors.Is(err, errors.NoChanges) {
+			return errors.Wrapf(
+				err, "failed to rewrite content type references in %T.%s", ref.Model, ref.Field,
+			)
+		}
+	}
+
+	return nil
+}
+
+func splitTypeName(typeName string) (appLabel, modelName string) {
+	var lastDot = strings.LastIndex(typeName, ".")
+	if lastDot == -1 {
+		return "", typeName
+	}
+
+	var pkgPath = typeName[:lastDot]
+	modelName = typeName[lastDot+1:]
+	appLabel = pkgPath[strings.LastIndex(pkgPath, "/")+1:]
+	return appLabel, modelName
+}
@@ -0,0 +1,195 @@
+package contenttypes
+
+import (
+	"context"
+	"sync"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	ctypes "github.com/Nigel2392/go-django/src/core/contenttypes"
+)
+
+var _ ctypes.IDStore = (*contentTypeStore)(nil)
+
+// contentTypeStore caches the persisted content types, it is set as
+// the [ctypes.IDStore] when the contenttypes app is initialized.
+type contentTypeStore struct {
+	mu     sync.RWMutex
+	byName map[string]int64
+	byID   map[int64]string
+}
+
+var store = &contentTypeStore{
+	byName: make(map[string]int64),
+	byID:   make(map[int64]string),
+}
+
+func (s *contentTypeStore) cache(ct *ContentType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byName[ct.TypeName] = ct.ID
+	s.byID[ct.ID] = ct.TypeName
+}
+
+func (s *contentTypeStore) rename(oldTypeName, newTypeName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var id, ok = s.byName[oldTypeName]
+	if !ok {
+		return
+	}
+	delete(s.byName, oldTypeName)
+	s.byName[newTypeName] = id
+	s.byID[id] = newTypeName
+}
+
+func (s *contentTypeStore) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byName = make(map[string]int64)
+	s.byID = make(map[int64]string)
+}
+
+// CachedContentTypeID returns the cached ID of the content type.
+//
+// All registered content types are cached when the app is ready, see [Sync].
+func (s *contentTypeStore) CachedContentTypeID(typeName string) (int64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var id, ok = s.byName[typeName]
+	return id, ok
+}
+
+// cacheOnCommit caches the content type once the transaction in the context is committed,
+// a content type created in a transaction which is rolled back must not be cached.
+func (s *contentTypeStore) cacheOnCommit(ctx context.Context, ct *ContentType) {
+	queries.OnCommit(ctx, func(context.Context) {
+		s.cache(ct)
+	})
+}
+
+// ContentTypeID returns the ID of the content type, the content type is
+// created if it is registered but not yet stored in the database.
+func (s *contentTypeStore) ContentTypeID(ctx context.Context, typeName string) (int64, error) {
+	s.mu.RLock()
+	var id, ok = s.byName[typeName]
+	s.mu.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	var definition = ctypes.DefinitionForType(typeName)
+	if definition == nil {
+		return 0, errors.InvalidContentType.Wrapf(
+			"content type %q is not registered", typeName,
+		)
+	}
+
+	var cType = definition.ContentType()
+	var ct, _, err = queries.GetQuerySetWithContext(ctx, &ContentType{}).
+		Filter("TypeName", cType.TypeName()).
+		GetOrCreate(&ContentType{
+			AppLabel:  cType.AppLabel(),
+			ModelName: cType.Model(),
+			TypeName:  cType.TypeName(),
+		})
+	if err != nil {
+		return 0, errors.Wrapf(
+			err, "failed to get content type %q", typeName,
+		)
+	}
+
+	s.cacheOnCommit(ctx, ct)
+	return ct.ID, nil
+}
+
+// ContentTypeName returns the type name of the content type with the given ID.
+func (s *contentTypeStore) ContentTypeName(ctx context.Context, id int64) (string, error) {
+	s.mu.RLock()
+	var typeName, ok = s.byID[id]
+	s.mu.RUnlock()
+	if ok {
+		return typeName, nil
+	}
+
+	var row, err = queries.GetQuerySetWithContext(ctx, &ContentType{}).
+		Filter("ID", id).
+		Get()
+	if err != nil {
+		return "", errors.InvalidContentType.WithCause(errors.Wrapf(
+			err, "failed to get content type with ID %d", id,
+		))
+	}
+
+	s.cacheOnCommit(ctx, row.Object)
+	return row.Object.TypeName, nil
+}
+
+// Get returns the persisted content type for the given type name or alias.
+func Get(ctx context.Context, typeName string) (*ContentType, error) {
+	var id, err = store.ContentTypeID(ctx, ctypes.ReverseAlias(typeName))
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := queries.GetQuerySetWithContext(ctx, &ContentType{}).
+		Filter("ID", id).
+		Get()
+	if err != nil {
+		return nil, err
+	}
+	return row.Object, nil
+}
+
+// ForObject returns the persisted content type for the given object.
+func ForObject(ctx context.Context, obj any) (*ContentType, error) {
+	return Get(ctx, ctypes.NewContentType(obj).TypeName())
+}
+
+// Sync stores all content types from the in-memory registry which are not yet stored in the database.
+//
+// It is called automatically after the `migrate` command has run, it returns the number of created content types.
+func Sync(ctx context.Context) (created int, err error) {
+	var rows queries.Rows[*ContentType]
+	rows, err = queries.GetQuerySetWithContext(ctx, &ContentType{}).All()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to retrieve content types")
+	}
+
+	store.clear()
+
+	var existing = make(map[string]struct{}, len(rows))
+	for _, row := range rows {
+		existing[row.Object.TypeName] = struct{}{}
+		store.cache(row.Object)
+	}
+
+	var missing = make([]*ContentType, 0)
+	for _, definition := range ctypes.ListDefinitions() {
+		var cType = definition.ContentType()
+		if _, ok := existing[cType.TypeName()]; ok {
+			continue
+		}
+
+		missing = append(missing, &ContentType{
+			AppLabel:  cType.AppLabel(),
+			ModelName: cType.Model(),
+			TypeName:  cType.TypeName(),
+		})
+	}
+
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	missing, err = queries.GetQuerySetWithContext(ctx, &ContentType{}).BulkCreate(missing)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create content types")
+	}
+
+	for _, ct := range missing {
+		store.cacheOnCommit(ctx, ct)
+	}
+
+	return len(missing), nil
+}
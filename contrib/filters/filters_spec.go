@@ -1,11 +1,18 @@
 package filters
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/src/core/assert"
+	"github.com/Nigel2392/go-django/src/core/attrs"
 	"github.com/Nigel2392/go-django/src/core/logger"
 	"github.com/Nigel2392/go-django/src/forms/fields"
+	"github.com/Nigel2392/go-django/src/forms/widgets"
+	"github.com/Nigel2392/go-django/src/forms/widgets/options"
 )
 
 type BaseFilterSpec[T any] struct {
@@ -29,3 +36,58 @@ func (b *BaseFilterSpec[T]) Filter(req *http.Request, value interface{}, object
 	}
 	return b.Apply(req, value, object)
 }
+
+// ChoicesFilter returns a [FilterSpec] which filters the queryset on a field with choices.
+//
+// The field must implement [attrs.CanChoices], the choices are rendered as a select input
+// with the translated labels of the choices.
+func ChoicesFilter[T attrs.Definer](model T, fieldName string) FilterSpec[T] {
+	return &choicesFilterSpec[T]{
+		model:     model,
+		fieldName: fieldName,
+	}
+}
+
+type choicesFilterSpec[T attrs.Definer] struct {
+	model     T
+	fieldName string
+}
+
+func (c *choicesFilterSpec[T]) Name() string {
+	return c.fieldName
+}
+
+func (c *choicesFilterSpec[T]) choicesField(ctx context.Context) attrs.CanChoices {
+	var defs = attrs.Define(ctx, c.model)
+	var field, ok = defs.Field(c.fieldName)
+	assert.True(ok, "field %q not found in model %T", c.fieldName, c.model)
+
+	choicesField, ok := field.(attrs.CanChoices)
+	assert.True(ok, "field %q in model %T does not have choices", c.fieldName, c.model)
+	return choicesField
+}
+
+func (c *choicesFilterSpec[T]) Field(r *http.Request) fields.Field {
+	var field = c.choicesField(r.Context())
+	return fields.CharField(
+		fields.Label(field.Label),
+		fields.Widget(options.NewSelectInput(nil, func() []widgets.Option {
+			return attrs.ChoiceOptions(r.Context(), field.Choices())
+		}, options.IncludeBlank(true))),
+	)
+}
+
+func (c *choicesFilterSpec[T]) Filter(r *http.Request, value interface{}, object *queries.QuerySet[T]) (*queries.QuerySet[T], error) {
+	if fields.IsZero(value) {
+		return object, nil
+	}
+
+	var field = c.choicesField(r.Context())
+	for _, choice := range field.Choices().ChoiceValues() {
+		if fmt.Sprint(choice) == value {
+			return object.Filter(c.fieldName, choice), nil
+		}
+	}
+
+	return object, nil
+}
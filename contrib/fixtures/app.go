@@ -0,0 +1,18 @@
+package fixtures
+
+import (
+	django "github.com/Nigel2392/go-django/src"
+	"github.com/Nigel2392/go-django/src/apps"
+	"github.com/Nigel2392/go-django/src/core/command"
+)
+
+// NewAppConfig returns the fixtures app, which adds the
+// `dumpdata` and `loaddata` commands.
+func NewAppConfig() django.AppConfig {
+	var app = apps.NewDBAppConfig("fixtures")
+	app.Cmd = []command.Command{
+		commandDumpData,
+		commandLoadData,
+	}
+	return app
+}
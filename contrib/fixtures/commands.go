@@ -0,0 +1,115 @@
+package fixtures
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Nigel2392/go-django/src/core/command"
+	"github.com/Nigel2392/go-django/src/core/command/flags"
+)
+
+type dumpDataFlags struct {
+	Format         string
+	Output         string
+	Exclude        flags.List
+	NaturalForeign bool
+	NaturalPrimary bool
+}
+
+var commandDumpData = &command.Cmd[dumpDataFlags]{
+	ID:   "dumpdata",
+	Desc: "Write the data of the given apps or models (I.E. `auth` or `auth.User`) to a fixture, all apps are dumped if none are given",
+	FlagFunc: func(m command.Manager, stored *dumpDataFlags, f *flag.FlagSet) error {
+		f.StringVar(&stored.Format, "format", "", "Fixture format: json, jsonl or yaml (default: based on the output file, or json)")
+		f.StringVar(&stored.Output, "output", "", "File to write the fixture to (default: stdout)")
+		f.StringVar(&stored.Output, "o", "", "Alias for --output")
+		f.Var(&stored.Exclude, "exclude", "List of apps or models to exclude")
+		f.Var(&stored.Exclude, "e", "Alias for --exclude")
+		f.BoolVar(&stored.NaturalForeign, "natural-foreign", false, "Write references to models which support natural keys as natural keys")
+		f.BoolVar(&stored.NaturalPrimary, "natural-primary", false, "Omit primary keys of models which support natural keys")
+		return nil
+	},
+	Execute: func(m command.Manager, stored dumpDataFlags, args []string) error {
+		var format = Format(stored.Format)
+		if format == "" && stored.Output != "" {
+			var f, err = FormatFromPath(stored.Output)
+			if err != nil {
+				return err
+			}
+			format = f
+		}
+
+		var objects, err = Dump(context.Background(), DumpOptions{
+			Models:         args,
+			Exclude:        stored.Exclude.List(),
+			NaturalForeign: stored.NaturalForeign,
+			NaturalPrimary: stored.NaturalPrimary,
+		})
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = m.Stdout()
+		if stored.Output != "" {
+			var file, err = os.Create(stored.Output)
+			if err != nil {
+				return fmt.Errorf("failed to create fixture %q: %w", stored.Output, err)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		if err := Encode(w, format, objects); err != nil {
+			return err
+		}
+
+		if stored.Output != "" {
+			m.Logf("Dumped %d object(s) to %s", len(objects), stored.Output)
+		}
+
+		return command.ErrShouldExit
+	},
+}
+
+type loadDataFlags struct {
+	Database string
+}
+
+var commandLoadData = &command.Cmd[loadDataFlags]{
+	ID:   "loaddata",
+	Desc: "Load the given fixture files into the database in a single transaction",
+	FlagFunc: func(m command.Manager, stored *loadDataFlags, f *flag.FlagSet) error {
+		f.StringVar(&stored.Database, "database", "", "Name of the database to load the fixtures into (default: the default database)")
+		return nil
+	},
+	Execute: func(m command.Manager, stored loadDataFlags, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("no fixture files provided: %w", command.ErrShouldExit)
+		}
+
+		var objects = make([]*Object, 0)
+		for _, path := range args {
+			var fileObjects, err = ReadFile(path)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, fileObjects...)
+		}
+
+		var database []string
+		if stored.Database != "" {
+			database = append(database, stored.Database)
+		}
+
+		var count, err = Load(context.Background(), objects, database...)
+		if err != nil {
+			return err
+		}
+
+		m.Logf("Installed %d object(s) from %d fixture(s)", count, len(args))
+		return command.ErrShouldExit
+	},
+}
@@ -0,0 +1,298 @@
+package fixtures
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"time"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+type DumpOptions struct {
+	// Models to dump, either app names (I.E. "auth") or model labels (I.E. "auth.User").
+	//
+	// All models of all installed apps are dumped if empty.
+	Models []string
+
+	// Exclude apps or models from the dump, uses the same format as Models.
+	Exclude []string
+
+	// NaturalForeign writes references to models implementing [NaturalKeyDefiner]
+	// as their natural key instead of their primary key.
+	NaturalForeign bool
+
+	// NaturalPrimary omits the primary key of models implementing [NaturalKeyDefiner],
+	// the natural key is written instead.
+	NaturalPrimary bool
+}
+
+type dumper struct {
+	ctx  context.Context
+	reg  *registry
+	opts DumpOptions
+
+	// natural keys of related objects by model label and primary key
+	naturalKeys map[string]map[string][]any
+}
+
+// Dump returns all objects of the selected models.
+//
+// Objects are ordered so that related objects come first,
+// the resulting list can be passed to [Load] as-is.
+func Dump(ctx context.Context, opts DumpOptions) ([]*Object, error) {
+	var reg, err = newRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := reg.selectEntries(opts.Models, opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var d = &dumper{
+		ctx:         ctx,
+		reg:         reg,
+		opts:        opts,
+		naturalKeys: make(map[string]map[string][]any),
+	}
+
+	var objects = make([]*Object, 0)
+	for _, entry := range entries {
+		var dumped, err = d.dumpModel(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dump %q: %w", entry.label, err)
+		}
+		objects = append(objects, dumped...)
+	}
+
+	return objects, nil
+}
+
+func (d *dumper) dumpModel(entry *modelEntry) ([]*Object, error) {
+	var qs = queries.GetQuerySetWithContext(d.ctx, entry.newObject(d.ctx))
+	if entry.primary != nil {
+		qs = qs.OrderBy(entry.primary.Name)
+	}
+
+	var rows, err = qs.All()
+	if err != nil {
+		return nil, err
+	}
+
+	var objects = make([]*Object, 0, len(rows))
+	for _, row := range rows {
+		var obj, err = d.dumpObject(entry, row.Object)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+func (d *dumper) dumpObject(entry *modelEntry, instance attrs.Definer) (*Object, error) {
+	var (
+		defs = attrs.Define(d.ctx, instance)
+		obj  = &Object{
+			Model:  entry.label,
+			Fields: make(map[string]any, len(entry.columns)),
+		}
+		_, natural = entry.naturalKeyFields()
+	)
+
+	for _, col := range entry.columns {
+		var field, ok = defs.Field(col.Name)
+		if !ok {
+			return nil, errors.FieldNotFound.Wrapf(
+				"field %q not found in %T", col.Name, instance,
+			)
+		}
+
+		if col.Primary {
+			var value, err = field.Value()
+			if err != nil {
+				return nil, err
+			}
+			if !(natural && d.opts.NaturalPrimary) {
+				obj.PK = dumpValue(value)
+			}
+			continue
+		}
+
+		if isForeignKey(col) {
+			var value, err = d.dumpForeignKey(col.Rel.Model(), field.GetValue())
+			if err != nil {
+				return nil, fmt.Errorf("failed to dump field %q: %w", col.Name, err)
+			}
+			obj.Fields[col.Name] = value
+			continue
+		}
+
+		var value, err = field.Value()
+		if err != nil {
+			return nil, err
+		}
+		obj.Fields[col.Name] = dumpFieldValue(field, value)
+	}
+
+	if natural && d.opts.NaturalPrimary {
+		var key, err = naturalKey(d.ctx, instance.(NaturalKeyDefiner))
+		if err != nil {
+			return nil, err
+		}
+		obj.NaturalKey = key
+	}
+
+	if d.opts.NaturalForeign {
+		for _, rel := range genericRelations(defs) {
+			var (
+				typeName = attrs.ToString(rel.contentType.GetValue())
+				target   = d.reg.byTypeName[typeName]
+			)
+			if target == nil {
+				continue
+			}
+			if _, ok := target.naturalKeyFields(); !ok {
+				continue
+			}
+
+			var key, err = d.relatedNaturalKey(target, rel.primary.GetValue())
+			if err != nil {
+				return nil, fmt.Errorf("failed to dump generic relation %q: %w", rel.primary.Name(), err)
+			}
+			obj.Fields[rel.primary.Name()] = key
+		}
+	}
+
+	return obj, nil
+}
+
+// dumpForeignKey returns the primary key of the related object,
+// or its natural key if [DumpOptions.NaturalForeign] is set.
+func (d *dumper) dumpForeignKey(target attrs.Definer, value any) (any, error) {
+	var pk = value
+	if definer, ok := value.(attrs.Definer); ok {
+		pk = attrs.PrimaryKey(d.ctx, definer)
+	}
+
+	if pk == nil || attrs.IsZero(pk) {
+		return nil, nil
+	}
+
+	var entry, ok = d.reg.byType[modelType(target)]
+	if ok && d.opts.NaturalForeign {
+		if _, natural := entry.naturalKeyFields(); natural {
+			return d.relatedNaturalKey(entry, pk)
+		}
+	}
+
+	return dumpValue(pk), nil
+}
+
+// relatedNaturalKey retrieves the object by its primary key and returns its natural key.
+func (d *dumper) relatedNaturalKey(entry *modelEntry, pk any) ([]any, error) {
+	if entry.primary == nil {
+		return nil, errors.NoUniqueKey.Wrapf(
+			"model %s has no primary key", entry.label,
+		)
+	}
+
+	var cache, ok = d.naturalKeys[entry.label]
+	if !ok {
+		cache = make(map[string][]any)
+		d.naturalKeys[entry.label] = cache
+	}
+
+	var cacheKey = attrs.ToString(pk)
+	if key, ok := cache[cacheKey]; ok {
+		return key, nil
+	}
+
+	var row, err = queries.GetQuerySetWithContext(d.ctx, entry.newObject(d.ctx)).
+		Filter(entry.primary.Name, pk).
+		Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve %s with primary key %v: %w", entry.label, pk, err)
+	}
+
+	key, err := naturalKey(d.ctx, row.Object.(NaturalKeyDefiner))
+	if err != nil {
+		return nil, err
+	}
+
+	cache[cacheKey] = key
+	return key, nil
+}
+
+// naturalKey returns the values of the natural key fields of the object.
+func naturalKey(ctx context.Context, obj NaturalKeyDefiner) ([]any, error) {
+	var (
+		names = obj.NaturalKeyFields()
+		defs  = attrs.Define(ctx, obj)
+		key   = make([]any, 0, len(names))
+	)
+
+	for _, name := range names {
+		var field, ok = defs.Field(name)
+		if !ok {
+			return nil, errors.FieldNotFound.Wrapf(
+				"natural key field %q not found in %T", name, obj,
+			)
+		}
+
+		var value any
+		if definer, ok := field.GetValue().(attrs.Definer); ok {
+			value = attrs.PrimaryKey(ctx, definer)
+		} else {
+			var v, err = field.Value()
+			if err != nil {
+				return nil, err
+			}
+			value = v
+		}
+
+		key = append(key, dumpValue(value))
+	}
+
+	return key, nil
+}
+
+// dumpFieldValue converts the driver value of the field with [dumpValue].
+//
+// Only values of []byte fields are base64 encoded, other fields which store
+// their value as bytes (I.E. JSON fields) are dumped as text, [scanValue] scans
+// the text back into the field.
+func dumpFieldValue(field attrs.Field, v any) any {
+	var b, ok = v.([]byte)
+	if !ok {
+		return dumpValue(v)
+	}
+
+	var typ = field.Type()
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == bytesType {
+		return dumpValue(b)
+	}
+
+	return string(b)
+}
+
+// dumpValue converts driver values to values which survive a round trip through JSON and YAML.
+func dumpValue(v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	}
+	return v
+}
@@ -0,0 +1,213 @@
+package fixtures
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Nigel2392/go-django/pkg/yml"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"gopkg.in/yaml.v3"
+)
+
+/*
+Package fixtures provides a way to export and import model data.
+
+Any model registered to an installed app can be dumped to a fixture,
+and fixtures can be loaded back into the database with [Load].
+
+Fixtures are lists of [Object], they can be stored as JSON, JSON lines or YAML.
+
+Only concrete columns are stored, models which cannot be migrated are skipped.
+Many-to-many relations are stored as objects of their through model.
+*/
+
+type Format string
+
+const (
+	FormatJSON  Format = "json"
+	FormatJSONL Format = "jsonl"
+	FormatYAML  Format = "yaml"
+)
+
+// Object is a single model instance in a fixture.
+//
+// Model is the label of the model, I.E. "auth.User".
+//
+// Fields maps field names to their values, foreign keys are stored as
+// the primary key of the related object, or its natural key if requested.
+type Object struct {
+	Model      string         `json:"model" yaml:"model"`
+	PK         any            `json:"pk,omitempty" yaml:"pk,omitempty"`
+	NaturalKey []any          `json:"natural_key,omitempty" yaml:"natural_key,omitempty"`
+	Fields     map[string]any `json:"fields" yaml:"fields"`
+}
+
+// NaturalKeyDefiner can be implemented by models to opt in to natural keys.
+//
+// A natural key is a list of field values which uniquely identifies an object
+// across databases, I.E. a username or a slug.
+//
+// When dumping with natural keys enabled, references to these models are written
+// as their natural key instead of their primary key; when loading, the natural key
+// is used to look up the existing object.
+type NaturalKeyDefiner interface {
+	attrs.Definer
+
+	// NaturalKeyFields returns the names of the fields which make up the natural key.
+	NaturalKeyFields() []string
+}
+
+// FormatFromPath returns the format of a fixture file based on its extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	}
+	return "", errors.ValueError.Wrapf(
+		"unknown fixture format for file %q", path,
+	)
+}
+
+// Encode writes the objects to w in the given format.
+func Encode(w io.Writer, format Format, objects []*Object) error {
+	switch format {
+	case FormatJSON, "":
+		var enc = json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(objects)
+	case FormatJSONL:
+		var enc = json.NewEncoder(w)
+		for _, obj := range objects {
+			if err := enc.Encode(obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatYAML:
+		var enc = yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(objects); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return errors.ValueError.Wrapf(
+		"unknown fixture format %q", format,
+	)
+}
+
+// Decode reads the objects from r in the given format.
+//
+// JSON numbers are decoded as int64 if possible, otherwise as float64.
+func Decode(r io.Reader, format Format) ([]*Object, error) {
+	var objects []*Object
+	switch format {
+	case FormatJSON, "":
+		var dec = json.NewDecoder(r)
+		dec.UseNumber()
+		if err := dec.Decode(&objects); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON fixture: %w", err)
+		}
+	case FormatJSONL:
+		var scanner = bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			var b = bytes.TrimSpace(scanner.Bytes())
+			if len(b) == 0 {
+				continue
+			}
+
+			var dec = json.NewDecoder(bytes.NewReader(b))
+			dec.UseNumber()
+
+			var obj = new(Object)
+			if err := dec.Decode(obj); err != nil {
+				return nil, fmt.Errorf("failed to decode JSONL fixture on line %d: %w", line, err)
+			}
+			objects = append(objects, obj)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		if err := yml.UnmarshalReader(r, &objects, true); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.ValueError.Wrapf(
+			"unknown fixture format %q", format,
+		)
+	}
+
+	for i, obj := range objects {
+		if obj == nil || obj.Model == "" {
+			return nil, errors.ValueError.Wrapf(
+				"fixture object at index %d has no model", i,
+			)
+		}
+		obj.PK = normalizeValue(obj.PK)
+		for j, v := range obj.NaturalKey {
+			obj.NaturalKey[j] = normalizeValue(v)
+		}
+		for k, v := range obj.Fields {
+			obj.Fields[k] = normalizeValue(v)
+		}
+	}
+
+	return objects, nil
+}
+
+// ReadFile reads the objects from the fixture file at path.
+//
+// The format is determined by the file's extension.
+func ReadFile(path string) ([]*Object, error) {
+	var format, err = FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture %q: %w", path, err)
+	}
+	defer file.Close()
+
+	objects, err := Decode(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture %q: %w", path, err)
+	}
+	return objects, nil
+}
+
+// normalizeValue converts decoded values to types which can be scanned into fields.
+func normalizeValue(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case int:
+		return int64(v)
+	case []any:
+		for i, elem := range v {
+			v[i] = normalizeValue(elem)
+		}
+		return v
+	}
+	return v
+}
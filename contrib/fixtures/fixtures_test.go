@@ -0,0 +1,82 @@
+package fixtures_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/Nigel2392/go-django/contrib/fixtures"
+)
+
+func TestFormatFromPath(t *testing.T) {
+	var tests = map[string]fixtures.Format{
+		"data.json":         fixtures.FormatJSON,
+		"data.JSONL":        fixtures.FormatJSONL,
+		"data.ndjson":       fixtures.FormatJSONL,
+		"fixtures/seed.yml": fixtures.FormatYAML,
+		"seed.yaml":         fixtures.FormatYAML,
+	}
+
+	for path, expected := range tests {
+		var format, err = fixtures.FormatFromPath(path)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", path, err)
+			continue
+		}
+		if format != expected {
+			t.Errorf("expected format %q for %q, got %q", expected, path, format)
+		}
+	}
+
+	if _, err := fixtures.FormatFromPath("data.xml"); err == nil {
+		t.Errorf("expected error for unknown extension")
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	var objects = []*fixtures.Object{
+		{
+			Model: "auth.User",
+			PK:    int64(1),
+			Fields: map[string]any{
+				"Username": "admin",
+				"IsActive": true,
+				"Score":    1.5,
+			},
+		},
+		{
+			Model:      "blog.Post",
+			NaturalKey: []any{"hello-world"},
+			Fields: map[string]any{
+				"Slug":   "hello-world",
+				"Author": []any{"admin"},
+				"Parent": nil,
+			},
+		},
+	}
+
+	for _, format := range []fixtures.Format{fixtures.FormatJSON, fixtures.FormatJSONL, fixtures.FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := fixtures.Encode(&buf, format, objects); err != nil {
+				t.Fatalf("failed to encode: %v", err)
+			}
+
+			var decoded, err = fixtures.Decode(&buf, format)
+			if err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+
+			if !reflect.DeepEqual(decoded, objects) {
+				t.Fatalf("decoded objects do not match:\n%#v\n%#v", decoded[0], objects[0])
+			}
+		})
+	}
+}
+
+func TestDecodeRequiresModel(t *testing.T) {
+	var _, err = fixtures.Decode(bytes.NewBufferString(`[{"pk": 1, "fields": {}}]`), fixtures.FormatJSON)
+	if err == nil {
+		t.Fatalf("expected error for object without model")
+	}
+}
@@ -0,0 +1,459 @@
+package fixtures
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/logger"
+)
+
+type loader struct {
+	ctx    context.Context
+	reg    *registry
+	tx     queries.DatabaseSpecificTransaction
+	loaded map[*modelEntry]struct{}
+}
+
+// LoadFiles reads the fixture files and loads all objects in a single transaction.
+//
+// See [Load] for more information.
+func LoadFiles(ctx context.Context, paths ...string) (int, error) {
+	var objects = make([]*Object, 0)
+	for _, path := range paths {
+		var fileObjects, err = ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+		objects = append(objects, fileObjects...)
+	}
+	return Load(ctx, objects)
+}
+
+// Load writes the objects to the database and returns the number of objects written.
+//
+// All objects are written inside of a single transaction, foreign key checks are deferred
+// until the transaction is committed (or disabled for the transaction on MySQL and MariaDB).
+//
+// On PostgreSQL only constraints created as DEFERRABLE are deferred, the migrator does not create them as such.
+// Objects of models which reference each other in a cycle can fail to load there.
+//
+// Objects are sorted so that objects of related models are written first.
+// An existing row with the same primary key (or natural key) is updated, otherwise a new row is inserted.
+//
+// Objects are written with raw queries, model methods like Save and signals are not called.
+//
+// Objects of models which cannot be migrated are skipped.
+func Load(ctx context.Context, objects []*Object, database ...string) (int, error) {
+	var reg, err = newRegistry()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		order   = make(map[*modelEntry]int, len(reg.entries))
+		entries = make(map[*Object]*modelEntry, len(objects))
+		toLoad  = make([]*Object, 0, len(objects))
+	)
+
+	for i, entry := range reg.entries {
+		order[entry] = i
+	}
+
+	for _, obj := range objects {
+		if reg.isSkipped(obj.Model) {
+			logger.Warnf("fixtures: skipping object of model %q, it cannot be migrated", obj.Model)
+			continue
+		}
+
+		var entry, err = reg.lookup(obj.Model)
+		if err != nil {
+			return 0, err
+		}
+
+		entries[obj] = entry
+		toLoad = append(toLoad, obj)
+	}
+
+	slices.SortStableFunc(toLoad, func(a, b *Object) int {
+		return order[entries[a]] - order[entries[b]]
+	})
+
+	if !queries.IsCommitContext(ctx) {
+		return len(toLoad), nil
+	}
+
+	ctx, tx, err := queries.StartTransaction(ctx, database...)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var l = &loader{
+		ctx:    ctx,
+		reg:    reg,
+		tx:     tx,
+		loaded: make(map[*modelEntry]struct{}),
+	}
+
+	restore, err := l.deferConstraints()
+	if err != nil {
+		return 0, err
+	}
+
+	// the checks must be restored before the transaction is finished,
+	// MySQL keeps FOREIGN_KEY_CHECKS disabled on the pooled connection otherwise.
+	var restored bool
+	defer func() {
+		if !restored {
+			restore()
+		}
+	}()
+
+	for i, obj := range toLoad {
+		if err := l.loadObject(entries[obj], obj); err != nil {
+			return 0, fmt.Errorf("failed to load object %d (%s, pk=%v): %w", i, obj.Model, obj.PK, err)
+		}
+	}
+
+	restored = true
+	if err := restore(); err != nil {
+		return 0, err
+	}
+
+	if err := l.resetSequences(); err != nil {
+		return 0, err
+	}
+
+	return len(toLoad), tx.Commit(ctx)
+}
+
+// querySet returns a queryset for the model which is bound to the selected database and the transaction of the loader.
+//
+// The base queryset is used instead of [queries.GetQuerySet], custom querysets of the model could select another database.
+func (l *loader) querySet(model attrs.Definer) *queries.QuerySet[attrs.Definer] {
+	return queries.Objects(model, l.tx.DatabaseName()).WithContext(l.ctx)
+}
+
+func (l *loader) driverName() string {
+	var d, ok = drivers.Retrieve(queries.Compiler(l.tx.DatabaseName()).Driver())
+	if !ok {
+		return ""
+	}
+	return d.Name
+}
+
+// deferConstraints defers foreign key checks until the end of the transaction.
+//
+// The returned function restores the checks for drivers which do not support deferring them.
+func (l *loader) deferConstraints() (restore func() error, err error) {
+	var stmt, enable string
+
+	switch l.driverName() {
+	case drivers.POSTGRES_DRIVER_NAME:
+		// only affects constraints which were created as DEFERRABLE,
+		// the objects are sorted by their dependencies for all others, see [Load].
+		stmt = "SET CONSTRAINTS ALL DEFERRED"
+	case drivers.SQLITE3_DRIVER_NAME:
+		stmt = "PRAGMA defer_foreign_keys = ON"
+	case drivers.MYSQL_DRIVER_NAME, drivers.MARIADB_DRIVER_NAME:
+		stmt = "SET FOREIGN_KEY_CHECKS = 0"
+		enable = "SET FOREIGN_KEY_CHECKS = 1"
+	}
+
+	restore = func() error {
+		if enable == "" {
+			return nil
+		}
+		var _, err = l.tx.ExecContext(l.ctx, enable)
+		return err
+	}
+
+	if stmt == "" {
+		return restore, nil
+	}
+
+	if _, err = l.tx.ExecContext(l.ctx, stmt); err != nil {
+		return nil, fmt.Errorf("failed to defer constraint checks: %w", err)
+	}
+
+	return restore, nil
+}
+
+// resetSequences updates the sequences of auto incrementing primary keys on PostgreSQL,
+// otherwise new rows would conflict with the primary keys of the loaded objects.
+func (l *loader) resetSequences() error {
+	if l.driverName() != drivers.POSTGRES_DRIVER_NAME {
+		return nil
+	}
+
+	for entry := range l.loaded {
+		if entry.primary == nil || !migrator.CanAutoIncrement(entry.primary.Field) {
+			continue
+		}
+
+		var (
+			compiler = l.querySet(entry.newObject(l.ctx)).Compiler()
+			table    = entry.table.TableName()
+			column   = compiler.QuoteIdentifier(entry.primary.Column)
+		)
+
+		var query = fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence(%s, %s), COALESCE(MAX(%s), 1), MAX(%s) IS NOT NULL) FROM %s",
+			compiler.QuoteString(table), compiler.QuoteString(entry.primary.Column),
+			column, column, compiler.QuoteIdentifier(table),
+		)
+
+		if _, err := l.tx.ExecContext(l.ctx, query); err != nil {
+			return fmt.Errorf("failed to reset sequence for %s: %w", entry.label, err)
+		}
+	}
+
+	return nil
+}
+
+func (l *loader) loadObject(entry *modelEntry, obj *Object) error {
+	var (
+		instance = entry.newObject(l.ctx)
+		defs     = attrs.Define(l.ctx, instance)
+		generic  = genericRelations(defs)
+	)
+
+	for name, value := range obj.Fields {
+		var col = entry.column(name)
+		if col == nil {
+			return errors.FieldNotFound.Wrapf(
+				"field %q is not a column of %s", name, entry.label,
+			)
+		}
+
+		var field, _ = defs.Field(name)
+		var key, isNaturalKey = value.([]any)
+		switch {
+		case isNaturalKey && isForeignKey(col):
+			var target, ok = l.reg.byType[modelType(col.Rel.Model())]
+			if !ok {
+				return errors.InvalidContentType.Wrapf(
+					"natural key for field %q refers to unknown model %T",
+					name, col.Rel.Model(),
+				)
+			}
+
+			var pk, err = l.resolveNaturalKey(target, key)
+			if err != nil {
+				return err
+			}
+			value = pk
+
+		case isNaturalKey:
+			var idx = slices.IndexFunc(generic, func(rel genericRelation) bool {
+				return rel.primary.Name() == name
+			})
+			if idx == -1 {
+				break
+			}
+
+			var typeName = attrs.ToString(obj.Fields[generic[idx].contentType.Name()])
+			var target = l.reg.byTypeName[typeName]
+			if target == nil {
+				return errors.InvalidContentType.Wrapf(
+					"generic relation %q refers to unknown model %q", name, typeName,
+				)
+			}
+
+			var pk, err = l.resolveNaturalKey(target, key)
+			if err != nil {
+				return err
+			}
+			value = pk
+		}
+
+		if err := scanValue(field, value); err != nil {
+			return fmt.Errorf("failed to set field %q: %w", name, err)
+		}
+	}
+
+	var pk = obj.PK
+	if pk == nil && len(obj.NaturalKey) > 0 {
+		var existing, err = l.lookupNaturalKey(entry, obj.NaturalKey)
+		if err != nil {
+			return err
+		}
+		pk = existing
+	}
+
+	if pk != nil && entry.primary != nil {
+		var field, _ = defs.Field(entry.primary.Name)
+		if err := scanValue(field, pk); err != nil {
+			return fmt.Errorf("failed to set primary key: %w", err)
+		}
+	}
+
+	l.loaded[entry] = struct{}{}
+	return l.write(entry, defs, pk != nil)
+}
+
+// resolveNaturalKey returns the primary key of the object with the given natural key,
+// the object must exist.
+func (l *loader) resolveNaturalKey(entry *modelEntry, key []any) (any, error) {
+	var pk, err = l.lookupNaturalKey(entry, key)
+	if err != nil {
+		return nil, err
+	}
+	if pk == nil {
+		return nil, errors.NoRows.Wrapf(
+			"no %s found with natural key %v", entry.label, key,
+		)
+	}
+	return pk, nil
+}
+
+// lookupNaturalKey returns the primary key of the object with the given natural key,
+// or nil if no such object exists.
+func (l *loader) lookupNaturalKey(entry *modelEntry, key []any) (any, error) {
+	var names, ok = entry.naturalKeyFields()
+	if !ok {
+		return nil, errors.NotImplemented.Wrapf(
+			"model %s does not support natural keys", entry.label,
+		)
+	}
+
+	if len(names) != len(key) {
+		return nil, errors.ValueError.Wrapf(
+			"natural key %v of %s should have %d values", key, entry.label, len(names),
+		)
+	}
+
+	var filter = make(map[string]any, len(names))
+	for i, name := range names {
+		filter[name] = key[i]
+	}
+
+	var row, err = l.querySet(entry.newObject(l.ctx)).
+		Filter(filter).
+		Get()
+	if errors.Is(err, errors.NoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return attrs.PrimaryKey(l.ctx, row.Object), nil
+}
+
+// write inserts or updates the row of the object.
+func (l *loader) write(entry *modelEntry, defs attrs.Definitions, hasPrimary bool) error {
+	var (
+		qs       = l.querySet(defs.Instance())
+		compiler = qs.Compiler()
+		columns  = make([]string, 0, len(entry.columns))
+		values   = make([]any, 0, len(entry.columns))
+		pkValue  any
+	)
+
+	for _, col := range entry.columns {
+		var field, _ = defs.Field(col.Name)
+		var value, err = field.Value()
+		if err != nil {
+			return err
+		}
+
+		if col.Primary {
+			pkValue = value
+			if !hasPrimary {
+				continue
+			}
+		}
+
+		columns = append(columns, compiler.QuoteIdentifier(col.Column))
+		values = append(values, value)
+	}
+
+	var exists bool
+	if hasPrimary && entry.primary != nil {
+		var err error
+		exists, err = l.querySet(entry.newObject(l.ctx)).
+			Filter(entry.primary.Name, pkValue).
+			Exists()
+		if err != nil {
+			return err
+		}
+	}
+
+	var (
+		table = compiler.QuoteIdentifier(entry.table.TableName())
+		query strings.Builder
+	)
+
+	if exists {
+		fmt.Fprintf(&query, "UPDATE %s SET ", table)
+		for i, column := range columns {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			fmt.Fprintf(&query, "%s = ?", column)
+		}
+		fmt.Fprintf(&query, " WHERE %s = ?", compiler.QuoteIdentifier(entry.primary.Column))
+		values = append(values, pkValue)
+	} else {
+		fmt.Fprintf(
+			&query, "INSERT INTO %s (%s) VALUES (%s)",
+			table, strings.Join(columns, ", "),
+			strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "),
+		)
+	}
+
+	var _, err = execSQL(l.ctx, compiler, query.String(), values...)
+	return err
+}
+
+func execSQL(ctx context.Context, compiler queries.QueryCompiler, query string, args ...any) (sql.Result, error) {
+	if rebinder, ok := compiler.(queries.RebindCompiler); ok {
+		query = rebinder.Rebind(ctx, query)
+	}
+	return compiler.DB().ExecContext(ctx, query, args...)
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// scanValue scans a fixture value into the field, converting
+// the values which were changed by [dumpValue] back to their original type.
+func scanValue(field attrs.Field, value any) error {
+	var typ = field.Type()
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if s, ok := value.(string); ok && typ != nil {
+		switch {
+		case typ == timeType:
+			var t, err = time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return err
+			}
+			value = t
+		case typ == bytesType:
+			var b, err = base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return err
+			}
+			value = b
+		}
+	}
+
+	return field.Scan(value)
+}
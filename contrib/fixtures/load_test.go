@@ -0,0 +1,195 @@
+package fixtures_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Nigel2392/go-django/contrib/fixtures"
+	"github.com/Nigel2392/go-django/djester/testdb"
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/drivers/dbtype"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	django "github.com/Nigel2392/go-django/src"
+	"github.com/Nigel2392/go-django/src/apps"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+const otherDatabase = "DATABASE_FIXTURES_OTHER"
+
+// Meta is stored as JSON bytes by the database driver.
+type Meta map[string]any
+
+func (m Meta) DBType() dbtype.Type {
+	return dbtype.JSON
+}
+
+func (m Meta) Value() (driver.Value, error) {
+	return json.Marshal(map[string]any(m))
+}
+
+func (m *Meta) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	case []byte:
+		return json.Unmarshal(v, m)
+	}
+	return fmt.Errorf("cannot scan %T into Meta", value)
+}
+
+type Book struct {
+	ID    int64 `attrs:"primary"`
+	Title string
+	Meta  Meta
+}
+
+func (b *Book) FieldDefs(ctx context.Context) attrs.Definitions {
+	return attrs.AutoDefinitions(ctx, b, "ID", "Title", "Meta")
+}
+
+// PaperBook is a proxy model of Book, it is stored in the same table.
+type PaperBook struct {
+	Book
+}
+
+func createBookTable(db drivers.Database) error {
+	var schemaEditor, err = migrator.GetSchemaEditor(db.Driver())
+	if err != nil {
+		return fmt.Errorf("failed to get schema editor: %w", err)
+	}
+
+	return schemaEditor.CreateTable(context.Background(), migrator.NewModelTable(&Book{}), true)
+}
+
+func init() {
+	attrs.RegisterModel(&Book{})
+	attrs.RegisterModel(&PaperBook{})
+
+	var _, db = testdb.Open()
+	var other, err = drivers.Open(context.Background(), "sqlite3", "file:fixtures_other?mode=memory&cache=shared&_loc=auto")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, db := range []drivers.Database{db, other} {
+		if err := createBookTable(db); err != nil {
+			panic(err)
+		}
+	}
+
+	var app = apps.NewAppConfig("fixtures_test")
+	app.ModelObjects = []attrs.Definer{&Book{}, &PaperBook{}}
+
+	var djangoApp = django.App(
+		django.Configure(map[string]interface{}{
+			django.APPVAR_DATABASE: db,
+			otherDatabase:          other,
+		}),
+		django.Apps(app),
+		django.Flag(django.FlagSkipCmds, django.FlagSkipChecks, django.FlagSkipDepsCheck),
+	)
+
+	if err := djangoApp.Initialize(); err != nil {
+		panic(fmt.Errorf("failed to initialize app: %w", err))
+	}
+}
+
+func TestLoadIntoDatabase(t *testing.T) {
+	var objects = []*fixtures.Object{
+		{Model: "fixtures_test.Book", PK: int64(1), Fields: map[string]any{"Title": "Dune", "Meta": `{}`}},
+		{Model: "fixtures_test.Book", PK: int64(2), Fields: map[string]any{"Title": "Hyperion", "Meta": `{}`}},
+	}
+
+	var count, err = fixtures.Load(context.Background(), objects, otherDatabase)
+	if err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected 2 objects to be loaded, got %d", count)
+	}
+
+	loaded, err := queries.Objects(&Book{}, otherDatabase).Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded != 2 {
+		t.Errorf("expected 2 books in %s, got %d", otherDatabase, loaded)
+	}
+
+	book, err := queries.Objects(&Book{}, otherDatabase).Filter("ID", 2).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if book.Object.Title != "Hyperion" {
+		t.Errorf("expected book 2 to be %q, got %q", "Hyperion", book.Object.Title)
+	}
+
+	defaultCount, err := queries.Objects(&Book{}).Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if defaultCount != 0 {
+		t.Errorf("expected no books in the default database, got %d", defaultCount)
+	}
+}
+
+func TestDumpRoundTrip(t *testing.T) {
+	var ctx = context.Background()
+	var objects = []*fixtures.Object{
+		{Model: "fixtures_test.Book", PK: int64(10), Fields: map[string]any{"Title": "Solaris", "Meta": `{"pages":204}`}},
+		{Model: "fixtures_test.PaperBook", PK: int64(11), Fields: map[string]any{"Title": "Ubik", "Meta": `{"pages":202}`}},
+	}
+
+	if _, err := fixtures.Load(ctx, objects); err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+
+	dumped, err := fixtures.Dump(ctx, fixtures.DumpOptions{Models: []string{"fixtures_test"}})
+	if err != nil {
+		t.Fatalf("failed to dump fixtures: %v", err)
+	}
+
+	if len(dumped) != 2 {
+		t.Fatalf("expected proxy models not to be dumped twice, got %d objects", len(dumped))
+	}
+
+	var buf bytes.Buffer
+	if err := fixtures.Encode(&buf, fixtures.FormatJSON, dumped); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	decoded, err := fixtures.Decode(&buf, fixtures.FormatJSON)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	if _, err = queries.Objects(&Book{}).Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = fixtures.Load(ctx, decoded); err != nil {
+		t.Fatalf("failed to load dumped fixtures: %v", err)
+	}
+
+	book, err := queries.Objects(&Book{}).Filter("ID", 11).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pages, _ := book.Object.Meta["pages"].(float64); pages != 202 {
+		t.Errorf("expected the JSON column to round trip, got %v", book.Object.Meta)
+	}
+
+	if _, err = queries.Objects(&Book{}).Delete(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,358 @@
+package fixtures
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/queries/src/fields"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	django "github.com/Nigel2392/go-django/src"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/contenttypes"
+)
+
+// modelEntry holds the information needed to dump and load a single model.
+type modelEntry struct {
+	label   string
+	app     string
+	model   attrs.Definer
+	table   *migrator.ModelTable
+	columns []*migrator.Column
+	primary *migrator.Column
+	deps    []*modelEntry
+}
+
+func (e *modelEntry) column(name string) *migrator.Column {
+	for _, col := range e.columns {
+		if col.Name == name {
+			return col
+		}
+	}
+	return nil
+}
+
+func (e *modelEntry) newObject(ctx context.Context) attrs.Definer {
+	return attrs.NewObject[attrs.Definer](ctx, e.model)
+}
+
+func (e *modelEntry) naturalKeyFields() ([]string, bool) {
+	var nk, ok = e.model.(NaturalKeyDefiner)
+	if !ok {
+		return nil, false
+	}
+	return nk.NaturalKeyFields(), true
+}
+
+// registry contains all models which can be stored in fixtures,
+// sorted so that models come after the models they depend on.
+type registry struct {
+	entries    []*modelEntry
+	byLabel    map[string]*modelEntry
+	byType     map[reflect.Type]*modelEntry
+	byTypeName map[string]*modelEntry
+	skipped    map[string]struct{}
+}
+
+func modelType(model any) reflect.Type {
+	var typ = reflect.TypeOf(model)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// newRegistry collects the models of all installed apps.
+//
+// Through models of many-to-many relations are included even if they
+// are not registered to an app, they are labeled by their content type.
+//
+// Models which cannot be migrated have no table and are skipped.
+//
+// Proxy models share the table of the model they embed, their rows are
+// dumped with that model and their labels resolve to its entry.
+func newRegistry() (*registry, error) {
+	if django.Global == nil || django.Global.Apps == nil {
+		return nil, errors.NotImplemented.Wrap(
+			"fixtures require an initialized django application",
+		)
+	}
+
+	var r = &registry{
+		byLabel:    make(map[string]*modelEntry),
+		byType:     make(map[reflect.Type]*modelEntry),
+		byTypeName: make(map[string]*modelEntry),
+		skipped:    make(map[string]struct{}),
+	}
+
+	type proxyEntry struct {
+		app      string
+		label    string
+		model    attrs.Definer
+		concrete attrs.Definer
+	}
+
+	var unsorted = make([]*modelEntry, 0)
+	var proxies = make([]proxyEntry, 0)
+	var add = func(appName, label string, model attrs.Definer) {
+		var typ = modelType(model)
+		if _, ok := r.byType[typ]; ok {
+			return
+		}
+
+		if !migrator.CheckCanMigrate(model) {
+			r.skipped[label] = struct{}{}
+			return
+		}
+
+		if concrete, ok := proxyOf(model); ok {
+			proxies = append(proxies, proxyEntry{appName, label, model, concrete})
+			return
+		}
+
+		var table = migrator.NewModelTable(model)
+		var entry = &modelEntry{
+			label: label,
+			app:   appName,
+			model: model,
+			table: table,
+		}
+
+		for _, col := range table.Columns() {
+			if !col.UseInDB || col.Generated != nil || !migrator.CheckCanMigrate(col.Field) {
+				continue
+			}
+			if col.Primary && entry.primary == nil {
+				entry.primary = col
+			}
+			entry.columns = append(entry.columns, col)
+		}
+
+		r.byType[typ] = entry
+		r.byLabel[label] = entry
+		r.byTypeName[contenttypes.NewContentType[any](model).TypeName()] = entry
+		unsorted = append(unsorted, entry)
+	}
+
+	for head := django.Global.Apps.Front(); head != nil; head = head.Next() {
+		for _, model := range head.Value.Models() {
+			add(head.Key, head.Key+"."+modelType(model).Name(), model)
+		}
+	}
+
+	for _, proxy := range proxies {
+		var cType = contenttypes.NewContentType[any](proxy.concrete)
+		add(proxy.app, cType.ShortTypeName(), proxy.concrete)
+
+		var entry, ok = r.byType[modelType(proxy.concrete)]
+		if !ok {
+			r.skipped[proxy.label] = struct{}{}
+			continue
+		}
+
+		r.byType[modelType(proxy.model)] = entry
+		r.byLabel[proxy.label] = entry
+		r.byTypeName[contenttypes.NewContentType[any](proxy.model).TypeName()] = entry
+	}
+
+	for _, entry := range slices.Clone(unsorted) {
+		var meta = attrs.GetModelMeta(entry.model)
+		if meta == nil {
+			continue
+		}
+		for head := meta.ForwardMap().Front(); head != nil; head = head.Next() {
+			var through = head.Value.Through()
+			if through == nil || through.Model() == nil {
+				continue
+			}
+			var cType = contenttypes.NewContentType[any](through.Model())
+			add(entry.app, cType.ShortTypeName(), through.Model())
+		}
+	}
+
+	for _, entry := range unsorted {
+		for _, col := range entry.columns {
+			if !isForeignKey(col) {
+				continue
+			}
+			if dep, ok := r.byType[modelType(col.Rel.Model())]; ok && dep != entry {
+				entry.deps = append(entry.deps, dep)
+			}
+		}
+	}
+
+	r.entries = sortEntries(unsorted)
+	return r, nil
+}
+
+// proxyOf returns the model embedded by value in a proxy model.
+//
+// A model is a proxy model if it embeds another registered model which is stored in the same table.
+// Parent models which are embedded through a proxy field (see [queries.ProxyFields]) are stored
+// in their own table, the embedding model is a concrete model.
+func proxyOf(model attrs.Definer) (attrs.Definer, bool) {
+	if !attrs.IsModelRegistered(model) {
+		return nil, false
+	}
+
+	var (
+		typ     = modelType(model)
+		table   = attrs.GetModelMeta(model).Definitions().TableName()
+		proxies = queries.ProxyFields(model)
+	)
+
+	for i := 0; i < typ.NumField(); i++ {
+		var field = typ.Field(i)
+		if !field.Anonymous || field.Type.Kind() != reflect.Struct || proxies.IsProxyField(field.Name) {
+			continue
+		}
+
+		var concrete, ok = reflect.New(field.Type).Interface().(attrs.Definer)
+		if !ok || !attrs.IsModelRegistered(concrete) {
+			continue
+		}
+
+		if attrs.GetModelMeta(concrete).Definitions().TableName() == table {
+			return concrete, true
+		}
+	}
+
+	return nil, false
+}
+
+// sortEntries sorts the entries so that dependencies come first.
+//
+// Cyclic dependencies are allowed, constraint checks are deferred when loading.
+func sortEntries(entries []*modelEntry) []*modelEntry {
+	var (
+		sorted   = make([]*modelEntry, 0, len(entries))
+		visited  = make(map[*modelEntry]bool, len(entries))
+		visiting = make(map[*modelEntry]bool)
+		visit    func(e *modelEntry)
+	)
+
+	visit = func(e *modelEntry) {
+		if visited[e] || visiting[e] {
+			return
+		}
+		visiting[e] = true
+		for _, dep := range e.deps {
+			visit(dep)
+		}
+		visiting[e] = false
+		visited[e] = true
+		sorted = append(sorted, e)
+	}
+
+	for _, e := range entries {
+		visit(e)
+	}
+
+	return sorted
+}
+
+// lookup returns the entry for a model label, app labels are case sensitive,
+// model names are not.
+func (r *registry) lookup(label string) (*modelEntry, error) {
+	if entry, ok := r.byLabel[label]; ok {
+		return entry, nil
+	}
+
+	for _, entry := range r.entries {
+		if strings.EqualFold(entry.label, label) {
+			return entry, nil
+		}
+	}
+
+	return nil, errors.InvalidContentType.Wrapf(
+		"model %q is not registered to an installed app", label,
+	)
+}
+
+// isSkipped reports if the label belongs to a model which cannot be migrated.
+func (r *registry) isSkipped(label string) bool {
+	var _, ok = r.skipped[label]
+	return ok
+}
+
+// selectEntries returns the entries matching the labels, in dependency order.
+//
+// A label is either an app name, I.E. "auth" or a model label, I.E. "auth.User".
+func (r *registry) selectEntries(include, exclude []string) ([]*modelEntry, error) {
+	var matches = func(entry *modelEntry, label string) bool {
+		return label == entry.app || strings.EqualFold(label, entry.label) || r.byLabel[label] == entry
+	}
+
+	for _, label := range slices.Concat(include, exclude) {
+		var found = slices.ContainsFunc(r.entries, func(entry *modelEntry) bool {
+			return matches(entry, label)
+		})
+		if !found && !r.isSkipped(label) {
+			return nil, errors.InvalidContentType.Wrapf(
+				"unknown app or model %q", label,
+			)
+		}
+	}
+
+	var selected = make([]*modelEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		var included = len(include) == 0 || slices.ContainsFunc(include, func(label string) bool {
+			return matches(entry, label)
+		})
+		var excluded = slices.ContainsFunc(exclude, func(label string) bool {
+			return matches(entry, label)
+		})
+		if included && !excluded {
+			selected = append(selected, entry)
+		}
+	}
+
+	return selected, nil
+}
+
+// isForeignKey reports if the column stores the primary key of another model.
+func isForeignKey(col *migrator.Column) bool {
+	if col.Rel == nil || col.Rel.Model() == nil || col.Rel.Through != nil {
+		return false
+	}
+	switch col.Rel.Type {
+	case attrs.RelManyToOne, attrs.RelOneToOne:
+		return true
+	}
+	return false
+}
+
+// genericRelation is a generic foreign key, stored as a content type and a primary key column.
+type genericRelation struct {
+	contentType attrs.Field
+	primary     attrs.Field
+}
+
+// genericRelations returns the generic foreign keys of the object.
+//
+// The object itself can define the columns by implementing [fields.GenericForeignKeyFieldDefiner].
+func genericRelations(defs attrs.Definitions) []genericRelation {
+	var relations = make([]genericRelation, 0)
+	if definer, ok := defs.Instance().(fields.GenericForeignKeyFieldDefiner); ok {
+		if ct, pk := definer.ContentTypeField(), definer.PrimaryField(); ct != nil && pk != nil {
+			relations = append(relations, genericRelation{ct, pk})
+		}
+	}
+
+	for _, field := range defs.Fields() {
+		var gfk, ok = field.(fields.GenericForeignKeyFieldDefiner)
+		if !ok {
+			continue
+		}
+		var ct, pk = gfk.ContentTypeField(), gfk.PrimaryField()
+		if ct == nil || pk == nil {
+			continue
+		}
+		relations = append(relations, genericRelation{ct, pk})
+	}
+
+	return relations
+}
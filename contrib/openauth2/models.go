@@ -10,6 +10,8 @@ import (
 	"github.com/Nigel2392/go-django/contrib/auth/users"
 	queries "github.com/Nigel2392/go-django/queries/src"
 	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/drivers/dbtype"
+	"github.com/Nigel2392/go-django/queries/src/fields"
 	"github.com/Nigel2392/go-django/queries/src/models"
 	"github.com/Nigel2392/go-django/src/core"
 	"github.com/Nigel2392/go-django/src/core/attrs"
@@ -190,6 +192,24 @@ func (o *User) UniqueTogether() [][]string {
 	}
 }
 
+// tokenEncryption is used to encrypt the access and refresh tokens, see [Config.EncryptTokens].
+//
+// The ciphertext is stored base64 encoded in the existing TEXT columns.
+// Tokens which were stored before encryption are read as plaintext,
+// and are encrypted the next time the user is saved.
+var tokenEncryption = &fields.EncryptedFieldConfig{
+	DBType:            dbtype.Text,
+	PlaintextFallback: true,
+}
+
+// tokenField wraps the field in an [fields.EncryptedField] if [Config.EncryptTokens] is enabled.
+func tokenField(field attrs.Field) attrs.Field {
+	if App == nil || App.Config == nil || !App.Config.EncryptTokens {
+		return field
+	}
+	return fields.Encrypted(field, tokenEncryption)
+}
+
 func (o *User) FieldDefs(ctx context.Context) attrs.Definitions {
 	return o.Model.Define(ctx, o,
 		o.Fields,
@@ -238,7 +258,7 @@ func (o *User) Fields(this attrs.Definer) []any {
 				Column:   "data",
 			},
 		),
-		attrs.NewField(
+		tokenField(attrs.NewField(
 			o, "AccessToken", &attrs.FieldConfig{
 				Null:     false,
 				Blank:    true,
@@ -246,8 +266,8 @@ func (o *User) Fields(this attrs.Definer) []any {
 				Label:    trans.S("Access Token"),
 				Column:   "access_token",
 			},
-		),
-		attrs.NewField(
+		)),
+		tokenField(attrs.NewField(
 			o, "RefreshToken", &attrs.FieldConfig{
 				Null:     false,
 				Blank:    true,
@@ -255,7 +275,7 @@ func (o *User) Fields(this attrs.Definer) []any {
 				Label:    trans.S("Refresh Token"),
 				Column:   "refresh_token",
 			},
-		),
+		)),
 		attrs.NewField(
 			o, "TokenType", &attrs.FieldConfig{
 				Null:      false,
@@ -28,6 +28,7 @@ import (
 	"github.com/Nigel2392/go-django/src/core/filesystem"
 	"github.com/Nigel2392/go-django/src/core/filesystem/staticfiles"
 	"github.com/Nigel2392/go-django/src/core/filesystem/tpl"
+	"github.com/Nigel2392/go-django/src/core/ratelimit"
 	"github.com/Nigel2392/go-django/src/core/trans"
 	"github.com/Nigel2392/go-django/src/forms"
 	"github.com/Nigel2392/go-django/src/forms/widgets"
@@ -65,6 +66,17 @@ type Config struct {
 	// If the user's state should be inactive by default.
 	UserDefaultIsDisabled bool
 
+	// EncryptTokens encrypts the access and refresh tokens before they are stored in the database.
+	//
+	// The tokens are encrypted with the application's encryption backend, see secrets.ENCRYPTION_BACKEND.
+	// The default backend requires APPVAR_SECRET_KEY to be a valid AES key (16, 24 or 32 bytes),
+	// a custom backend can be set with APPVAR_ENCRYPTION_BACKEND.
+	//
+	// Tokens which were stored before encryption was enabled are read as plaintext,
+	// they are encrypted the next time the user is saved.
+	// Encryption can not simply be disabled again once tokens have been encrypted.
+	EncryptTokens bool
+
 	// A function to generate the default URL after the user has logged in.
 	//
 	// Note:
@@ -481,7 +493,7 @@ func NewAppConfig(cnf Config) django.AppConfig {
 		base.Any("/login", mux.NewHandler(App.LoginHandler), "login")
 
 		var rt = base.Any("/<<provider>>", App.handler(App.AuthHandler), "provider")
-		rt.Any("/callback", App.handler(App.CallbackHandler), "callback")
+		rt.Any("/callback", ratelimit.ProtectLogin(App.handler(App.CallbackHandler)), "callback")
 	}
 
 	contenttypes.Register(&contenttypes.ContentTypeDefinition{
@@ -16,6 +16,7 @@ import (
 	django "github.com/Nigel2392/go-django/src"
 	"github.com/Nigel2392/go-django/src/core/attrs"
 	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-django/src/core/secrets"
 	"golang.org/x/oauth2"
 )
 
@@ -27,6 +28,7 @@ type User struct {
 
 func init() {
 	var openauth = openauth2.NewAppConfig(openauth2.Config{
+		EncryptTokens: true,
 		AuthConfigurations: []openauth2.AuthConfig{
 			{
 				ProviderInfo: openauth2.ConfigInfo{
@@ -56,7 +58,8 @@ func init() {
 	var _, db = testdb.Open()
 	var app = django.App(
 		django.Configure(map[string]interface{}{
-			django.APPVAR_DATABASE: db,
+			django.APPVAR_DATABASE:    db,
+			secrets.APPVAR_SECRET_KEY: "openauth2-test-secret-key-32byte",
 		}),
 		django.Apps(
 			openauth,
@@ -9,6 +9,7 @@ import (
 	queries "github.com/Nigel2392/go-django/queries/src"
 	"github.com/Nigel2392/go-django/queries/src/drivers"
 	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/src/core"
 	"github.com/Nigel2392/go-django/src/core/except"
 	"github.com/Nigel2392/go-django/src/core/logger"
 	"github.com/Nigel2392/go-django/src/views"
@@ -96,19 +97,36 @@ func (oa *OpenAuth2AppConfig) AuthHandler(w http.ResponseWriter, r *http.Request
 	http.Redirect(w, r, oauthURL, http.StatusFound)
 }
 
+// loginFailed sends [core.SIGNAL_LOGIN_FAILED] for a callback which could not be authenticated,
+// so the failed attempt counts towards the login lockout.
+func loginFailed(r *http.Request) {
+	if err := core.SIGNAL_LOGIN_FAILED.Send(r.Context(), r); err != nil {
+		logger.Warnf("Failed to send the login failed signal: %v", err)
+	}
+}
+
 func (oa *OpenAuth2AppConfig) CallbackHandler(w http.ResponseWriter, r *http.Request, a *AuthConfig) {
 	var code = r.URL.Query().Get("code")
-	except.Assert(
-		code != "", http.StatusBadRequest,
-		"Missing code in URL",
-	)
+	if code == "" {
+		loginFailed(r)
+		except.Fail(
+			http.StatusBadRequest,
+			"Missing code in URL",
+		)
+		return
+	}
 
 	// Exchange the access code for a token
 	token, err := a.Oauth2.Exchange(r.Context(), code)
-	except.AssertNil(
-		err, http.StatusInternalServerError,
-		"Failed to exchange code for authentication token",
-	)
+	if err != nil {
+		logger.Warnf("Failed to exchange code for authentication token: %v", err)
+		loginFailed(r)
+		except.Fail(
+			http.StatusInternalServerError,
+			"Failed to exchange code for authentication token",
+		)
+		return
+	}
 
 	if a.DataConfig.DetailsURL == "" {
 		logger.Errorf("DataConfig.DetailsURL was not provided, incomplete Oauth2 flow")
@@ -134,12 +134,17 @@ func NewAppConfig() django.AppConfig {
 		&PageNode{},
 	}
 
+	// rewrite the stored type names when a persisted content type is renamed
+	contenttypes.RegisterReference(&PageNode{}, "ContentType")
+
 	pageApp.Cmd = []command.Command{
 		commandFixTree,
 	}
 
 	pageApp.Init = func(settings django.Settings, db drivers.Database) error {
 
+		registerCacheSignals()
+
 		if !django.AppInstalled("migrator") {
 			var schemaEditor, err = migrator.GetSchemaEditor(db.Driver())
 			if err != nil {
@@ -0,0 +1,73 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-django/src/views"
+	"github.com/Nigel2392/go-signals"
+)
+
+// PagesCacheTag is the cache tag added to the cached responses of all served pages.
+//
+// Invalidating it with [views.InvalidateCacheTags] purges the cached responses of every page.
+const PagesCacheTag = "pages"
+
+// PageCacheTag returns the cache tag added to the cached responses of the page with the given ID.
+func PageCacheTag(pageID int64) string {
+	return fmt.Sprintf("pages.page.%d", pageID)
+}
+
+// PurgePageCache purges the cached responses of the page with the given ID.
+//
+// Only responses cached by [views.CacheMiddleware] or [views.CacheView] are purged.
+func PurgePageCache(ctx context.Context, pageID int64) error {
+	return views.InvalidateCacheTags(ctx, PageCacheTag(pageID))
+}
+
+// PurgePagesCache purges the cached responses of all pages.
+func PurgePagesCache(ctx context.Context) error {
+	return views.InvalidateCacheTags(ctx, PagesCacheTag)
+}
+
+// addPageCacheTags tags the response of the page so it can be purged when the page changes.
+func addPageCacheTags(r *http.Request, page *PageNode) {
+	views.AddCacheTags(r, PagesCacheTag, PageCacheTag(page.PK))
+}
+
+// purgeCache calls the purge function once the transaction in the context is committed, see [queries.OnCommit],
+// a response rendered before the commit would otherwise be cached with the old content.
+//
+// The error is logged if the cache could not be purged, a stale cache should never
+// make the operation which triggered the purge fail.
+func purgeCache(ctx context.Context, purge func(ctx context.Context) error) {
+	queries.OnCommit(ctx, func(ctx context.Context) {
+		if err := purge(ctx); err != nil {
+			logger.Ctx(ctx).Warnf("Failed to purge the page cache: %v", err)
+		}
+	})
+}
+
+var registerCacheSignals = sync.OnceFunc(func() {
+	SignalNodeUpdated.Listen(context.Background(), func(ctx context.Context, s signals.Signal[*PageNodeSignal], ps *PageNodeSignal) error {
+		purgeCache(ps.Ctx, func(ctx context.Context) error {
+			return PurgePageCache(ctx, ps.Node.PK)
+		})
+		return nil
+	})
+
+	// Moving or deleting a node changes the URLs and the menus of other pages too.
+	SignalNodeBeforeDelete.Listen(context.Background(), func(ctx context.Context, s signals.Signal[*PageNodeSignal], ps *PageNodeSignal) error {
+		purgeCache(ps.Ctx, PurgePagesCache)
+		return nil
+	})
+
+	SignalNodeMoved.Listen(context.Background(), func(ctx context.Context, s signals.Signal[*PageMovedSignal], ps *PageMovedSignal) error {
+		purgeCache(ps.Ctx, PurgePagesCache)
+		return nil
+	})
+})
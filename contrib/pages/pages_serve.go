@@ -152,6 +152,8 @@ func PageServeView(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	addPageCacheTags(req, page)
+
 	specific, err := Specific(r.Context(), page, true)
 	if err != nil {
 		pageNotFound(w, req, err, pathParts)
@@ -973,7 +973,13 @@ func (qs *PageQuerySet) PublishNode(node *PageNode) error {
 	}
 
 	node.StatusFlags |= StatusFlagPublished
-	return qs.updateNodeStatusFlags(int64(StatusFlagPublished), node.PK)
+	if err := qs.updateNodeStatusFlags(int64(StatusFlagPublished), node.PK); err != nil {
+		return err
+	}
+
+	// the menus and listings of other pages change as well
+	purgeCache(qs.Context(), PurgePagesCache)
+	return nil
 }
 
 // UnpublishNode will unset the published flag on the node
@@ -1029,7 +1035,13 @@ func (qs *PageQuerySet) UnpublishNode(node *PageNode, unpublishChildren bool) er
 		return errors.NoChanges.Wrapf("failed to unpublish node with PK %d", node.PK)
 	}
 
-	return transaction.Commit(qs.Context())
+	if err = transaction.Commit(qs.Context()); err != nil {
+		return err
+	}
+
+	// the menus and listings of other pages change as well
+	purgeCache(qs.Context(), PurgePagesCache)
+	return nil
 }
 
 // ParentNode returns the parent node of the given node.
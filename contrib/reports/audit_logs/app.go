@@ -11,6 +11,8 @@ import (
 	"reflect"
 	"slices"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/Nigel2392/go-django/contrib/admin"
 	"github.com/Nigel2392/go-django/contrib/admin/components"
@@ -63,6 +65,42 @@ var templateFileSys embed.FS
 //go:embed migrations/*
 var migrationFileSys embed.FS
 
+// registerM2MSignals logs the changes to the groups and permissions of users.
+var registerM2MSignals = sync.OnceFunc(func() {
+	queries.SignalPostM2MChange.Listen(context.Background(), func(ctx context.Context, s signals.Signal[queries.M2MSignal], ms queries.M2MSignal) error {
+		if !Logs.IsReady() {
+			return nil
+		}
+
+		var user, ok = ms.Source.(users.User)
+		if !ok {
+			return nil
+		}
+
+		var entryType string
+		switch ms.Through.(type) {
+		case *users.UserGroup:
+			entryType = "auth.user.groups_changed"
+		case *users.UserPermission:
+			entryType = "auth.user.permissions_changed"
+		default:
+			return nil
+		}
+
+		var logData = map[string]interface{}{
+			"action": string(ms.Action),
+			"object": attrs.ToString(user),
+			"pks":    ms.PKs,
+		}
+
+		if _, err := Log(ms.Context, entryType, logger.INF, user, logData); err != nil {
+			logger.Warn(err)
+		}
+
+		return nil
+	})
+})
+
 func NewAppConfig() django.AppConfig {
 	Logs.Deps = []string{
 		"reports",
@@ -72,6 +110,9 @@ func NewAppConfig() django.AppConfig {
 		&Entry{},
 	}
 
+	// rewrite the stored type names when a persisted content type is renamed
+	contenttypes.RegisterReference(&Entry{}, "ContentType")
+
 	Logs.Init = func(settings django.Settings, db drivers.Database) error {
 
 		if !django.AppInstalled("migrator") {
@@ -208,13 +249,66 @@ func NewAppConfig() django.AppConfig {
 		return nil
 	})
 
+	core.SIGNAL_CSP_VIOLATION.Listen(context.Background(), func(ctx context.Context, s signals.Signal[*core.CSPViolation], v *core.CSPViolation) error {
+		if !Logs.IsReady() {
+			return nil
+		}
+
+		var logData = map[string]interface{}{
+			"document_url": v.DocumentURL,
+			"blocked_url":  v.BlockedURL,
+			"directive":    v.Directive,
+			"disposition":  v.Disposition,
+			"source_file":  v.SourceFile,
+			"line_number":  v.LineNumber,
+			"ip":           django.GetIP(v.R),
+			"user_agent":   v.R.UserAgent(),
+		}
+
+		if _, err := Log(v.R.Context(), "security.csp_violation", logger.WRN, nil, logData); err != nil {
+			logger.Warn(err)
+		}
+
+		return nil
+	})
+
+	core.SIGNAL_LOCKED_OUT.Listen(context.Background(), func(ctx context.Context, s signals.Signal[*core.Lockout], l *core.Lockout) error {
+		if !Logs.IsReady() {
+			return nil
+		}
+
+		var logData = map[string]interface{}{
+			"name":     l.Name,
+			"key":      l.Key,
+			"failures": l.Failures,
+			"until":    l.Until.Format(time.RFC3339),
+			"ip":       django.GetIP(l.R),
+		}
+
+		if _, err := Log(l.R.Context(), "auth.locked_out", logger.WRN, nil, logData); err != nil {
+			logger.Warn(err)
+		}
+
+		return nil
+	})
+
 	goldcrest.Register(admin.AdminModelHookDelete, 0, admin.AdminModelDeleteFunc(func(r *http.Request, adminSite *admin.AdminApplication, model *admin.ModelDefinition, instances []attrs.Definer) {
 		for _, instance := range instances {
 			hookFunc(admin.AdminModelHookDelete, r, adminSite, model, instance)
 		}
 	}))
 
+	registerM2MSignals()
+
 	RegisterDefinition("auth.login_failed", &loginFailedDefinition{})
+	RegisterDefinition("security.csp_violation", &cspViolationDefinition{})
+	RegisterDefinition("auth.locked_out", &lockoutDefinition{})
+	RegisterDefinition("auth.user.groups_changed", &userRelationsDefinition{
+		label: func(ctx context.Context) string { return trans.T(ctx, "Groups") },
+	})
+	RegisterDefinition("auth.user.permissions_changed", &userRelationsDefinition{
+		label: func(ctx context.Context) string { return trans.T(ctx, "Permissions") },
+	})
 
 	if admin.AdminSite.TemplateConfig != nil {
 		Logs.TemplateConfig = &tpl.Config{
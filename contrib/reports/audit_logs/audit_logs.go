@@ -66,7 +66,8 @@ func Log(ctx context.Context, entryType string, level logger.LogLevel, forObject
 		}
 	}
 
-	var user = authentication.UserFromContext(ctx).(users.User)
+	// changes can be logged outside of a request, I.E. from commands
+	var user, _ = authentication.UserFromContext(ctx).(users.User)
 	var baseEntry = models.Setup(ctx, &Entry{
 		Typ:  drivers.String(entryType),
 		Lvl:  level,
@@ -1,6 +1,7 @@
 package auditlogs
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"maps"
@@ -15,6 +16,9 @@ import (
 
 var (
 	_ Definition = (*loginFailedDefinition)(nil)
+	_ Definition = (*userRelationsDefinition)(nil)
+	_ Definition = (*cspViolationDefinition)(nil)
+	_ Definition = (*lockoutDefinition)(nil)
 )
 
 type loginFailedDefinition struct{}
@@ -88,3 +92,113 @@ func (p *loginFailedDefinition) FormatMessage(r *http.Request, logEntry LogEntry
 
 	return template.HTML(sb.String())
 }
+
+type userRelationsDefinition struct {
+	label func(ctx context.Context) string
+}
+
+func (p *userRelationsDefinition) TypeLabel(r *http.Request, typeName string) string {
+	return p.label(r.Context())
+}
+
+func (p *userRelationsDefinition) GetLabel(r *http.Request, logEntry LogEntry) string {
+	var data = logEntry.Data()
+	return trans.T(r.Context(), "%s of %v changed (%v)", p.label(r.Context()), data["object"], data["action"])
+}
+
+func (p *userRelationsDefinition) GetActions(r *http.Request, l LogEntry) []LogEntryAction {
+	return nil
+}
+
+func (p *userRelationsDefinition) FormatMessage(r *http.Request, logEntry LogEntry) any {
+	var data = logEntry.Data()
+	var sb = new(strings.Builder)
+	sb.WriteString("<p>")
+	sb.WriteString(template.HTMLEscapeString(trans.T(r.Context(), "Action: %v", data["action"])))
+	sb.WriteString("</p>\n")
+
+	var pks, _ = data["pks"].([]interface{})
+	if len(pks) == 0 {
+		return template.HTML(sb.String())
+	}
+
+	sb.WriteString("<p>")
+	sb.WriteString(template.HTMLEscapeString(trans.T(r.Context(), "Affected IDs: %v", pks)))
+	sb.WriteString("</p>\n")
+
+	return template.HTML(sb.String())
+}
+
+type cspViolationDefinition struct{}
+
+func (p *cspViolationDefinition) TypeLabel(r *http.Request, typeName string) string {
+	return trans.T(r.Context(), "Content Security Policy violation")
+}
+
+func (p *cspViolationDefinition) GetLabel(r *http.Request, logEntry LogEntry) string {
+	var data = logEntry.Data()
+	return trans.T(r.Context(), "%v blocked by %v on %v", data["blocked_url"], data["directive"], data["document_url"])
+}
+
+func (p *cspViolationDefinition) GetActions(r *http.Request, l LogEntry) []LogEntryAction {
+	return nil
+}
+
+func (p *cspViolationDefinition) FormatMessage(r *http.Request, logEntry LogEntry) any {
+	var data = logEntry.Data()
+	var sb = new(strings.Builder)
+	var lines = []string{
+		trans.T(r.Context(), "Page: %v", data["document_url"]),
+		trans.T(r.Context(), "Blocked: %v", data["blocked_url"]),
+		trans.T(r.Context(), "Directive: %v", data["directive"]),
+		trans.T(r.Context(), "Disposition: %v", data["disposition"]),
+	}
+
+	if source, _ := data["source_file"].(string); source != "" {
+		lines = append(lines, trans.T(r.Context(), "Source: %s:%v", source, data["line_number"]))
+	}
+
+	lines = append(lines, trans.T(r.Context(), "IP Address: %v", data["ip"]))
+
+	for _, line := range lines {
+		sb.WriteString("<p>")
+		sb.WriteString(template.HTMLEscapeString(line))
+		sb.WriteString("</p>\n")
+	}
+
+	return template.HTML(sb.String())
+}
+
+type lockoutDefinition struct{}
+
+func (p *lockoutDefinition) TypeLabel(r *http.Request, typeName string) string {
+	return trans.T(r.Context(), "Locked out")
+}
+
+func (p *lockoutDefinition) GetLabel(r *http.Request, logEntry LogEntry) string {
+	var data = logEntry.Data()
+	return trans.T(r.Context(), "%v locked out after %v failed attempts", data["key"], data["failures"])
+}
+
+func (p *lockoutDefinition) GetActions(r *http.Request, l LogEntry) []LogEntryAction {
+	return nil
+}
+
+func (p *lockoutDefinition) FormatMessage(r *http.Request, logEntry LogEntry) any {
+	var data = logEntry.Data()
+	var sb = new(strings.Builder)
+	var lines = []string{
+		trans.T(r.Context(), "Policy: %v", data["name"]),
+		trans.T(r.Context(), "Failed attempts: %v", data["failures"]),
+		trans.T(r.Context(), "Locked out until: %v", data["until"]),
+		trans.T(r.Context(), "IP Address: %v", data["ip"]),
+	}
+
+	for _, line := range lines {
+		sb.WriteString("<p>")
+		sb.WriteString(template.HTMLEscapeString(line))
+		sb.WriteString("</p>\n")
+	}
+
+	return template.HTML(sb.String())
+}
@@ -11,6 +11,7 @@ import (
 	django "github.com/Nigel2392/go-django/src"
 	"github.com/Nigel2392/go-django/src/apps"
 	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/contenttypes"
 	"github.com/Nigel2392/go-django/src/core/filesystem"
 )
 
@@ -41,6 +42,9 @@ func NewAppConfig() django.AppConfig {
 		&Revision{},
 	}
 
+	// rewrite the stored type names when a persisted content type is renamed
+	contenttypes.RegisterReference(&Revision{}, "ContentType")
+
 	app.Init = func(settings django.Settings, db drivers.Database) error {
 
 		if !django.AppInstalled("migrator") {
@@ -13,7 +13,9 @@ import (
 	"github.com/Nigel2392/go-django/src/core/assert"
 	"github.com/Nigel2392/go-django/src/core/attrs"
 	"github.com/Nigel2392/go-django/src/core/filesystem"
+	"github.com/Nigel2392/go-django/src/core/health"
 	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/goldcrest"
 	"github.com/Nigel2392/mux"
 	"github.com/Nigel2392/mux/middleware/sessions"
 	"github.com/alexedwards/scs/v2"
@@ -96,6 +98,29 @@ func NewAppConfig() django.AppConfig {
 		return nil
 	}
 
+	// stop the cleanup goroutine of the session store when the server shuts down
+	goldcrest.Register(django.HOOK_SERVER_SHUTDOWN, 0, django.DjangoHook(func(a *django.Application) error {
+		if store, ok := sessionManager.Store.(interface{ StopCleanup() }); ok {
+			store.StopCleanup()
+		}
+		return nil
+	}))
+
+	// the session store must be reachable for the application to be ready
+	goldcrest.Register(django.HOOK_HEALTH_PROBES, 0, django.HealthProbesHook(func(a *django.Application) []health.Probe {
+		return []health.Probe{{
+			Name: "session.store",
+			Check: func(ctx context.Context) (err error) {
+				if store, ok := sessionManager.Store.(scs.CtxStore); ok {
+					_, _, err = store.FindCtx(ctx, "healthcheck")
+				} else {
+					_, _, err = sessionManager.Store.Find("healthcheck")
+				}
+				return err
+			},
+		}}
+	}))
+
 	app.Routing = func(m mux.Multiplexer) {
 		m.Use(
 			django.NonStaticMiddleware(
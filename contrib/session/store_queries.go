@@ -12,6 +12,7 @@ import (
 	"github.com/Nigel2392/go-django/queries/src/migrator"
 	"github.com/Nigel2392/go-django/src/core/attrs"
 	"github.com/Nigel2392/go-django/src/core/attrs/fattrs"
+	"github.com/Nigel2392/go-django/src/core/metrics"
 	"github.com/alexedwards/scs/v2"
 )
 
@@ -77,8 +78,23 @@ func (s *Session) DatabaseIndexes(obj attrs.Definer) []migrator.Index {
 
 var (
 	cleanupMu = &sync.Mutex{}
+
+	storeOperationsTotal = metrics.Default.Counter(metrics.Opts{
+		Name:   "django_session_store_operations_total",
+		Help:   "The number of operations on the session QueryStore, by operation and status.",
+		Labels: []string{"operation", "status"},
+	})
 )
 
+// recordOperation records the operation on the store in the session store metrics.
+func recordOperation(operation string, err error) {
+	var status = "ok"
+	if err != nil {
+		status = "error"
+	}
+	storeOperationsTotal.Inc(operation, status)
+}
+
 type QueryStore struct {
 	db          drivers.Database
 	stopCleanup chan bool
@@ -121,8 +137,10 @@ func (p *QueryStore) All() (map[string][]byte, error) {
 // Find returns the data for a given session token from the QueryStore instance.
 // If the session token is not found or is expired, the returned exists flag will
 // be set to false.
-func (p *QueryStore) FindCtx(ctx context.Context, token string) ([]byte, bool, error) {
-	var row, err = queries.GetQuerySet(&Session{}).
+func (p *QueryStore) FindCtx(ctx context.Context, token string) (_ []byte, _ bool, err error) {
+	defer func() { recordOperation("find", err) }()
+
+	row, err := queries.GetQuerySet(&Session{}).
 		WithContext(ctx).
 		Filter("Token", token).
 		Filter("Expiry__gt", time.Now().UTC().UnixNano()).
@@ -138,7 +156,8 @@ func (p *QueryStore) FindCtx(ctx context.Context, token string) ([]byte, bool, e
 // Commit adds a session token and data to the QueryStore instance with the
 // given expiry time. If the session token already exists, then the data and expiry
 // time are updated.
-func (p *QueryStore) CommitCtx(ctx context.Context, token string, b []byte, expiry time.Time) error {
+func (p *QueryStore) CommitCtx(ctx context.Context, token string, b []byte, expiry time.Time) (err error) {
+	defer func() { recordOperation("commit", err) }()
 
 	var session = &Session{
 		Token:  drivers.Char(token),
@@ -150,7 +169,7 @@ func (p *QueryStore) CommitCtx(ctx context.Context, token string, b []byte, expi
 		WithContext(ctx).
 		Filter("Token", token)
 
-	var transaction, err = querySet.GetOrCreateTransaction()
+	transaction, err := querySet.GetOrCreateTransaction()
 	if err != nil {
 		return err
 	}
@@ -180,15 +199,19 @@ func (p *QueryStore) CommitCtx(ctx context.Context, token string, b []byte, expi
 
 // Delete removes a session token and corresponding data from the QueryStore
 // instance.
-func (p *QueryStore) DeleteCtx(ctx context.Context, token string) error {
-	var _, err = queries.GetQuerySet(&Session{}).WithContext(ctx).Filter("Token", token).Delete()
+func (p *QueryStore) DeleteCtx(ctx context.Context, token string) (err error) {
+	defer func() { recordOperation("delete", err) }()
+
+	_, err = queries.GetQuerySet(&Session{}).WithContext(ctx).Filter("Token", token).Delete()
 	return err
 }
 
 // All returns a map containing the token and data for all active (i.e.
 // not expired) sessions in the QueryStore instance.
-func (p *QueryStore) AllCtx(ctx context.Context) (map[string][]byte, error) {
-	var rows, err = queries.GetQuerySet(&Session{}).
+func (p *QueryStore) AllCtx(ctx context.Context) (_ map[string][]byte, err error) {
+	defer func() { recordOperation("all", err) }()
+
+	rows, err := queries.GetQuerySet(&Session{}).
 		WithContext(ctx).
 		Select("Token", "Data").
 		Filter("Expiry__gt", time.Now().UTC().UnixNano()).
@@ -0,0 +1,63 @@
+package tenants
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	django "github.com/Nigel2392/go-django/src"
+	"github.com/Nigel2392/go-django/src/apps"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/command"
+	"github.com/Nigel2392/go-django/src/core/filesystem"
+	"github.com/Nigel2392/mux"
+)
+
+//go:embed migrations/*
+var migrationFS embed.FS
+
+// NewAppConfig returns the tenants app.
+//
+// The app resolves the tenant for each request by it's domain and
+// adds the `migrate_tenants` command to migrate the schema of each tenant.
+//
+// See [APPVAR_TENANTS_MODE] for the available tenancy modes.
+func NewAppConfig() django.AppConfig {
+	var app = apps.NewDBAppConfig("tenants")
+
+	app.ModelObjects = []attrs.Definer{
+		&Tenant{},
+	}
+
+	app.Cmd = []command.Command{
+		commandMigrateTenants,
+	}
+
+	app.Init = func(settings django.Settings, db drivers.Database) error {
+		var mode = django.ConfigGet(settings, APPVAR_TENANTS_MODE, ModeRow)
+		switch mode {
+		case ModeRow:
+		case ModeSchema:
+			if !drivers.SupportsSchemas(db) {
+				return fmt.Errorf(
+					"tenants: schema mode is not supported for database driver %T", db.Driver(),
+				)
+			}
+		default:
+			return fmt.Errorf("tenants: unknown tenancy mode %q", mode)
+		}
+		return nil
+	}
+
+	app.Routing = func(m mux.Multiplexer) {
+		m.Use(TenantMiddleware())
+	}
+
+	return &migrator.MigratorAppConfig{
+		AppConfig: app,
+		MigrationFS: filesystem.Sub(
+			migrationFS, "migrations/tenants",
+		),
+	}
+}
@@ -0,0 +1,61 @@
+package tenants
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	django "github.com/Nigel2392/go-django/src"
+	"github.com/Nigel2392/go-django/src/core/command"
+	"github.com/Nigel2392/go-django/src/core/command/flags"
+)
+
+type migrateTenantsFlags struct {
+	Tenants flags.List
+}
+
+var commandMigrateTenants = &command.Cmd[migrateTenantsFlags]{
+	ID:   "migrate_tenants",
+	Desc: "Apply the database migrations to the schema of each tenant, the default schema is migrated with `migrate`",
+	FlagFunc: func(m command.Manager, stored *migrateTenantsFlags, f *flag.FlagSet) error {
+		f.Var(&stored.Tenants, "tenants", "List of tenant domains to migrate (default: all tenants)")
+		f.Var(&stored.Tenants, "t", "Alias for --tenants")
+		return nil
+	},
+	Execute: func(m command.Manager, stored migrateTenantsFlags, args []string) error {
+		var ctx = context.Background()
+		var qs = queries.GetQuerySet(&Tenant{}).
+			WithContext(ctx)
+
+		if domains := stored.Tenants.List(); len(domains) > 0 {
+			qs = qs.Filter("Domain__in", domains)
+		}
+
+		var rows, err = qs.All()
+		if err != nil {
+			return fmt.Errorf("failed to retrieve tenants: %w", err)
+		}
+
+		var apps = django.ConfigGet(
+			django.Global.Settings,
+			APPVAR_TENANTS_APPS,
+			[]string{},
+		)
+
+		for _, row := range rows {
+			if row.Object.Schema == "" {
+				m.Logf("Skipping tenant %q, it has no database schema", row.Object.Name)
+				continue
+			}
+
+			m.Logf("Migrating schema %q of tenant %q", row.Object.Schema, row.Object.Name)
+			if err := migrator.MigrateSchemas(ctx, []string{row.Object.Schema}, apps...); err != nil {
+				return err
+			}
+		}
+
+		return command.ErrShouldExit
+	},
+}
@@ -0,0 +1,262 @@
+package tenants
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	django "github.com/Nigel2392/go-django/src"
+	"github.com/Nigel2392/go-django/src/core/except"
+	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/mux"
+)
+
+// TenantForRequest returns the tenant for the domain of the request.
+//
+// If a tenant is already stored in the context (I.E. by the [TenantMiddleware])
+// it is returned instead.
+//
+// If no tenant is registered for the domain, an error wrapping `errors.NoRows` is returned.
+func TenantForRequest(requestOrContext any) (context.Context, *Tenant, error) {
+	var (
+		ctx  context.Context
+		host string
+	)
+
+	switch v := requestOrContext.(type) {
+	case *http.Request:
+		ctx = v.Context()
+		host = mux.GetHost(v)
+	case context.Context:
+		ctx = v
+	case nil:
+		ctx = context.Background()
+	default:
+		panic(fmt.Sprintf(
+			"expected *http.Request or context.Context, got %T",
+			v,
+		))
+	}
+
+	if tenant, ok := queries.TenantFromContext(ctx); ok {
+		if t, ok := tenant.(*Tenant); ok {
+			return ctx, t, nil
+		}
+	}
+
+	if host == "" {
+		return ctx, nil, errors.NoRows.Wrap(
+			"no tenant in context and no host to resolve it from",
+		)
+	}
+
+	var row, err = queries.GetQuerySet(&Tenant{}).
+		WithContext(ctx).
+		Filter("Domain", host).
+		Get()
+	if err != nil {
+		return ctx, nil, errors.Wrapf(
+			err, "failed to get tenant for host %q", host,
+		)
+	}
+
+	return ctx, row.Object, nil
+}
+
+// TenantMiddleware resolves the tenant for the request, see [TenantForRequest].
+//
+// The tenant is stored in the request's context, querysets for [queries.TenantScopedModel]
+// models are filtered on the tenant when they are bound to the context with [queries.QuerySet.WithContext].
+//
+// In [ModeSchema] a transaction is started for the request when it executes it's first query,
+// the tenant's schema is set as the search path for the transaction. Querysets only use the
+// transaction if they are bound to the context. The response is buffered and only sent after
+// the transaction has been committed.
+//
+// If no tenant is found for the request a 404 is returned.
+func TenantMiddleware() func(next mux.Handler) mux.Handler {
+	var mode = django.ConfigGet(
+		django.Global.Settings,
+		APPVAR_TENANTS_MODE,
+		ModeRow,
+	)
+
+	return func(next mux.Handler) mux.Handler {
+		return mux.NewHandler(func(w http.ResponseWriter, req *http.Request) {
+			if django.IsStaticRouteRequest(req) {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			var ctx, tenant, err = TenantForRequest(req)
+			if err != nil {
+				if errors.Is(err, errors.NoRows) {
+					except.Fail(http.StatusNotFound, "Tenant not found")
+					return
+				}
+				logger.Errorf("Failed to resolve tenant for request %s: %v", req.URL.Path, err)
+				except.Fail(http.StatusInternalServerError, "Internal Server Error")
+				return
+			}
+
+			ctx = queries.ContextWithTenant(ctx, tenant)
+
+			if mode != ModeSchema {
+				next.ServeHTTP(w, req.WithContext(ctx))
+				return
+			}
+
+			serveTenantSchema(w, req.WithContext(ctx), tenant, next)
+		})
+	}
+}
+
+func serveTenantSchema(w http.ResponseWriter, req *http.Request, tenant *Tenant, next mux.Handler) {
+	if tenant.Schema == "" {
+		logger.Errorf("Tenant %q has no database schema", tenant.Name)
+		except.Fail(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	var ctx, tx, err = queries.StartLazyTransaction(req.Context(), func(ctx context.Context, tx drivers.Transaction) error {
+		if err := drivers.UseSchema(ctx, tx, tenant.Schema); err != nil {
+			return fmt.Errorf("failed to use schema %q for tenant %q: %w", tenant.Schema, tenant.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("Failed to start transaction for tenant %q: %v", tenant.Name, err)
+		except.Fail(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil {
+			logger.Errorf("Failed to rollback transaction for tenant %q: %v", tenant.Name, err)
+		}
+	}()
+
+	// Once the transaction has started the response is buffered until it is committed,
+	// a failed commit must not be sent to the client as a successful response.
+	//
+	// Responses of requests which did not execute any queries before writing are passed through.
+	var rw = &tenantResponseWriter{ResponseWriter: w}
+	if starter, ok := tx.(interface{ Started() bool }); ok {
+		rw.started = starter.Started
+	}
+	next.ServeHTTP(rw, req.WithContext(ctx))
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.Errorf("Failed to commit transaction for tenant %q: %v", tenant.Name, err)
+		if rw.mode == writePassthrough {
+			return
+		}
+		for k := range w.Header() {
+			w.Header().Del(k)
+		}
+		except.Fail(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	rw.flush()
+}
+
+const (
+	writeUndecided = iota
+	writeBuffered
+	writePassthrough
+)
+
+// tenantResponseWriter decides on the first write whether the response is buffered.
+//
+// If the transaction was started by then, the status and body are held until
+// [tenantResponseWriter.flush] is called after the commit.
+// Otherwise the response is written directly to the underlying writer.
+type tenantResponseWriter struct {
+	http.ResponseWriter
+	started func() bool
+	mode    int
+	buf     bytes.Buffer
+	status  int
+}
+
+func (w *tenantResponseWriter) decide() int {
+	if w.mode == writeUndecided {
+		w.mode = writePassthrough
+		if w.started == nil || w.started() {
+			w.mode = writeBuffered
+		}
+	}
+	return w.mode
+}
+
+func (w *tenantResponseWriter) WriteHeader(status int) {
+	if w.decide() == writePassthrough {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *tenantResponseWriter) Write(b []byte) (int, error) {
+	if w.decide() == writePassthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.buf.Write(b)
+}
+
+func (w *tenantResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	if w.decide() == writePassthrough {
+		return io.Copy(w.ResponseWriter, r)
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.buf.ReadFrom(r)
+}
+
+// Flush is a no-op for buffered responses, flushing would send the response before the commit.
+func (w *tenantResponseWriter) Flush() {
+	if w.decide() == writePassthrough {
+		http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+func (w *tenantResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.decide() != writePassthrough {
+		return nil, nil, http.ErrNotSupported
+	}
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns nil for buffered responses, the underlying writer must not be written to before the commit.
+func (w *tenantResponseWriter) Unwrap() http.ResponseWriter {
+	if w.decide() != writePassthrough {
+		return nil
+	}
+	return w.ResponseWriter
+}
+
+func (w *tenantResponseWriter) flush() {
+	if w.mode != writeBuffered {
+		return
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if w.buf.Len() > 0 {
+		w.ResponseWriter.Write(w.buf.Bytes())
+	}
+}
@@ -0,0 +1,94 @@
+package tenants
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	"github.com/Nigel2392/go-django/queries/src/models"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/trans"
+)
+
+var (
+	_ queries.Tenant = (*Tenant)(nil)
+
+	schemaNameRe = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+)
+
+// Tenant is a customer of the application, resolved by the domain of the request.
+//
+// In [ModeSchema] the tables of the tenant live in their own database schema,
+// in [ModeRow] the rows of [queries.TenantScopedModel] models are filtered on the tenant's ID.
+type Tenant struct {
+	models.Model `table:"tenants"`
+	ID           int64
+	Name         string
+	Domain       string
+	Schema       string
+}
+
+func (t *Tenant) String() string {
+	return t.Name
+}
+
+// TenantID returns the ID of the tenant, this is stored
+// in the tenant field of tenant scoped models.
+func (t *Tenant) TenantID() any {
+	return t.ID
+}
+
+func (t *Tenant) DatabaseIndexes(obj attrs.Definer) []migrator.Index {
+	if reflect.TypeOf(obj) != reflect.TypeOf(t) {
+		return nil
+	}
+
+	return []migrator.Index{
+		{Fields: []string{"Domain"}, Unique: true},
+	}
+}
+
+func (t *Tenant) FieldDefs(ctx context.Context) attrs.Definitions {
+	return t.Model.Define(ctx, t,
+		attrs.NewField(t, "ID", &attrs.FieldConfig{
+			HelpText: trans.S("The unique identifier for the tenant."),
+			Primary:  true,
+			Column:   "id",
+			ReadOnly: true,
+		}),
+		attrs.NewField(t, "Name", &attrs.FieldConfig{
+			HelpText:  trans.S("The name of the tenant."),
+			Column:    "name",
+			MinLength: 2,
+			MaxLength: 64,
+		}),
+		attrs.NewField(t, "Domain", &attrs.FieldConfig{
+			HelpText:  trans.S("The domain the tenant is served on, e.g. customer.example.com."),
+			Column:    "domain",
+			MinLength: 2,
+			MaxLength: 256,
+		}),
+		attrs.NewField(t, "Schema", &attrs.FieldConfig{
+			HelpText:  trans.S("The database schema of the tenant, only used with the schema tenancy mode."),
+			Column:    "schema_name",
+			Blank:     true,
+			MaxLength: 63,
+			Validators: []func(interface{}) error{
+				validateSchemaName,
+			},
+		}),
+	)
+}
+
+func validateSchemaName(v interface{}) error {
+	var s, _ = v.(string)
+	if s == "" || schemaNameRe.MatchString(s) {
+		return nil
+	}
+	return fmt.Errorf(
+		"invalid schema name %q: only lowercase letters, digits and underscores are allowed", s,
+	)
+}
@@ -0,0 +1,25 @@
+package tenants
+
+// Mode is the way tenants are isolated from each other.
+type Mode string
+
+const (
+	// ModeRow stores the data of all tenants in the same tables,
+	// querysets of [queries.TenantScopedModel] models are filtered on the tenant.
+	//
+	// This is supported by all database drivers.
+	ModeRow Mode = "row"
+
+	// ModeSchema stores the data of each tenant in it's own database schema.
+	//
+	// The search path of the request's transaction is set to the tenant's schema,
+	// tables which do not exist in the tenant's schema are looked up in the public schema.
+	//
+	// This is only supported by PostgreSQL.
+	ModeSchema Mode = "schema"
+)
+
+const (
+	APPVAR_TENANTS_MODE = "APPVAR_TENANTS_MODE" // tenants.Mode, defaults to ModeRow
+	APPVAR_TENANTS_APPS = "APPVAR_TENANTS_APPS" // []string, apps migrated to each tenant's schema, defaults to all apps
+)
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/Nigel2392/go-django/contrib/fixtures"
 	"github.com/Nigel2392/go-django/queries/src/drivers"
 	django "github.com/Nigel2392/go-django/src"
 	"github.com/Nigel2392/go-django/src/apps"
@@ -127,6 +128,10 @@ type (
 		// so they can be used in queries without too much hassle.
 		ExtraModels []attrs.Definer
 
+		// fixture files which are loaded after the app was initialized,
+		// see [fixtures.LoadFiles] for more information.
+		Fixtures []string
+
 		// Private fields to be used by the Tester struct
 		db         drivers.Database
 		App        *django.Application
@@ -207,6 +212,10 @@ func (d *Tester) Setup(t TB) error {
 		return err
 	}
 
+	if err := d.LoadFixtures(d.Fixtures...); err != nil {
+		return err
+	}
+
 	if d.Auth != nil {
 		if !django.AppInstalled("session") {
 			t.Fatal("cannot initialize authentication without app 'session'")
@@ -244,6 +253,16 @@ func (d *Tester) DB() drivers.Database {
 	return d.db
 }
 
+// LoadFixtures loads the fixture files into the database in a single transaction.
+func (d *Tester) LoadFixtures(paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	var _, err = fixtures.LoadFiles(context.Background(), paths...)
+	return err
+}
+
 func (d *Tester) Assert(t BaseTB, verbose bool) Assertion {
 	return &assertion[BaseTB]{test: t, verbose: verbose}
 }
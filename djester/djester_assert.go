@@ -4,11 +4,13 @@
 package djester
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/Nigel2392/go-django/queries/src/drivers"
 	"github.com/PuerkitoBio/goquery"
 )
 
@@ -23,6 +25,11 @@ type Assertion interface {
 
 	AssertHTMLString(document string, asserts ...HTMLAssertFunc)
 	AssertHTMLDoc(doc *goquery.Document, asserts ...HTMLAssertFunc)
+
+	// AssertNumQueries asserts that exactly n queries are executed with the context passed to fn.
+	//
+	// Transaction control statements (BEGIN, COMMIT, ROLLBACK) and pings are not counted.
+	AssertNumQueries(n int, fn func(ctx context.Context))
 }
 
 type assertion[GOTEST BaseTB] struct {
@@ -150,3 +157,30 @@ func (d *assertion[TEST]) AssertNotContains(haystack, needle any) {
 		d.test.Fatalf("expected slice of length %d to not contain %v", sliceV.Len(), needle)
 	}
 }
+
+func (d *assertion[TEST]) AssertNumQueries(n int, fn func(ctx context.Context)) {
+	d.test.Helper()
+
+	var ctx, qi = drivers.ContextWithQueryInfo(d.test.Context())
+	fn(ctx)
+
+	var statements = qi.Statements()
+	if len(statements) == n {
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "expected %d queries, got %d", n, len(statements))
+	for i, q := range statements {
+		fmt.Fprintf(&sb, "\n  %d. %s", i+1, q.Query)
+		if d.verbose && q.Caller != "" {
+			fmt.Fprintf(&sb, "\n     at %s", q.Caller)
+		}
+	}
+
+	for _, dup := range qi.Duplicates(2) {
+		fmt.Fprintf(&sb, "\nquery executed %d times: %s", dup.Count(), dup.Shape)
+	}
+
+	d.test.Fatalf("assertion failed: %s", sb.String())
+}
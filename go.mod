@@ -3,6 +3,7 @@ module github.com/Nigel2392/go-django
 go 1.27.0
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/Nigel2392/cache v1.0.4
 	github.com/Nigel2392/go-signals v1.1.2
 	github.com/Nigel2392/go-telepath v1.4.3
@@ -29,22 +29,53 @@ func ContextQueryInfo(ctx context.Context) (*QueryInformation, bool) {
 }
 
 func ContextQueryExec[T any](ctx context.Context, driver string, query string, args []any, flags QueryFlag, fn func(ctx context.Context, query string, args ...any) (T, error)) (T, error) {
+	var result, _, err = contextQueryExec(ctx, driver, query, args, flags, fn)
+	return result, err
+}
+
+// contextQueryExec executes the function and records the query if query information is stored in the context.
+//
+// The recorded query is returned so the row count can be updated while the result is scanned.
+//
+// Statements which take longer than [SLOW_QUERY_THRESHOLD] are logged and all statements are recorded
+// in the query metrics and traced, regardless of the query information.
+func contextQueryExec[T any](ctx context.Context, driver string, query string, args []any, flags QueryFlag, fn func(ctx context.Context, query string, args ...any) (T, error)) (T, *Query, error) {
+	var spanCtx, span = startQuerySpan(ctx, driver, flags, query)
+	var start = time.Now()
+	var result, err = fn(spanCtx, query, args...)
+	var timeTaken = time.Since(start)
+	span.SetError(err)
+	span.End()
+	logSlowQuery(ctx, driver, flags, timeTaken, query, args)
+	recordQueryMetrics(ctx, driver, flags, timeTaken, err)
+
 	var qi, ok = ContextQueryInfo(ctx)
 	if !ok {
-		return fn(ctx, query, args...)
+		return result, nil, err
 	}
 
-	var start = time.Now()
-	var result, err = fn(ctx, query, args...)
-	qi.Queries = append(qi.Queries, &Query{
+	var q = &Query{
 		Context:   qi,
 		Driver:    driver,
 		Query:     query,
 		Args:      args,
 		Error:     err,
 		Start:     start,
-		TimeTaken: time.Since(start),
+		TimeTaken: timeTaken,
 		Flags:     flags,
-	})
-	return result, err
+		Caller:    queryCaller(),
+	}
+
+	if err == nil {
+		switch r := any(result).(type) {
+		case interface{ RowsAffected() (int64, error) }:
+			var affected, _ = r.RowsAffected()
+			q.Rows.Store(affected)
+		case interface{ RowsAffected() int64 }:
+			q.Rows.Store(r.RowsAffected())
+		}
+	}
+
+	qi.add(q)
+	return result, q, err
 }
@@ -3,6 +3,12 @@ package drivers
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
@@ -17,6 +23,43 @@ type Query struct {
 	Error     error
 	Start     time.Time
 	TimeTaken time.Duration
+
+	// Caller is the location in the code which executed the query,
+	// frames inside of the queries package are skipped.
+	Caller string
+
+	// Rows is the number of rows scanned for select queries,
+	// or the number of rows affected for other statements.
+	//
+	// It is updated while the rows are scanned, which may happen concurrently with reading it.
+	Rows atomic.Int64
+}
+
+var (
+	shapeWhitespace   = regexp.MustCompile(`\s+`)
+	shapePlaceholder  = regexp.MustCompile(`\$\d+|@p\d+|\?`)
+	shapeValueList    = regexp.MustCompile(`\(\?(?:\s*,\s*\?)+\)`)
+	shapeLiteralValue = regexp.MustCompile(`'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b`)
+)
+
+// Shape returns the normalized SQL of the query.
+//
+// Placeholders and literal values are replaced with "?" and lists of
+// placeholders are collapsed, queries with the same shape only differ
+// in the values they were executed with.
+func (q *Query) Shape() string {
+	var shape = strings.TrimSpace(q.Query)
+	shape = shapeWhitespace.ReplaceAllString(shape, " ")
+	shape = shapePlaceholder.ReplaceAllString(shape, "?")
+	shape = shapeLiteralValue.ReplaceAllString(shape, "?")
+	shape = shapeValueList.ReplaceAllString(shape, "(?)")
+	return shape
+}
+
+// IsStatement reports whether the query is a statement sent by the application,
+// transaction control statements and pings are not considered statements.
+func (q *Query) IsStatement() bool {
+	return q.Flags&(Q_QUERY|Q_QUERYROW|Q_EXEC) != 0
 }
 
 func (q *Query) Explain(c context.Context, db DB) (string, error) {
@@ -35,9 +78,125 @@ func (q *Query) Explain(c context.Context, db DB) (string, error) {
 type QueryInformation struct {
 	Queries []*Query
 	Start   time.Time
+	mu      sync.Mutex
+
+	// results served from or missed in the queryset result cache
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
+}
+
+// CacheHit records a queryset result which was served from the cache instead of the database.
+func (q *QueryInformation) CacheHit() {
+	q.cacheHits.Add(1)
+}
+
+// CacheMiss records a cacheable queryset result which had to be retrieved from the database.
+func (q *QueryInformation) CacheMiss() {
+	q.cacheMisses.Add(1)
+}
+
+// CacheStats returns the number of queryset cache hits and misses recorded.
+func (q *QueryInformation) CacheStats() (hits, misses int64) {
+	return q.cacheHits.Load(), q.cacheMisses.Load()
+}
+
+func (q *QueryInformation) add(query *Query) {
+	q.mu.Lock()
+	q.Queries = append(q.Queries, query)
+	q.mu.Unlock()
+}
+
+// Statements returns the recorded queries, excluding transaction control statements and pings.
+func (q *QueryInformation) Statements() []*Query {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var statements = make([]*Query, 0, len(q.Queries))
+	for _, query := range q.Queries {
+		if query.IsStatement() {
+			statements = append(statements, query)
+		}
+	}
+	return statements
+}
+
+// DuplicateQuery is a group of select queries with the same [Query.Shape].
+//
+// Many queries with the same shape in a single request usually means
+// related objects are fetched one by one (N+1 queries), instead of
+// with a single join or prefetch.
+type DuplicateQuery struct {
+	Shape   string
+	Queries []*Query
+}
+
+// Count returns the number of times the query was executed.
+func (d DuplicateQuery) Count() int {
+	return len(d.Queries)
+}
+
+// TotalTime returns the time spent executing all of the queries.
+func (d DuplicateQuery) TotalTime() time.Duration {
+	var total time.Duration
+	for _, q := range d.Queries {
+		total += q.TimeTaken
+	}
+	return total
+}
+
+// Callers returns the unique locations in the code which executed the queries.
+func (d DuplicateQuery) Callers() []string {
+	var callers = make([]string, 0, 1)
+	for _, q := range d.Queries {
+		if q.Caller != "" && !slices.Contains(callers, q.Caller) {
+			callers = append(callers, q.Caller)
+		}
+	}
+	return callers
+}
+
+// Duplicates returns the select queries which were executed at least threshold times
+// with the same shape, ordered by the number of executions.
+func (q *QueryInformation) Duplicates(threshold int) []DuplicateQuery {
+	if threshold < 2 {
+		threshold = 2
+	}
+
+	var (
+		order  = make([]string, 0)
+		shapes = make(map[string][]*Query)
+	)
+	for _, query := range q.Statements() {
+		if query.Flags&(Q_QUERY|Q_QUERYROW) == 0 {
+			continue
+		}
+
+		var shape = query.Shape()
+		if _, ok := shapes[shape]; !ok {
+			order = append(order, shape)
+		}
+		shapes[shape] = append(shapes[shape], query)
+	}
+
+	var duplicates = make([]DuplicateQuery, 0)
+	for _, shape := range order {
+		if len(shapes[shape]) >= threshold {
+			duplicates = append(duplicates, DuplicateQuery{
+				Shape:   shape,
+				Queries: shapes[shape],
+			})
+		}
+	}
+
+	slices.SortStableFunc(duplicates, func(a, b DuplicateQuery) int {
+		return b.Count() - a.Count()
+	})
+
+	return duplicates
 }
 
 func (q *QueryInformation) TotalExecutionTime() time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
 	var s = q.Start
 	if len(q.Queries) == 0 {
 		return 0
@@ -47,6 +206,8 @@ func (q *QueryInformation) TotalExecutionTime() time.Duration {
 }
 
 func (q *QueryInformation) TotalTime() time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
 	var total time.Duration
 	for _, ql := range q.Queries {
 		total += ql.TimeTaken
@@ -55,6 +216,8 @@ func (q *QueryInformation) TotalTime() time.Duration {
 }
 
 func (q *QueryInformation) Slowest() *Query {
+	q.mu.Lock()
+	defer q.mu.Unlock()
 	if len(q.Queries) == 0 {
 		return nil
 	}
@@ -72,8 +235,29 @@ func (q *QueryInformation) Slowest() *Query {
 }
 
 func (q *QueryInformation) AverageTime() time.Duration {
+	var total = q.TotalTime()
+	q.mu.Lock()
+	defer q.mu.Unlock()
 	if len(q.Queries) == 0 {
 		return 0
 	}
-	return q.TotalTime() / time.Duration(len(q.Queries))
+	return total / time.Duration(len(q.Queries))
+}
+
+const queriesPackage = "github.com/Nigel2392/go-django/queries/src"
+
+// queryCaller returns the first frame outside of the queries package.
+func queryCaller() string {
+	var pcs = make([]uintptr, 32)
+	var n = runtime.Callers(3, pcs)
+	var frames = runtime.CallersFrames(pcs[:n])
+	for {
+		var frame, more = frames.Next()
+		if !strings.HasPrefix(frame.Function, queriesPackage) && !strings.HasPrefix(frame.Function, "runtime.") {
+			return fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
 }
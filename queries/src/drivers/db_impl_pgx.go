@@ -6,6 +6,8 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -34,14 +36,38 @@ func OpenPGX(ctx context.Context, drv *Driver, dsn string, opts ...OpenOption) (
 		return nil, databaseError(drv, err)
 	}
 
+	// the pgx pool cannot be resized in place, the options of
+	// [WithPoolConfig] return their configuration to recreate the pool with.
+	var configs []PoolConfig
 	for _, opt := range opts {
-		if err := opt(POSTGRES_DRIVER_NAME, pool); err != nil && !errors.Is(err, errors.NotImplemented) {
+		var err = opt(POSTGRES_DRIVER_NAME, pool)
+		var poolConfig *pgxPoolConfig
+		switch {
+		case err == nil, errors.Is(err, errors.NotImplemented):
+		case errors.As(err, &poolConfig):
+			configs = append(configs, poolConfig.config)
+		default:
+			pool.Close()
 			return nil, err
 		}
 	}
 
-	var qW = queryWrapperPGX[*pgxpool.Pool]{conn: pool, d: drv}
-	var cW = connWrapperPGX[*pgxpool.Pool]{queryWrapperPGX: qW}
+	if len(configs) > 0 {
+		var cnf = pool.Config()
+		for _, config := range configs {
+			config.applyPGX(cnf)
+		}
+
+		var configured, err = pgxpool.NewWithConfig(ctx, cnf)
+		pool.Close()
+		if err != nil {
+			return nil, databaseError(drv, err)
+		}
+		pool = configured
+	}
+
+	var qW = queryWrapperPGX[*pgxPool]{conn: newPGXPool(pool), d: drv}
+	var cW = connWrapperPGX[*pgxPool]{queryWrapperPGX: qW}
 	return &poolWrapperPGX{connWrapperPGX: cW}, nil
 }
 
@@ -57,6 +83,15 @@ func PGXOption(opt func(driverName string, db *pgxpool.Pool) error) OpenOption {
 type queryWrapperPGX[T pgxQuerier] struct {
 	conn T
 	d    *Driver
+
+	// tx is only set for transactions
+	tx *pgxTxState
+}
+
+// pgxTxState holds the state of a pgx transaction shared by its statements.
+type pgxTxState struct {
+	// the statement_timeout which was set for the transaction with SET LOCAL
+	statementTimeout time.Duration
 }
 
 func (c *queryWrapperPGX[T]) Unwrap() any {
@@ -66,26 +101,65 @@ func (c *queryWrapperPGX[T]) Unwrap() any {
 	return c.conn
 }
 
+// statementTimeout applies the timeout stored in the context to the statement, see [statementTimeout].
+//
+// Inside of a transaction the statement_timeout is set with SET LOCAL as well, it lasts until the
+// end of the transaction and is only set again when a statement is executed with a different timeout.
+func (c *queryWrapperPGX[T]) statementTimeout(ctx context.Context, query string) (context.Context, string, context.CancelFunc) {
+	ctx, query, cancel := statementTimeout(ctx, c.d, query)
+	if c.tx == nil {
+		return ctx, query, cancel
+	}
+
+	var timeout, _ = ContextQueryTimeout(ctx)
+	if timeout < 0 {
+		timeout = 0
+	}
+
+	if timeout == c.tx.statementTimeout {
+		return ctx, query, cancel
+	}
+
+	var stmt = "SET LOCAL statement_timeout TO DEFAULT"
+	if timeout > 0 {
+		stmt = fmt.Sprintf("SET LOCAL statement_timeout = %d", timeoutMillis(timeout))
+	}
+
+	var _, err = ContextQueryExec(ctx, c.d.Name, stmt, nil, Q_EXEC, c.conn.Exec)
+	LogSQL(ctx, fmt.Sprintf("%T", c.conn), err, stmt)
+	if err == nil {
+		c.tx.statementTimeout = timeout
+	}
+
+	return ctx, query, cancel
+}
+
 func (c *queryWrapperPGX[T]) QueryContext(ctx context.Context, query string, args ...any) (SQLRows, error) {
-	var rows, err = ContextQueryExec(ctx, c.d.Name, query, args, Q_QUERY, c.conn.Query)
+	ctx, query, cancel := c.statementTimeout(ctx, query)
+	var rows, q, err = contextQueryExec(ctx, c.d.Name, query, args, Q_QUERY, c.conn.Query)
 	LogSQL(ctx, fmt.Sprintf("%T", c.conn), err, query, args...)
 	if err != nil {
-		return nil, databaseError(c.d, err)
+		err = statementError(ctx, c.d, err)
+		cancel()
+		return nil, err
 	}
-	return &pgxRows{Rows: rows, d: c.d}, nil
+	return &pgxRows{Rows: rows, d: c.d, q: q, ctx: ctx, cancel: cancel}, nil
 }
 
 func (c *queryWrapperPGX[T]) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, query, cancel := c.statementTimeout(ctx, query)
+	defer cancel()
 	result, err := ContextQueryExec(ctx, c.d.Name, query, args, Q_EXEC, c.conn.Exec)
 	LogSQL(ctx, fmt.Sprintf("%T", c.conn), err, query, args...)
 	if err != nil {
-		return nil, databaseError(c.d, err)
+		return nil, statementError(ctx, c.d, err)
 	}
 	return &pgResult{CommandTag: result}, nil
 }
 
 func (c *queryWrapperPGX[T]) QueryRowContext(ctx context.Context, query string, args ...any) SQLRow {
-	var row, err = ContextQueryExec(ctx, c.d.Name, query, args, Q_QUERYROW, func(ctx context.Context, query string, args ...any) (pgx.Row, error) {
+	ctx, query, cancel := c.statementTimeout(ctx, query)
+	var row, q, err = contextQueryExec(ctx, c.d.Name, query, args, Q_QUERYROW, func(ctx context.Context, query string, args ...any) (pgx.Row, error) {
 		var res = c.conn.QueryRow(ctx, query, args...)
 		if canErr, ok := res.(interface{ Err() error }); ok {
 			return res, canErr.Err()
@@ -93,7 +167,10 @@ func (c *queryWrapperPGX[T]) QueryRowContext(ctx context.Context, query string,
 		return res, nil
 	})
 	LogSQL(ctx, fmt.Sprintf("%T", c.conn), err, query, args...)
-	return &pgxRow{Row: row, d: c.d}
+	if err != nil {
+		cancel()
+	}
+	return &pgxRow{Row: row, d: c.d, q: q, ctx: ctx, cancel: cancel}
 }
 
 func (c *queryWrapperPGX[T]) SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults {
@@ -144,24 +221,86 @@ func (c *connWrapperPGX[T]) Begin(ctx context.Context) (Transaction, error) {
 	}
 	return &pgxTx{
 		inTx:            true,
-		queryWrapperPGX: queryWrapperPGX[pgx.Tx]{conn: tx, d: c.d},
+		queryWrapperPGX: queryWrapperPGX[pgx.Tx]{conn: tx, d: c.d, tx: &pgxTxState{}},
 	}, nil
 }
 
+// pgxPool holds the pgx pool of a database, the pool
+// is swapped out when it is reconfigured with [ConfigurePool].
+type pgxPool struct {
+	pool atomic.Pointer[pgxpool.Pool]
+}
+
+func newPGXPool(pool *pgxpool.Pool) *pgxPool {
+	var p = &pgxPool{}
+	p.pool.Store(pool)
+	return p
+}
+
+func (p *pgxPool) Unwrap() any {
+	return p.pool.Load()
+}
+
+func (p *pgxPool) Query(ctx context.Context, query string, args ...any) (pgx.Rows, error) {
+	return p.pool.Load().Query(ctx, query, args...)
+}
+
+func (p *pgxPool) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	return p.pool.Load().QueryRow(ctx, query, args...)
+}
+
+func (p *pgxPool) Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	return p.pool.Load().Exec(ctx, query, args...)
+}
+
+func (p *pgxPool) SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults {
+	return p.pool.Load().SendBatch(ctx, batch)
+}
+
+func (p *pgxPool) Ping(ctx context.Context) error {
+	return p.pool.Load().Ping(ctx)
+}
+
+func (p *pgxPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return p.pool.Load().Begin(ctx)
+}
+
 type poolWrapperPGX struct {
-	connWrapperPGX[*pgxpool.Pool]
+	connWrapperPGX[*pgxPool]
 }
 
 func (p *poolWrapperPGX) Close() error {
 	if p.conn == nil {
 		return nil
 	}
-	p.conn.Close()
+	p.conn.pool.Load().Close()
+	return nil
+}
+
+func (p *poolWrapperPGX) PoolStats() PoolStats {
+	return pgxPoolStats(p.conn.pool.Load())
+}
+
+// ConfigurePool creates a new pool with the configuration and swaps it in.
+//
+// The old pool is closed in the background, closing it waits
+// until the connections acquired from it have been released.
+func (p *poolWrapperPGX) ConfigurePool(ctx context.Context, config PoolConfig) error {
+	var cnf = p.conn.pool.Load().Config()
+	config.applyPGX(cnf)
+
+	var pool, err = pgxpool.NewWithConfig(ctx, cnf)
+	if err != nil {
+		return databaseError(p.d, err)
+	}
+
+	var old = p.conn.pool.Swap(pool)
+	go old.Close()
 	return nil
 }
 
 func (p *poolWrapperPGX) Acquire(ctx context.Context) (Database, error) {
-	conn, err := p.conn.Acquire(ctx)
+	conn, err := p.conn.pool.Load().Acquire(ctx)
 	if err != nil {
 		return nil, databaseError(p.d, err)
 	}
@@ -220,11 +359,23 @@ func (d *pgxTx) SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResult
 
 type pgxRows struct {
 	pgx.Rows
-	d *Driver
+	d      *Driver
+	q      *Query
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *pgxRows) Next() bool {
+	var next = r.Rows.Next()
+	if next && r.q != nil {
+		r.q.Rows.Add(1)
+	}
+	return next
 }
 
 func (r *pgxRows) Close() error {
 	r.Rows.Close()
+	r.cancel()
 	return nil
 }
 
@@ -242,17 +393,29 @@ func (r *pgxRows) Columns() ([]string, error) {
 }
 
 func (r *pgxRows) Err() error {
-	return databaseError(r.d, r.Rows.Err())
+	return statementError(r.ctx, r.d, r.Rows.Err())
 }
 
 type pgxRow struct {
 	pgx.Row
-	d *Driver
+	d      *Driver
+	q      *Query
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *pgxRow) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	r.cancel()
+	if err == nil && r.q != nil {
+		r.q.Rows.Store(1)
+	}
+	return err
 }
 
 func (r *pgxRow) Err() error {
 	if canErr, ok := r.Row.(interface{ Err() error }); ok {
-		return databaseError(r.d, canErr.Err())
+		return statementError(r.ctx, r.d, canErr.Err())
 	}
 	return nil
 }
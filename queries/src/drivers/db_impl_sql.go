@@ -51,27 +51,42 @@ func (d *queryWrapper[T]) Unwrap() any {
 }
 
 func (d *queryWrapper[T]) QueryContext(ctx context.Context, query string, args ...any) (SQLRows, error) {
-	var res, err = ContextQueryExec(ctx, d.d.Name, query, args, Q_QUERY, d.conn.QueryContext)
+	// the rows are closed when the context is canceled,
+	// the timeout is canceled when the rows are closed instead.
+	ctx, query, cancel := statementTimeout(ctx, d.d, query)
+	var res, q, err = contextQueryExec(ctx, d.d.Name, query, args, Q_QUERY, d.conn.QueryContext)
 	LogSQL(ctx, "sql.DB", err, query, args...)
-	return &sqlRowsWrapper{Rows: res, d: d.d}, databaseError(d.d, err)
+	if err != nil {
+		cancel()
+	}
+	return &sqlRowsWrapper{Rows: res, d: d.d, q: q, ctx: ctx, cancel: cancel}, statementError(ctx, d.d, err)
 }
 
 func (d *queryWrapper[T]) QueryRowContext(ctx context.Context, query string, args ...any) SQLRow {
-	var res, err = ContextQueryExec(ctx, d.d.Name, query, args, Q_QUERYROW, func(ctx context.Context, query string, args ...any) (*sql.Row, error) {
+	ctx, query, cancel := statementTimeout(ctx, d.d, query)
+	var res, q, err = contextQueryExec(ctx, d.d.Name, query, args, Q_QUERYROW, func(ctx context.Context, query string, args ...any) (*sql.Row, error) {
 		r := d.conn.QueryRowContext(ctx, query, args...)
 		return r, r.Err()
 	})
 	LogSQL(ctx, "sql.DB", err, query, args...)
+	if err != nil {
+		cancel()
+	}
 	return &sqlRowWrapper{
-		Row: res,
-		d:   d.d,
+		Row:    res,
+		d:      d.d,
+		q:      q,
+		ctx:    ctx,
+		cancel: cancel,
 	}
 }
 
 func (d *queryWrapper[T]) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, query, cancel := statementTimeout(ctx, d.d, query)
+	defer cancel()
 	var res, err = ContextQueryExec(ctx, d.d.Name, query, args, Q_EXEC, d.conn.ExecContext)
 	LogSQL(ctx, "sql.DB", err, query, args...)
-	return res, databaseError(d.d, err)
+	return res, statementError(ctx, d.d, err)
 }
 
 type dbWrapper struct {
@@ -100,6 +115,15 @@ func (d *dbWrapper) Ping(ctx context.Context) error {
 	return databaseError(d.d, err)
 }
 
+func (d *dbWrapper) PoolStats() PoolStats {
+	return sqlPoolStats(d.queryWrapper.conn)
+}
+
+func (d *dbWrapper) ConfigurePool(ctx context.Context, config PoolConfig) error {
+	config.applySQL(d.queryWrapper.conn)
+	return nil
+}
+
 func (d *dbWrapper) Driver() driver.Driver {
 	return d.queryWrapper.conn.Driver()
 }
@@ -155,24 +179,52 @@ func (t *txWrapper) Rollback(ctx context.Context) error {
 
 type sqlRowWrapper struct {
 	*sql.Row
-	d *Driver
+	d      *Driver
+	q      *Query
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *sqlRowWrapper) Scan(dest ...any) error {
+	defer r.cancel()
+	err := r.Row.Scan(dest...)
+	if err == nil && r.q != nil {
+		r.q.Rows.Store(1)
+	}
+	return err
 }
 
 func (r *sqlRowWrapper) Err() error {
-	return databaseError(r.d, r.Row.Err())
+	return statementError(r.ctx, r.d, r.Row.Err())
 }
 
 type sqlRowsWrapper struct {
 	*sql.Rows
-	d *Driver
+	d      *Driver
+	q      *Query
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *sqlRowsWrapper) Close() error {
+	defer r.cancel()
+	return r.Rows.Close()
+}
+
+func (r *sqlRowsWrapper) Next() bool {
+	var next = r.Rows.Next()
+	if next && r.q != nil {
+		r.q.Rows.Add(1)
+	}
+	return next
 }
 
 func (r *sqlRowsWrapper) Scan(dest ...any) error {
 	err := r.Rows.Scan(dest...)
-	return databaseError(r.d, err)
+	return statementError(r.ctx, r.d, err)
 }
 
 func (r *sqlRowsWrapper) Err() error {
 	err := r.Rows.Err()
-	return databaseError(r.d, err)
+	return statementError(r.ctx, r.d, err)
 }
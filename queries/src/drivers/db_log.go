@@ -49,6 +49,12 @@ func getLogger() logger.Log {
 	return localLogger
 }
 
+// getContextLogger returns the SQL logger with the attributes held by the context,
+// i.e. the request ID of the request the query is executed in.
+func getContextLogger(ctx context.Context) logger.Log {
+	return logger.WithContext(getLogger(), ctx)
+}
+
 func ContextLogIsTx(ctx context.Context) bool {
 	b, ok := ctx.Value(txContextKey).(*bool)
 	return (b != nil && ok && *b)
@@ -73,13 +79,20 @@ func LogSQL(ctx context.Context, from string, err error, query string, args ...a
 		txStr = "TX."
 	}
 
+	// the logger with the context attributes is only created if the message is written
 	if err != nil {
-		getLogger().Errorf("[%s%s.Query]: %s: %s %v", txStr, from, err.Error(), query, args)
-		return wasLogged(logger.ERR)
+		if !wasLogged(logger.ERR) {
+			return false
+		}
+		getContextLogger(ctx).Errorf("[%s%s.Query]: %s: %s %v", txStr, from, err.Error(), query, args)
+		return true
 	}
 
-	getLogger().Debugf("[%s%s.Query]: %s %v", txStr, from, query, args)
-	return wasLogged(logger.DBG)
+	if !wasLogged(logger.DBG) {
+		return false
+	}
+	getContextLogger(ctx).Debugf("[%s%s.Query]: %s %v", txStr, from, query, args)
+	return true
 }
 
 // SetLogSQL sets the logging flag for SQL queries in the context.
@@ -103,7 +116,7 @@ func LogSQLScope(ctx context.Context, log bool, fn func(context.Context) error)
 
 	// probably should do this only when query could be logged? right?
 	if err != nil && log && logged {
-		getLogger().Errorf("Error in LogSQLScope: %v", err)
+		getContextLogger(newCtx).Errorf("Error in LogSQLScope: %v", err)
 	}
 
 	return newCtx, err, logged
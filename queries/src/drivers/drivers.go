@@ -21,6 +21,13 @@ type Driver struct {
 	Open               func(ctx context.Context, drv *Driver, dsn string, opts ...OpenOption) (Database, error)
 	BuildDatabaseError func(err error) errors.DatabaseError
 	ExplainQuery       func(ctx context.Context, q DB, query string, args []any) (string, error)
+	ExplainPlan        func(ctx context.Context, q DB, query string, args []any, opts ExplainOptions) (*QueryPlan, error)
+	CreateSchema       func(ctx context.Context, q DB, schema string) error
+	UseSchema          func(ctx context.Context, q DB, schema string) error
+
+	// StatementTimeout rewrites the query so the database server
+	// limits the execution time of the statement to the timeout.
+	StatementTimeout func(query string, timeout time.Duration) string
 }
 
 type driverRegistry struct {
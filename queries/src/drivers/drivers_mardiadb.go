@@ -24,9 +24,11 @@ func init() {
 			return OpenSQL(MARIADB_DRIVER_NAME, drv, dsn, opts...)
 		},
 		BuildDatabaseError: mySQLDatabaseError,
+		ExplainPlan:        explainPlanMariaDB,
 		ExplainQuery: func(ctx context.Context, q DB, query string, args []any) (string, error) {
 			return explainMySQL(ctx, q, query, args)
 		},
+		StatementTimeout: mariaDBStatementTimeout,
 	})
 }
 
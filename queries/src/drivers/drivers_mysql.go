@@ -55,7 +55,11 @@ func mySQLDatabaseError(err error) errors.DatabaseError {
 
 	// --- Transaction/Locking ---
 	case 1205: // ER_LOCK_WAIT_TIMEOUT
-		return errors.QueryTimeout.WithCause(err)
+		return errors.LockTimeout.WithCause(err)
+
+	case 3024, // ER_QUERY_TIMEOUT
+		1969: // ER_STATEMENT_TIMEOUT (MariaDB)
+		return errors.StatementTimeout.WithCause(err)
 
 	case 1213: // ER_LOCK_DEADLOCK
 		return errors.DeadlockDetected.WithCause(err)
@@ -165,8 +169,10 @@ func init() {
 			return OpenSQL(MYSQL_DRIVER_NAME, drv, dsn, opts...)
 		},
 		BuildDatabaseError: mySQLDatabaseError,
+		ExplainPlan:        explainPlanMySQL,
 		ExplainQuery: func(ctx context.Context, q DB, query string, args []any) (string, error) {
 			return explainMySQL(ctx, q, query, args)
 		},
+		StatementTimeout: mySQLStatementTimeout,
 	})
 }
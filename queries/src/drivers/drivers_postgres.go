@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	pg_stdlib "github.com/jackc/pgx/v5/stdlib"
 )
 
@@ -19,7 +21,8 @@ func init() {
 		Open: func(ctx context.Context, drv *Driver, dsn string, opts ...OpenOption) (Database, error) {
 			return OpenPGX(ctx, drv, dsn, opts...)
 		},
-		BuildDatabaseError: errors.InvalidDatabaseError,
+		BuildDatabaseError: postgresDatabaseError,
+		ExplainPlan:        explainPlanPostgres,
 		ExplainQuery: func(ctx context.Context, q DB, query string, args []any) (string, error) {
 			query = "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) " + query
 			var rows, err = q.QueryContext(ctx, query, args...)
@@ -37,5 +40,64 @@ func init() {
 			}
 			return sb.String(), nil
 		},
+		CreateSchema: func(ctx context.Context, q DB, schema string) error {
+			var _, err = q.ExecContext(ctx, "CREATE SCHEMA IF NOT EXISTS "+pgx.Identifier{schema}.Sanitize())
+			return err
+		},
+		UseSchema: func(ctx context.Context, q DB, schema string) error {
+			// SET LOCAL only lasts until the end of the transaction,
+			// the connection is returned to the pool with the default search_path.
+			var _, err = q.ExecContext(ctx, "SET LOCAL search_path TO "+pgx.Identifier{schema}.Sanitize()+", public")
+			return err
+		},
 	})
 }
+
+// postgresDatabaseError classifies connection errors and errors which cause
+// the statement to be rolled back, these can be retried with [Retry].
+//
+// Other errors are returned as an invalid database error.
+func postgresDatabaseError(err error) errors.DatabaseError {
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return errors.ConnectionFailed.WithCause(err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return errors.InvalidDatabaseError(err)
+	}
+
+	switch pgErr.Code {
+	case "08000", // connection_exception
+		"08001", // sqlclient_unable_to_establish_sqlconnection
+		"08004", // sqlserver_rejected_establishment_of_sqlconnection
+		"57P03": // cannot_connect_now
+		return errors.ConnectionFailed.WithCause(err)
+
+	case "08003", // connection_does_not_exist
+		"57P01", // admin_shutdown
+		"57P02": // crash_shutdown
+		return errors.ConnectionClosed.WithCause(err)
+
+	case "08006": // connection_failure
+		return errors.ConnectionLost.WithCause(err)
+
+	case "53300": // too_many_connections
+		return errors.TooManyConnections.WithCause(err)
+
+	case "40001": // serialization_failure
+		return errors.SerializationFailure.WithCause(err)
+
+	case "40P01": // deadlock_detected
+		return errors.DeadlockDetected.WithCause(err)
+
+	case "57014": // query_canceled
+		if strings.Contains(pgErr.Message, "statement timeout") {
+			return errors.StatementTimeout.WithCause(err)
+		}
+		return errors.QueryCanceled.WithCause(err)
+	}
+
+	return errors.InvalidDatabaseError(err)
+}
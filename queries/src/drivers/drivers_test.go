@@ -128,7 +128,8 @@ func TestMySQLDatabaseError(t *testing.T) {
 		{"InvalidTable", &mysql.MySQLError{Number: 1146}, errors.InvalidTable},
 		{"DivisionByZero", &mysql.MySQLError{Number: 1365}, errors.DivisionByZero},
 		{"TypeMismatch", &mysql.MySQLError{Number: 1264}, errors.DBTypeMismatch},
-		{"QueryTimeout", &mysql.MySQLError{Number: 1205}, errors.QueryTimeout},
+		{"LockTimeout", &mysql.MySQLError{Number: 1205}, errors.LockTimeout},
+		{"StatementTimeout", &mysql.MySQLError{Number: 3024}, errors.StatementTimeout},
 		{"Deadlock", &mysql.MySQLError{Number: 1213}, errors.DeadlockDetected},
 		{"ConnectionFailed", &mysql.MySQLError{Number: 2002}, errors.ConnectionFailed},
 		{"ConnectionLost", &mysql.MySQLError{Number: 2013}, errors.ConnectionLost},
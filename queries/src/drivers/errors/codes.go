@@ -105,6 +105,8 @@ const (
 	CodeDiskFull             DBCode = "E508"
 	CodePermissionDenied     DBCode = "E509"
 	CodeSerializationFailure DBCode = "E510"
+	CodeStatementTimeout     DBCode = "E511"
+	CodeLockTimeout          DBCode = "E512"
 
 	// Planning / Execution
 	CodeAmbiguousColumn        DBCode = "E601"
@@ -148,6 +150,8 @@ var databaseErrors = map[DBCode]DatabaseError{
 	CodeDiskFull:                 DiskFull,
 	CodePermissionDenied:         PermissionDenied,
 	CodeSerializationFailure:     SerializationFailure,
+	CodeStatementTimeout:         StatementTimeout,
+	CodeLockTimeout:              LockTimeout,
 	CodeConnectionClosed:         ConnectionClosed,
 	CodeConnectionLost:           ConnectionLost,
 	CodeConnectionFailed:         ConnectionFailed,
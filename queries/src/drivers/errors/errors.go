@@ -110,6 +110,8 @@ var (
 	DiskFull             DatabaseError = dbError(CodeDiskFull, "Disk full or write failure", IOError)
 	PermissionDenied     DatabaseError = dbError(CodePermissionDenied, "Permission denied for operation")
 	SerializationFailure DatabaseError = dbError(CodeSerializationFailure, "Could not serialize access due to concurrent update")
+	StatementTimeout     DatabaseError = dbError(CodeStatementTimeout, "Statement exceeded its timeout", QueryTimeout)
+	LockTimeout          DatabaseError = dbError(CodeLockTimeout, "Timed out waiting for a lock", QueryTimeout)
 
 	// Planning / Execution
 	AmbiguousColumn        DatabaseError = dbError(CodeAmbiguousColumn, "Ambiguous column reference")
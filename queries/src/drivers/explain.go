@@ -0,0 +1,463 @@
+package drivers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+)
+
+// ExplainOptions are the options used to explain a query.
+type ExplainOptions struct {
+	// Analyze executes the query to include the actual row counts in the plan.
+	//
+	// This is supported by PostgreSQL and MariaDB and ignored for other databases.
+	// Keep in mind that the query is actually executed.
+	Analyze bool
+}
+
+// PlanNode is a single step in a [QueryPlan].
+//
+// It is a driver-neutral representation of the steps the database takes to execute a query.
+type PlanNode struct {
+	// Operation is the type of the step, i.e. "Seq Scan" on PostgreSQL,
+	// the access type ("ALL", "ref", ...) on MySQL or "SCAN" / "SEARCH" on SQLite.
+	Operation string
+
+	// Table is the table the step reads from, if any.
+	Table string
+
+	// Index is the index used by the step, if any.
+	Index string
+
+	// FullScan is true if the step reads the whole table without the use of an index.
+	FullScan bool
+
+	// Rows is the number of rows the database estimates to be read.
+	Rows float64
+
+	// ActualRows is the number of rows read, only set if [ExplainOptions.Analyze] is true.
+	ActualRows float64
+
+	// Cost is the estimated cost of the step, the unit depends on the database.
+	Cost float64
+
+	// Detail contains any additional information about the step,
+	// i.e. the filter condition or the SQLite plan line.
+	Detail string
+
+	Children []*PlanNode
+}
+
+// QueryPlan is the result of explaining a query.
+type QueryPlan struct {
+	// Driver is the name of the driver used to explain the query.
+	Driver string
+
+	// Query is the query which was explained.
+	Query string
+	Args  []any
+
+	// Raw is the unparsed output of the EXPLAIN statement.
+	Raw string
+
+	// Nodes are the top-level steps of the plan.
+	Nodes []*PlanNode
+}
+
+// Walk calls fn for each node in the plan, depth first.
+//
+// If fn returns false, the walk is stopped.
+func (p *QueryPlan) Walk(fn func(node *PlanNode, depth int) bool) {
+	var walk func(nodes []*PlanNode, depth int) bool
+	walk = func(nodes []*PlanNode, depth int) bool {
+		for _, node := range nodes {
+			if !fn(node, depth) || !walk(node.Children, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(p.Nodes, 0)
+}
+
+// FullTableScans returns all nodes in the plan which read a whole table without the use of an index.
+func (p *QueryPlan) FullTableScans() []*PlanNode {
+	var nodes = make([]*PlanNode, 0)
+	p.Walk(func(node *PlanNode, depth int) bool {
+		if node.FullScan {
+			nodes = append(nodes, node)
+		}
+		return true
+	})
+	return nodes
+}
+
+// HasFullTableScan reports whether any node in the plan reads a whole table without the use of an index.
+func (p *QueryPlan) HasFullTableScan() bool {
+	return len(p.FullTableScans()) > 0
+}
+
+func (p *QueryPlan) String() string {
+	return p.Raw
+}
+
+// Explain runs the database specific EXPLAIN statement for the query and parses the result into a [QueryPlan].
+//
+// The query should already be rebound to the placeholders of the database.
+func Explain(ctx context.Context, db DB, query string, args []any, opts ExplainOptions) (*QueryPlan, error) {
+	var d, ok = Retrieve(db.Driver())
+	if !ok {
+		return nil, errors.UnknownDriver.Wrapf(
+			"no driver registered for %T", db.Driver(),
+		)
+	}
+
+	if d.ExplainPlan == nil {
+		return nil, errors.NotImplemented.Wrapf(
+			"explain plan not implemented for driver %q", d.Name,
+		)
+	}
+
+	var plan, err = d.ExplainPlan(ctx, db, query, args, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Driver = d.Name
+	plan.Query = query
+	plan.Args = args
+	return plan, nil
+}
+
+// explainJSON executes the explain statement and returns the JSON document from the first column.
+func explainJSON(ctx context.Context, q DB, explainQuery string, args []any) (string, error) {
+	var rows, err = q.QueryContext(ctx, explainQuery, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var sb strings.Builder
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return "", err
+		}
+		sb.WriteString(result)
+	}
+	return sb.String(), rows.Err()
+}
+
+func explainPlanPostgres(ctx context.Context, q DB, query string, args []any, opts ExplainOptions) (*QueryPlan, error) {
+	var explain = "EXPLAIN (FORMAT JSON) "
+	if opts.Analyze {
+		explain = "EXPLAIN (ANALYZE, FORMAT JSON) "
+	}
+
+	var raw, err = explainJSON(ctx, q, explain+query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePostgresPlan(raw)
+}
+
+func explainPlanMySQL(ctx context.Context, q DB, query string, args []any, opts ExplainOptions) (*QueryPlan, error) {
+	var raw, err = explainJSON(ctx, q, "EXPLAIN FORMAT=JSON "+query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseMySQLPlan(raw)
+}
+
+func explainPlanMariaDB(ctx context.Context, q DB, query string, args []any, opts ExplainOptions) (*QueryPlan, error) {
+	var explain = "EXPLAIN FORMAT=JSON "
+	if opts.Analyze {
+		explain = "ANALYZE FORMAT=JSON "
+	}
+
+	var raw, err = explainJSON(ctx, q, explain+query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseMySQLPlan(raw)
+}
+
+func explainPlanSQLite(ctx context.Context, q DB, query string, args []any, opts ExplainOptions) (*QueryPlan, error) {
+	var rows, err = q.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		plan  = &QueryPlan{}
+		nodes = make(map[int64]*PlanNode)
+		depth = make(map[int64]int)
+		raw   strings.Builder
+	)
+
+	raw.WriteString("QUERY PLAN")
+	for rows.Next() {
+		var (
+			id, parent, notUsed int64
+			detail              string
+		)
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			return nil, err
+		}
+
+		var node = parseSQLitePlanLine(detail)
+		nodes[id] = node
+
+		if p, ok := nodes[parent]; ok {
+			p.Children = append(p.Children, node)
+			depth[id] = depth[parent] + 1
+		} else {
+			plan.Nodes = append(plan.Nodes, node)
+		}
+
+		raw.WriteString("\n")
+		raw.WriteString(strings.Repeat("  ", depth[id]))
+		raw.WriteString(detail)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	plan.Raw = raw.String()
+	return plan, nil
+}
+
+// parseSQLitePlanLine parses a line of the EXPLAIN QUERY PLAN output,
+// i.e. "SCAN users" or "SEARCH users USING INDEX users_email_idx (email=?)".
+func parseSQLitePlanLine(detail string) *PlanNode {
+	var node = &PlanNode{Detail: detail}
+	var parts = strings.Fields(detail)
+	if len(parts) == 0 {
+		return node
+	}
+
+	node.Operation = parts[0]
+	if node.Operation != "SCAN" && node.Operation != "SEARCH" || len(parts) < 2 {
+		return node
+	}
+
+	// older versions of SQLite use "SCAN TABLE users"
+	var tableIdx = 1
+	if parts[1] == "TABLE" && len(parts) > 2 {
+		tableIdx = 2
+	}
+
+	var table = parts[tableIdx]
+	if table == "CONSTANT" || strings.HasPrefix(table, "(") {
+		return node
+	}
+
+	node.Table = table
+	if idx := strings.Index(detail, " USING "); idx != -1 {
+		var using = strings.Fields(detail[idx+len(" USING "):])
+		for i, word := range using {
+			if word == "INDEX" && i+1 < len(using) {
+				node.Index = using[i+1]
+				break
+			}
+			if word == "KEY" {
+				node.Index = "PRIMARY KEY"
+				break
+			}
+		}
+	}
+
+	node.FullScan = node.Operation == "SCAN" && node.Index == ""
+	return node
+}
+
+type postgresPlanNode struct {
+	NodeType     string              `json:"Node Type"`
+	RelationName string              `json:"Relation Name"`
+	IndexName    string              `json:"Index Name"`
+	PlanRows     float64             `json:"Plan Rows"`
+	ActualRows   float64             `json:"Actual Rows"`
+	TotalCost    float64             `json:"Total Cost"`
+	Filter       string              `json:"Filter"`
+	IndexCond    string              `json:"Index Cond"`
+	Plans        []*postgresPlanNode `json:"Plans"`
+}
+
+func (n *postgresPlanNode) node() *PlanNode {
+	var node = &PlanNode{
+		Operation:  n.NodeType,
+		Table:      n.RelationName,
+		Index:      n.IndexName,
+		FullScan:   n.NodeType == "Seq Scan",
+		Rows:       n.PlanRows,
+		ActualRows: n.ActualRows,
+		Cost:       n.TotalCost,
+		Detail:     n.Filter,
+	}
+
+	if node.Detail == "" {
+		node.Detail = n.IndexCond
+	}
+
+	for _, child := range n.Plans {
+		node.Children = append(node.Children, child.node())
+	}
+	return node
+}
+
+// ParsePostgresPlan parses the output of EXPLAIN (FORMAT JSON) on PostgreSQL.
+func ParsePostgresPlan(raw string) (*QueryPlan, error) {
+	var result []struct {
+		Plan *postgresPlanNode `json:"Plan"`
+	}
+
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return nil, errors.TypeMismatch.Wrapf(
+			"failed to parse PostgreSQL query plan: %v", err,
+		)
+	}
+
+	var plan = &QueryPlan{Raw: raw}
+	for _, r := range result {
+		if r.Plan != nil {
+			plan.Nodes = append(plan.Nodes, r.Plan.node())
+		}
+	}
+	return plan, nil
+}
+
+// mysqlPlanOperations are the objects in the MySQL / MariaDB JSON plan
+// which are represented as a separate node in the [QueryPlan].
+var mysqlPlanOperations = []string{
+	"ordering_operation",
+	"grouping_operation",
+	"duplicates_removal",
+	"union_result",
+	"filesort",
+	"temporary_table",
+	"materialized_from_subquery",
+}
+
+// ParseMySQLPlan parses the output of EXPLAIN FORMAT=JSON on MySQL and MariaDB.
+func ParseMySQLPlan(raw string) (*QueryPlan, error) {
+	var result map[string]any
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return nil, errors.TypeMismatch.Wrapf(
+			"failed to parse MySQL query plan: %v", err,
+		)
+	}
+
+	return &QueryPlan{
+		Raw:   raw,
+		Nodes: mysqlPlanNodes("", result),
+	}, nil
+}
+
+func mysqlPlanNodes(name string, value any) []*PlanNode {
+	switch v := value.(type) {
+	case []any:
+		var nodes = make([]*PlanNode, 0, len(v))
+		for _, item := range v {
+			nodes = append(nodes, mysqlPlanNodes("", item)...)
+		}
+		return nodes
+
+	case map[string]any:
+		if name == "table" {
+			return []*PlanNode{mysqlTableNode(v)}
+		}
+
+		var keys = make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		var children = make([]*PlanNode, 0)
+		for _, key := range keys {
+			children = append(children, mysqlPlanNodes(key, v[key])...)
+		}
+
+		if slices.Contains(mysqlPlanOperations, name) {
+			return []*PlanNode{{
+				Operation: name,
+				Children:  children,
+			}}
+		}
+		return children
+	}
+	return nil
+}
+
+func mysqlTableNode(table map[string]any) *PlanNode {
+	var node = &PlanNode{
+		Table:     mysqlString(table["table_name"]),
+		Operation: mysqlString(table["access_type"]),
+		Index:     mysqlString(table["key"]),
+		Detail:    mysqlString(table["attached_condition"]),
+	}
+
+	node.FullScan = node.Operation == "ALL"
+
+	if rows, ok := table["rows_examined_per_scan"]; ok {
+		node.Rows = mysqlFloat(rows)
+	} else {
+		node.Rows = mysqlFloat(table["rows"])
+	}
+
+	// MariaDB ANALYZE FORMAT=JSON
+	if rows, ok := table["r_rows"]; ok {
+		node.ActualRows = mysqlFloat(rows)
+	}
+
+	if costInfo, ok := table["cost_info"].(map[string]any); ok {
+		node.Cost = mysqlFloat(costInfo["prefix_cost"])
+	} else {
+		node.Cost = mysqlFloat(table["cost"])
+	}
+
+	var keys = make([]string, 0, len(table))
+	for key, value := range table {
+		if _, ok := value.(map[string]any); ok && key != "cost_info" {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		node.Children = append(node.Children, mysqlPlanNodes(key, table[key])...)
+	}
+
+	return node
+}
+
+func mysqlString(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func mysqlFloat(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case string:
+		var f, _ = strconv.ParseFloat(n, 64)
+		return f
+	}
+	return 0
+}
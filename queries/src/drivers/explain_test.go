@@ -0,0 +1,113 @@
+package drivers_test
+
+import (
+	"testing"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+)
+
+const postgresPlan = `[
+  {
+    "Plan": {
+      "Node Type": "Nested Loop",
+      "Total Cost": 35.5,
+      "Plan Rows": 10,
+      "Plans": [
+        {
+          "Node Type": "Seq Scan",
+          "Relation Name": "posts",
+          "Total Cost": 12.5,
+          "Plan Rows": 10,
+          "Filter": "(published = true)"
+        },
+        {
+          "Node Type": "Index Scan",
+          "Relation Name": "users",
+          "Index Name": "users_pkey",
+          "Total Cost": 2.3,
+          "Plan Rows": 1,
+          "Index Cond": "(id = posts.author_id)"
+        }
+      ]
+    }
+  }
+]`
+
+const mysqlPlan = `{
+  "query_block": {
+    "select_id": 1,
+    "cost_info": {"query_cost": "4.75"},
+    "ordering_operation": {
+      "using_filesort": true,
+      "nested_loop": [
+        {
+          "table": {
+            "table_name": "posts",
+            "access_type": "ALL",
+            "rows_examined_per_scan": 10,
+            "cost_info": {"prefix_cost": "1.25"},
+            "attached_condition": "(posts.published = 1)"
+          }
+        },
+        {
+          "table": {
+            "table_name": "users",
+            "access_type": "eq_ref",
+            "key": "PRIMARY",
+            "rows_examined_per_scan": 1,
+            "cost_info": {"prefix_cost": "4.75"}
+          }
+        }
+      ]
+    }
+  }
+}`
+
+func TestParsePostgresPlan(t *testing.T) {
+	var plan, err = drivers.ParsePostgresPlan(postgresPlan)
+	if err != nil {
+		t.Fatalf("failed to parse plan: %v", err)
+	}
+
+	if len(plan.Nodes) != 1 || len(plan.Nodes[0].Children) != 2 {
+		t.Fatalf("expected 1 root node with 2 children, got %+v", plan.Nodes)
+	}
+
+	var scans = plan.FullTableScans()
+	if len(scans) != 1 || scans[0].Table != "posts" {
+		t.Fatalf("expected a full table scan on posts, got %+v", scans)
+	}
+
+	var index = plan.Nodes[0].Children[1]
+	if index.Index != "users_pkey" || index.FullScan {
+		t.Errorf("expected index scan on users_pkey, got %+v", index)
+	}
+}
+
+func TestParseMySQLPlan(t *testing.T) {
+	var plan, err = drivers.ParseMySQLPlan(mysqlPlan)
+	if err != nil {
+		t.Fatalf("failed to parse plan: %v", err)
+	}
+
+	if len(plan.Nodes) != 1 || plan.Nodes[0].Operation != "ordering_operation" {
+		t.Fatalf("expected ordering operation as root node, got %+v", plan.Nodes)
+	}
+
+	var tables []string
+	plan.Walk(func(node *drivers.PlanNode, depth int) bool {
+		if node.Table != "" {
+			tables = append(tables, node.Table)
+		}
+		return true
+	})
+
+	if len(tables) != 2 || tables[0] != "posts" || tables[1] != "users" {
+		t.Fatalf("expected tables [posts users], got %v", tables)
+	}
+
+	var scans = plan.FullTableScans()
+	if len(scans) != 1 || scans[0].Table != "posts" || scans[0].Rows != 10 || scans[0].Cost != 1.25 {
+		t.Fatalf("expected a full table scan on posts, got %+v", scans)
+	}
+}
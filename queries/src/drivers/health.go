@@ -0,0 +1,62 @@
+package drivers
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultPingTimeout is the timeout used by [Ping] and [CheckHealth]
+// when no (or a non-positive) timeout is provided.
+var DefaultPingTimeout = 5 * time.Second
+
+// Health describes the health of a database connection.
+type Health struct {
+	// The name of the driver used by the database.
+	Driver string `json:"driver"`
+
+	// Healthy is true if the database could be pinged within the timeout.
+	Healthy bool `json:"healthy"`
+
+	// The time it took to ping the database.
+	Latency time.Duration `json:"latency"`
+
+	// The error returned when pinging the database, if any.
+	Error error `json:"-"`
+
+	// The statistics of the connection pool, nil if
+	// the database does not manage a pool of connections.
+	Stats *PoolStats `json:"stats,omitempty"`
+}
+
+// Ping pings the database, the ping fails if it does not complete within the timeout.
+func Ping(ctx context.Context, db Database, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultPingTimeout
+	}
+
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return db.Ping(ctx)
+}
+
+// CheckHealth pings the database with the given timeout and
+// returns the health of the database along with the pool statistics.
+func CheckHealth(ctx context.Context, db Database, timeout time.Duration) Health {
+	var health Health
+	if d, ok := Retrieve(db.Driver()); ok {
+		health.Driver = d.Name
+	}
+
+	var start = time.Now()
+	health.Error = Ping(ctx, db, timeout)
+	health.Latency = time.Since(start)
+	health.Healthy = health.Error == nil
+
+	if stats, err := Stats(db); err == nil {
+		health.Stats = &stats
+	}
+
+	return health
+}
@@ -0,0 +1,124 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Nigel2392/go-django/src/core/metrics"
+)
+
+var databaseNameContextKey = dbContextKey{"db.name"}
+
+var (
+	queriesTotal = metrics.Default.Counter(metrics.Opts{
+		Name:   "django_db_queries_total",
+		Help:   "The number of statements executed, by database and status.",
+		Labels: []string{"database", "driver", "status"},
+	})
+	queryDuration = metrics.Default.Histogram(metrics.Opts{
+		Name:   "django_db_query_duration_seconds",
+		Help:   "The time taken to execute a statement, by database.",
+		Labels: []string{"database", "driver"},
+	})
+)
+
+// ContextWithDatabaseName returns a context in which statements are recorded under the name of the database.
+func ContextWithDatabaseName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, databaseNameContextKey, name)
+}
+
+// ContextDatabaseName returns the database name stored in the context.
+func ContextDatabaseName(ctx context.Context) (string, bool) {
+	var name, ok = ctx.Value(databaseNameContextKey).(string)
+	return name, ok
+}
+
+// recordQueryMetrics records the statement in the query metrics,
+// the database is the name stored in the context or the driver name if none is stored.
+func recordQueryMetrics(ctx context.Context, driver string, flags QueryFlag, timeTaken time.Duration, err error) {
+	if flags&(Q_QUERY|Q_QUERYROW|Q_EXEC) == 0 {
+		return
+	}
+
+	var database, ok = ContextDatabaseName(ctx)
+	if !ok {
+		database = driver
+	}
+
+	var status = "ok"
+	if err != nil {
+		status = "error"
+	}
+
+	queriesTotal.Inc(database, driver, status)
+	queryDuration.Observe(timeTaken.Seconds(), database, driver)
+}
+
+// WithName wraps the database so that its statements are recorded under the name in the query metrics,
+// this also applies to the statements of transactions started on the database.
+//
+// The name is usually the key of the database in the settings, i.e. "DATABASE".
+func WithName(db Database, name string) Database {
+	if n, ok := withoutPoolInterfaces(db).(*namedDatabase); ok {
+		db = n.Database
+	}
+	return withPoolInterfaces(&namedDatabase{Database: db, name: name})
+}
+
+func withDatabaseName(ctx context.Context, name string) context.Context {
+	if _, ok := ContextDatabaseName(ctx); ok {
+		return ctx
+	}
+	return ContextWithDatabaseName(ctx, name)
+}
+
+type namedDatabase struct {
+	Database
+	name string
+}
+
+func (n *namedDatabase) database() Database {
+	return n.Database
+}
+
+func (n *namedDatabase) Unwrap() any {
+	return n.Database.Unwrap()
+}
+
+func (n *namedDatabase) QueryContext(ctx context.Context, query string, args ...any) (SQLRows, error) {
+	return n.Database.QueryContext(withDatabaseName(ctx, n.name), query, args...)
+}
+
+func (n *namedDatabase) QueryRowContext(ctx context.Context, query string, args ...any) SQLRow {
+	return n.Database.QueryRowContext(withDatabaseName(ctx, n.name), query, args...)
+}
+
+func (n *namedDatabase) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return n.Database.ExecContext(withDatabaseName(ctx, n.name), query, args...)
+}
+
+func (n *namedDatabase) Begin(ctx context.Context) (Transaction, error) {
+	var tx, err = n.Database.Begin(withDatabaseName(ctx, n.name))
+	if err != nil {
+		return nil, err
+	}
+	return &namedTransaction{Transaction: tx, name: n.name}, nil
+}
+
+type namedTransaction struct {
+	Transaction
+	name string
+}
+
+func (n *namedTransaction) QueryContext(ctx context.Context, query string, args ...any) (SQLRows, error) {
+	return n.Transaction.QueryContext(withDatabaseName(ctx, n.name), query, args...)
+}
+
+func (n *namedTransaction) QueryRowContext(ctx context.Context, query string, args ...any) SQLRow {
+	return n.Transaction.QueryRowContext(withDatabaseName(ctx, n.name), query, args...)
+}
+
+func (n *namedTransaction) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return n.Transaction.ExecContext(withDatabaseName(ctx, n.name), query, args...)
+}
@@ -0,0 +1,270 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolStats holds the statistics of a database connection pool.
+//
+// It unifies the statistics of [sql.DBStats] and [pgxpool.Stat],
+// fields which are not supported by the underlying pool are left zero.
+type PoolStats struct {
+	// The maximum number of open connections to the database.
+	MaxOpenConnections int `json:"max_open_connections"`
+
+	// The number of established connections, both in use and idle.
+	OpenConnections int `json:"open_connections"`
+
+	// The number of connections currently in use.
+	InUse int `json:"in_use"`
+
+	// The number of idle connections.
+	Idle int `json:"idle"`
+
+	// The total number of times a caller had to wait for a connection.
+	WaitCount int64 `json:"wait_count"`
+
+	// The total time spent waiting for a connection.
+	WaitDuration time.Duration `json:"wait_duration"`
+
+	// The total number of connections closed due to the idle time limits.
+	MaxIdleClosed int64 `json:"max_idle_closed"`
+
+	// The total number of connections closed due to the maximum lifetime.
+	MaxLifetimeClosed int64 `json:"max_lifetime_closed"`
+}
+
+// PoolConfig configures the sizing and the lifetime of the connections in a connection pool.
+//
+// Zero values leave the current configuration of the pool unchanged.
+type PoolConfig struct {
+	// The maximum number of open connections to the database.
+	MaxOpenConns int
+
+	// The maximum number of idle connections kept in the pool.
+	//
+	// This is only supported by [database/sql] based drivers.
+	MaxIdleConns int
+
+	// The minimum number of connections kept open in the pool.
+	//
+	// This is only supported by the postgres (pgx) driver.
+	MinConns int
+
+	// The maximum amount of time a connection may be reused.
+	ConnMaxLifetime time.Duration
+
+	// The maximum amount of time a connection may be idle before being closed.
+	ConnMaxIdleTime time.Duration
+
+	// The interval at which idle connections are checked for their health.
+	//
+	// This is only supported by the postgres (pgx) driver.
+	HealthCheckPeriod time.Duration
+}
+
+func (c PoolConfig) applySQL(db *sql.DB) {
+	if c.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(c.MaxOpenConns)
+	}
+	if c.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	if c.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	}
+	if c.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+	}
+}
+
+func (c PoolConfig) applyPGX(cnf *pgxpool.Config) {
+	if c.MaxOpenConns > 0 {
+		cnf.MaxConns = int32(c.MaxOpenConns)
+	}
+	if c.MinConns > 0 {
+		cnf.MinConns = int32(c.MinConns)
+	}
+	if c.ConnMaxLifetime > 0 {
+		cnf.MaxConnLifetime = c.ConnMaxLifetime
+	}
+	if c.ConnMaxIdleTime > 0 {
+		cnf.MaxConnIdleTime = c.ConnMaxIdleTime
+	}
+	if c.HealthCheckPeriod > 0 {
+		cnf.HealthCheckPeriod = c.HealthCheckPeriod
+	}
+}
+
+// Pool is implemented by databases which manage a pool of connections.
+//
+// Both the [database/sql] and the pgx based databases returned by [Open] implement this interface.
+type Pool interface {
+	// PoolStats returns the current statistics of the connection pool.
+	PoolStats() PoolStats
+
+	// ConfigurePool applies the configuration to the connection pool.
+	ConfigurePool(ctx context.Context, config PoolConfig) error
+}
+
+// Acquirer is implemented by databases which can acquire a dedicated connection from their pool.
+//
+// The pgx based database returned by [Open] implements this interface.
+type Acquirer interface {
+	// Acquire acquires a connection from the pool, it is released when the returned database is closed.
+	Acquire(ctx context.Context) (Database, error)
+}
+
+// withPoolInterfaces extends the wrapping database with the [Pool] and [Acquirer]
+// interfaces implemented by the database it wraps, so they can still be type asserted.
+//
+// Connections acquired through the wrapping database are not wrapped.
+func withPoolInterfaces(wrapper Database) Database {
+	var pool, isPool = unwrapDatabase(wrapper).(Pool)
+	if !isPool {
+		return wrapper
+	}
+	if acquirer, ok := pool.(Acquirer); ok {
+		return &acquirerPoolDatabase{Database: wrapper, Pool: pool, Acquirer: acquirer}
+	}
+	return &poolDatabase{Database: wrapper, Pool: pool}
+}
+
+// withoutPoolInterfaces returns the wrapping database extended by [withPoolInterfaces].
+func withoutPoolInterfaces(db Database) Database {
+	switch db := db.(type) {
+	case *poolDatabase:
+		return db.Database
+	case *acquirerPoolDatabase:
+		return db.Database
+	}
+	return db
+}
+
+type poolDatabase struct {
+	Database
+	Pool
+}
+
+func (p *poolDatabase) database() Database {
+	return p.Database
+}
+
+type acquirerPoolDatabase struct {
+	Database
+	Pool
+	Acquirer
+}
+
+func (p *acquirerPoolDatabase) database() Database {
+	return p.Database
+}
+
+// WithPoolConfig returns an [OpenOption] which applies the pool configuration
+// to the database connection pool when it is opened.
+//
+// The pgx pool cannot be resized in place, it is recreated with the configuration
+// after all options were applied. Options which keep a reference to the
+// *pgxpool.Pool should retrieve it with [Database.Unwrap] after opening instead.
+func WithPoolConfig(config PoolConfig) OpenOption {
+	return func(driverName string, db any) error {
+		switch db := db.(type) {
+		case *sql.DB:
+			config.applySQL(db)
+			return nil
+		case *pgxpool.Pool:
+			return &pgxPoolConfig{config: config}
+		}
+		return errors.NotImplemented
+	}
+}
+
+// pgxPoolConfig is returned by the option of [WithPoolConfig] for a pgx pool,
+// [OpenPGX] then recreates the pool with the configuration.
+type pgxPoolConfig struct {
+	config PoolConfig
+}
+
+func (c *pgxPoolConfig) Error() string {
+	return "the pgx pool must be recreated to apply the pool configuration"
+}
+
+// Stats returns the statistics of the database connection pool.
+//
+// If the database does not manage a pool of connections, an [errors.NotImplemented] error is returned.
+func Stats(db DB) (PoolStats, error) {
+	db = unwrapDatabase(db)
+
+	if pool, ok := db.(Pool); ok {
+		return pool.PoolStats(), nil
+	}
+
+	switch conn := db.Unwrap().(type) {
+	case *sql.DB:
+		return sqlPoolStats(conn), nil
+	case *pgxpool.Pool:
+		return pgxPoolStats(conn), nil
+	}
+
+	return PoolStats{}, errors.NotImplemented.WithCause(fmt.Errorf(
+		"database %T does not manage a connection pool", db,
+	))
+}
+
+// ConfigurePool applies the pool configuration to the database connection pool.
+//
+// For the postgres (pgx) driver the pool cannot be resized in place,
+// a new pool is created with the updated configuration and swapped in.
+// The old pool is closed in the background once its acquired connections are released.
+//
+// If the database does not manage a pool of connections, an [errors.NotImplemented] error is returned.
+func ConfigurePool(ctx context.Context, db DB, config PoolConfig) error {
+	db = unwrapDatabase(db)
+
+	if pool, ok := db.(Pool); ok {
+		return pool.ConfigurePool(ctx, config)
+	}
+
+	if conn, ok := db.Unwrap().(*sql.DB); ok {
+		config.applySQL(conn)
+		return nil
+	}
+
+	return errors.NotImplemented.WithCause(fmt.Errorf(
+		"database %T does not manage a connection pool", db,
+	))
+}
+
+func sqlPoolStats(db *sql.DB) PoolStats {
+	var stats = db.Stats()
+	return PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration,
+		MaxIdleClosed:      stats.MaxIdleClosed + stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+}
+
+func pgxPoolStats(pool *pgxpool.Pool) PoolStats {
+	var stats = pool.Stat()
+	return PoolStats{
+		MaxOpenConnections: int(stats.MaxConns()),
+		OpenConnections:    int(stats.TotalConns()),
+		InUse:              int(stats.AcquiredConns()),
+		Idle:               int(stats.IdleConns()),
+		WaitCount:          stats.EmptyAcquireCount(),
+		WaitDuration:       stats.EmptyAcquireWaitTime(),
+		MaxIdleClosed:      stats.MaxIdleDestroyCount(),
+		MaxLifetimeClosed:  stats.MaxLifetimeDestroyCount(),
+	}
+}
@@ -0,0 +1,205 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"math/rand/v2"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// TransientErrors are the database errors which are considered transient by [IsTransient].
+//
+// These errors occur before a statement is applied, or cause the statement to be rolled back,
+// retrying the statement is thus safe. For MySQL and MariaDB a deadlock (1213) rolls back the
+// transaction and a lock wait timeout (1205) rolls back the statement.
+//
+// [errors.ConnectionLost] is deliberately not included, the statement might
+// have been applied before the connection to the database server was lost.
+var TransientErrors = []errors.DatabaseError{
+	errors.ConnectionFailed,
+	errors.ConnectionClosed,
+	errors.TooManyConnections,
+	errors.DeadlockDetected,
+	errors.TransactionDeadlock,
+	errors.SerializationFailure,
+	errors.LockTimeout,
+}
+
+// IsTransient reports whether the error is a transient database error,
+// meaning the operation which returned the error can safely be retried.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var dbErr errors.DatabaseError
+	if !errors.As(err, &dbErr) {
+		return false
+	}
+
+	var code = dbErr.Code()
+	for _, transient := range TransientErrors {
+		if transient.Code() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryConfig configures how operations are retried with [Retry] and [WithRetry].
+type RetryConfig struct {
+	// The maximum number of attempts, including the first attempt.
+	MaxAttempts int
+
+	// The backoff before the second attempt, it is multiplied by the Multiplier for each next attempt.
+	InitialBackoff time.Duration
+
+	// The maximum backoff between two attempts.
+	MaxBackoff time.Duration
+
+	// The factor the backoff is multiplied by after each attempt.
+	Multiplier float64
+
+	// Retryable reports whether an error should be retried, it defaults to [IsTransient].
+	Retryable func(err error) bool
+}
+
+// DefaultRetryConfig is the configuration used when a zero [RetryConfig] is provided.
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts:    3,
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Multiplier:     2,
+	Retryable:      IsTransient,
+}
+
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultRetryConfig.MaxAttempts
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = DefaultRetryConfig.InitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultRetryConfig.MaxBackoff
+	}
+	if c.Multiplier < 1 {
+		c.Multiplier = DefaultRetryConfig.Multiplier
+	}
+	if c.Retryable == nil {
+		c.Retryable = IsTransient
+	}
+	return c
+}
+
+// backoff returns the backoff before the given (zero based) retry,
+// a random jitter of up to 20% is subtracted to spread out retries.
+func (c RetryConfig) backoff(retry int) time.Duration {
+	var backoff = float64(c.InitialBackoff)
+	for i := 0; i < retry; i++ {
+		backoff *= c.Multiplier
+		if backoff >= float64(c.MaxBackoff) {
+			backoff = float64(c.MaxBackoff)
+			break
+		}
+	}
+	return time.Duration(backoff - backoff*0.2*rand.Float64())
+}
+
+// Retry calls fn until it succeeds, returns an error which is not retryable or the maximum number of attempts is reached.
+//
+// The backoff between attempts grows exponentially, waiting stops early if the context is canceled.
+func Retry(ctx context.Context, config RetryConfig, fn func(ctx context.Context) error) error {
+	var _, err = RetryValue(ctx, config, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// RetryValue is like [Retry] but returns the value returned by the last call to fn.
+func RetryValue[T any](ctx context.Context, config RetryConfig, fn func(ctx context.Context) (T, error)) (T, error) {
+	config = config.withDefaults()
+
+	var (
+		value T
+		err   error
+	)
+	for attempt := 0; attempt < config.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			var timer = time.NewTimer(config.backoff(attempt - 1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return value, errors.Join(err, ctx.Err())
+			case <-timer.C:
+			}
+		}
+
+		value, err = fn(ctx)
+		if err == nil || !config.Retryable(err) {
+			return value, err
+		}
+	}
+	return value, err
+}
+
+// WithRetry wraps the database so that pings, starting transactions and
+// statements executed outside of a transaction are retried when they fail with a retryable error.
+//
+// Statements executed inside of a transaction are never retried,
+// the transaction as a whole should be retried instead.
+func WithRetry(db Database, config RetryConfig) Database {
+	if r, ok := withoutPoolInterfaces(db).(*retryDatabase); ok {
+		db = r.Database
+	}
+	return withPoolInterfaces(&retryDatabase{Database: db, config: config.withDefaults()})
+}
+
+type retryDatabase struct {
+	Database
+	config RetryConfig
+}
+
+func (r *retryDatabase) database() Database {
+	return r.Database
+}
+
+func (r *retryDatabase) Unwrap() any {
+	return r.Database.Unwrap()
+}
+
+func (r *retryDatabase) QueryContext(ctx context.Context, query string, args ...any) (SQLRows, error) {
+	return RetryValue(ctx, r.config, func(ctx context.Context) (SQLRows, error) {
+		return r.Database.QueryContext(ctx, query, args...)
+	})
+}
+
+func (r *retryDatabase) QueryRowContext(ctx context.Context, query string, args ...any) SQLRow {
+	var row, _ = RetryValue(ctx, r.config, func(ctx context.Context) (SQLRow, error) {
+		var row = r.Database.QueryRowContext(ctx, query, args...)
+		return row, row.Err()
+	})
+	return row
+}
+
+func (r *retryDatabase) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return RetryValue(ctx, r.config, func(ctx context.Context) (sql.Result, error) {
+		return r.Database.ExecContext(ctx, query, args...)
+	})
+}
+
+func (r *retryDatabase) Ping(ctx context.Context) error {
+	return Retry(ctx, r.config, r.Database.Ping)
+}
+
+func (r *retryDatabase) Begin(ctx context.Context) (Transaction, error) {
+	return RetryValue(ctx, r.config, r.Database.Begin)
+}
@@ -0,0 +1,161 @@
+package drivers_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsTransient(t *testing.T) {
+	var mysqlDriver, _ = drivers.Retrieve("mysql")
+	var tests = []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"Nil", nil, false},
+		{"BadConn", driver.ErrBadConn, true},
+		{"ConnectionFailed", errors.ConnectionFailed.WithCause(errors.New(errors.CodeUnknown, "dial tcp")), true},
+		{"TooManyConnections", errors.TooManyConnections, true},
+		{"Deadlock", errors.DeadlockDetected, true},
+		{"MySQLDeadlock", mysqlDriver.BuildDatabaseError(&mysql.MySQLError{Number: 1213}), true},
+		{"MySQLLockWaitTimeout", mysqlDriver.BuildDatabaseError(&mysql.MySQLError{Number: 1205}), true},
+		{"ConnectionLost", errors.ConnectionLost, false},
+		{"UniqueViolation", errors.UniqueViolation, false},
+		{"NoRows", errors.NoRows, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := drivers.IsTransient(test.err); got != test.transient {
+				t.Errorf("expected IsTransient(%v) to be %v, got %v", test.err, test.transient, got)
+			}
+		})
+	}
+}
+
+func TestPostgresDatabaseError(t *testing.T) {
+	var tests = []struct {
+		code     string
+		expected errors.DatabaseError
+	}{
+		{"08001", errors.ConnectionFailed},
+		{"57P03", errors.ConnectionFailed},
+		{"57P01", errors.ConnectionClosed},
+		{"08006", errors.ConnectionLost},
+		{"53300", errors.TooManyConnections},
+		{"40001", errors.SerializationFailure},
+		{"40P01", errors.DeadlockDetected},
+	}
+
+	var d, _ = drivers.Retrieve("postgres")
+	for _, test := range tests {
+		t.Run(test.code, func(t *testing.T) {
+			var got = d.BuildDatabaseError(&pgconn.PgError{Code: test.code})
+			if got.Code() != test.expected.Code() {
+				t.Errorf("expected code %s, got %s", test.expected.Code(), got.Code())
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var config = drivers.RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	}
+
+	t.Run("Transient", func(t *testing.T) {
+		var attempts int
+		var err = drivers.Retry(context.Background(), config, func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.ConnectionFailed
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("Permanent", func(t *testing.T) {
+		var attempts int
+		var err = drivers.Retry(context.Background(), config, func(ctx context.Context) error {
+			attempts++
+			return errors.UniqueViolation
+		})
+		if !errors.Is(err, errors.UniqueViolation) {
+			t.Fatalf("expected unique violation, got %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("MaxAttempts", func(t *testing.T) {
+		var attempts int
+		var err = drivers.Retry(context.Background(), config, func(ctx context.Context) error {
+			attempts++
+			return errors.TooManyConnections
+		})
+		if !errors.Is(err, errors.TooManyConnections) {
+			t.Fatalf("expected too many connections, got %v", err)
+		}
+		if attempts != config.MaxAttempts {
+			t.Errorf("expected %d attempts, got %d", config.MaxAttempts, attempts)
+		}
+	})
+}
+
+func TestPoolHealth(t *testing.T) {
+	var ctx = context.Background()
+	var db, err = drivers.Open(ctx, "sqlite3", "file:drivers_pool_health?mode=memory", drivers.WithPoolConfig(drivers.PoolConfig{
+		MaxOpenConns: 4,
+	}))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	db = drivers.WithRetry(drivers.WithName(db, "default"), drivers.RetryConfig{})
+
+	if _, ok := db.(drivers.Pool); !ok {
+		t.Fatalf("expected the wrapped database to implement drivers.Pool")
+	}
+
+	var health = drivers.CheckHealth(ctx, db, time.Second)
+	if !health.Healthy {
+		t.Fatalf("expected database to be healthy, got %v", health.Error)
+	}
+
+	if health.Driver != "sqlite3" {
+		t.Errorf("expected driver sqlite3, got %q", health.Driver)
+	}
+
+	if health.Stats == nil || health.Stats.MaxOpenConnections != 4 {
+		t.Fatalf("expected max open connections to be 4, got %+v", health.Stats)
+	}
+
+	if err := drivers.ConfigurePool(ctx, db, drivers.PoolConfig{MaxOpenConns: 8}); err != nil {
+		t.Fatalf("failed to configure pool: %v", err)
+	}
+
+	stats, err := drivers.Stats(db)
+	if err != nil {
+		t.Fatalf("failed to get stats: %v", err)
+	}
+
+	if stats.MaxOpenConnections != 8 {
+		t.Errorf("expected max open connections to be 8, got %d", stats.MaxOpenConnections)
+	}
+}
@@ -0,0 +1,55 @@
+package drivers
+
+import (
+	"context"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+)
+
+// SupportsSchemas returns true if the driver of the database supports
+// creating and switching between schemas, see [CreateSchema] and [UseSchema].
+//
+// Only PostgreSQL supports schemas out of the box.
+func SupportsSchemas(db DB) bool {
+	var d, ok = Retrieve(db.Driver())
+	return ok && d.CreateSchema != nil && d.UseSchema != nil
+}
+
+// CreateSchema creates the given schema if it does not yet exist.
+func CreateSchema(ctx context.Context, db DB, schema string) error {
+	var d, err = schemaDriver(db)
+	if err != nil {
+		return err
+	}
+	return d.CreateSchema(ctx, db, schema)
+}
+
+// UseSchema makes queries executed with the given database use the schema.
+//
+// The schema is only used for the lifetime of the transaction, db should be a transaction.
+//
+// Tables which are not found in the schema are looked up in the default schema.
+func UseSchema(ctx context.Context, db DB, schema string) error {
+	var d, err = schemaDriver(db)
+	if err != nil {
+		return err
+	}
+	return d.UseSchema(ctx, db, schema)
+}
+
+func schemaDriver(db DB) (*Driver, error) {
+	var d, ok = Retrieve(db.Driver())
+	if !ok {
+		return nil, errors.UnknownDriver.Wrapf(
+			"no driver registered for %T", db.Driver(),
+		)
+	}
+
+	if d.CreateSchema == nil || d.UseSchema == nil {
+		return nil, errors.NotImplemented.Wrapf(
+			"schemas are not supported for driver %q", d.Name,
+		)
+	}
+
+	return d, nil
+}
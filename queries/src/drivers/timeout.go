@@ -0,0 +1,199 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+)
+
+var queryTimeoutContextKey = dbContextKey{"db.query.timeout"}
+
+// errStatementTimeout is the cause of the context of a statement which exceeded its timeout.
+var errStatementTimeout = errors.StatementTimeout
+
+// SLOW_QUERY_THRESHOLD is the duration after which a statement is logged as a slow query.
+//
+// Slow queries are logged as a warning with the SQL and the time taken, regardless of [LOG_SQL_QUERIES].
+// The arguments are only logged at the debug level. A zero value disables logging slow queries.
+var SLOW_QUERY_THRESHOLD time.Duration
+
+// ContextWithQueryTimeout returns a context in which each statement is limited to the given timeout.
+//
+// The timeout applies to every statement executed with the context separately, not to all of them together.
+// A timeout of zero disables the default timeout configured with [WithQueryTimeout].
+func ContextWithQueryTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutContextKey, timeout)
+}
+
+// ContextQueryTimeout returns the statement timeout stored in the context.
+func ContextQueryTimeout(ctx context.Context) (time.Duration, bool) {
+	var timeout, ok = ctx.Value(queryTimeoutContextKey).(time.Duration)
+	return timeout, ok
+}
+
+// statementTimeout applies the timeout stored in the context to a single statement.
+//
+// The returned context has a deadline and the query is rewritten with [Driver.StatementTimeout]
+// so the database server enforces the timeout as well.
+//
+// The cancel function must be called once the statement and its results are no longer used.
+func statementTimeout(ctx context.Context, d *Driver, query string) (context.Context, string, context.CancelFunc) {
+	var timeout, ok = ContextQueryTimeout(ctx)
+	if !ok || timeout <= 0 {
+		return ctx, query, func() {}
+	}
+
+	if d != nil && d.StatementTimeout != nil {
+		query = d.StatementTimeout(query, timeout)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, errStatementTimeout)
+	return ctx, query, cancel
+}
+
+// statementError converts the error of a statement to a [errors.DatabaseError].
+//
+// If the statement was canceled because it exceeded the timeout set by [statementTimeout]
+// an [errors.StatementTimeout] error is returned, the deadline of the caller's context is not a statement timeout.
+func statementError(ctx context.Context, d *Driver, err error) error {
+	if err != nil && errors.Is(err, context.DeadlineExceeded) && context.Cause(ctx) == errStatementTimeout {
+		return errors.StatementTimeout.WithCause(err)
+	}
+	return databaseError(d, err)
+}
+
+// timeoutMillis returns the timeout in milliseconds, rounded up so a timeout is never disabled by rounding.
+func timeoutMillis(timeout time.Duration) int64 {
+	var ms = timeout.Milliseconds()
+	if timeout%time.Millisecond != 0 {
+		ms++
+	}
+	return ms
+}
+
+// mySQLStatementTimeout adds the MAX_EXECUTION_TIME optimizer hint to select statements,
+// MySQL does not support limiting the execution time of other statements.
+func mySQLStatementTimeout(query string, timeout time.Duration) string {
+	var trimmed = strings.TrimLeft(query, " \t\r\n")
+	if len(trimmed) < 6 || !strings.EqualFold(trimmed[:6], "SELECT") {
+		return query
+	}
+	return fmt.Sprintf("SELECT /*+ MAX_EXECUTION_TIME(%d) */%s", timeoutMillis(timeout), trimmed[6:])
+}
+
+// mariaDBStatementTimeout limits the execution time of the statement with max_statement_time, which is set in seconds.
+//
+// Only queries and data manipulation statements are limited, the timeout does not apply to DDL statements.
+func mariaDBStatementTimeout(query string, timeout time.Duration) string {
+	switch statementKeyword(query) {
+	case "SELECT", "WITH", "INSERT", "UPDATE", "DELETE", "REPLACE":
+		return fmt.Sprintf("SET STATEMENT max_statement_time=%.3f FOR %s", float64(timeoutMillis(timeout))/1000, query)
+	}
+	return query
+}
+
+// statementKeyword returns the first keyword of the query in upper case.
+func statementKeyword(query string) string {
+	var trimmed = strings.TrimLeft(query, " \t\r\n(")
+	var end = strings.IndexAny(trimmed, " \t\r\n(")
+	if end >= 0 {
+		trimmed = trimmed[:end]
+	}
+	return strings.ToUpper(trimmed)
+}
+
+// logSlowQuery logs the statement if it took longer than [SLOW_QUERY_THRESHOLD].
+func logSlowQuery(ctx context.Context, driver string, flags QueryFlag, timeTaken time.Duration, query string, args []any) {
+	if SLOW_QUERY_THRESHOLD <= 0 || timeTaken < SLOW_QUERY_THRESHOLD || flags&(Q_QUERY|Q_QUERYROW|Q_EXEC) == 0 {
+		return
+	}
+	// the arguments might contain sensitive data, they are only logged at the debug level
+	var log = getContextLogger(ctx)
+	log.Warnf("[%s.SlowQuery]: took %s: %s", driver, timeTaken, query)
+	if len(args) > 0 {
+		log.Debugf("[%s.SlowQuery]: arguments: %v", driver, args)
+	}
+}
+
+// WithQueryTimeout wraps the database so that statements are limited to the timeout by default.
+//
+// Statements executed with a context which has a timeout set with [ContextWithQueryTimeout]
+// use that timeout instead, this also applies to the statements of transactions started on the database.
+func WithQueryTimeout(db Database, timeout time.Duration) Database {
+	if t, ok := withoutPoolInterfaces(db).(*timeoutDatabase); ok {
+		db = t.Database
+	}
+	return withPoolInterfaces(&timeoutDatabase{Database: db, timeout: timeout})
+}
+
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	if _, ok := ContextQueryTimeout(ctx); ok {
+		return ctx
+	}
+	return ContextWithQueryTimeout(ctx, timeout)
+}
+
+type timeoutDatabase struct {
+	Database
+	timeout time.Duration
+}
+
+func (t *timeoutDatabase) database() Database {
+	return t.Database
+}
+
+func (t *timeoutDatabase) Unwrap() any {
+	return t.Database.Unwrap()
+}
+
+func (t *timeoutDatabase) QueryContext(ctx context.Context, query string, args ...any) (SQLRows, error) {
+	return t.Database.QueryContext(withDefaultTimeout(ctx, t.timeout), query, args...)
+}
+
+func (t *timeoutDatabase) QueryRowContext(ctx context.Context, query string, args ...any) SQLRow {
+	return t.Database.QueryRowContext(withDefaultTimeout(ctx, t.timeout), query, args...)
+}
+
+func (t *timeoutDatabase) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.Database.ExecContext(withDefaultTimeout(ctx, t.timeout), query, args...)
+}
+
+func (t *timeoutDatabase) Begin(ctx context.Context) (Transaction, error) {
+	var tx, err = t.Database.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &timeoutTransaction{Transaction: tx, timeout: t.timeout}, nil
+}
+
+type timeoutTransaction struct {
+	Transaction
+	timeout time.Duration
+}
+
+func (t *timeoutTransaction) QueryContext(ctx context.Context, query string, args ...any) (SQLRows, error) {
+	return t.Transaction.QueryContext(withDefaultTimeout(ctx, t.timeout), query, args...)
+}
+
+func (t *timeoutTransaction) QueryRowContext(ctx context.Context, query string, args ...any) SQLRow {
+	return t.Transaction.QueryRowContext(withDefaultTimeout(ctx, t.timeout), query, args...)
+}
+
+func (t *timeoutTransaction) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.Transaction.ExecContext(withDefaultTimeout(ctx, t.timeout), query, args...)
+}
+
+// unwrapDatabase returns the database wrapped by [WithRetry], [WithQueryTimeout] and [WithName].
+func unwrapDatabase(db DB) DB {
+	for {
+		var wrapped, ok = db.(interface{ database() Database })
+		if !ok {
+			return db
+		}
+		db = wrapped.database()
+	}
+}
@@ -0,0 +1,89 @@
+package drivers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const infiniteQuery = `WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT COUNT(*) FROM c`
+
+func TestStatementTimeout(t *testing.T) {
+	var tests = []struct {
+		driver   string
+		query    string
+		expected string
+	}{
+		{"mysql", "SELECT * FROM users", "SELECT /*+ MAX_EXECUTION_TIME(1500) */ * FROM users"},
+		{"mysql", "UPDATE users SET name = ?", "UPDATE users SET name = ?"},
+		{"mariadb", "SELECT * FROM users", "SET STATEMENT max_statement_time=1.500 FOR SELECT * FROM users"},
+		{"mariadb", "ALTER TABLE users ADD COLUMN age INT", "ALTER TABLE users ADD COLUMN age INT"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.driver, func(t *testing.T) {
+			var d, _ = drivers.Retrieve(test.driver)
+			if got := d.StatementTimeout(test.query, 1500*time.Millisecond); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestTimeoutDatabaseError(t *testing.T) {
+	var d, _ = drivers.Retrieve("postgres")
+
+	var err = d.BuildDatabaseError(&pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"})
+	if !errors.Is(err, errors.StatementTimeout) || !errors.Is(err, errors.QueryTimeout) {
+		t.Errorf("expected statement timeout, got %v", err)
+	}
+
+	err = d.BuildDatabaseError(&pgconn.PgError{Code: "57014", Message: "canceling statement due to user request"})
+	if err.Code() != errors.CodeQueryCanceled {
+		t.Errorf("expected query canceled, got %v", err)
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	var ctx = context.Background()
+	var db, err = drivers.Open(ctx, "sqlite3", "file:drivers_query_timeout?mode=memory")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	t.Run("Context", func(t *testing.T) {
+		var _, err = db.ExecContext(drivers.ContextWithQueryTimeout(ctx, 50*time.Millisecond), infiniteQuery)
+		if !errors.Is(err, errors.StatementTimeout) {
+			t.Fatalf("expected statement timeout, got %v", err)
+		}
+	})
+
+	t.Run("CallerDeadline", func(t *testing.T) {
+		var ctx, cancel = context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		var _, err = db.ExecContext(ctx, infiniteQuery)
+		if err == nil || errors.Is(err, errors.StatementTimeout) {
+			t.Fatalf("expected the caller's deadline not to be a statement timeout, got %v", err)
+		}
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		var db = drivers.WithQueryTimeout(db, 50*time.Millisecond)
+		var _, err = db.ExecContext(ctx, infiniteQuery)
+		if !errors.Is(err, errors.StatementTimeout) {
+			t.Fatalf("expected statement timeout, got %v", err)
+		}
+
+		var row = db.QueryRowContext(ctx, "SELECT 1")
+		var one int
+		if err := row.Scan(&one); err != nil || one != 1 {
+			t.Fatalf("expected 1, got %d (%v)", one, err)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package drivers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Nigel2392/go-django/src/core/trace"
+)
+
+// startQuerySpan starts the span of a statement, i.e. "db.query" or "db.transaction_start".
+//
+// The span is a child of the span stored in the context, like the request span of the request which executes the statement.
+func startQuerySpan(ctx context.Context, driver string, flags QueryFlag, query string) (context.Context, *trace.Span) {
+	if !trace.Default.Enabled() {
+		return ctx, nil
+	}
+
+	var args = []any{"db.driver", driver}
+	if database, ok := ContextDatabaseName(ctx); ok {
+		args = append(args, "db.name", database)
+	}
+
+	if query != "" {
+		args = append(args, "db.statement", query)
+	}
+
+	return trace.Start(ctx, "db."+strings.ToLower(flags.String()), args...)
+}
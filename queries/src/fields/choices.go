@@ -0,0 +1,230 @@
+package fields
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"maps"
+	"reflect"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/checks"
+	"github.com/Nigel2392/go-django/src/forms/fields"
+	"github.com/Nigel2392/go-django/src/forms/widgets"
+	"github.com/Nigel2392/go-django/src/forms/widgets/options"
+)
+
+var (
+	_ attrs.Field              = (*ChoicesField[string])(nil)
+	_ attrs.CanChoices         = (*ChoicesField[string])(nil)
+	_ attrs.CanOnModelRegister = (*ChoicesField[string])(nil)
+)
+
+type ChoicesFieldConfig struct {
+	// Constraint is the database constraint used to enforce the choices.
+	//
+	// By default the choices are only validated in Go, see [migrator.ChoicesConstraint].
+	Constraint migrator.ChoicesConstraint
+
+	// Widget is an optional function which returns the widget for the form field.
+	//
+	// The options passed to the function are the choices of the field,
+	// by default a select input is used.
+	Widget func(f attrs.Field, opts func() []widgets.Option) widgets.Widget
+}
+
+// ChoicesField wraps an [attrs.Field] and restricts it's value to a fixed set of choices.
+//
+// The field is validated against the choices in [ChoicesField.Validate],
+// rendered as a select in forms and the label of the value is displayed in list views.
+//
+// The choices can optionally be enforced by the database, see [ChoicesFieldConfig.Constraint].
+type ChoicesField[T comparable] struct {
+	attrs.Field
+	choices attrs.Choices[T]
+	cnf     *ChoicesFieldConfig
+}
+
+// Choices wraps the provided field and returns a [ChoicesField].
+//
+// Example:
+//
+//	fields.Choices(attrs.NewField(o, "Status", &attrs.FieldConfig{
+//		Default: StatusDraft,
+//	}), StatusChoices, &fields.ChoicesFieldConfig{
+//		Constraint: migrator.ChoicesConstraintCheck,
+//	})
+func Choices[T comparable](field attrs.Field, choices attrs.Choices[T], cnf ...*ChoicesFieldConfig) *ChoicesField[T] {
+	if field == nil {
+		panic("Choices: field is nil")
+	}
+
+	var conf = &ChoicesFieldConfig{}
+	if len(cnf) > 0 && cnf[0] != nil {
+		conf = cnf[0]
+	}
+
+	return &ChoicesField[T]{
+		Field:   field,
+		choices: choices,
+		cnf:     conf,
+	}
+}
+
+// Unwrap returns the field which is wrapped by the [ChoicesField].
+func (c *ChoicesField[T]) Unwrap() attrs.Field {
+	return c.Field
+}
+
+func (c *ChoicesField[T]) Choices() attrs.ChoiceList {
+	return c.choices
+}
+
+func (c *ChoicesField[T]) OnModelRegister(model attrs.Definer, outer attrs.FieldDefinition) error {
+	if f, ok := c.Field.(attrs.CanOnModelRegister); ok {
+		return f.OnModelRegister(model, outer)
+	}
+	return nil
+}
+
+func (c *ChoicesField[T]) StructField() *reflect.StructField {
+	if f, ok := c.Field.(attrs.StructFieldDefinition); ok {
+		return f.StructField()
+	}
+	return nil
+}
+
+func (c *ChoicesField[T]) Check(ctx context.Context) []checks.Message {
+	var messages []checks.Message
+	if f, ok := c.Field.(interface {
+		Check(ctx context.Context) []checks.Message
+	}); ok {
+		messages = append(messages, f.Check(ctx)...)
+	}
+
+	if len(c.choices) == 0 {
+		messages = append(messages, checks.Error(
+			"field.choices_empty",
+			fmt.Sprintf("Field %q in model %T has no choices", c.Name(), c.Instance()),
+			c, "",
+		))
+	}
+
+	if dflt := c.GetDefault(); dflt != nil && !attrs.IsZero(dflt) {
+		if _, ok := c.choices.ChoiceLabel(ctx, dflt); !ok {
+			messages = append(messages, checks.Error(
+				"field.choices_invalid_default",
+				fmt.Sprintf("Default value %v of field %q in model %T is not a valid choice", dflt, c.Name(), c.Instance()),
+				c, "",
+			))
+		}
+	}
+
+	return messages
+}
+
+func (c *ChoicesField[T]) Attrs() map[string]any {
+	var atts = make(map[string]any)
+	maps.Copy(atts, c.Field.Attrs())
+
+	var values = make([]any, 0, len(c.choices))
+	for _, choice := range c.choices {
+		values = append(values, choiceDBValue(choice.Value))
+	}
+
+	atts[attrs.AttrChoicesKey] = values
+	if c.cnf.Constraint != migrator.ChoicesConstraintNone {
+		atts[migrator.AttrChoicesConstraintKey] = c.cnf.Constraint
+	}
+	return atts
+}
+
+func (c *ChoicesField[T]) context() context.Context {
+	if defs := c.FieldDefinitions(); defs != nil {
+		return defs.Context()
+	}
+	return context.Background()
+}
+
+// DisplayValue returns the translated label of the current value of the field.
+//
+// If the value is not a valid choice, the string representation of the value is returned.
+func (c *ChoicesField[T]) DisplayValue(ctx context.Context) string {
+	var value = c.GetValue()
+	if label, ok := c.choices.ChoiceLabel(ctx, value); ok {
+		return label
+	}
+	return attrs.ToString(value)
+}
+
+func (c *ChoicesField[T]) ToString() string {
+	return c.DisplayValue(c.context())
+}
+
+func (c *ChoicesField[T]) Validate() error {
+	if err := c.Field.Validate(); err != nil {
+		return err
+	}
+
+	var value = c.GetValue()
+	if value == nil || attrs.IsZero(value) && c.AllowBlank() {
+		return nil
+	}
+
+	var v, ok = c.choices.Convert(value)
+	if !ok || !c.choices.Contains(v) {
+		return errors.ValueError.Wrapf(
+			"value %v is not a valid choice for field %q",
+			value, c.Name(),
+		)
+	}
+
+	return nil
+}
+
+func (c *ChoicesField[T]) FormField() fields.Field {
+	var formField = c.Field.FormField()
+	if formField == nil {
+		return nil
+	}
+
+	var opts = func() []widgets.Option {
+		return attrs.ChoiceOptions(c.context(), c.choices)
+	}
+
+	if c.cnf.Widget != nil {
+		formField.SetWidget(c.cnf.Widget(c, opts))
+		return formField
+	}
+
+	formField.SetWidget(options.NewSelectInput(
+		nil, opts, options.IncludeBlank(c.AllowBlank()),
+	))
+	return formField
+}
+
+// choiceDBValue converts the value of a choice to the value stored in the database.
+func choiceDBValue(value any) any {
+	if valuer, ok := value.(driver.Valuer); ok {
+		if v, err := valuer.Value(); err == nil {
+			return v
+		}
+	}
+
+	var rv = reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		return rv.Bool()
+	}
+	return value
+}
@@ -0,0 +1,77 @@
+package fields_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Nigel2392/go-django/queries/src/fields"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+type choiceStatus string
+
+const (
+	choiceStatusDraft     choiceStatus = "draft"
+	choiceStatusPublished choiceStatus = "published"
+)
+
+var choiceStatusChoices = attrs.NewChoices(
+	attrs.NewChoice(choiceStatusDraft, "Draft"),
+	attrs.NewChoice(choiceStatusPublished, "Published"),
+)
+
+type choicesModel struct {
+	ID     int
+	Status choiceStatus
+}
+
+func (m *choicesModel) FieldDefs(ctx context.Context) attrs.Definitions {
+	return attrs.Make[*choicesModel, attrs.Field](ctx, m,
+		attrs.NewField(m, "ID", &attrs.FieldConfig{
+			Primary: true,
+		}),
+		fields.Choices(attrs.NewField(m, "Status", nil), choiceStatusChoices, &fields.ChoicesFieldConfig{
+			Constraint: migrator.ChoicesConstraintCheck,
+		}),
+	)
+}
+
+func TestChoicesFieldValidate(t *testing.T) {
+	var m = &choicesModel{Status: choiceStatusPublished}
+	var field, _ = attrs.Define(context.Background(), m).Field("Status")
+	if err := field.Validate(); err != nil {
+		t.Fatalf("expected valid choice, got error: %v", err)
+	}
+
+	m.Status = "unknown"
+	if err := field.Validate(); err == nil {
+		t.Fatalf("expected error for invalid choice %q", m.Status)
+	}
+}
+
+func TestChoicesFieldDisplay(t *testing.T) {
+	var m = &choicesModel{Status: choiceStatusDraft}
+	var field, _ = attrs.Define(context.Background(), m).Field("Status")
+	if field.ToString() != "Draft" {
+		t.Errorf("expected label %q, got %q", "Draft", field.ToString())
+	}
+
+	var label, ok = field.(attrs.CanChoices).Choices().ChoiceLabel(context.Background(), "published")
+	if !ok || label != "Published" {
+		t.Errorf("expected label %q for database value, got %q (%v)", "Published", label, ok)
+	}
+}
+
+func TestChoicesFieldColumn(t *testing.T) {
+	var m = &choicesModel{}
+	var field, _ = attrs.Define(context.Background(), m).Field("Status")
+	var col = migrator.NewTableColumn(nil, field)
+	if !col.HasChoicesConstraint() {
+		t.Fatalf("expected column to have a choices constraint")
+	}
+
+	if sql := col.ChoicesSQL(); sql != "'draft', 'published'" {
+		t.Errorf("expected choices SQL %q, got %q", "'draft', 'published'", sql)
+	}
+}
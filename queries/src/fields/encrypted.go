@@ -0,0 +1,589 @@
+package fields
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/dbtype"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/checks"
+	"github.com/Nigel2392/go-django/src/core/secrets"
+	"github.com/Nigel2392/go-django/src/core/secrets/encryption"
+	"github.com/Nigel2392/go-django/src/core/trans"
+	"github.com/Nigel2392/go-django/src/forms/fields"
+)
+
+var (
+	_ attrs.Field              = (*EncryptedField)(nil)
+	_ attrs.CanOnModelRegister = (*EncryptedField)(nil)
+	_ dbtype.CanDBType         = (*EncryptedField)(nil)
+)
+
+type EncryptedFieldConfig struct {
+	// Backend is the encryption backend used to encrypt and decrypt the value.
+	//
+	// If nil, the application's default backend is used, see [secrets.ENCRYPTION_BACKEND].
+	//
+	// Key rotation is handled by the backend, the ID of the key used for encryption
+	// is stored in the ciphertext and fallback keys are tried when decrypting.
+	Backend encryption.Encryption
+
+	// AdditionalData is an optional function which returns additional authenticated data
+	// for the field, i.e. the name of the model's table.
+	//
+	// The ciphertext can then only be decrypted when the same additional data is provided.
+	// The data must be known before the row is inserted, an auto-incremented primary key
+	// is zero when the value is encrypted and can not be used.
+	AdditionalData func(f *EncryptedField) []byte
+
+	// DBType is the database type used to store the ciphertext.
+	//
+	// It can be either [dbtype.BLOB] (default) or [dbtype.Text].
+	// If [dbtype.Text] is used, the ciphertext is stored base64 encoded.
+	DBType dbtype.Type
+
+	// PlaintextFallback scans the database value as-is when it was not written
+	// by an [EncryptedField], I.E. when it does not start with the version prefix.
+	//
+	// This allows an existing plaintext column to be wrapped in an [EncryptedField]
+	// without a data migration, the value is encrypted the next time it is saved.
+	//
+	// Values which were encrypted but cannot be decrypted, i.e. because of a wrong key
+	// or because they were tampered with, always return an error.
+	PlaintextFallback bool
+}
+
+// encryptedVersion is prepended to each value written by an [EncryptedField],
+// it is used to tell encrypted values apart from plaintext ones.
+var encryptedVersion = []byte("enc1$")
+
+// EncryptedField wraps an [attrs.Field] and transparently encrypts it's value
+// when it is saved to the database, and decrypts it when it is scanned from the database.
+//
+// The wrapped field should be a string, []byte or JSON-like field.
+// Non-string and non-[]byte values are marshalled to JSON before encrypting,
+// and unmarshalled into the type of the wrapped field after decrypting.
+//
+// The value of the field is stored in the model as plaintext,
+// only the database representation of the value is encrypted.
+//
+// Encrypted values cannot be used for lookups, a [BlindIndexField] can be
+// used alongside the encrypted field to allow for exact-match lookups.
+type EncryptedField struct {
+	attrs.Field
+	cnf *EncryptedFieldConfig
+}
+
+// Encrypted wraps the provided field and returns an [EncryptedField].
+//
+// Example:
+//
+//	fields.Encrypted(attrs.NewField(o, "AccessToken", &attrs.FieldConfig{
+//		Column: "access_token",
+//	}))
+func Encrypted(field attrs.Field, cnf ...*EncryptedFieldConfig) *EncryptedField {
+	if field == nil {
+		panic("Encrypted: field is nil")
+	}
+
+	var conf = &EncryptedFieldConfig{}
+	if len(cnf) > 0 && cnf[0] != nil {
+		conf = cnf[0]
+	}
+
+	if conf.DBType == dbtype.Invalid {
+		conf.DBType = dbtype.BLOB
+	}
+
+	if conf.DBType != dbtype.BLOB && conf.DBType != dbtype.Text {
+		panic(fmt.Sprintf(
+			"Encrypted: invalid database type %s for field %q, must be BLOB or TEXT",
+			conf.DBType, field.Name(),
+		))
+	}
+
+	return &EncryptedField{
+		Field: field,
+		cnf:   conf,
+	}
+}
+
+// Unwrap returns the field which is wrapped by the [EncryptedField].
+func (e *EncryptedField) Unwrap() attrs.Field {
+	return e.Field
+}
+
+func (e *EncryptedField) DBType() dbtype.Type {
+	return e.cnf.DBType
+}
+
+func (e *EncryptedField) OnModelRegister(model attrs.Definer, outer attrs.FieldDefinition) error {
+	if f, ok := e.Field.(attrs.CanOnModelRegister); ok {
+		return f.OnModelRegister(model, outer)
+	}
+	return nil
+}
+
+func (e *EncryptedField) StructField() *reflect.StructField {
+	if f, ok := e.Field.(attrs.StructFieldDefinition); ok {
+		return f.StructField()
+	}
+	return nil
+}
+
+func (e *EncryptedField) Check(ctx context.Context) []checks.Message {
+	var messages []checks.Message
+	if f, ok := e.Field.(interface {
+		Check(ctx context.Context) []checks.Message
+	}); ok {
+		messages = append(messages, f.Check(ctx)...)
+	}
+
+	if e.IsPrimary() {
+		messages = append(messages, checks.Error(
+			"field.encrypted_primary",
+			fmt.Sprintf("Field %q in model %T cannot be both encrypted and a primary key", e.Name(), e.Instance()),
+			e, "",
+		))
+	}
+
+	if _, ok := e.Attrs()[attrs.AttrUniqueKey]; ok {
+		messages = append(messages, checks.Warning(
+			"field.encrypted_unique",
+			fmt.Sprintf("Field %q in model %T is encrypted, unique constraints will not work as expected", e.Name(), e.Instance()),
+			e, "Use a BlindIndexField with a unique constraint instead",
+		))
+	}
+
+	return messages
+}
+
+// Attrs returns the attributes of the wrapped field.
+//
+// The length attributes are removed, the ciphertext is
+// always longer than the plaintext and the database column
+// should not be limited by it.
+func (e *EncryptedField) Attrs() map[string]any {
+	var atts = make(map[string]any)
+	maps.Copy(atts, e.Field.Attrs())
+	delete(atts, attrs.AttrMaxLengthKey)
+	delete(atts, attrs.AttrMinLengthKey)
+	return atts
+}
+
+func (e *EncryptedField) context() context.Context {
+	var ctx = context.Background()
+	if defs := e.FieldDefinitions(); defs != nil {
+		ctx = defs.Context()
+	}
+
+	if e.cnf.AdditionalData != nil {
+		ctx = encryption.ContextWithAdditionalData(
+			ctx, e.cnf.AdditionalData(e),
+		)
+	}
+
+	return ctx
+}
+
+func (e *EncryptedField) backend() (encryption.Encryption, error) {
+	if e.cnf.Backend != nil {
+		return e.cnf.Backend, nil
+	}
+	return secrets.ENCRYPTION_BACKEND()
+}
+
+// Plaintext returns the database value of the wrapped field as bytes.
+//
+// It returns nil if the value of the field is nil.
+func (e *EncryptedField) Plaintext() ([]byte, error) {
+	var value, err = e.Field.Value()
+	if err != nil {
+		return nil, err
+	}
+	return encryptedPlaintext(value)
+}
+
+func encryptedPlaintext(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case json.RawMessage:
+		return []byte(v), nil
+	case driver.Valuer:
+		var val, err = v.Value()
+		if err != nil {
+			return nil, err
+		}
+		return encryptedPlaintext(val)
+	}
+
+	var b, err = json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal %T for encryption", value)
+	}
+	return b, nil
+}
+
+// Value returns the encrypted value of the wrapped field.
+func (e *EncryptedField) Value() (driver.Value, error) {
+	var plaintext, err = e.Plaintext()
+	if err != nil {
+		return nil, err
+	}
+
+	if plaintext == nil {
+		return nil, nil
+	}
+
+	backend, err := e.backend()
+	if err != nil {
+		return nil, encryption.ErrEncryption.WithCause(err)
+	}
+
+	ciphertext, err := backend.Encrypt(e.context(), plaintext)
+	if err != nil {
+		return nil, encryption.ErrEncryption.WithCause(errors.Wrapf(
+			err, "failed to encrypt field %q", e.Name(),
+		))
+	}
+
+	if e.cnf.DBType == dbtype.Text {
+		return string(encryptedVersion) + base64.StdEncoding.EncodeToString(ciphertext), nil
+	}
+
+	return append(slices.Clip(encryptedVersion), ciphertext...), nil
+}
+
+// Scan decrypts the database value and scans the plaintext into the wrapped field.
+func (e *EncryptedField) Scan(src any) error {
+	var ciphertext []byte
+	switch v := src.(type) {
+	case nil:
+		return e.Field.Scan(nil)
+	case string:
+		ciphertext = []byte(v)
+	case []byte:
+		ciphertext = v
+	default:
+		return errors.TypeMismatch.Wrapf(
+			"cannot scan %T into encrypted field %q", src, e.Name(),
+		)
+	}
+
+	if len(ciphertext) == 0 {
+		return e.Field.Scan(nil)
+	}
+
+	if !bytes.HasPrefix(ciphertext, encryptedVersion) {
+		if e.cnf.PlaintextFallback {
+			return e.scanPlaintext(ciphertext)
+		}
+		return encryption.ErrDecryption.WithCause(fmt.Errorf(
+			"value of field %q is not encrypted", e.Name(),
+		))
+	}
+
+	plaintext, err := e.decrypt(ciphertext[len(encryptedVersion):])
+	if err != nil {
+		return err
+	}
+
+	return e.scanPlaintext(plaintext)
+}
+
+func (e *EncryptedField) decrypt(ciphertext []byte) ([]byte, error) {
+	if e.cnf.DBType == dbtype.Text {
+		var decoded = make([]byte, base64.StdEncoding.DecodedLen(len(ciphertext)))
+		var n, err = base64.StdEncoding.Decode(decoded, ciphertext)
+		if err != nil {
+			return nil, encryption.ErrDecryption.WithCause(err)
+		}
+		ciphertext = decoded[:n]
+	}
+
+	backend, err := e.backend()
+	if err != nil {
+		return nil, encryption.ErrDecryption.WithCause(err)
+	}
+
+	plaintext, err := backend.Decrypt(e.context(), ciphertext)
+	if err != nil {
+		return nil, encryption.ErrDecryption.WithCause(errors.Wrapf(
+			err, "failed to decrypt field %q", e.Name(),
+		))
+	}
+
+	return plaintext, nil
+}
+
+// scanPlaintext scans the plaintext into the wrapped field.
+//
+// Strings, byte slices and [sql.Scanner] implementations are scanned directly,
+// any other type was marshalled to JSON by [encryptedPlaintext] and is unmarshalled
+// into a new value of the field's type.
+func (e *EncryptedField) scanPlaintext(plaintext []byte) error {
+	var fieldType = e.Field.Type()
+	var typ = fieldType
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8:
+		return e.Field.Scan(plaintext)
+	case typ.Kind() == reflect.String,
+		reflect.PointerTo(typ).Implements(reflect.TypeOf((*sql.Scanner)(nil)).Elem()):
+		return e.Field.Scan(string(plaintext))
+	}
+
+	var value = reflect.New(typ)
+	if err := json.Unmarshal(plaintext, value.Interface()); err != nil {
+		return encryption.ErrDecryption.WithCause(errors.Wrapf(
+			err, "failed to unmarshal decrypted value of field %q into %s", e.Name(), fieldType,
+		))
+	}
+
+	if fieldType.Kind() == reflect.Ptr {
+		return e.Field.SetValue(value.Interface(), true)
+	}
+	return e.Field.SetValue(value.Elem().Interface(), true)
+}
+
+type BlindIndexFieldConfig struct {
+	// Key is the key used to calculate the HMAC of the value.
+	//
+	// If nil, a key derived from the application's secret key is used.
+	// The key cannot be rotated without recalculating all indexes.
+	Key []byte
+
+	Label    any
+	HelpText any
+}
+
+var _ attrs.Field = (*BlindIndexField)(nil)
+
+// BlindIndexField is a field which stores a keyed hash (HMAC-SHA256) of
+// the plaintext value of another field in the model.
+//
+// It is meant to be used alongside an [EncryptedField] to allow
+// for exact-match lookups on the encrypted value.
+//
+// The blind index does not have a backing struct field, it's value is
+// calculated from the source field when the model is saved.
+//
+// To query the index, use [BlindIndexField.Lookup] or the package level [BlindIndex] function:
+//
+//	queries.GetQuerySet(&User{}).Filter("EmailIndex", fields.BlindIndex(nil, []byte(email)))
+type BlindIndexField struct {
+	model  attrs.Definer
+	defs   attrs.Definitions
+	name   string
+	column string
+	source string
+	cnf    *BlindIndexFieldConfig
+	stored string
+}
+
+// NewBlindIndexField creates a new [BlindIndexField] for the source field in the model.
+func NewBlindIndexField(forModel attrs.Definer, name, column, sourceField string, cnf ...*BlindIndexFieldConfig) *BlindIndexField {
+	if forModel == nil {
+		panic("NewBlindIndexField: model is nil")
+	}
+
+	if name == "" || sourceField == "" {
+		panic("NewBlindIndexField: name or source field is empty")
+	}
+
+	var conf = &BlindIndexFieldConfig{}
+	if len(cnf) > 0 && cnf[0] != nil {
+		conf = cnf[0]
+	}
+
+	if column == "" {
+		column = attrs.ColumnName(name)
+	}
+
+	return &BlindIndexField{
+		model:  forModel,
+		name:   name,
+		column: column,
+		source: sourceField,
+		cnf:    conf,
+	}
+}
+
+func blindIndexKey() []byte {
+	var mac = hmac.New(sha256.New, secrets.SECRET_KEY().Bytes())
+	mac.Write([]byte("go-django.fields.blind-index"))
+	return mac.Sum(nil)
+}
+
+// BlindIndex calculates the blind index for the given plaintext.
+//
+// If the key is nil, a key derived from the application's secret key is used.
+func BlindIndex(key []byte, plaintext []byte) string {
+	if key == nil {
+		key = blindIndexKey()
+	}
+	var mac = hmac.New(sha256.New, key)
+	mac.Write(plaintext)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Lookup returns the blind index for the given value, it can be used in
+// queryset filters for exact-match lookups on the blind index field.
+func (b *BlindIndexField) Lookup(value any) (string, error) {
+	var plaintext, err = encryptedPlaintext(value)
+	if err != nil {
+		return "", err
+	}
+	return BlindIndex(b.cnf.Key, plaintext), nil
+}
+
+func (b *BlindIndexField) sourceField() (attrs.Field, bool) {
+	var defs = b.defs
+	if defs == nil {
+		defs = attrs.Define(context.Background(), b.model)
+	}
+	return defs.Field(b.source)
+}
+
+func (b *BlindIndexField) compute() (string, error) {
+	var field, ok = b.sourceField()
+	if !ok {
+		return "", errors.FieldNotFound.Wrapf(
+			"source field %q not found for blind index %q in %T",
+			b.source, b.name, b.model,
+		)
+	}
+
+	var plaintext []byte
+	var err error
+	if enc, ok := field.(*EncryptedField); ok {
+		plaintext, err = enc.Plaintext()
+	} else {
+		var val driver.Value
+		if val, err = field.Value(); err == nil {
+			plaintext, err = encryptedPlaintext(val)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if plaintext == nil {
+		return "", nil
+	}
+
+	return BlindIndex(b.cnf.Key, plaintext), nil
+}
+
+func (b *BlindIndexField) Name() string                             { return b.name }
+func (b *BlindIndexField) ColumnName() string                       { return b.column }
+func (b *BlindIndexField) Type() reflect.Type                       { return reflect.TypeOf("") }
+func (b *BlindIndexField) DBType() dbtype.Type                      { return dbtype.Char }
+func (b *BlindIndexField) Instance() attrs.Definer                  { return b.model }
+func (b *BlindIndexField) Rel() attrs.Relation                      { return nil }
+func (b *BlindIndexField) IsPrimary() bool                          { return false }
+func (b *BlindIndexField) AllowNull() bool                          { return true }
+func (b *BlindIndexField) AllowBlank() bool                         { return true }
+func (b *BlindIndexField) AllowEdit() bool                          { return false }
+func (b *BlindIndexField) AllowDBEdit() bool                        { return true }
+func (b *BlindIndexField) FieldDefinitions() attrs.Definitions      { return b.defs }
+func (b *BlindIndexField) BindToDefinitions(defs attrs.Definitions) { b.defs = defs }
+func (b *BlindIndexField) GetDefault() interface{}                  { return nil }
+func (b *BlindIndexField) Validate() error                          { return nil }
+func (b *BlindIndexField) ToString() string                         { return fmt.Sprint(b.GetValue()) }
+
+func (b *BlindIndexField) Attrs() map[string]any {
+	return map[string]any{
+		attrs.AttrNameKey:      b.name,
+		attrs.AttrMaxLengthKey: int64(sha256.Size * 2),
+		attrs.AttrMinLengthKey: int64(0),
+	}
+}
+
+func (b *BlindIndexField) Label(ctx context.Context) string {
+	if b.cnf.Label != nil {
+		if text, ok := trans.GetText(ctx, b.cnf.Label); ok {
+			return text
+		}
+	}
+	return trans.T(ctx, attrs.NiceName(b.name))
+}
+
+func (b *BlindIndexField) HelpText(ctx context.Context) string {
+	if b.cnf.HelpText != nil {
+		if text, ok := trans.GetText(ctx, b.cnf.HelpText); ok {
+			return text
+		}
+	}
+	return ""
+}
+
+// The blind index is never edited directly.
+func (b *BlindIndexField) FormField() fields.Field {
+	return nil
+}
+
+// GetValue returns the blind index as it was last scanned from the database,
+// or calculates it from the source field if it has not been scanned yet.
+func (b *BlindIndexField) GetValue() interface{} {
+	if b.stored != "" {
+		return b.stored
+	}
+	var v, _ = b.compute()
+	return v
+}
+
+// SetValue is a no-op, the blind index is always calculated from the source field.
+func (b *BlindIndexField) SetValue(v interface{}, force bool) error {
+	return nil
+}
+
+func (b *BlindIndexField) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		b.stored = ""
+	case string:
+		b.stored = v
+	case []byte:
+		b.stored = string(v)
+	default:
+		return errors.TypeMismatch.Wrapf(
+			"cannot scan %T into blind index %q", src, b.name,
+		)
+	}
+	return nil
+}
+
+// Value always re-calculates the blind index from the source field,
+// this makes sure the index stays up to date with the encrypted value.
+func (b *BlindIndexField) Value() (driver.Value, error) {
+	var v, err = b.compute()
+	if err != nil {
+		return nil, err
+	}
+	b.stored = v
+	if v == "" {
+		return nil, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,247 @@
+package fields_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/dbtype"
+	"github.com/Nigel2392/go-django/queries/src/fields"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/secrets/encryption"
+)
+
+var (
+	encryptionKey = bytes.Repeat([]byte{0x01}, 32)
+	blindIndexKey = bytes.Repeat([]byte{0x02}, 32)
+	backend, _    = encryption.GetCrypto(encryption.DEFAULT, encryptionKey, nil)
+)
+
+type encryptedModel struct {
+	ID     int
+	Secret string
+	Data   []byte
+}
+
+func (m *encryptedModel) FieldDefs(ctx context.Context) attrs.Definitions {
+	return attrs.Make[*encryptedModel, attrs.Field](ctx, m,
+		attrs.NewField(m, "ID", &attrs.FieldConfig{
+			Primary: true,
+		}),
+		fields.Encrypted(attrs.NewField(m, "Secret", &attrs.FieldConfig{
+			MaxLength: 16,
+		}), &fields.EncryptedFieldConfig{
+			Backend: backend,
+			DBType:  dbtype.Text,
+		}),
+		fields.Encrypted(attrs.NewField(m, "Data", nil), &fields.EncryptedFieldConfig{
+			Backend: backend,
+		}),
+		fields.NewBlindIndexField(m, "SecretIndex", "secret_index", "Secret", &fields.BlindIndexFieldConfig{
+			Key: blindIndexKey,
+		}),
+	)
+}
+
+func TestEncryptedFieldRoundTrip(t *testing.T) {
+	var src = &encryptedModel{
+		ID:     1,
+		Secret: "my-secret",
+		Data:   []byte("my-data"),
+	}
+
+	var srcDefs = attrs.Define(context.Background(), src)
+	var dst = &encryptedModel{}
+	var dstDefs = attrs.Define(context.Background(), dst)
+
+	for _, name := range []string{"Secret", "Data"} {
+		var srcField, _ = srcDefs.Field(name)
+		var dstField, _ = dstDefs.Field(name)
+
+		var value, err = srcField.Value()
+		if err != nil {
+			t.Fatalf("failed to get value for %q: %v", name, err)
+		}
+
+		switch v := value.(type) {
+		case string:
+			if v == src.Secret {
+				t.Fatalf("expected %q to be encrypted, got plaintext", name)
+			}
+		case []byte:
+			if bytes.Equal(v, src.Data) {
+				t.Fatalf("expected %q to be encrypted, got plaintext", name)
+			}
+		default:
+			t.Fatalf("unexpected value type %T for %q", value, name)
+		}
+
+		if err := dstField.Scan(value); err != nil {
+			t.Fatalf("failed to scan %q: %v", name, err)
+		}
+	}
+
+	if dst.Secret != src.Secret {
+		t.Errorf("expected Secret to be %q, got %q", src.Secret, dst.Secret)
+	}
+
+	if !bytes.Equal(dst.Data, src.Data) {
+		t.Errorf("expected Data to be %q, got %q", src.Data, dst.Data)
+	}
+}
+
+type encryptedSettings struct {
+	Theme   string
+	Retries int
+}
+
+type encryptedJSONModel struct {
+	ID       int
+	Settings encryptedSettings
+	Labels   map[string]string
+	Count    int64
+}
+
+func (m *encryptedJSONModel) FieldDefs(ctx context.Context) attrs.Definitions {
+	var cnf = &fields.EncryptedFieldConfig{
+		Backend: backend,
+	}
+	return attrs.Make[*encryptedJSONModel, attrs.Field](ctx, m,
+		attrs.NewField(m, "ID", &attrs.FieldConfig{
+			Primary: true,
+		}),
+		fields.Encrypted(attrs.NewField(m, "Settings", nil), cnf),
+		fields.Encrypted(attrs.NewField(m, "Labels", nil), cnf),
+		fields.Encrypted(attrs.NewField(m, "Count", nil), cnf),
+	)
+}
+
+func TestEncryptedFieldRoundTripJSON(t *testing.T) {
+	var src = &encryptedJSONModel{
+		ID: 1,
+		Settings: encryptedSettings{
+			Theme:   "dark",
+			Retries: 3,
+		},
+		Labels: map[string]string{
+			"env": "production",
+		},
+		Count: 42,
+	}
+
+	var srcDefs = attrs.Define(context.Background(), src)
+	var dst = &encryptedJSONModel{}
+	var dstDefs = attrs.Define(context.Background(), dst)
+
+	for _, name := range []string{"Settings", "Labels", "Count"} {
+		var srcField, _ = srcDefs.Field(name)
+		var dstField, _ = dstDefs.Field(name)
+
+		var value, err = srcField.Value()
+		if err != nil {
+			t.Fatalf("failed to get value for %q: %v", name, err)
+		}
+
+		if err := dstField.Scan(value); err != nil {
+			t.Fatalf("failed to scan %q: %v", name, err)
+		}
+	}
+
+	if !reflect.DeepEqual(dst.Settings, src.Settings) {
+		t.Errorf("expected Settings to be %+v, got %+v", src.Settings, dst.Settings)
+	}
+
+	if !reflect.DeepEqual(dst.Labels, src.Labels) {
+		t.Errorf("expected Labels to be %v, got %v", src.Labels, dst.Labels)
+	}
+
+	if dst.Count != src.Count {
+		t.Errorf("expected Count to be %d, got %d", src.Count, dst.Count)
+	}
+}
+
+func TestEncryptedFieldPlaintextFallback(t *testing.T) {
+	var m = &encryptedModel{}
+	var field = fields.Encrypted(attrs.NewField(m, "Secret", nil), &fields.EncryptedFieldConfig{
+		Backend:           backend,
+		DBType:            dbtype.Text,
+		PlaintextFallback: true,
+	})
+
+	if err := field.Scan("not-encrypted"); err != nil {
+		t.Fatalf("expected plaintext to be scanned, got error: %v", err)
+	}
+
+	if m.Secret != "not-encrypted" {
+		t.Errorf("expected Secret to be %q, got %q", "not-encrypted", m.Secret)
+	}
+
+	// values which were encrypted with another key are never scanned as plaintext
+	var otherBackend, _ = encryption.GetCrypto(encryption.DEFAULT, bytes.Repeat([]byte{0x03}, 32), nil)
+	var other = fields.Encrypted(attrs.NewField(&encryptedModel{Secret: "other-secret"}, "Secret", nil), &fields.EncryptedFieldConfig{
+		Backend: otherBackend,
+		DBType:  dbtype.Text,
+	})
+
+	var value, err = other.Value()
+	if err != nil {
+		t.Fatalf("failed to encrypt value: %v", err)
+	}
+
+	if err := field.Scan(value); err == nil {
+		t.Errorf("expected an error when scanning a value encrypted with another key")
+	}
+
+	if m.Secret != "not-encrypted" {
+		t.Errorf("expected Secret to be left unchanged, got %q", m.Secret)
+	}
+
+	var strict = fields.Encrypted(attrs.NewField(m, "Secret", nil), &fields.EncryptedFieldConfig{
+		Backend: backend,
+		DBType:  dbtype.Text,
+	})
+
+	if err := strict.Scan("not-encrypted"); err == nil {
+		t.Errorf("expected an error when scanning plaintext without fallback")
+	}
+}
+
+func TestEncryptedFieldAttrs(t *testing.T) {
+	var m = &encryptedModel{}
+	var field, _ = attrs.Define(context.Background(), m).Field("Secret")
+	if _, ok := field.Attrs()[attrs.AttrMaxLengthKey]; ok {
+		t.Errorf("expected max length attribute to be removed from encrypted field")
+	}
+
+	if field.(dbtype.CanDBType).DBType() != dbtype.Text {
+		t.Errorf("expected encrypted field to have db type TEXT")
+	}
+}
+
+func TestBlindIndexField(t *testing.T) {
+	var m = &encryptedModel{
+		Secret: "my-secret",
+	}
+
+	var field, _ = attrs.Define(context.Background(), m).Field("SecretIndex")
+	var value, err = field.Value()
+	if err != nil {
+		t.Fatalf("failed to get blind index value: %v", err)
+	}
+
+	var lookup, _ = field.(*fields.BlindIndexField).Lookup("my-secret")
+	if value != lookup {
+		t.Errorf("expected blind index %q, got %q", lookup, value)
+	}
+
+	if value != fields.BlindIndex(blindIndexKey, []byte("my-secret")) {
+		t.Errorf("expected blind index to match BlindIndex()")
+	}
+
+	m.Secret = ""
+	value, _ = field.Value()
+	if value != fields.BlindIndex(blindIndexKey, []byte("")) {
+		t.Errorf("expected blind index of empty string, got %v", value)
+	}
+}
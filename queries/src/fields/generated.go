@@ -0,0 +1,335 @@
+package fields
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/alias"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/queries/src/expr"
+	"github.com/Nigel2392/go-django/queries/src/expr/builder"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	"github.com/Nigel2392/go-django/queries/src/resolver"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/checks"
+	"github.com/Nigel2392/go-django/src/forms/fields"
+)
+
+var (
+	_ attrs.Field                 = (*GeneratedField)(nil)
+	_ attrs.CanOnModelRegister    = (*GeneratedField)(nil)
+	_ queries.ForDBEditableField  = (*GeneratedField)(nil)
+	_ migrator.CanGeneratedColumn = (*GeneratedField)(nil)
+	_ expr.FieldResolver          = (*generatedFieldResolver)(nil)
+)
+
+type GeneratedFieldConfig struct {
+	// Stored indicates whether the value is computed when the row is written and stored,
+	// or computed every time the row is read.
+	//
+	// Only stored columns can be indexed on all supported databases.
+	// PostgreSQL always stores the value, see [migrator.GeneratedColumn.Stored].
+	Stored bool
+
+	// Database is the name of the database setting used to render the expression,
+	// it defaults to [django.APPVAR_DATABASE].
+	Database string
+}
+
+// GeneratedField wraps an [attrs.Field] of which the value is computed by the database
+// with a GENERATED ALWAYS AS (...) column.
+//
+// Unlike [NewVirtualField], the column exists in the table and can be indexed.
+//
+// The expression can only refer to fields of the same model, relations cannot be used.
+// The field is never written to the database and is read-only in forms.
+//
+// The value of the field is only updated when the model is retrieved from the database.
+type GeneratedField struct {
+	attrs.Field
+	expr expr.Expression
+	cnf  *GeneratedFieldConfig
+}
+
+// Generated wraps the provided field and returns a [GeneratedField].
+//
+// Example:
+//
+//	fields.Generated(
+//		attrs.NewField(o, "SlugLower", nil),
+//		expr.LOWER("Slug"),
+//		&fields.GeneratedFieldConfig{Stored: true},
+//	)
+func Generated(field attrs.Field, expression expr.Expression, cnf ...*GeneratedFieldConfig) *GeneratedField {
+	if field == nil {
+		panic("Generated: field is nil")
+	}
+
+	if expression == nil {
+		panic(fmt.Sprintf("Generated: expression is nil for field %q", field.Name()))
+	}
+
+	var conf = &GeneratedFieldConfig{}
+	if len(cnf) > 0 && cnf[0] != nil {
+		conf = cnf[0]
+	}
+
+	return &GeneratedField{
+		Field: field,
+		expr:  expression,
+		cnf:   conf,
+	}
+}
+
+// Unwrap returns the field which is wrapped by the [GeneratedField].
+func (g *GeneratedField) Unwrap() attrs.Field {
+	return g.Field
+}
+
+// Expression returns the expression used to compute the value of the field.
+func (g *GeneratedField) Expression() expr.Expression {
+	return g.expr
+}
+
+func (g *GeneratedField) OnModelRegister(model attrs.Definer, outer attrs.FieldDefinition) error {
+	if f, ok := g.Field.(attrs.CanOnModelRegister); ok {
+		return f.OnModelRegister(model, outer)
+	}
+	return nil
+}
+
+func (g *GeneratedField) StructField() *reflect.StructField {
+	if f, ok := g.Field.(attrs.StructFieldDefinition); ok {
+		return f.StructField()
+	}
+	return nil
+}
+
+func (g *GeneratedField) Check(ctx context.Context) []checks.Message {
+	var messages []checks.Message
+	if f, ok := g.Field.(interface {
+		Check(ctx context.Context) []checks.Message
+	}); ok {
+		messages = append(messages, f.Check(ctx)...)
+	}
+
+	if g.IsPrimary() {
+		messages = append(messages, checks.Error(
+			"field.generated_primary",
+			fmt.Sprintf("Field %q in model %T cannot be both generated and a primary key", g.Name(), g.Instance()),
+			g, "",
+		))
+	}
+
+	return messages
+}
+
+func (g *GeneratedField) AllowEdit() bool {
+	return false
+}
+
+func (g *GeneratedField) AllowBlank() bool {
+	return true
+}
+
+// AllowDBEdit returns false, the value of the field is computed by the database
+// and cannot be used in INSERT or UPDATE statements.
+func (g *GeneratedField) AllowDBEdit() bool {
+	return false
+}
+
+func (g *GeneratedField) FormField() fields.Field {
+	var formField = g.Field.FormField()
+	if formField == nil {
+		return nil
+	}
+
+	fields.Required(false)(formField)
+	fields.ReadOnly(true)(formField)
+	return formField
+}
+
+func (g *GeneratedField) GeneratedColumn() (*migrator.GeneratedColumn, error) {
+	var sql, err = g.SQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return &migrator.GeneratedColumn{
+		Expression: sql,
+		Stored:     g.cnf.Stored,
+	}, nil
+}
+
+// SQL renders the expression of the field for the configured database.
+//
+// Column references are not qualified with the table name and values are written
+// as literals, DDL statements cannot use placeholders.
+func (g *GeneratedField) SQL() (sql string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.ValueError.Wrapf(
+				"failed to render expression for generated field %q: %v",
+				g.Name(), r,
+			)
+		}
+	}()
+
+	var model = g.Instance()
+	meta, err := resolver.NewModelInfo(model)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		compiler = queries.Compiler(g.cnf.Database)
+		res      = &generatedFieldResolver{
+			ctx:   context.Background(),
+			model: model,
+			meta:  meta,
+			alias: alias.NewGenerator(),
+		}
+		inf = queries.NewExpressionInfo(queries.ExpressionInfoInit{
+			Compiler: compiler,
+			Resolver: res,
+		})
+		sb builder.BaseBuilder
+	)
+
+	g.expr.Resolve(inf).SQL(&sb)
+
+	if err := sb.GetError(); err != nil {
+		return "", err
+	}
+
+	return inlineSQLValues(sb.String(), inf.Placeholder, sb.Vars, compiler.QuoteString)
+}
+
+// inlineSQLValues replaces the placeholders in the query with the SQL literals of the values.
+func inlineSQLValues(query, placeholder string, values []any, quote func(string) string) (string, error) {
+	var (
+		sb       strings.Builder
+		inString bool
+		idx      int
+	)
+
+	for i := 0; i < len(query); i++ {
+		switch {
+		case query[i] == '\'':
+			inString = !inString
+			sb.WriteByte(query[i])
+		case !inString && strings.HasPrefix(query[i:], placeholder):
+			if idx >= len(values) {
+				return "", errors.ValueError.Wrapf(
+					"not enough values for placeholders in %q", query,
+				)
+			}
+
+			var literal, err = sqlLiteral(values[idx], quote)
+			if err != nil {
+				return "", err
+			}
+
+			sb.WriteString(literal)
+			i += len(placeholder) - 1
+			idx++
+		default:
+			sb.WriteByte(query[i])
+		}
+	}
+
+	if idx != len(values) {
+		return "", errors.ValueError.Wrapf(
+			"too many values for placeholders in %q", query,
+		)
+	}
+
+	return sb.String(), nil
+}
+
+func sqlLiteral(value any, quote func(string) string) (string, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		var v, err = valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		value = v
+	}
+
+	var rv = reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return "NULL", nil
+	case reflect.String:
+		return quote(strings.ReplaceAll(rv.String(), "'", "''")), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strings.ToUpper(strconv.FormatBool(rv.Bool())), nil
+	}
+
+	return "", errors.TypeMismatch.Wrapf(
+		"unsupported value type %T in generated column expression", value,
+	)
+}
+
+// generatedFieldResolver resolves fields to unqualified columns of the model's own table.
+type generatedFieldResolver struct {
+	ctx   context.Context
+	model attrs.Definer
+	meta  resolver.ModelInfo
+	alias *alias.Generator
+}
+
+func (r *generatedFieldResolver) Meta() expr.ModelMeta {
+	return r.meta
+}
+
+func (r *generatedFieldResolver) Alias() *alias.Generator {
+	return r.alias
+}
+
+func (r *generatedFieldResolver) Context() context.Context {
+	return r.ctx
+}
+
+func (r *generatedFieldResolver) ResolverInfoForModel(model attrs.Definer) *expr.ExpressionInfo {
+	panic(errors.NotImplemented.Wrapf(
+		"generated columns of %T cannot refer to other models (%T)",
+		r.model, model,
+	))
+}
+
+func (r *generatedFieldResolver) Resolve(fieldName string, inf *expr.ExpressionInfo) (attrs.Definer, attrs.FieldDefinition, *expr.TableColumn, error) {
+	if strings.Contains(fieldName, ".") {
+		return nil, nil, nil, errors.UnsupportedLookup.Wrapf(
+			"generated columns cannot refer to related fields, got %q", fieldName,
+		)
+	}
+
+	var field, ok = attrs.Define(r.ctx, r.model).Field(fieldName)
+	if !ok {
+		return nil, nil, nil, errors.FieldNotFound.Wrapf(
+			"field %q not found in %T", fieldName, r.model,
+		)
+	}
+
+	if field.ColumnName() == "" {
+		return nil, nil, nil, errors.FieldNotFound.Wrapf(
+			"field %q in %T has no column", fieldName, r.model,
+		)
+	}
+
+	return r.model, field, &expr.TableColumn{
+		FieldColumn: field,
+	}, nil
+}
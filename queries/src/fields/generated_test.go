@@ -0,0 +1,67 @@
+package fields_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/expr"
+	"github.com/Nigel2392/go-django/queries/src/fields"
+	"github.com/Nigel2392/go-django/queries/src/migrator"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+type generatedModel struct {
+	ID        int
+	Slug      string
+	SlugLower string
+}
+
+func (m *generatedModel) FieldDefs(ctx context.Context) attrs.Definitions {
+	return attrs.Make[*generatedModel, attrs.Field](ctx, m,
+		attrs.NewField(m, "ID", &attrs.FieldConfig{
+			Primary: true,
+		}),
+		attrs.NewField(m, "Slug", nil),
+		fields.Generated(attrs.NewField(m, "SlugLower", nil), expr.LOWER("Slug"), &fields.GeneratedFieldConfig{
+			Stored: true,
+		}),
+	)
+}
+
+func TestGeneratedFieldNotEditable(t *testing.T) {
+	var m = &generatedModel{Slug: "Hello", SlugLower: "hello"}
+	var field, _ = attrs.Define(context.Background(), m).Field("SlugLower")
+	if queries.ForDBEdit(field) {
+		t.Errorf("expected generated field to be excluded from INSERT and UPDATE")
+	}
+
+	var formField, ok = field.FormField().(interface{ ReadOnly() bool })
+	if !ok || !formField.ReadOnly() {
+		t.Errorf("expected generated field to be read-only in forms")
+	}
+}
+
+func TestGeneratedColumnChanges(t *testing.T) {
+	var stored = &migrator.GeneratedColumn{Expression: `LOWER("slug")`, Stored: true}
+	if sql := stored.SQL(); sql != `GENERATED ALWAYS AS (LOWER("slug")) STORED` {
+		t.Errorf("unexpected generated column SQL: %q", sql)
+	}
+
+	var virtual = &migrator.GeneratedColumn{Expression: `LOWER("slug")`}
+	if sql := virtual.SQL(); sql != `GENERATED ALWAYS AS (LOWER("slug")) VIRTUAL` {
+		t.Errorf("unexpected generated column SQL: %q", sql)
+	}
+
+	var oldCol = &migrator.Column{Name: "slug_lower", Generated: stored}
+	var newCol = &migrator.Column{Name: "slug_lower", Generated: virtual}
+	if !slices.Contains(oldCol.ChangeList(newCol), "Generated") {
+		t.Errorf("expected change from stored to virtual to be detected")
+	}
+
+	newCol.Generated = &migrator.GeneratedColumn{Expression: `LOWER("slug")`, Stored: true}
+	if slices.Contains(oldCol.ChangeList(newCol), "Generated") {
+		t.Errorf("expected equal generated columns to not be changed")
+	}
+}
@@ -345,6 +345,12 @@ func (r *RelationField[T]) Attrs() map[string]any {
 	if r.cnf.Rel != nil {
 		atts[migrator.AttrUseInDBKey] = r.cnf.Rel.Through() == nil && !r.IsReverse()
 	}
+
+	// A one-to-one proxy is the parent link of a child model,
+	// each parent row can only have a single child row.
+	if r.cnf.IsProxy && r.cnf.Rel != nil && r.cnf.Rel.Type() == attrs.RelOneToOne {
+		atts[attrs.AttrUniqueKey] = true
+	}
 	return atts
 }
 
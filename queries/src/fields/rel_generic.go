@@ -7,6 +7,7 @@ import (
 	"github.com/Nigel2392/go-django/queries/src/expr"
 	"github.com/Nigel2392/go-django/src/core/attrs"
 	"github.com/Nigel2392/go-django/src/core/contenttypes"
+	"github.com/Nigel2392/go-django/src/core/logger"
 )
 
 var (
@@ -35,6 +36,13 @@ type GenericForeignKeyConfig struct {
 	RelPrimary       string
 	ContentTypeField string
 	TargetField      string
+
+	// StoreContentTypeID stores the persisted ID of the content type in the content type field
+	// instead of the type name, the field should then be an integer field.
+	//
+	// This requires the contenttypes app to be installed, see [contenttypes.IDForType].
+	// Queries are compiled with the IDs cached by the app, see [contenttypes.CachedIDForType].
+	StoreContentTypeID bool
 }
 
 type GenericForeignKeyFieldDefiner interface {
@@ -94,7 +102,19 @@ func (f *genericForeignKeyField[T]) SetValue(value interface{}, force bool) erro
 		targetcType        = contenttypes.NewContentType(definer)
 	)
 
-	if err := targetCtypeField.SetValue(targetcType.TypeName(), force); err != nil {
+	var ctypeValue any = targetcType.TypeName()
+	if f.cnf.StoreContentTypeID {
+		var id, err = contenttypes.IDForType(f.defs.Context(), targetcType.TypeName())
+		if err != nil {
+			return fmt.Errorf(
+				"failed to get content type ID for %T in model %T: %w",
+				definer, f.obj, err,
+			)
+		}
+		ctypeValue = id
+	}
+
+	if err := targetCtypeField.SetValue(ctypeValue, force); err != nil {
 		return fmt.Errorf(
 			"failed to set value for target CType field %s in model %T: %w",
 			targetCtypeField.Name(), f.obj, err,
@@ -215,6 +235,22 @@ func (f *genericForeignKeyField[T]) GenerateTargetClause(qs expr.FieldResolver,
 	f.setupRelatedFields()
 
 	var sourceType = contenttypes.NewContentType(f.obj)
+	var ctypeValue any = sourceType.TypeName()
+	if f.cnf.StoreContentTypeID {
+		// The IDs are resolved before queries are compiled, the database is never queried here.
+		//
+		// A content type which is not cached has not been stored yet,
+		// so no row can reference it and the join matches no rows.
+		var id, ok = contenttypes.CachedIDForType(sourceType.TypeName())
+		if !ok {
+			logger.Warnf(
+				"generic foreign key field %q in model %T: content type %q has no ID",
+				f.Name(), f.obj, sourceType.TypeName(),
+			)
+		}
+		ctypeValue = id
+	}
+
 	var cond = queries.JoinDefCondition{
 		ConditionA: expr.TableColumn{
 			TableOrAlias: lhs.Table.Alias,
@@ -233,7 +269,7 @@ func (f *genericForeignKeyField[T]) GenerateTargetClause(qs expr.FieldResolver,
 			Operator: expr.EQ,
 			ConditionB: expr.TableColumn{
 				RawSQL: "?",
-				Values: []any{sourceType.TypeName()},
+				Values: []any{ctypeValue},
 			},
 		},
 	}
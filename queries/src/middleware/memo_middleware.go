@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/mux"
+)
+
+type queryMemoMiddleware struct {
+	next mux.Handler
+}
+
+func (m *queryMemoMiddleware) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	m.next.ServeHTTP(w, req.WithContext(
+		queries.ContextWithQueryMemo(req.Context()),
+	))
+}
+
+// QueryMemoMiddleware memoizes the results of [queries.QuerySet.Get] for the duration of the request.
+//
+// Repeatedly retrieving the same object (I.E. by its primary key) only queries the database once,
+// see [queries.ContextWithQueryMemo].
+func QueryMemoMiddleware(next mux.Handler) mux.Handler {
+	return &queryMemoMiddleware{next: next}
+}
@@ -7,20 +7,34 @@ import (
 	"fmt"
 
 	"github.com/Nigel2392/go-django/src/core/command"
+	"github.com/Nigel2392/go-django/src/core/command/flags"
 	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-signals"
 )
 
-var commandMigrate = &command.Cmd[migrationFlags]{
+// OnMigrated is sent after the `migrate` command has finished applying the migrations,
+// also when there were no migrations left to apply.
+//
+// It can be used to synchronize data with the state of the database, I.E. the persisted content types.
+var OnMigrated = signals.New[*MigrationEngine]("migrator.OnMigrated")
+
+type migrateFlags struct {
+	migrationFlags
+	Schemas flags.List
+}
+
+var commandMigrate = &command.Cmd[migrateFlags]{
 	ID:   "migrate",
 	Desc: "Apply database migrations created with `makemigrations`",
-	FlagFunc: func(m command.Manager, flags *migrationFlags, f *flag.FlagSet) error {
+	FlagFunc: func(m command.Manager, flags *migrateFlags, f *flag.FlagSet) error {
 		f.BoolVar(&flags.Fake, "fake", false, "Do not create the migration files, just print what would be done")
 		f.BoolVar(&flags.Fake, "f", false, "Alias for --fake")
 		f.Var(&flags.Apps, "apps", "List of apps to create migrations for (default: all apps)")
 		f.Var(&flags.Apps, "a", "Alias for --apps")
+		f.Var(&flags.Schemas, "schema", "List of database schemas to apply the migrations to, I.E. for tenants (default: the default schema)")
 		return nil
 	},
-	Execute: func(m command.Manager, stored migrationFlags, args []string) error {
+	Execute: func(m command.Manager, stored migrateFlags, args []string) error {
 		var engine = app.engine
 		if engine == nil {
 			panic("migrate: engine is nil, please call django.Initialize() first")
@@ -43,17 +57,51 @@ var commandMigrate = &command.Cmd[migrationFlags]{
 			)
 		}
 
+		if schemas := stored.Schemas.List(); len(schemas) > 0 {
+			if err := MigrateSchemas(ctx, schemas, appsList...); err != nil {
+				return err
+			}
+			return command.ErrShouldExit
+		}
+
 		err = engine.Migrate(ctx, appsList...)
-		if errors.Is(err, ErrNoChanges) {
+		if err != nil && !errors.Is(err, ErrNoChanges) {
+			return err
+		}
+
+		if sigErr := OnMigrated.Send(ctx, engine); sigErr != nil {
+			return fmt.Errorf("failed to handle migrated signal: %w", sigErr)
+		}
+
+		if err != nil {
 			logger.Info(err)
 			return errors.Join(
 				err, command.ErrShouldExit,
 			)
 		}
-		if err != nil {
-			return err
-		}
 
 		return command.ErrShouldExit
 	},
 }
+
+// MigrateSchemas applies the migrations of the given apps to each of the database schemas,
+// see [MigrationEngine.MigrateSchema].
+//
+// This requires the migrator app to be installed and initialized.
+func MigrateSchemas(ctx context.Context, schemas []string, apps ...string) error {
+	if app.engine == nil {
+		return errors.New("migrator engine is not initialized, please call django.Initialize() first")
+	}
+
+	for _, schema := range schemas {
+		var err = app.engine.MigrateSchema(ctx, schema, apps...)
+		if errors.Is(err, ErrNoChanges) {
+			logger.Infof("schema %q: %s", schema, err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to migrate schema %q: %w", schema, err)
+		}
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/Nigel2392/go-django/queries/src/drivers"
 	django "github.com/Nigel2392/go-django/src"
 	"github.com/Nigel2392/go-django/src/core/attrs"
 	"github.com/Nigel2392/go-django/src/core/contenttypes"
@@ -309,9 +310,16 @@ func (m *MigrationEngine) Migrate(ctx context.Context, apps ...string) error {
 		return err
 	}
 
-	transaction, err := m.SchemaEditor.StartTransaction(ctx)
-	if err != nil {
-		return errors.Wrap(err, "failed to begin transaction")
+	// the transaction is managed by the caller if one is
+	// already present in the context, see [MigrationEngine.MigrateSchema]
+	var transaction drivers.Transaction
+	if tx, ok := DbFromContext(ctx, nil).(drivers.Transaction); ok {
+		transaction = &callerTransaction{tx}
+	} else {
+		transaction, err = m.SchemaEditor.StartTransaction(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to begin transaction")
+		}
 	}
 	defer transaction.Rollback(ctx)
 
@@ -420,6 +428,57 @@ func (m *MigrationEngine) Migrate(ctx context.Context, apps ...string) error {
 	return nil
 }
 
+// MigrateSchema applies the migrations of the given apps to the database schema.
+//
+// The schema is created if it does not exist yet, the migrations are tracked
+// in the schema itself, so each schema is migrated independently.
+//
+// This is used to migrate the schemas of tenants, it requires a driver
+// which supports schemas, see [drivers.SupportsSchemas].
+func (m *MigrationEngine) MigrateSchema(ctx context.Context, schema string, apps ...string) error {
+	if schema == "" {
+		return errors.New("schema name cannot be empty")
+	}
+
+	transaction, err := m.SchemaEditor.StartTransaction(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer transaction.Rollback(ctx)
+
+	if err = drivers.CreateSchema(ctx, transaction, schema); err != nil {
+		return errors.Wrapf(err, "failed to create schema %q", schema)
+	}
+
+	if err = drivers.UseSchema(ctx, transaction, schema); err != nil {
+		return errors.Wrapf(err, "failed to use schema %q", schema)
+	}
+
+	err = m.Migrate(ContextWithDb(ctx, transaction), apps...)
+	if err != nil && !errors.Is(err, ErrNoChanges) {
+		return err
+	}
+
+	if err := transaction.Commit(ctx); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return err
+}
+
+// callerTransaction is a transaction which is committed or rolled back by the caller.
+type callerTransaction struct {
+	drivers.Transaction
+}
+
+func (t *callerTransaction) Commit(context.Context) error {
+	return nil
+}
+
+func (t *callerTransaction) Rollback(context.Context) error {
+	return nil
+}
+
 type NeedsToMigrateInfo struct {
 	model *contenttypes.BaseContentType[attrs.Definer]
 	mig   *MigrationFile
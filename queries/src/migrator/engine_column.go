@@ -2,10 +2,12 @@ package migrator
 
 import (
 	"context"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 
 	dr "github.com/Nigel2392/go-django/internal/django_reflect"
@@ -15,6 +17,48 @@ import (
 	"github.com/Nigel2392/go-django/src/core/contenttypes"
 )
 
+// ChoicesConstraint defines how the allowed values of a field with choices are enforced by the database.
+type ChoicesConstraint string
+
+const (
+	// ChoicesConstraintNone does not enforce the choices in the database.
+	ChoicesConstraintNone ChoicesConstraint = ""
+
+	// ChoicesConstraintCheck adds a CHECK (column IN (...)) constraint to the column.
+	ChoicesConstraintCheck ChoicesConstraint = "check"
+
+	// ChoicesConstraintEnum uses a native ENUM type for the column if the database supports it.
+	//
+	// Only MySQL supports inline ENUM types, SQLite falls back to a CHECK constraint.
+	// Postgres only has named ENUM types which the migrator does not create, migrating
+	// a column with this constraint returns an error, use [ChoicesConstraintCheck] instead.
+	ChoicesConstraintEnum ChoicesConstraint = "enum"
+)
+
+// GeneratedColumn describes a column of which the value is computed by the database.
+type GeneratedColumn struct {
+	// Expression is the SQL expression used to compute the value of the column.
+	Expression string `json:"expression"`
+
+	// Stored is true if the value is computed when the row is written and stored on disk,
+	// otherwise the value is computed when the row is read.
+	//
+	// PostgreSQL always uses stored generated columns, virtual ones are only supported from version 18.
+	Stored bool `json:"stored,omitempty"`
+}
+
+// SQL returns the GENERATED ALWAYS AS (...) clause for the column.
+//
+// Columns which are not stored are VIRTUAL, the PostgreSQL schema editor
+// writes its own (always stored) clause.
+func (g *GeneratedColumn) SQL() string {
+	var kind = "VIRTUAL"
+	if g.Stored {
+		kind = "STORED"
+	}
+	return fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", g.Expression, kind)
+}
+
 type Column struct {
 	Table        Table              `json:"-"`
 	Field        attrs.Field        `json:"-"`
@@ -34,6 +78,10 @@ type Column struct {
 	Default      drivers.Value[any] `json:"default,omitzero"`
 	ReverseAlias string             `json:"reverse_alias,omitempty"`
 	Rel          *MigrationRelation `json:"relation,omitempty"`
+
+	Choices           []any             `json:"choices,omitempty"`
+	ChoicesConstraint ChoicesConstraint `json:"choices_constraint,omitempty"`
+	Generated         *GeneratedColumn  `json:"generated,omitempty"`
 }
 
 func (c *Column) String() string {
@@ -81,6 +129,14 @@ func NewTableColumn(table Table, field attrs.Field) Column {
 	attrScale, _ := attrs.GetFromAttributes[int64](atts, attrs.AttrScaleKey)
 	attrOnDelete, _ := attrs.GetFromAttributes[Action](atts, AttrOnDeleteKey)
 	attrOnUpdate, _ := attrs.GetFromAttributes[Action](atts, AttrOnUpdateKey)
+	attrChoicesConstraint, _ := attrs.GetFromAttributes[ChoicesConstraint](atts, AttrChoicesConstraintKey)
+
+	// choices are only stored in the column if they
+	// have to be enforced by the database
+	var attrChoices []any
+	if attrChoicesConstraint != ChoicesConstraintNone {
+		attrChoices, _ = attrs.GetFromAttributes[[]any](atts, attrs.AttrChoicesKey)
+	}
 
 	var rel *MigrationRelation
 	var fRel = field.Rel()
@@ -184,6 +240,18 @@ func NewTableColumn(table Table, field attrs.Field) Column {
 		panic(fmt.Sprintf("field (%T)%v has name %q", field, field, field))
 	}
 
+	// generated columns cannot have a default value,
+	// the value is always computed by the database
+	var generated *GeneratedColumn
+	if g, ok := field.(CanGeneratedColumn); ok {
+		var err error
+		generated, err = g.GeneratedColumn()
+		if err != nil {
+			panic(fmt.Errorf("failed to get generated column for field %q: %w", field.Name(), err))
+		}
+		dflt = nil
+	}
+
 	var col = Column{
 		Table:     table,
 		Field:     field,
@@ -203,8 +271,11 @@ func NewTableColumn(table Table, field attrs.Field) Column {
 		Default: drivers.Value[any]{
 			V: dflt,
 		},
-		ReverseAlias: attrReverseAlias,
-		Rel:          rel,
+		ReverseAlias:      attrReverseAlias,
+		Rel:               rel,
+		Choices:           attrChoices,
+		ChoicesConstraint: attrChoicesConstraint,
+		Generated:         generated,
 	}
 
 	return col
@@ -284,7 +355,7 @@ func jsonCompare(newDefault, oldDefault interface{}) (bool, error) {
 
 // even zero values are considered valid defaults
 func (c *Column) HasDefault() bool {
-	if c == nil {
+	if c == nil || c.Generated != nil {
 		return false
 	}
 	if c.Default.V == nil {
@@ -393,6 +464,15 @@ checkRevAlias:
 	if c.ReverseAlias != other.ReverseAlias {
 		l = append(l, "ReverseAlias")
 	}
+	if c.ChoicesConstraint != other.ChoicesConstraint {
+		l = append(l, "ChoicesConstraint")
+	}
+	if equal, err := jsonCompare(c.Choices, other.Choices); err != nil || !equal {
+		l = append(l, "Choices")
+	}
+	if (c.Generated == nil) != (other.Generated == nil) || c.Generated != nil && *c.Generated != *other.Generated {
+		l = append(l, "Generated")
+	}
 	if (c.Rel == nil) != (other.Rel == nil) {
 		l = append(l, "Rel")
 		return l
@@ -459,6 +539,49 @@ checkRevAlias:
 	return l
 }
 
+// HasChoicesConstraint reports whether the choices of the column should be enforced by the database.
+func (c *Column) HasChoicesConstraint() bool {
+	return c != nil && len(c.Choices) > 0 && c.ChoicesConstraint != ChoicesConstraintNone
+}
+
+// ChoicesConstraintName returns the name of the constraint
+// used to enforce the choices of the column in the given table.
+func (c *Column) ChoicesConstraintName(tableName string) string {
+	return fmt.Sprintf("%s_%s_choices", tableName, c.Column)
+}
+
+// ChoicesSQL returns the choices of the column as a comma-separated list of SQL literals,
+// this can be used to write the CHECK constraint or ENUM type for the column.
+func (c *Column) ChoicesSQL() string {
+	var values = make([]string, 0, len(c.Choices))
+	for _, choice := range c.Choices {
+		if valuer, ok := choice.(driver.Valuer); ok {
+			var v, err = valuer.Value()
+			if err != nil {
+				panic(fmt.Errorf("failed to get value from driver.Valuer: %w", err))
+			}
+			choice = v
+		}
+
+		var rv = reflect.ValueOf(choice)
+		switch rv.Kind() {
+		case reflect.String:
+			values = append(values, fmt.Sprintf("'%s'", strings.ReplaceAll(rv.String(), "'", "''")))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			values = append(values, strconv.FormatInt(rv.Int(), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			values = append(values, strconv.FormatUint(rv.Uint(), 10))
+		case reflect.Float32, reflect.Float64:
+			values = append(values, strconv.FormatFloat(rv.Float(), 'f', -1, 64))
+		case reflect.Bool:
+			values = append(values, strings.ToUpper(strconv.FormatBool(rv.Bool())))
+		default:
+			panic(fmt.Errorf("unsupported choice type %T for column %q", choice, c.Column))
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
 func (c *Column) Equals(other *Column) bool {
 	return len(c.ChangeList(other)) == 0
 }
@@ -17,6 +17,9 @@ const (
 	AttrOnDeleteKey = "migrator.on_delete"
 	AttrOnUpdateKey = "migrator.on_update"
 
+	// The database constraint to use for fields with choices, see [ChoicesConstraint].
+	AttrChoicesConstraintKey = "migrator.choices_constraint"
+
 	// Keys for attrs.ModelMeta
 	MetaAllowMigrateKey = "migrator.allow_migrate"
 
@@ -28,6 +31,13 @@ type CanColumnDBType interface {
 	DBType(*Column) dbtype.Type
 }
 
+// CanGeneratedColumn is implemented by fields of which the value is generated by the database.
+//
+// The column will be created with a GENERATED ALWAYS AS (...) clause.
+type CanGeneratedColumn interface {
+	GeneratedColumn() (*GeneratedColumn, error)
+}
+
 type CanMigrate interface {
 	CanMigrate() bool
 }
@@ -6,11 +6,13 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Nigel2392/go-django/queries/src/drivers"
 	"github.com/Nigel2392/go-django/queries/src/migrator"
+	"github.com/Nigel2392/go-django/queries/src/migrator/sql/mysql"
 	"github.com/Nigel2392/go-django/queries/src/models"
 	"github.com/Nigel2392/go-django/src/core/attrs"
 	mysql_driver "github.com/go-sql-driver/mysql"
@@ -168,3 +170,24 @@ func TestTableTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteColumnChoicesConstraint(t *testing.T) {
+	var table = migrator.NewModelTable(&User{})
+	var col migrator.Column
+	for _, c := range table.Columns() {
+		if c.Column == "name" {
+			col = *c
+		}
+	}
+
+	col.Choices = []any{"draft", "published"}
+	col.ChoicesConstraint = migrator.ChoicesConstraintCheck
+
+	var w strings.Builder
+	mysql.WriteColumn(&w, col)
+
+	var expected = "CONSTRAINT `users_name_choices` CHECK (`name` IN ('draft', 'published'))"
+	if !strings.Contains(w.String(), expected) {
+		t.Fatalf("expected the CHECK constraint to be named, got %s", w.String())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -135,7 +136,7 @@ func (m *MySQLSchemaEditor) CreateTable(ctx context.Context, table migrator.Tabl
 			w.WriteString(",\n")
 		}
 		w.WriteString("  ")
-		WriteColumn(&w, *col)
+		writeColumn(&w, table.TableName(), *col, true)
 		written = true
 	}
 	w.WriteString("\n);")
@@ -239,7 +240,7 @@ func (m *MySQLSchemaEditor) AddField(ctx context.Context, table migrator.Table,
 	w.WriteString("ALTER TABLE `")
 	w.WriteString(table.TableName())
 	w.WriteString("` ADD COLUMN ")
-	WriteColumn(&w, col)
+	writeColumn(&w, table.TableName(), col, true)
 	_, err := m.Execute(ctx, w.String())
 	return err
 }
@@ -251,21 +252,97 @@ func (m *MySQLSchemaEditor) RemoveField(ctx context.Context, table migrator.Tabl
 }
 
 func (m *MySQLSchemaEditor) AlterField(ctx context.Context, table migrator.Table, oldCol, newCol migrator.Column) error {
-	query := fmt.Sprintf("ALTER TABLE `%s` MODIFY COLUMN ", table.TableName())
+	var (
+		changes         = oldCol.ChangeList(&newCol)
+		choicesChanged  = slices.Contains(changes, "Choices") || slices.Contains(changes, "ChoicesConstraint")
+		dropOldCheck    = choicesChanged && writesCheck(oldCol)
+		addNewCheck     = choicesChanged && writesCheck(newCol)
+		constraintName  = newCol.ChoicesConstraintName(table.TableName())
+		alterTableQuery = fmt.Sprintf("ALTER TABLE `%s`", table.TableName())
+	)
+
+	// Only stored generated columns can be converted to regular columns in place,
+	// other changes to generated columns require the column to be re-created.
+	if slices.Contains(changes, "Generated") && !(oldCol.Generated != nil && oldCol.Generated.Stored && newCol.Generated == nil) {
+		var w strings.Builder
+		w.WriteString(alterTableQuery)
+		w.WriteString(" DROP COLUMN `")
+		w.WriteString(oldCol.Column)
+		w.WriteString("`, ADD COLUMN ")
+		writeColumn(&w, table.TableName(), newCol, true)
+		_, err := m.Execute(ctx, w.String())
+		return err
+	}
+
+	if dropOldCheck {
+		var _, err = m.Execute(ctx, fmt.Sprintf(
+			"%s DROP CHECK `%s`;", alterTableQuery, constraintName,
+		))
+		if err != nil {
+			return err
+		}
+	}
+
 	var w strings.Builder
-	w.WriteString(query)
-	WriteColumn(&w, newCol)
-	_, err := m.Execute(ctx, w.String())
-	return err
+	w.WriteString(alterTableQuery)
+	w.WriteString(" MODIFY COLUMN ")
+	writeColumn(&w, table.TableName(), newCol, false)
+	if _, err := m.Execute(ctx, w.String()); err != nil {
+		return err
+	}
+
+	if addNewCheck {
+		var _, err = m.Execute(ctx, fmt.Sprintf(
+			"%s ADD CONSTRAINT `%s` CHECK (`%s` IN (%s));",
+			alterTableQuery, constraintName, newCol.Column, newCol.ChoicesSQL(),
+		))
+		return err
+	}
+
+	return nil
+}
+
+// writesCheck reports whether the choices of the column are enforced with a CHECK constraint.
+//
+// Columns with [migrator.ChoicesConstraintEnum] are written as a native ENUM type instead.
+func writesCheck(col migrator.Column) bool {
+	return col.HasChoicesConstraint() && col.ChoicesConstraint != migrator.ChoicesConstraintEnum
 }
 
+// WriteColumn writes the definition of the column, the CHECK constraint for its choices
+// is named after the table of the column, see [migrator.Column.ChoicesConstraintName].
 func WriteColumn(w *strings.Builder, col migrator.Column) {
+	var tableName string
+	if col.Table != nil {
+		tableName = col.Table.TableName()
+	}
+	writeColumn(w, tableName, col, true)
+}
+
+// writeColumn writes the definition of the column in the table.
+//
+// The CHECK constraint for the choices of the column is always named if the table is known,
+// MySQL generates a name for unnamed constraints which AlterField would not be able to drop.
+func writeColumn(w *strings.Builder, tableName string, col migrator.Column, withCheck bool) {
 	w.WriteString("`")
 	w.WriteString(col.Column)
 	w.WriteString("` ")
-	w.WriteString(migrator.GetFieldType(
-		&mysql.MySQLDriver{}, &col,
-	))
+
+	if col.HasChoicesConstraint() && col.ChoicesConstraint == migrator.ChoicesConstraintEnum {
+		w.WriteString("ENUM(")
+		w.WriteString(col.ChoicesSQL())
+		w.WriteString(")")
+	} else {
+		w.WriteString(migrator.GetFieldType(
+			&mysql.MySQLDriver{}, &col,
+		))
+	}
+
+	// the generated clause must directly follow the data type
+	if col.Generated != nil {
+		w.WriteString(" ")
+		w.WriteString(col.Generated.SQL())
+	}
 
 	if !col.Nullable {
 		w.WriteString(" NOT NULL")
@@ -352,4 +429,17 @@ checkRels:
 			w.WriteString(col.Rel.OnUpdate.String())
 		}
 	}
+
+	if withCheck && writesCheck(col) {
+		if tableName != "" {
+			w.WriteString(" CONSTRAINT `")
+			w.WriteString(col.ChoicesConstraintName(tableName))
+			w.WriteString("`")
+		}
+		w.WriteString(" CHECK (`")
+		w.WriteString(col.Column)
+		w.WriteString("` IN (")
+		w.WriteString(col.ChoicesSQL())
+		w.WriteString("))")
+	}
 }
@@ -6,11 +6,13 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Nigel2392/go-django/queries/src/drivers"
 	"github.com/Nigel2392/go-django/queries/src/migrator"
+	"github.com/Nigel2392/go-django/queries/src/migrator/sql/postgres"
 	"github.com/Nigel2392/go-django/src/core/attrs"
 	pg_stdlib "github.com/jackc/pgx/v5/stdlib"
 )
@@ -115,3 +117,19 @@ func TestTableTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteGeneratedColumn(t *testing.T) {
+	var obj = &tableTypeTest[string]{}
+	var col = migrator.Column{
+		Field:     attrs.NewField(obj, "Val", &attrs.FieldConfig{}),
+		Nullable:  true,
+		Generated: &migrator.GeneratedColumn{Expression: `LOWER("name")`},
+	}
+
+	var w strings.Builder
+	postgres.NewPostgresSchemaEditor(nil).WriteColumn(&w, col)
+
+	if !strings.Contains(w.String(), `GENERATED ALWAYS AS (LOWER("name")) STORED`) {
+		t.Fatalf("expected a stored generated column, got %s", w.String())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -102,10 +103,13 @@ func (m *PostgresSchemaEditor) CreateTable(ctx context.Context, table migrator.T
 		if !col.UseInDB {
 			continue
 		}
+		if err := checkChoicesConstraint(*col); err != nil {
+			return err
+		}
 		if written {
 			w.WriteString(", ")
 		}
-		m.WriteColumn(&w, *col)
+		m.writeColumn(&w, table.TableName(), *col)
 		written = true
 	}
 
@@ -193,11 +197,15 @@ func (m *PostgresSchemaEditor) RenameIndex(ctx context.Context, table migrator.T
 }
 
 func (m *PostgresSchemaEditor) AddField(ctx context.Context, table migrator.Table, col migrator.Column) error {
+	if err := checkChoicesConstraint(col); err != nil {
+		return err
+	}
+
 	var w strings.Builder
 	w.WriteString(`ALTER TABLE "`)
 	w.WriteString(table.TableName())
 	w.WriteString(`" ADD COLUMN `)
-	m.WriteColumn(&w, col)
+	m.writeColumn(&w, table.TableName(), col)
 	w.WriteString(";")
 	_, err := m.Execute(ctx, w.String())
 	return err
@@ -221,10 +229,40 @@ func (m *PostgresSchemaEditor) AlterField(ctx context.Context, table migrator.Ta
 		colName   = oldCol.Field.ColumnName()
 	)
 
+	if err := checkChoicesConstraint(newCol); err != nil {
+		return err
+	}
+
 	w.WriteString(`ALTER TABLE "`)
 	w.WriteString(tableName)
 	w.WriteString(`"`)
 
+	var changes = oldCol.ChangeList(&newCol)
+	if slices.Contains(changes, "Generated") {
+		switch {
+		case oldCol.Generated != nil && newCol.Generated != nil && oldCol.Generated.Expression == newCol.Generated.Expression:
+			// Only Stored changed, generated columns are always stored on postgres.
+		case oldCol.Generated != nil && newCol.Generated == nil:
+			// The stored values are kept when the expression is dropped.
+			w.WriteString(` ALTER COLUMN "`)
+			w.WriteString(colName)
+			w.WriteString(`" DROP EXPRESSION,`)
+		default:
+			// Generated columns cannot be altered in place, the values
+			// are computed by the database so the column is re-created.
+			w.WriteString(` DROP COLUMN "`)
+			w.WriteString(colName)
+			w.WriteString(`", ADD COLUMN `)
+			m.writeColumn(&w, tableName, newCol)
+			w.WriteString(`;`)
+
+			if _, err := m.Execute(ctx, w.String()); err != nil {
+				return fmt.Errorf("alter generated field failed: %w\nquery: %s", err, w.String())
+			}
+			return nil
+		}
+	}
+
 	// Alter column type
 
 	var (
@@ -304,6 +342,24 @@ func (m *PostgresSchemaEditor) AlterField(ctx context.Context, table migrator.Ta
 		}
 	}
 
+	// Alter choices constraint
+	if slices.Contains(changes, "Choices") || slices.Contains(changes, "ChoicesConstraint") {
+		var constraintName = newCol.ChoicesConstraintName(tableName)
+		w.WriteString(` DROP CONSTRAINT IF EXISTS "`)
+		w.WriteString(constraintName)
+		w.WriteString(`",`)
+
+		if newCol.HasChoicesConstraint() {
+			w.WriteString(` ADD CONSTRAINT "`)
+			w.WriteString(constraintName)
+			w.WriteString(`" CHECK ("`)
+			w.WriteString(newCol.Field.ColumnName())
+			w.WriteString(`" IN (`)
+			w.WriteString(newCol.ChoicesSQL())
+			w.WriteString(`)),`)
+		}
+	}
+
 	// Trim trailing comma
 	sql := strings.TrimSuffix(w.String(), ",")
 
@@ -315,12 +371,34 @@ func (m *PostgresSchemaEditor) AlterField(ctx context.Context, table migrator.Ta
 	return nil
 }
 
+// WriteColumn writes the definition of the column, the CHECK constraint for its choices
+// is named after the table of the column, see [migrator.Column.ChoicesConstraintName].
 func (m *PostgresSchemaEditor) WriteColumn(w *strings.Builder, col migrator.Column) {
+	var tableName string
+	if col.Table != nil {
+		tableName = col.Table.TableName()
+	}
+	m.writeColumn(w, tableName, col)
+}
+
+// writeColumn writes the definition of the column in the table.
+//
+// The CHECK constraint for the choices of the column is always named if the table is known,
+// AlterField drops the constraint by its name when the choices change.
+func (m *PostgresSchemaEditor) writeColumn(w *strings.Builder, tableName string, col migrator.Column) {
 	w.WriteString(`"`)
 	w.WriteString(col.Field.ColumnName())
 	w.WriteString(`" `)
 	w.WriteString(migrator.GetFieldType(&drivers.DriverPostgres{}, &col))
 
+	// PostgreSQL only supports virtual generated columns from version 18,
+	// generated columns are always stored to support all versions.
+	if col.Generated != nil {
+		w.WriteString(" GENERATED ALWAYS AS (")
+		w.WriteString(col.Generated.Expression)
+		w.WriteString(") STORED")
+	}
+
 	if col.Primary {
 		w.WriteString(" PRIMARY KEY")
 	}
@@ -401,4 +479,29 @@ checkRels:
 			w.WriteString(col.Rel.OnUpdate.String())
 		}
 	}
+
+	if col.HasChoicesConstraint() {
+		if tableName != "" {
+			w.WriteString(` CONSTRAINT "`)
+			w.WriteString(col.ChoicesConstraintName(tableName))
+			w.WriteString(`"`)
+		}
+		w.WriteString(` CHECK ("`)
+		w.WriteString(col.Field.ColumnName())
+		w.WriteString(`" IN (`)
+		w.WriteString(col.ChoicesSQL())
+		w.WriteString(`))`)
+	}
+}
+
+// checkChoicesConstraint returns an error if the choices of the column should be enforced with an ENUM type,
+// Postgres only has named ENUM types which are not created by the migrator.
+func checkChoicesConstraint(col migrator.Column) error {
+	if col.HasChoicesConstraint() && col.ChoicesConstraint == migrator.ChoicesConstraintEnum {
+		return fmt.Errorf(
+			"column %q uses migrator.ChoicesConstraintEnum, which is not supported by Postgres: use migrator.ChoicesConstraintCheck instead",
+			col.Column,
+		)
+	}
+	return nil
 }
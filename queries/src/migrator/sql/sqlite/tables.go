@@ -312,6 +312,11 @@ func (m *SQLiteSchemaEditor) RenameIndex(ctx context.Context, table migrator.Tab
 //	}
 
 func (m *SQLiteSchemaEditor) AddField(ctx context.Context, table migrator.Table, col migrator.Column) error {
+	// SQLite cannot add stored generated columns with ALTER TABLE.
+	if col.Generated != nil && col.Generated.Stored {
+		return m.rebuildTable(ctx, table, nil, col)
+	}
+
 	var w strings.Builder
 	w.WriteString("ALTER TABLE `")
 	w.WriteString(table.TableName())
@@ -344,6 +349,19 @@ func (m *SQLiteSchemaEditor) AlterField(
 	table migrator.Table,
 	oldCol migrator.Column,
 	newCol migrator.Column,
+) error {
+	return m.rebuildTable(ctx, table, &oldCol, newCol)
+}
+
+// rebuildTable re-creates the table with the new column and copies the data over.
+//
+// If oldCol is nil the new column is added to the table.
+// Generated columns are not copied, their values are computed by the database.
+func (m *SQLiteSchemaEditor) rebuildTable(
+	ctx context.Context,
+	table migrator.Table,
+	oldCol *migrator.Column,
+	newCol migrator.Column,
 ) error {
 	var (
 		tableName     = table.TableName()
@@ -368,8 +386,17 @@ func (m *SQLiteSchemaEditor) AlterField(
 			continue
 		}
 
-		if c.Name == oldCol.Name {
+		switch {
+		case oldCol == nil && c.Name == newCol.Name:
+			// the column is added, there is no data to copy yet
 			newTable.Fields.Set(newCol.Name, newCol)
+
+		case oldCol != nil && c.Name == oldCol.Name:
+			newTable.Fields.Set(newCol.Name, newCol)
+			if newCol.Generated != nil {
+				continue
+			}
+
 			columnNamesDst = append(columnNamesDst, fmt.Sprintf("`%s`", newCol.Column))
 
 			if oldCol.Nullable && !newCol.Nullable {
@@ -389,13 +416,22 @@ func (m *SQLiteSchemaEditor) AlterField(
 			} else {
 				columnNamesSrc = append(columnNamesSrc, fmt.Sprintf("`%s`", oldCol.Column))
 			}
-		} else {
+
+		default:
 			newTable.Fields.Set(c.Name, *c)
+			if c.Generated != nil {
+				continue
+			}
+
 			columnNamesDst = append(columnNamesDst, fmt.Sprintf("`%s`", c.Column))
 			columnNamesSrc = append(columnNamesSrc, fmt.Sprintf("`%s`", c.Column))
 		}
 	}
 
+	if _, ok := newTable.Fields.Get(newCol.Name); !ok {
+		newTable.Fields.Set(newCol.Name, newCol)
+	}
+
 	// Step 2: Fetch related schema (indexes, triggers)
 	var rows, err = m.query(ctx, `
 		SELECT type, name, sql FROM sqlite_schema
@@ -499,6 +535,11 @@ func (m *SQLiteSchemaEditor) WriteColumn(w *strings.Builder, col migrator.Column
 		&sqlite3.SQLiteDriver{}, &col,
 	))
 
+	if col.Generated != nil {
+		w.WriteString(" ")
+		w.WriteString(col.Generated.SQL())
+	}
+
 	if col.Primary {
 		w.WriteString(" PRIMARY KEY")
 	}
@@ -612,4 +653,14 @@ checkRels:
 			w.WriteString(col.Rel.OnUpdate.String())
 		}
 	}
+
+	// SQLite has no native ENUM type, both constraints are written as a CHECK.
+	// Altering the constraint is handled by the table rebuild in AlterField.
+	if col.HasChoicesConstraint() {
+		w.WriteString(" CHECK (`")
+		w.WriteString(col.Column)
+		w.WriteString("` IN (")
+		w.WriteString(col.ChoicesSQL())
+		w.WriteString("))")
+	}
 }
@@ -35,6 +35,8 @@ var (
 	_ queries.ThroughModelSetter           = &Model{}
 	_ queries.ActsAfterSave                = &Model{}
 	_ queries.ActsAfterQuery               = &Model{}
+	_ queries.DeferredFieldsSetter         = &Model{}
+	_ attrs.CanLoadDeferred                = &Model{}
 	_ attrs.CanSignalChanged               = &Model{}
 	_ attrs.CanCreateObject[attrs.Definer] = &Model{}
 )
@@ -774,6 +776,95 @@ func (m *Model) AfterSave(ctx context.Context) error {
 	return nil
 }
 
+// SetDeferred marks the given fields as deferred in the model's state.
+//
+// This is called by the queryset after the model was retrieved from the database
+// without these fields, see [queries.QuerySet.Defer] and [queries.QuerySet.Only].
+// The fields are loaded from the same database by [Model.LoadDeferred].
+func (m *Model) SetDeferred(database string, fieldNames ...string) {
+	m.checkValid()
+	m.internals.deferredDatabase = database
+	m.State().Defer(fieldNames...)
+}
+
+// FieldDeferred reports whether the field with the given name
+// was not retrieved from the database.
+func (m *Model) FieldDeferred(fieldName string) bool {
+	if m.internals == nil {
+		return false
+	}
+	return m.internals.State.IsDeferred(fieldName)
+}
+
+// LoadDeferred retrieves all deferred fields of the model from the database in a single query.
+//
+// This is automatically called when a deferred field is accessed through
+// [attrs.Get] or [attrs.Definitions.Get], the values of any other fields are left untouched.
+//
+// The fields are retrieved from the database the model was retrieved from,
+// inside of the transaction in the context if it belongs to that database.
+func (m *Model) LoadDeferred(ctx context.Context) error {
+	if m.internals == nil || m.internals.ReflectValue == nil {
+		return errors.NotImplemented.WithCause(fmt.Errorf(
+			"cannot load deferred fields %w (internals==nil: %t, object==nil: %t)",
+			ErrModelInitialized, m.internals == nil, m.internals != nil && m.internals.ReflectValue == nil,
+		))
+	}
+
+	var deferred = m.internals.State.Deferred()
+	if len(deferred) == 0 {
+		return nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var model = m.internals.ReflectValue.Interface().(attrs.Definer)
+	var uqFilter, err = queries.GenerateObjectsWhereClause(model)
+	if err != nil {
+		return errors.Wrapf(err, "Error generating unique filter for model %v", model)
+	}
+
+	var selectFields = make([]any, len(deferred))
+	for i, name := range deferred {
+		selectFields[i] = name
+	}
+
+	var database []string
+	if m.internals.deferredDatabase != "" {
+		database = []string{m.internals.deferredDatabase}
+	}
+
+	row, err := queries.Objects(model, database...).
+		WithContext(ctx).
+		Select(selectFields...).
+		Filter(uqFilter).
+		Get()
+	if err != nil {
+		return errors.Wrapf(err, "Error loading deferred fields %v of model %T", deferred, model)
+	}
+
+	for _, name := range deferred {
+		var loaded, ok = row.ObjectFieldDefs.Field(name)
+		if !ok {
+			return errors.FieldNotFound.Wrapf(
+				"Field %q not found in model %T definitions",
+				name, row.Object,
+			)
+		}
+
+		field, _ := m.internals.Defs.Field(name)
+		if err := field.SetValue(loaded.GetValue(), true); err != nil {
+			return errors.Wrapf(err, "Error setting deferred field %q of model %T", name, model)
+		}
+
+		m.internals.State.Undefer(name)
+	}
+
+	return nil
+}
+
 // If this model was the target end of a through relation,
 // this method will set the through model for this model.
 func (m *Model) SetThroughModel(throughModel attrs.Definer) {
@@ -1224,8 +1315,10 @@ func (m *Model) SaveObject(ctx context.Context, cnf SaveConfig) (err error) {
 	}
 
 	// Start transaction, if one was already started this is a no-op.
+	//
+	// Models which write to multiple tables are always saved in a transaction.
 	var transaction drivers.Transaction
-	if queries.QUERYSET_CREATE_IMPLICIT_TRANSACTION {
+	if queries.QUERYSET_CREATE_IMPLICIT_TRANSACTION || m.savesMultipleTables() {
 		ctx, transaction, err = queries.StartTransaction(ctx)
 		if err != nil {
 			return fmt.Errorf(
@@ -1288,6 +1381,12 @@ func (m *Model) SaveObject(ctx context.Context, cnf SaveConfig) (err error) {
 			mustInclField = true
 		}
 
+		// Deferred fields were never retrieved from the database,
+		// saving them would overwrite the stored value with a zero value.
+		if m.internals.State.IsDeferred(name) {
+			continue
+		}
+
 		// No changes were made to the field, we can skip it.
 		var hasChanged = m.internals.State.FieldChanged(name)
 		if !hasChanged && !mustInclField && !cnf.Force() && m.internals.Flags.Is(flagFromDB) {
@@ -1426,6 +1525,25 @@ func (m *Model) SaveObject(ctx context.Context, cnf SaveConfig) (err error) {
 	return transaction.Commit(ctx)
 }
 
+// savesMultipleTables reports whether saving the model writes to more than one table.
+//
+// This is the case for models with proxies (I.E. a parent model) and for
+// models with fields which are saved before or after the model itself.
+func (m *Model) savesMultipleTables() bool {
+	if m.internals.Base != nil && len(m.internals.Base.proxies) > 0 {
+		return true
+	}
+
+	for _, field := range m.internals.Defs.ObjectFields.Iter() {
+		switch field.(type) {
+		case queries.SaveableField, queries.SaveableDependantField:
+			return true
+		}
+	}
+
+	return false
+}
+
 func (m *Model) DeleteObject(ctx context.Context) error {
 	var this = m.internals.ReflectValue.Interface().(attrs.Definer)
 	var defs = attrs.Define(ctx, this)
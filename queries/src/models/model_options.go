@@ -21,6 +21,9 @@ type ModelOptions struct {
 	Defs         *attrs.ObjectDefinitions
 	State        *state.ModelState
 	Flags        bitch.Flag
+
+	// the database the deferred fields are loaded from, see [Model.SetDeferred]
+	deferredDatabase string
 }
 
 func OptionsFromModel(modelObject any) (options *ModelOptions, err error) {
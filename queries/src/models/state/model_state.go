@@ -27,6 +27,10 @@ type ModelState struct {
 	// which are retrieved from the fields Value() method,
 	// which normally returns the database compatible value
 	initial map[string]interface{}
+
+	// deferred fields, these were not loaded from the database
+	// and should not be saved until they are loaded or changed.
+	deferred map[string]struct{}
 }
 
 // initState initializes the model state for the given model.
@@ -36,7 +40,8 @@ func New(model *attrs.ObjectDefinitions) *ModelState {
 	}
 
 	var state = &ModelState{
-		model: model,
+		model:    model,
+		deferred: make(map[string]struct{}),
 	}
 
 	state.Reset()
@@ -63,6 +68,7 @@ func (m *ModelState) checkState() {
 		var initialValue, ok = m.initial[name]
 		if !ok || !reflect.DeepEqual(value, initialValue) {
 			m.changed[name] = struct{}{}
+			delete(m.deferred, name)
 		} else {
 			// if the value is equal to the initial value,
 			// we need to remove the field from the changed map
@@ -83,8 +89,10 @@ func (m *ModelState) Mark(fieldName string) {
 		panic("model state is not properly initialized: model is nil")
 	}
 
-	// mark the field as changed
+	// mark the field as changed, a changed field
+	// is no longer deferred as it holds the value to save.
 	m.changed[fieldName] = struct{}{}
+	delete(m.deferred, fieldName)
 }
 
 // Changed returns true if the model's state has changed,
@@ -189,3 +197,63 @@ func (m *ModelState) ResetFieldState(fieldName string) {
 	delete(m.changed, fieldName)
 	m.initial[fieldName] = field.GetValue()
 }
+
+// Defer marks the given fields as deferred in the model's state.
+//
+// Deferred fields were not loaded from the database, their
+// current value is meaningless and they should not be saved.
+func (m *ModelState) Defer(fieldNames ...string) {
+	if m == nil {
+		return
+	}
+
+	for _, fieldName := range fieldNames {
+		if _, ok := m.model.Field(fieldName); !ok {
+			assert.Fail("field %q does not exist in model %T", fieldName, m.model)
+		}
+
+		m.deferred[fieldName] = struct{}{}
+		delete(m.changed, fieldName)
+	}
+}
+
+// Undefer marks the given field as loaded in the model's state
+// and resets its initial value to the field's current value.
+func (m *ModelState) Undefer(fieldName string) {
+	if m == nil {
+		return
+	}
+
+	if _, ok := m.deferred[fieldName]; !ok {
+		return
+	}
+
+	delete(m.deferred, fieldName)
+	m.ResetFieldState(fieldName)
+}
+
+// IsDeferred checks if a specific field is deferred in the model's state.
+func (m *ModelState) IsDeferred(fieldName string) bool {
+	if m == nil {
+		return false
+	}
+
+	_, ok := m.deferred[fieldName]
+	return ok
+}
+
+// Deferred returns the names of all deferred fields in the model's state,
+// in the order they were defined on the model.
+func (m *ModelState) Deferred() []string {
+	if m == nil || len(m.deferred) == 0 {
+		return nil
+	}
+
+	var names = make([]string, 0, len(m.deferred))
+	for name := range m.model.ObjectFields.Iter() {
+		if _, ok := m.deferred[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
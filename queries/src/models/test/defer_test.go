@@ -0,0 +1,175 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Nigel2392/go-django/djester/quest"
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/models"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+type DeferredModel struct {
+	models.Model
+	ID    int64
+	Title string
+	Body  string
+	Data  JSONMap
+}
+
+func (m *DeferredModel) FieldDefs(ctx context.Context) attrs.Definitions {
+	return m.Model.Define(ctx, m,
+		attrs.Unbound("ID", &attrs.FieldConfig{Primary: true}),
+		attrs.Unbound("Title"),
+		attrs.Unbound("Body"),
+		attrs.Unbound("Data"),
+	)
+}
+
+func TestDeferredFields(t *testing.T) {
+	var tables = quest.Table(t,
+		&DeferredModel{},
+	)
+
+	tables.Create()
+	defer tables.Drop()
+
+	var model = models.Setup(context.Background(), &DeferredModel{
+		Title: "Title",
+		Body:  "Body",
+		Data: JSONMap{
+			"key": "value",
+		},
+	})
+
+	if err := model.Save(context.Background()); err != nil {
+		t.Fatalf("Failed to save model: %v", err)
+	}
+
+	t.Run("Defer", func(t *testing.T) {
+		var row, err = queries.GetQuerySet(&DeferredModel{}).
+			Defer("Body", "Data").
+			Filter("ID", model.ID).
+			Get()
+		if err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+
+		if row.Object.Title != "Title" {
+			t.Errorf("Expected Title to be loaded, got: %q", row.Object.Title)
+		}
+
+		if row.Object.Body != "" || row.Object.Data != nil {
+			t.Errorf("Expected Body and Data to be deferred, got: %q, %v", row.Object.Body, row.Object.Data)
+		}
+
+		for _, name := range []string{"Body", "Data"} {
+			if !row.Object.FieldDeferred(name) {
+				t.Errorf("Expected %s to be deferred", name)
+			}
+		}
+
+		if row.Object.FieldDeferred("Title") {
+			t.Error("Expected Title not to be deferred")
+		}
+
+		var body = attrs.Get[string](t.Context(), row.Object, "Body")
+		if body != "Body" {
+			t.Errorf("Expected Body to be loaded on access, got: %q", body)
+		}
+
+		if row.Object.FieldDeferred("Data") || row.Object.Data["key"] != "value" {
+			t.Errorf("Expected Data to be loaded with Body, got: %v", row.Object.Data)
+		}
+
+		if row.Object.State().Changed(true) {
+			t.Error("Expected state to be unchanged after loading deferred fields")
+		}
+	})
+
+	t.Run("Only", func(t *testing.T) {
+		var row, err = queries.GetQuerySet(&DeferredModel{}).
+			Only("Title").
+			Filter("ID", model.ID).
+			Get()
+		if err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+
+		if row.Object.ID != model.ID {
+			t.Errorf("Expected primary key to be loaded, got: %d", row.Object.ID)
+		}
+
+		if !row.Object.FieldDeferred("Body") || !row.Object.FieldDeferred("Data") {
+			t.Error("Expected Body and Data to be deferred")
+		}
+	})
+
+	t.Run("NotSelected", func(t *testing.T) {
+		// Body is never selected, so deferring it does not apply
+		var row, err = queries.GetQuerySet(&DeferredModel{}).
+			Select("ID", "Title").
+			Defer("Body").
+			Filter("ID", model.ID).
+			Get()
+		if err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+
+		if row.Object.FieldDeferred("Body") {
+			t.Error("Expected Body not to be marked as deferred when it was not selected")
+		}
+	})
+
+	t.Run("Memoized", func(t *testing.T) {
+		var ctx = queries.ContextWithQueryMemo(context.Background())
+		for i := 0; i < 2; i++ {
+			var row, err = queries.GetQuerySetWithContext(ctx, &DeferredModel{}).
+				Defer("Body").
+				Filter("ID", model.ID).
+				Get()
+			if err != nil {
+				t.Fatalf("Failed to get model: %v", err)
+			}
+
+			if !row.Object.FieldDeferred("Body") || row.Object.FieldDeferred("Data") {
+				t.Errorf("Expected only Body to be deferred on get %d", i+1)
+			}
+
+			if row.Object.Body != "" || row.Object.Data["key"] != "value" {
+				t.Errorf("Expected only Body to be left out on get %d, got: %q, %v", i+1, row.Object.Body, row.Object.Data)
+			}
+		}
+	})
+
+	t.Run("SaveExcludesDeferred", func(t *testing.T) {
+		var row, err = queries.GetQuerySet(&DeferredModel{}).
+			Only("Title").
+			Filter("ID", model.ID).
+			Get()
+		if err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+
+		row.Object.Title = "New Title"
+		if err := row.Object.Save(context.Background()); err != nil {
+			t.Fatalf("Failed to save model: %v", err)
+		}
+
+		reloaded, err := queries.GetQuerySet(&DeferredModel{}).
+			Filter("ID", model.ID).
+			Get()
+		if err != nil {
+			t.Fatalf("Failed to reload model: %v", err)
+		}
+
+		if reloaded.Object.Title != "New Title" {
+			t.Errorf("Expected Title to be saved, got: %q", reloaded.Object.Title)
+		}
+
+		if reloaded.Object.Body != "Body" || reloaded.Object.Data["key"] != "value" {
+			t.Errorf("Expected deferred fields to be left untouched, got: %q, %v", reloaded.Object.Body, reloaded.Object.Data)
+		}
+	})
+}
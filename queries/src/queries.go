@@ -385,6 +385,15 @@ type ThroughModelSetter interface {
 	SetThroughModel(throughModel attrs.Definer)
 }
 
+// A model can adhere to this interface to keep track of the fields
+// which were not retrieved from the database, see [QuerySet.Defer].
+//
+// SetDeferred is called with the name of the database the object was retrieved from
+// and the names of the deferred fields, the deferred fields should be loaded from the same database.
+type DeferredFieldsSetter interface {
+	SetDeferred(database string, fieldNames ...string)
+}
+
 // A model can adhere to this interface to indicate that the queries package
 // should not automatically save or delete the model to/from the database when
 // `django/models.SaveObject()` or `django/models.DeleteObject()` is called.
@@ -0,0 +1,281 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+// ChildModel is a model which inherits from a parent model.
+//
+// A model inherits from a parent model by embedding a pointer to it,
+// the embedded pointer becomes a one-to-one proxy field (the parent link)
+// which is stored in a column of the child's table.
+type ChildModel struct {
+	// Model is the (empty) child model.
+	Model attrs.Definer
+
+	// ParentLink is the name of the proxy field on the child
+	// which links the child to the parent.
+	ParentLink string
+}
+
+var inheritance = struct {
+	mu       sync.RWMutex
+	children map[reflect.Type][]ChildModel
+}{
+	children: make(map[reflect.Type][]ChildModel),
+}
+
+// isParentLink reports whether the proxy field is the parent link of a child model.
+//
+// Only one-to-one proxies which store the parent link in a column of the child's
+// table are parent links, polymorphic proxies (I.E. content type based) are resolved
+// by the specific package.
+func isParentLink(field ProxyField) bool {
+	var rel = field.Rel()
+	return rel != nil && rel.Type() == attrs.RelOneToOne && field.ColumnName() != ""
+}
+
+// hasParentLink reports whether the model inherits from a parent model.
+func hasParentLink(definer attrs.Definer) bool {
+	var tree = ProxyFields(definer)
+	for head := tree.fields.Front(); head != nil; head = head.Next() {
+		if isParentLink(head.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// registerChildModel registers the definer as a child model for each
+// of the parent links in the proxy tree.
+func registerChildModel(definer attrs.Definer, tree *proxyTree) {
+	inheritance.mu.Lock()
+	defer inheritance.mu.Unlock()
+
+	var childType = reflect.TypeOf(definer)
+	for head := tree.fields.Front(); head != nil; head = head.Next() {
+		if !isParentLink(head.Value) {
+			continue
+		}
+
+		var parentType = reflect.TypeOf(head.Value.Rel().Model())
+		var exists = slices.ContainsFunc(inheritance.children[parentType], func(c ChildModel) bool {
+			return reflect.TypeOf(c.Model) == childType
+		})
+		if exists {
+			continue
+		}
+
+		inheritance.children[parentType] = append(inheritance.children[parentType], ChildModel{
+			Model:      definer,
+			ParentLink: head.Key,
+		})
+	}
+}
+
+// reverseName returns the name of the reverse relation of the
+// child's parent link on the parent model.
+func (c ChildModel) reverseName(parent attrs.Definer) (string, error) {
+	var childType = reflect.TypeOf(c.Model)
+	var revMap = attrs.GetModelMeta(parent).ReverseMap()
+	for head := revMap.Front(); head != nil; head = head.Next() {
+		var rel = head.Value
+		if rel.Type() != attrs.RelOneToOne || reflect.TypeOf(rel.Model()) != childType {
+			continue
+		}
+
+		if field := rel.Field(); field == nil || field.Name() == c.ParentLink {
+			return head.Key, nil
+		}
+	}
+
+	return "", errors.FieldNotFound.Wrapf(
+		"reverse relation of parent link %q in %T not found on %T",
+		c.ParentLink, c.Model, parent,
+	)
+}
+
+// ChildModels returns the registered models which directly inherit from the parent model.
+func ChildModels(parent attrs.Definer) []ChildModel {
+	inheritance.mu.RLock()
+	defer inheritance.mu.RUnlock()
+	return slices.Clone(inheritance.children[reflect.TypeOf(parent)])
+}
+
+// Specific marks the queryset to downcast each row to the most specific child model.
+//
+// After the rows are retrieved the children of the objects are loaded with a single
+// query which joins all child models, the most specific object is stored in [Row.Specific].
+//
+// If an object has no children, [Row.Specific] is the object itself.
+func (qs *QuerySet[T]) Specific() *QuerySet[T] {
+	var nqs = qs.clone()
+	nqs.specific = true
+	return nqs
+}
+
+// SpecificObjects returns the most specific child model for each of the objects.
+//
+// The objects must all be of the same type, objects without children are returned as-is.
+//
+// The children of all child models are loaded in a single query on the parent model,
+// which selects the reverse relation of each parent link. Grandchildren are resolved
+// recursively in the same manner, with a single query for each child model which has
+// children of it's own.
+func SpecificObjects[T attrs.Definer](ctx context.Context, objects []T) ([]attrs.Definer, error) {
+	var specific = make([]attrs.Definer, len(objects))
+	for i, obj := range objects {
+		specific[i] = obj
+	}
+
+	if len(objects) == 0 {
+		return specific, nil
+	}
+
+	var children = ChildModels(objects[0])
+	if len(children) == 0 {
+		return specific, nil
+	}
+
+	var primary = attrs.Define(ctx, objects[0]).Primary()
+	if primary == nil {
+		return nil, errors.NoUniqueKey.Wrapf(
+			"cannot load children of %T: model has no primary key",
+			objects[0],
+		)
+	}
+
+	var (
+		pks     = make([]any, 0, len(objects))
+		indexes = make(map[any]int, len(objects))
+	)
+	for i, obj := range objects {
+		var pk = attrs.PrimaryKey(ctx, obj)
+		if attrs.IsZero(pk) {
+			continue
+		}
+		pks = append(pks, pk)
+		indexes[pk] = i
+	}
+
+	if len(pks) == 0 {
+		return specific, nil
+	}
+
+	var (
+		reverseNames = make([]string, len(children))
+		selectFields = make([]any, 0, len(children)+1)
+	)
+	selectFields = append(selectFields, primary.Name())
+	for i, child := range children {
+		var name, err = child.reverseName(objects[0])
+		if err != nil {
+			return nil, err
+		}
+		reverseNames[i] = name
+		selectFields = append(selectFields, fmt.Sprintf("%s.*", name))
+	}
+
+	var rows, err = GetQuerySet(objects[0]).
+		WithContext(ctx).
+		Select(selectFields...).
+		Filter(fmt.Sprintf("%s__in", primary.Name()), pks).
+		All()
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "failed to load children for %T",
+			objects[0],
+		)
+	}
+
+	var (
+		childObjects = make([][]attrs.Definer, len(children))
+		childIndexes = make([][]int, len(children))
+	)
+	for _, row := range rows {
+		var idx, ok = indexes[attrs.PrimaryKey(ctx, row.Object)]
+		if !ok {
+			continue
+		}
+
+		var defs = attrs.Define(ctx, row.Object)
+		for i, name := range reverseNames {
+			var field, ok = defs.Field(name)
+			if !ok {
+				return nil, errors.FieldNotFound.Wrapf(
+					"reverse relation %q not found in model %T",
+					name, row.Object,
+				)
+			}
+
+			var value = field.GetValue()
+			if attrs.IsZero(value) {
+				continue
+			}
+
+			child, ok := value.(attrs.Definer)
+			if !ok || attrs.IsZero(attrs.PrimaryKey(ctx, child)) {
+				continue
+			}
+
+			// The parent was not joined for the child,
+			// link it to the object which was passed in.
+			link, ok := attrs.Define(ctx, child).Field(children[i].ParentLink)
+			if !ok {
+				return nil, errors.FieldNotFound.Wrapf(
+					"parent link %q not found in model %T",
+					children[i].ParentLink, child,
+				)
+			}
+
+			if err := link.SetValue(objects[idx], true); err != nil {
+				return nil, errors.Wrapf(
+					err, "failed to set parent link %q in %T",
+					children[i].ParentLink, child,
+				)
+			}
+
+			childObjects[i] = append(childObjects[i], child)
+			childIndexes[i] = append(childIndexes[i], idx)
+		}
+	}
+
+	for i := range children {
+		var objs, err = SpecificObjects(ctx, childObjects[i])
+		if err != nil {
+			return nil, err
+		}
+
+		for j, obj := range objs {
+			specific[childIndexes[i][j]] = obj
+		}
+	}
+
+	return specific, nil
+}
+
+// loadSpecific sets [Row.Specific] for each of the rows, see [QuerySet.Specific].
+func (qs *QuerySet[T]) loadSpecific(rows []*Row[T]) error {
+	var objects = make([]T, len(rows))
+	for i, row := range rows {
+		objects[i] = row.Object
+	}
+
+	var specific, err = SpecificObjects(qs.context, objects)
+	if err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		row.Specific = specific[i]
+	}
+
+	return nil
+}
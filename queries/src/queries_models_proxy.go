@@ -27,6 +27,8 @@ var _, _ = attrs.OnModelRegister.Listen(context.Background(), func(ctx context.C
 		proxyFields,
 	)
 
+	registerChildModel(meta.Definer, proxyFields)
+
 	return nil
 })
 
@@ -41,3 +41,58 @@ var (
 	// Signal to be executed after deleting a model instance.
 	SignalPostModelDelete = signals_model.Get("queries.model.post_delete")
 )
+
+// M2MAction is the kind of change made to a many-to-many relation.
+type M2MAction string
+
+const (
+	// Targets were added to the relation, see [RelManyToManyQuerySet.AddTargets].
+	M2MActionAdd M2MAction = "add"
+
+	// Targets were removed from the relation, see [RelManyToManyQuerySet.RemoveTargets].
+	M2MActionRemove M2MAction = "remove"
+
+	// All targets were removed from the relation, see [RelManyToManyQuerySet.ClearTargets].
+	M2MActionClear M2MAction = "clear"
+
+	// The targets of the relation were replaced, see [RelManyToManyQuerySet.SetTargets].
+	M2MActionSet M2MAction = "set"
+)
+
+// M2MSignal is used to notify when the targets of a many-to-many relation change.
+type M2MSignal struct {
+	Context context.Context
+
+	// The action which was performed on the relation.
+	Action M2MAction
+
+	// The object which owns the relation and the relation's field.
+	Source attrs.Definer
+	Field  attrs.Field
+
+	// An instance of the target and through model of the relation.
+	Target  attrs.Definer
+	Through attrs.Definer
+
+	// The primary keys of the affected targets.
+	//
+	// For [M2MActionSet] these are the primary keys of the new targets,
+	// the previous targets are removed before with an [M2MActionClear] signal.
+	//
+	// Pre-add signals do not include targets which are not yet saved.
+	PKs []any
+}
+
+var (
+	signals_m2m = signals.NewPool[M2MSignal]()
+
+	// Signal to be executed before the targets of a many-to-many relation are changed.
+	//
+	// Returning an error from a receiver aborts the change.
+	SignalPreM2MChange = signals_m2m.Get("queries.m2m.pre_change")
+
+	// Signal to be executed after the targets of a many-to-many relation were changed.
+	//
+	// The change is already made, errors returned by receivers are logged.
+	SignalPostM2MChange = signals_m2m.Get("queries.m2m.post_change")
+)
@@ -0,0 +1,161 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/queries/src/expr"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+// Tenant is a tenant that queries are executed for, see [ContextWithTenant].
+type Tenant interface {
+	// TenantID returns the value which is stored in
+	// the tenant field of tenant scoped models.
+	TenantID() any
+}
+
+// TenantScopedModel can be implemented by models of which each row belongs to a single tenant.
+//
+// QuerySets for these models are automatically filtered on the tenant
+// stored in the queryset's context and the tenant field is set on objects created
+// through a queryset, if the field does not have a value yet.
+//
+// The scope fails closed: querysets which are not bound to a context with a tenant
+// (see [ContextWithTenant]) do not select, update or delete any rows, and objects
+// without a tenant cannot be created.
+//
+// The tenant field should hold the value returned by [Tenant.TenantID].
+type TenantScopedModel interface {
+	attrs.Definer
+
+	// TenantField returns the name of the field which holds the tenant.
+	TenantField() string
+}
+
+type tenantContextKey struct{}
+
+type tenantContextValue struct {
+	tenant Tenant
+}
+
+// ContextWithTenant returns a context in which queries are executed for the given tenant.
+//
+// Querysets for [TenantScopedModel] models bound to the context with [QuerySet.WithContext]
+// only select, update and delete rows of the tenant.
+//
+// Passing a nil tenant explicitly disables the tenant scope, I.E. for management
+// commands which should operate on the rows of all tenants.
+func ContextWithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, &tenantContextValue{
+		tenant: tenant,
+	})
+}
+
+// TenantFromContext returns the tenant stored in the context, see [ContextWithTenant].
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	var v, ok = tenantFromContext(ctx)
+	if !ok || v.tenant == nil {
+		return nil, false
+	}
+	return v.tenant, true
+}
+
+// tenantFromContext returns the value stored by [ContextWithTenant],
+// the tenant of the value is nil if the tenant scope was disabled.
+func tenantFromContext(ctx context.Context) (*tenantContextValue, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	var v, ok = ctx.Value(tenantContextKey{}).(*tenantContextValue)
+	return v, ok
+}
+
+// tenantClauses returns the where clause which limits the queryset
+// to the rows of the tenant stored in it's context.
+//
+// If the model is tenant scoped but no tenant is stored in the context,
+// a clause which matches no rows is returned.
+//
+// It returns nil if the model is not tenant scoped or the tenant scope is disabled.
+func (qs *QuerySet[T]) tenantClauses() []expr.ClauseExpression {
+	var scoped, ok = qs.internals.Model.Object.(TenantScopedModel)
+	if !ok {
+		return nil
+	}
+
+	value, ok := tenantFromContext(qs.context)
+	if !ok {
+		return []expr.ClauseExpression{expr.And(expr.Raw("1 = 0"))}
+	}
+
+	if value.tenant == nil {
+		return nil
+	}
+
+	return expr.Express(scoped.TenantField(), value.tenant.TenantID())
+}
+
+// scopeTenant adds the tenant filter to the where clause of the queryset.
+//
+// It is called right before the query is compiled, so filters added by the user,
+// I.E. `Filter(expr.Or(...))` cannot escape the tenant scope.
+func (qs *QuerySet[T]) scopeTenant() {
+	if qs.internals.tenantScoped {
+		return
+	}
+
+	var where = qs.tenantClauses()
+	if len(where) == 0 {
+		return
+	}
+
+	qs.internals.Where = append(qs.internals.Where, where...)
+	qs.internals.tenantScoped = true
+}
+
+// setTenant sets the tenant field of the object to the tenant stored
+// in the context of the queryset if the field has no value yet.
+//
+// An error is returned if the field has no value and no tenant is stored in the context.
+func (qs *QuerySet[T]) setTenant(ctx context.Context, object T) error {
+	var scoped, ok = any(object).(TenantScopedModel)
+	if !ok {
+		return nil
+	}
+
+	var defs = attrs.Define(ctx, scoped)
+	field, ok := defs.Field(scoped.TenantField())
+	if !ok {
+		return errors.FieldNotFound.Wrapf(
+			"tenant field %q not found in model %T",
+			scoped.TenantField(), object,
+		)
+	}
+
+	if !attrs.IsZero(field.GetValue()) {
+		return nil
+	}
+
+	value, ok := tenantFromContext(qs.context)
+	if !ok {
+		return errors.FieldNull.Wrapf(
+			"tenant field %q of %T has no value and the queryset is not bound to a tenant context",
+			scoped.TenantField(), object,
+		)
+	}
+
+	var tenant = value.tenant
+	if tenant == nil {
+		return nil
+	}
+
+	if err := field.SetValue(tenant.TenantID(), true); err != nil {
+		return errors.Wrapf(
+			err, "failed to set tenant field %q on %T",
+			scoped.TenantField(), object,
+		)
+	}
+
+	return nil
+}
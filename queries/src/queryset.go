@@ -172,10 +172,18 @@ type QuerySetInternals struct {
 	Offset      int
 	ForUpdate   bool
 	Distinct    bool
+	Deferred    []string
 
 	fieldsMap map[string]*FieldInfo[attrs.FieldDefinition]
 	joinsMap  map[string]struct{}
 	proxyMap  map[string]struct{}
+
+	// the tenant filter was added to the where clause, see [QuerySet.scopeTenant]
+	tenantScoped bool
+
+	// the deferred fields which were left out of the selection by [QuerySet.QueryAll],
+	// only these are passed to [DeferredFieldsSetter.SetDeferred]
+	omittedFields []string
 }
 
 func (i *QuerySetInternals) AddJoin(join JoinDef) {
@@ -292,6 +300,9 @@ type QuerySet[T attrs.Definer] struct {
 	useCache     bool
 	peekInfo     *expr.QueryInformation
 	cached       any
+	resultCache  *resultCacheConfig
+	specific     bool
+	timeout      *time.Duration
 }
 
 // GetQuerySet creates a new QuerySet for the given model.
@@ -435,6 +446,9 @@ func ChangeObjectsType[OldT, NewT attrs.Definer](qs *QuerySet[OldT]) *QuerySet[N
 		explicitSave: qs.explicitSave,
 		useCache:     qs.useCache,
 		cached:       qs.cached,
+		resultCache:  qs.resultCache,
+		specific:     qs.specific,
+		timeout:      qs.timeout,
 		internals:    qs.internals,
 		context:      qs.context,
 		forEachRow: func(_ *QuerySet[NewT], row *Row[NewT]) error {
@@ -563,6 +577,11 @@ func (qs *QuerySet[T]) WithContext(ctx context.Context) *QuerySet[T] {
 		}
 	}
 
+	// keep the timeout set with [QuerySet.Timeout]
+	if qs.timeout != nil {
+		ctx = drivers.ContextWithQueryTimeout(ctx, *qs.timeout)
+	}
+
 	qs.context = ctx
 	return qs
 }
@@ -600,9 +619,15 @@ func (qs *QuerySet[T]) WithTransaction(tx drivers.Transaction) (drivers.Transact
 // GetOrCreateTransaction returns the current transaction if one exists,
 // or starts a new transaction if the QuerySet is not already in a transaction and QUERYSET_CREATE_IMPLICIT_TRANSACTION is true.
 func (qs *QuerySet[T]) GetOrCreateTransaction() (tx drivers.Transaction, err error) {
+	return qs.getOrCreateTransaction(QUERYSET_CREATE_IMPLICIT_TRANSACTION)
+}
+
+// getOrCreateTransaction is like [QuerySet.GetOrCreateTransaction], a new transaction
+// is only started when implicit is true.
+func (qs *QuerySet[T]) getOrCreateTransaction(implicit bool) (tx drivers.Transaction, err error) {
 	// Check if we need to start a transaction
 	var inTransaction = qs.compiler.InTransaction()
-	if !inTransaction && QUERYSET_CREATE_IMPLICIT_TRANSACTION {
+	if !inTransaction && implicit {
 		return qs.StartTransaction(qs.context)
 	}
 
@@ -638,11 +663,13 @@ func (qs *QuerySet[T]) clone() *QuerySet[T] {
 			Offset:      qs.internals.Offset,
 			ForUpdate:   qs.internals.ForUpdate,
 			Distinct:    qs.internals.Distinct,
+			Deferred:    slices.Clone(qs.internals.Deferred),
 			Unions:      slices.Clone(qs.internals.Unions),
 
-			fieldsMap: maps.Clone(qs.internals.fieldsMap),
-			joinsMap:  maps.Clone(qs.internals.joinsMap),
-			proxyMap:  maps.Clone(qs.internals.proxyMap),
+			fieldsMap:    maps.Clone(qs.internals.fieldsMap),
+			joinsMap:     maps.Clone(qs.internals.joinsMap),
+			proxyMap:     maps.Clone(qs.internals.proxyMap),
+			tenantScoped: qs.internals.tenantScoped,
 
 			// annotations are not cloned
 			// this is to prevent the previous annotations
@@ -651,6 +678,9 @@ func (qs *QuerySet[T]) clone() *QuerySet[T] {
 		forEachRow:   qs.forEachRow,
 		explicitSave: qs.explicitSave,
 		useCache:     qs.useCache,
+		resultCache:  qs.resultCache,
+		specific:     qs.specific,
+		timeout:      qs.timeout,
 		compiler:     qs.compiler,
 		context:      qs.context,
 
@@ -1066,6 +1096,132 @@ fieldsLoop:
 	return qs
 }
 
+// Defer is used to exclude fields of the model from the selection.
+//
+// The deferred fields are not retrieved from the database, objects which implement
+// [DeferredFieldsSetter] (like `models.Model`) keep track of them.
+// Their values are loaded with a single query when one of them is accessed
+// through `attrs.Get` or `Definitions.Get`, and they are left out when the object is saved.
+//
+// The primary key is always selected and cannot be deferred.
+//
+// Calling Defer without any fields clears all deferred fields.
+//
+// How to call Defer:
+//
+// `Defer("Data")`
+// `Defer("Body", "Data").Select("*", "Author.*")`
+func (qs *QuerySet[T]) Defer(fields ...string) *QuerySet[T] {
+	qs = qs.clone()
+
+	if len(fields) == 0 {
+		qs.internals.Deferred = nil
+		return qs
+	}
+
+	var deferrable = qs.deferrableFields()
+	for _, name := range fields {
+		if !slices.Contains(deferrable, name) {
+			panic(errors.FieldNotFound.Wrapf(
+				"Defer: field %q cannot be deferred in model %T",
+				name, qs.internals.Model.Object,
+			))
+		}
+
+		if !slices.Contains(qs.internals.Deferred, name) {
+			qs.internals.Deferred = append(qs.internals.Deferred, name)
+		}
+	}
+
+	return qs
+}
+
+// Only is the opposite of [QuerySet.Defer], it defers all fields of the model
+// except for the given fields and the primary key.
+//
+// Any previously deferred fields are replaced.
+//
+// How to call Only:
+//
+// `Only("Title", "Slug")`
+func (qs *QuerySet[T]) Only(fields ...string) *QuerySet[T] {
+	qs = qs.clone()
+
+	var (
+		deferrable = qs.deferrableFields()
+		primary    = attrs.GetModelMeta(qs.internals.Model.Object).Definitions().Primary()
+	)
+	for _, name := range fields {
+		if !slices.Contains(deferrable, name) && (primary == nil || primary.Name() != name) {
+			panic(errors.FieldNotFound.Wrapf(
+				"Only: field %q cannot be selected in model %T",
+				name, qs.internals.Model.Object,
+			))
+		}
+	}
+
+	qs.internals.Deferred = make([]string, 0, len(deferrable))
+	for _, name := range deferrable {
+		if !slices.Contains(fields, name) {
+			qs.internals.Deferred = append(qs.internals.Deferred, name)
+		}
+	}
+
+	return qs
+}
+
+// deferrableFields returns the names of the fields of the model
+// which are selected by default and are not the primary key.
+func (qs *QuerySet[T]) deferrableFields() []string {
+	var (
+		defs    = attrs.GetModelMeta(qs.internals.Model.Object).Definitions()
+		primary = defs.Primary()
+		fields  = ForSelectAllFields[attrs.FieldDefinition](defs)
+		names   = make([]string, 0, len(fields))
+	)
+	for _, field := range fields {
+		if primary != nil && field.Name() == primary.Name() {
+			continue
+		}
+		names = append(names, field.Name())
+	}
+	return names
+}
+
+// deferFields returns the field infos without the deferred fields of the root model,
+// together with the names of the deferred fields which were left out.
+//
+// The field infos of the root model are copied, as they
+// might be shared with other querysets.
+func deferFields(model attrs.Definer, infos []*FieldInfo[attrs.FieldDefinition], deferred []string) ([]*FieldInfo[attrs.FieldDefinition], []string) {
+	var (
+		modelType = reflect.TypeOf(model)
+		result    = make([]*FieldInfo[attrs.FieldDefinition], 0, len(infos))
+		omitted   = make([]string, 0, len(deferred))
+	)
+	for _, info := range infos {
+		if len(info.Chain) > 0 || info.Model == nil || reflect.TypeOf(info.Model) != modelType {
+			result = append(result, info)
+			continue
+		}
+
+		var copied = *info
+		copied.Fields = make([]attrs.FieldDefinition, 0, len(info.Fields))
+		for _, field := range info.Fields {
+			if !slices.Contains(deferred, field.Name()) {
+				copied.Fields = append(copied.Fields, field)
+			} else if !slices.Contains(omitted, field.Name()) {
+				omitted = append(omitted, field.Name())
+			}
+		}
+
+		if len(copied.Fields) > 0 {
+			result = append(result, &copied)
+		}
+	}
+	return result, omitted
+}
+
 func (qs *QuerySet[T]) Alias() *alias.Generator {
 	if qs.AliasGen == nil {
 		qs.AliasGen = alias.NewGenerator()
@@ -2576,6 +2732,8 @@ func (qs *QuerySet[T]) BuildExpression() expr.Expression {
 }
 
 func (qs *QuerySet[T]) QueryAll(fields ...any) CompiledRowsQuery[[][]interface{}] {
+	qs.scopeTenant()
+
 	// Select all fields if no fields are provided
 	//
 	// Override the pointer to the original QuerySet with the Select("*") QuerySet
@@ -2583,8 +2741,14 @@ func (qs *QuerySet[T]) QueryAll(fields ...any) CompiledRowsQuery[[][]interface{}
 		*qs = *qs.Select("*")
 	}
 
+	// fields which are explicitly passed are selected as-is, deferral does not apply to them
+	qs.internals.omittedFields = nil
 	if len(fields) > 0 {
 		*qs = *qs.Select(fields...)
+	} else if len(qs.internals.Deferred) > 0 {
+		qs.internals.Fields, qs.internals.omittedFields = deferFields(
+			qs.internals.Model.Object, qs.internals.Fields, qs.internals.Deferred,
+		)
 	}
 
 	var query = qs.compiler.BuildSelectQuery(
@@ -2596,6 +2760,7 @@ func (qs *QuerySet[T]) QueryAll(fields ...any) CompiledRowsQuery[[][]interface{}
 }
 
 func (qs *QuerySet[T]) QueryAggregate() CompiledRowsQuery[[][]interface{}] {
+	qs.scopeTenant()
 	qs.internals.OrderBy = nil     // no order by for aggregates
 	qs.internals.Limit = 0         // no limit for aggregates
 	qs.internals.Offset = 0        // no offset for aggregates
@@ -2621,6 +2786,7 @@ func (qs *QuerySet[T]) ClearOrderBy() *QuerySet[T] {
 }
 
 func (qs *QuerySet[T]) QueryCount() CompiledRowQuery[int64] {
+	qs.scopeTenant()
 	var q = qs.compiler.BuildCountQuery(
 		qs.context, qs, qs.internals,
 	)
@@ -2884,6 +3050,19 @@ func (qs *QuerySet[T]) All() (Rows[T], error) {
 		return qs.cached.([]*Row[T]), nil
 	}
 
+	if qs.resultCache != nil {
+		// copy the rows, the cached rows are shared with other callers
+		var compiled = qs.clone()
+		var rows, err = cacheResult(
+			qs, qs.resultCache.backend, qs.resultCache.ttl, "all",
+			compiled.QueryAll(), qs.withoutResultCache().All,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return copyRows(compiled, rows)
+	}
+
 	var rowIdx = 0
 	var rowCount, rowIter, err = qs.IterAll()
 	if err != nil {
@@ -2902,6 +3081,14 @@ func (qs *QuerySet[T]) All() (Rows[T], error) {
 		rowIdx++
 	}
 
+	if qs.specific {
+		if err := qs.loadSpecific(root); err != nil {
+			return nil, errors.Wrapf(
+				err, "failed to load specific objects for QuerySet.All: %s", err,
+			)
+		}
+	}
+
 	return root, nil
 }
 
@@ -2923,6 +3110,24 @@ func (qs *QuerySet[T]) Values(fields ...any) ([]map[string]any, error) {
 		return qs.cached.([]map[string]any), nil
 	}
 
+	if qs.resultCache != nil {
+		var values, err = cacheResult(
+			qs, qs.resultCache.backend, qs.resultCache.ttl, "values",
+			qs.clone().QueryAll(fields...), func() ([]map[string]any, error) {
+				return qs.withoutResultCache().Values(fields...)
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var copied = make([]map[string]any, len(values))
+		for i, value := range values {
+			copied[i] = maps.Clone(value)
+		}
+		return copied, nil
+	}
+
 	var (
 		resultQuery       = qs.QueryAll(fields...)
 		preAlloc, results = iterQuery(resultQuery)
@@ -3069,6 +3274,24 @@ func (qs *QuerySet[T]) ValuesList(fields ...any) ([][]interface{}, error) {
 		return qs.cached.([][]any), nil
 	}
 
+	if qs.resultCache != nil {
+		var values, err = cacheResult(
+			qs, qs.resultCache.backend, qs.resultCache.ttl, "values_list",
+			qs.clone().QueryAll(fields...), func() ([][]interface{}, error) {
+				return qs.withoutResultCache().ValuesList(fields...)
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var copied = make([][]interface{}, len(values))
+		for i, value := range values {
+			copied[i] = slices.Clone(value)
+		}
+		return copied, nil
+	}
+
 	var preAlloc, results, err = qs.IterValuesList(fields...)
 	if err != nil {
 		return nil, err
@@ -3169,11 +3392,29 @@ func (qs *QuerySet[T]) Get() (*Row[T], error) {
 		return qs.cached.(*Row[T]), nil
 	}
 
-	var nillRow = &Row[T]{}
-
 	// limit to max_get_results
 	qs.internals.Limit = MAX_GET_RESULTS
 
+	// memoize the result for the duration of the context, see [ContextWithQueryMemo]
+	if memo, ok := queryMemoFromContext(qs.context); ok {
+		// the compiled clone knows which deferred fields were left out of the selection
+		var compiled = qs.clone()
+		var row, err = cacheResult(
+			qs, memo, 0, "get",
+			compiled.QueryAll(), qs.get,
+		)
+		if err != nil {
+			return row, err
+		}
+		return copyRow(compiled, row)
+	}
+
+	return qs.get()
+}
+
+func (qs *QuerySet[T]) get() (*Row[T], error) {
+	var nillRow = &Row[T]{}
+
 	var results, err = qs.All()
 	if err != nil {
 		return nillRow, err
@@ -3300,6 +3541,7 @@ func (qs *QuerySet[T]) Last() (*Row[T], error) {
 func (qs *QuerySet[T]) Exists() (bool, error) {
 	qs.internals.Limit = 1  // limit to 1 row
 	qs.internals.Offset = 0 // no offset for exists
+	qs.scopeTenant()
 	var resultQuery = qs.compiler.BuildCountQuery(
 		qs.context, qs, qs.internals,
 	)
@@ -3317,6 +3559,13 @@ func (qs *QuerySet[T]) Exists() (bool, error) {
 // It returns a CountQuery that can be executed to get the result, which is an int64 indicating the number of rows.
 func (qs *QuerySet[T]) Count() (int64, error) {
 	var q = qs.QueryCount()
+	if qs.resultCache != nil {
+		return cacheResult(
+			qs, qs.resultCache.backend, qs.resultCache.ttl, "count",
+			q, q.Exec,
+		)
+	}
+
 	var count, err = q.Exec()
 	if err != nil {
 		return 0, err
@@ -3324,6 +3573,28 @@ func (qs *QuerySet[T]) Count() (int64, error) {
 	return count, nil
 }
 
+// Explain is used to retrieve the query plan for the current query.
+//
+// The query is compiled the same way as [QuerySet.All] would compile it,
+// and explained with the database specific EXPLAIN statement.
+//
+// The returned plan contains both the raw output of the database and a driver-neutral tree of the plan,
+// [drivers.QueryPlan.FullTableScans] can be used to check if the query uses the expected indexes.
+//
+// Only the main query is explained, queries executed to preload relations are not.
+func (qs *QuerySet[T]) Explain(opts ...drivers.ExplainOptions) (*drivers.QueryPlan, error) {
+	var options drivers.ExplainOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	var query = qs.clone().QueryAll()
+	return drivers.Explain(
+		qs.Context(), qs.compiler.DB(),
+		query.SQL(), query.Args(), options,
+	)
+}
+
 // Create is used to create a new object in the database.
 //
 // It takes a definer object as an argument and returns a Query that can be executed
@@ -3336,9 +3607,14 @@ func (qs *QuerySet[T]) Count() (int64, error) {
 //
 // If `ExplicitSave()` was called, the `Create()` method will return a query that can be executed to create the object
 // without calling the `Save()` method on the model.
+//
+// Models which inherit from a parent model write to multiple tables,
+// these are always created in a transaction.
 func (qs *QuerySet[T]) Create(value T) (T, error) {
 
-	var tx, err = qs.GetOrCreateTransaction()
+	var tx, err = qs.getOrCreateTransaction(
+		QUERYSET_CREATE_IMPLICIT_TRANSACTION || hasParentLink(qs.internals.Model.Object),
+	)
 	if err != nil {
 		return *new(T), errors.FailedStartTransaction.WithCause(err)
 	}
@@ -3532,6 +3808,10 @@ func (qs *QuerySet[T]) BulkCreate(objects []T) ([]T, error) {
 			)
 		}
 
+		if err = qs.setTenant(ctx, object); err != nil {
+			return nil, err
+		}
+
 		if _, err = runActor(ctx, actsBeforeCreate, object); err != nil {
 			return nil, errors.Wrapf(
 				err,
@@ -3670,6 +3950,8 @@ func (qs *QuerySet[T]) BulkCreate(objects []T) ([]T, error) {
 		if err != nil {
 			return nil, err
 		}
+
+		invalidateQueryCacheOnCommit(qs.context, qs.internals.Model.Table)
 	}
 
 	// Check results & which returning method to use
@@ -3856,6 +4138,7 @@ func (qs *QuerySet[T]) BuildUpdateInfo(params ...any) ([]UpdateInfo, error) {
 		objects         = make([]T, 0, len(params))
 		infos           = make([]UpdateInfo, 0, len(params))
 		where           = slices.Clone(qs.internals.Where)
+		tenantWhere     = qs.tenantClauses()
 		joins           = slices.Clone(qs.internals.Joins)
 		context         = &ValidationContext{
 			Context: qs.context,
@@ -4038,6 +4321,9 @@ func (qs *QuerySet[T]) BuildUpdateInfo(params ...any) ([]UpdateInfo, error) {
 			info.Where = where
 		}
 
+		// scope the update to the tenant of the context, see [ContextWithTenant]
+		info.Where = slices.Concat(info.Where, tenantWhere)
+
 		if len(joins) > 0 {
 			info.Joins = joins
 		}
@@ -4054,7 +4340,7 @@ func (qs *QuerySet[T]) BuildUpdateInfo(params ...any) ([]UpdateInfo, error) {
 				},
 				Fields: make([]attrs.Field, 0, len(usedExprs)),
 			},
-			Where: where,
+			Where: slices.Concat(where, tenantWhere),
 			Joins: joins,
 		}
 
@@ -4116,6 +4402,8 @@ func (qs *QuerySet[T]) BulkUpdate(params ...any) (int64, error) {
 		if err != nil {
 			return 0, err
 		}
+
+		invalidateQueryCacheOnCommit(qs.context, referencingTables(qs.context, qs.internals.Model.Object)...)
 	}
 
 	return res, tx.Commit(qs.context)
@@ -4236,6 +4524,8 @@ func (qs *QuerySet[T]) Delete(objects ...T) (int64, error) {
 
 	var res int64
 	if IsCommitContext(qs.context) {
+		qs.scopeTenant()
+
 		var resultQuery = qs.compiler.BuildDeleteQuery(
 			qs.context, qs, qs.internals,
 		)
@@ -4245,6 +4535,8 @@ func (qs *QuerySet[T]) Delete(objects ...T) (int64, error) {
 		if err != nil {
 			return 0, err
 		}
+
+		invalidateQueryCacheOnCommit(qs.context, referencingTables(qs.context, qs.internals.Model.Object)...)
 	}
 
 	if len(objects) > 0 {
@@ -4335,6 +4627,9 @@ func (qs *QuerySet[T]) updateFields(obj attrs.Definer) (attrs.Definitions, []att
 						"field %q not found in %T", field.Name(), obj,
 					)))
 				}
+				if !ForDBEdit(f) {
+					continue
+				}
 				fields = append(fields, f)
 			}
 		}
@@ -4342,6 +4637,9 @@ func (qs *QuerySet[T]) updateFields(obj attrs.Definer) (attrs.Definitions, []att
 		var all = defs.Fields()
 		fields = make([]attrs.Field, 0, len(all))
 		for _, field := range all {
+			if !ForDBEdit(field) {
+				continue
+			}
 			var val = field.GetValue()
 			var rVal = reflect.ValueOf(val)
 			if rVal.IsValid() && rVal.IsZero() {
@@ -0,0 +1,479 @@
+package queries
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-django/src/core/metrics"
+	"github.com/Nigel2392/go-django/src/core/trace"
+	"github.com/Nigel2392/go-signals"
+)
+
+// QueryCache is a backend to store the results of querysets in, see [QuerySet.Cache].
+//
+// It is satisfied by the backends of `github.com/Nigel2392/cache`, I.E. `cache.GetCache("default")`.
+//
+// Results are stored as-is, the backend must be able to store arbitrary Go values,
+// like the in-memory backend does.
+type QueryCache interface {
+	Get(ctx context.Context, key string) (interface{}, error)
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// QueryCacheStats holds the counters of the queryset result cache.
+type QueryCacheStats struct {
+	// Results served from the cache.
+	Hits int64
+
+	// Results which had to be retrieved from the database.
+	Misses int64
+
+	// Number of times the cached results for a table were invalidated.
+	Invalidations int64
+}
+
+type resultCacheConfig struct {
+	ttl     time.Duration
+	backend QueryCache
+}
+
+var (
+	queryCacheHits          atomic.Int64
+	queryCacheMisses        atomic.Int64
+	queryCacheInvalidations atomic.Int64
+
+	// generation counter for each table, map[string]*atomic.Uint64
+	//
+	// the generations of the tables involved are part of the cache key,
+	// invalidating a table makes all previously cached keys unreachable.
+	queryCacheGenerations sync.Map
+
+	cacheRequestsTotal = metrics.Default.Counter(metrics.Opts{
+		Name:   "django_cache_requests_total",
+		Help:   "The number of cache lookups, by cache and result.",
+		Labels: []string{"cache", "result"},
+	})
+)
+
+func init() {
+	var invalidateInstance = func(ctx context.Context, s signals.Signal[ModelSignal], ms ModelSignal) error {
+		if ms.Instance == nil {
+			return nil
+		}
+		invalidateQueryCacheOnCommit(ctx, attrs.Define(ctx, ms.Instance).TableName())
+		return nil
+	}
+
+	SignalPostModelSave.Listen(context.Background(), invalidateInstance)
+	SignalPostModelCreate.Listen(context.Background(), invalidateInstance)
+	SignalPostModelDelete.Listen(context.Background(), func(ctx context.Context, s signals.Signal[ModelSignal], ms ModelSignal) error {
+		if ms.Instance == nil {
+			return nil
+		}
+		invalidateQueryCacheOnCommit(ctx, referencingTables(ctx, ms.Instance)...)
+		return nil
+	})
+
+	SignalPostM2MChange.Listen(context.Background(), func(ctx context.Context, s signals.Signal[M2MSignal], ms M2MSignal) error {
+		if ms.Through == nil {
+			return nil
+		}
+		invalidateQueryCacheOnCommit(ctx, attrs.Define(ctx, ms.Through).TableName())
+		return nil
+	})
+}
+
+// GetQueryCacheStats returns the counters of the queryset result cache since the program started.
+//
+// Counters for a single request are available on [drivers.QueryInformation.CacheStats].
+func GetQueryCacheStats() QueryCacheStats {
+	return QueryCacheStats{
+		Hits:          queryCacheHits.Load(),
+		Misses:        queryCacheMisses.Load(),
+		Invalidations: queryCacheInvalidations.Load(),
+	}
+}
+
+// InvalidateQueryCache invalidates all cached queryset results
+// which involve any of the given tables.
+//
+// This is automatically done for the model's table when objects are
+// created, updated or deleted through a queryset, when the post save and delete
+// signals are sent and for the through table when a many-to-many relation changes.
+//
+// Invalidation is tracked per process, results cached in a shared backend
+// by other processes are only invalidated once their TTL expires.
+func InvalidateQueryCache(tables ...string) {
+	for _, table := range tables {
+		var gen, _ = queryCacheGenerations.LoadOrStore(table, new(atomic.Uint64))
+		gen.(*atomic.Uint64).Add(1)
+		queryCacheInvalidations.Add(1)
+	}
+}
+
+// invalidateQueryCacheOnCommit invalidates the tables once the transaction
+// in the context is committed, or immediately if there is no transaction, see [OnCommit].
+//
+// Results are not cached inside of a transaction, invalidating before the commit
+// would allow other callers to cache the old results again until it is committed.
+func invalidateQueryCacheOnCommit(ctx context.Context, tables ...string) {
+	OnCommit(ctx, func(ctx context.Context) {
+		InvalidateQueryCache(tables...)
+	})
+}
+
+// referencingTables returns the table of the model, along with the tables of all models
+// which (transitively) reference it through a relation.
+//
+// Rows in these tables can be changed by the database itself when the model's rows are
+// deleted or updated, I.E. through ON DELETE CASCADE or ON UPDATE SET NULL.
+func referencingTables(ctx context.Context, model attrs.Definer) []string {
+	var (
+		tables []string
+		seen   = make(map[string]struct{})
+		walk   func(model attrs.Definer)
+	)
+
+	walk = func(model attrs.Definer) {
+		var table = attrs.Define(ctx, model).TableName()
+		if _, ok := seen[table]; ok {
+			return
+		}
+		seen[table] = struct{}{}
+		tables = append(tables, table)
+
+		var reverse = attrs.GetModelMeta(model).ReverseMap()
+		for head := reverse.Front(); head != nil; head = head.Next() {
+			if through := head.Value.Through(); through != nil {
+				walk(through.Model())
+			}
+			if related := head.Value.Model(); related != nil {
+				walk(related)
+			}
+		}
+	}
+
+	walk(model)
+	return tables
+}
+
+func queryCacheGeneration(table string) uint64 {
+	var gen, ok = queryCacheGenerations.Load(table)
+	if !ok {
+		return 0
+	}
+	return gen.(*atomic.Uint64).Load()
+}
+
+// Cache enables caching of the results of [QuerySet.All], [QuerySet.Values],
+// [QuerySet.ValuesList] and [QuerySet.Count] (and any method which uses these) in the given backend.
+//
+// Results are keyed on the compiled SQL and arguments, and tagged with the tables involved
+// in the query (including joined and preloaded tables), see [InvalidateQueryCache].
+//
+// Each call returns copies of the cached results, they can safely be modified.
+//
+// Results are neither cached nor served from the cache inside of a transaction,
+// the transaction might have changed the data or see uncommitted changes.
+//
+// Passing a nil backend disables caching for the queryset.
+func (qs *QuerySet[T]) Cache(ttl time.Duration, backend QueryCache) *QuerySet[T] {
+	qs = qs.clone()
+	if backend == nil {
+		qs.resultCache = nil
+		return qs
+	}
+	qs.resultCache = &resultCacheConfig{
+		ttl:     ttl,
+		backend: backend,
+	}
+	return qs
+}
+
+// withoutResultCache returns a shallow copy of the queryset which does not use the result cache.
+func (qs *QuerySet[T]) withoutResultCache() *QuerySet[T] {
+	var cpy = *qs
+	cpy.resultCache = nil
+	return &cpy
+}
+
+// cacheTables returns the tables involved in the query.
+func (qs *QuerySet[T]) cacheTables() []string {
+	var tables = []string{qs.internals.Model.Table}
+	for _, join := range qs.internals.Joins {
+		tables = append(tables, join.Table.Name)
+	}
+
+	if qs.internals.Preload != nil {
+		for _, preload := range qs.internals.Preload.Preloads {
+			var model = preload.Model
+			if model == nil && preload.Rel != nil {
+				model = preload.Rel.Model()
+			}
+			if model != nil {
+				tables = append(tables, attrs.Define(qs.context, model).TableName())
+			}
+			if preload.Rel == nil {
+				continue
+			}
+			if through := preload.Rel.Through(); through != nil {
+				tables = append(tables, attrs.Define(qs.context, through.Model()).TableName())
+			}
+		}
+	}
+
+	slices.Sort(tables)
+	return slices.Compact(tables)
+}
+
+// queryCacheKey generates the key for the result of the query, the current
+// generations of the involved tables are part of the key.
+func queryCacheKey[T attrs.Definer](qs *QuerySet[T], kind string, query QueryInfo) string {
+	var hash = sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s\x00%#v", kind, reflect.TypeFor[T](), query.SQL(), query.Args())
+	for _, table := range qs.cacheTables() {
+		fmt.Fprintf(hash, "\x00%s:%d", table, queryCacheGeneration(table))
+	}
+	return "queries:qs:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// cacheResult returns the cached result for the query, or executes
+// the query and stores the result in the backend.
+func cacheResult[T attrs.Definer, R any](qs *QuerySet[T], backend QueryCache, ttl time.Duration, kind string, query QueryInfo, exec func() (R, error)) (R, error) {
+	if _, _, inTx := transactionFromContext(qs.context); inTx || qs.compiler.InTransaction() {
+		return exec()
+	}
+
+	var (
+		key     = queryCacheKey(qs, kind, query)
+		qi, _   = drivers.ContextQueryInfo(qs.context)
+		hasInfo = qi != nil
+	)
+
+	var _, span = trace.Start(qs.context, "cache.get", "cache", "queryset", "cache.kind", kind)
+	var cached, err = backend.Get(qs.context, key)
+	var result, hit = cached.(R)
+	hit = hit && err == nil
+	span.SetAttributes("cache.hit", hit)
+	span.End()
+
+	if hit {
+		queryCacheHits.Add(1)
+		cacheRequestsTotal.Inc("queryset", "hit")
+		if hasInfo {
+			qi.CacheHit()
+		}
+		return result, nil
+	}
+
+	queryCacheMisses.Add(1)
+	cacheRequestsTotal.Inc("queryset", "miss")
+	if hasInfo {
+		qi.CacheMiss()
+	}
+
+	result, err = exec()
+	if err != nil {
+		return result, err
+	}
+
+	_, span = trace.Start(qs.context, "cache.set", "cache", "queryset", "cache.kind", kind)
+	err = backend.Set(qs.context, key, result, ttl)
+	span.SetError(err)
+	span.End()
+
+	if err != nil {
+		logger.Warnf("failed to cache %s result for %T: %v", kind, qs.internals.Model.Object, err)
+	}
+
+	return result, nil
+}
+
+type queryMemoKey struct{}
+
+// queryMemo is an in-memory [QueryCache] which lives for the duration of a context.
+//
+// The memoized rows are never returned to the caller directly, see [copyRow].
+type queryMemo struct {
+	mu     sync.Mutex
+	values map[string]any
+}
+
+func (m *queryMemo) Get(ctx context.Context, key string) (interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var value, ok = m.values[key]
+	if !ok {
+		return nil, fmt.Errorf("no memoized result for key %q", key)
+	}
+	return value, nil
+}
+
+func (m *queryMemo) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	return nil
+}
+
+func (m *queryMemo) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, key)
+	return nil
+}
+
+// ContextWithQueryMemo returns a context in which the results of [QuerySet.Get]
+// are memoized, repeated calls with the same query (I.E. retrieving an object by its primary key)
+// only hit the database once.
+//
+// Memoized results are invalidated the same way as results cached with [QuerySet.Cache].
+// Each call returns a copy of the memoized object, it can safely be modified.
+//
+// This is meant to be used for the duration of a single request,
+// see `middleware.QueryMemoMiddleware`.
+func ContextWithQueryMemo(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(queryMemoKey{}).(*queryMemo); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, queryMemoKey{}, &queryMemo{
+		values: make(map[string]any),
+	})
+}
+
+func queryMemoFromContext(ctx context.Context) (*queryMemo, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	var memo, ok = ctx.Value(queryMemoKey{}).(*queryMemo)
+	return memo, ok
+}
+
+// copyRow returns a copy of the row with a new object, the values of the fields
+// of the row's object are copied over to the new object.
+//
+// Related objects which are stored in the fields of the object and the specific object are
+// copied as well, the through object and the values of reverse relations are shared with the original row.
+// The queryset must be compiled with [QuerySet.QueryAll], the deferred fields
+// it left out of the selection are not copied and are passed to [DeferredFieldsSetter].
+func copyRow[T attrs.Definer](qs *QuerySet[T], row *Row[T]) (*Row[T], error) {
+	var (
+		deferred = qs.internals.omittedFields
+		seen     = make(map[attrs.Definer]attrs.Definer)
+	)
+
+	var obj, err = copyObject(qs.context, row.Object, deferred, seen)
+	if err != nil {
+		return nil, err
+	}
+
+	var specific = row.Specific
+	if specific != nil {
+		if cpy, ok := seen[specific]; ok {
+			specific = cpy
+		} else if specific, err = copyObject(qs.context, specific, nil, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	var defs = obj.FieldDefs(qs.context)
+	var annotations = maps.Clone(row.Annotations)
+	if annotator, ok := any(obj).(DataModel); ok {
+		var datastore = annotator.DataStore()
+		for alias, value := range annotations {
+			datastore.SetValue(alias, value)
+		}
+	}
+
+	if setter, ok := any(obj).(DeferredFieldsSetter); ok && len(deferred) > 0 {
+		setter.SetDeferred(qs.compiler.DatabaseName(), deferred...)
+	}
+
+	if annotator, ok := any(obj).(Annotator); ok {
+		annotator.Annotate(annotations)
+	}
+
+	if _, err := runActor(qs.context, actsAfterQuery, obj); err != nil {
+		return nil, err
+	}
+
+	return &Row[T]{
+		Object:          obj,
+		ObjectFieldDefs: defs,
+		Through:         row.Through,
+		Annotations:     annotations,
+		Specific:        specific,
+	}, nil
+}
+
+// copyRows returns a copy of each of the rows, see [copyRow].
+func copyRows[T attrs.Definer](qs *QuerySet[T], rows []*Row[T]) ([]*Row[T], error) {
+	var copied = make([]*Row[T], len(rows))
+	for i, row := range rows {
+		var cpy, err = copyRow(qs, row)
+		if err != nil {
+			return nil, err
+		}
+		copied[i] = cpy
+	}
+	return copied, nil
+}
+
+// copyObject returns a new object with the values of the fields of src,
+// related objects are copied recursively.
+//
+// The copies which were already made are tracked in seen,
+// an object which is referenced more than once is only copied once.
+func copyObject[T attrs.Definer](ctx context.Context, src T, skip []string, seen map[attrs.Definer]attrs.Definer) (T, error) {
+	var (
+		obj  = attrs.NewObject[T](ctx, src)
+		defs = obj.FieldDefs(ctx)
+	)
+
+	seen[src] = obj
+
+	for _, srcField := range src.FieldDefs(ctx).Fields() {
+		if slices.Contains(skip, srcField.Name()) {
+			continue
+		}
+
+		var field, ok = defs.Field(srcField.Name())
+		if !ok {
+			continue
+		}
+
+		var value = srcField.GetValue()
+		if related, ok := value.(attrs.Definer); ok && !attrs.IsZero(related) {
+			if cpy, ok := seen[related]; ok {
+				value = cpy
+			} else {
+				var cpy, err = copyObject(ctx, related, nil, seen)
+				if err != nil {
+					return obj, err
+				}
+				value = cpy
+			}
+		}
+
+		if err := field.SetValue(value, true); err != nil {
+			return obj, errors.Wrapf(
+				err, "failed to copy field %q of %T", srcField.Name(), src,
+			)
+		}
+	}
+
+	return obj, nil
+}
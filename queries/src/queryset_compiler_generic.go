@@ -546,7 +546,7 @@ func (g *genericQueryBuilder) WithTransaction(t drivers.Transaction) (drivers.Tr
 		return nil, errors.TransactionNil
 	}
 
-	g.transaction = &wrappedTransaction{t, g}
+	g.transaction = &wrappedTransaction{Transaction: t, compiler: g}
 	return g.transaction, nil
 }
 
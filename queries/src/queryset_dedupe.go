@@ -785,6 +785,11 @@ func (r *rows[T]) compile(ctx context.Context) (count int, rowIter iter.Seq2[*Ro
 				continue
 			}
 
+			// Inform the object about fields which were not retrieved
+			if setter, ok := obj.object.obj.(DeferredFieldsSetter); ok && len(r.qs.internals.omittedFields) > 0 {
+				setter.SetDeferred(r.qs.compiler.DatabaseName(), r.qs.internals.omittedFields...)
+			}
+
 			// Annotate the object if it implements the Annotator interface
 			if annotator, ok := obj.object.obj.(Annotator); ok {
 				annotator.Annotate(obj.annotations)
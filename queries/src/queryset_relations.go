@@ -9,7 +9,9 @@ import (
 	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
 	"github.com/Nigel2392/go-django/queries/src/expr"
 	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/logger"
 	"github.com/Nigel2392/go-django/src/forms/fields"
+	"github.com/Nigel2392/go-signals"
 )
 
 type throughProxy struct {
@@ -531,6 +533,12 @@ func (r *RelManyToManyQuerySet[T]) AddTargets(targets ...T) (int64, error) {
 		return 0, fmt.Errorf("back reference is nil, cannot add targets")
 	}
 
+	r.setup()
+
+	if err := r.sendSignal(SignalPreM2MChange, M2MActionAdd, targetPrimaryKeys(r.qs.Context(), targets)); err != nil {
+		return 0, err
+	}
+
 	var relations, added, err = r.createThroughObjects(targets)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create through objects: %w", err)
@@ -548,6 +556,7 @@ func (r *RelManyToManyQuerySet[T]) AddTargets(targets ...T) (int64, error) {
 	relList = append(relList, relations...)
 
 	r.backRef.SetValues(relList)
+	r.sendPostSignal(M2MActionAdd, relationPrimaryKeys(r.qs.Context(), relations))
 	return added, nil
 }
 
@@ -556,6 +565,13 @@ func (r *RelManyToManyQuerySet[T]) SetTargets(targets []T) (added int64, err err
 		return 0, fmt.Errorf("back reference is nil, cannot set targets")
 	}
 
+	r.setup()
+
+	if err := r.sendSignal(SignalPreM2MChange, M2MActionSet, targetPrimaryKeys(r.qs.Context(), targets)); err != nil {
+		return 0, err
+	}
+
+	// the previous targets are removed with their own clear signals
 	_, err = r.ClearTargets()
 	if err != nil && !errors.Is(err, errors.NoChanges) {
 		return 0, fmt.Errorf("failed to clear targets: %w", err)
@@ -571,6 +587,7 @@ func (r *RelManyToManyQuerySet[T]) SetTargets(targets []T) (added int64, err err
 	}
 
 	r.backRef.SetValues(relations)
+	r.sendPostSignal(M2MActionSet, relationPrimaryKeys(r.qs.Context(), relations))
 	return added, nil
 }
 
@@ -595,6 +612,8 @@ func (r *RelManyToManyQuerySet[T]) RemoveTargets(targets ...any) (int64, error)
 		return 0, fmt.Errorf("back reference is nil, cannot remove targets")
 	}
 
+	r.setup()
+
 	targets = listUnpack(targets)
 
 	var (
@@ -626,6 +645,10 @@ targetLoop:
 		pkMap[val] = struct{}{}
 	}
 
+	if err := r.sendSignal(SignalPreM2MChange, M2MActionRemove, pkValues); err != nil {
+		return 0, err
+	}
+
 	var throughModel = newThroughProxy(r.rel.Through())
 	var throughQs = GetQuerySet(throughModel.object).
 		WithContext(r.qs.Context()).
@@ -655,6 +678,8 @@ targetLoop:
 		))
 	}
 
+	r.sendPostSignal(M2MActionRemove, pkValues)
+
 	var relList = r.backRef.GetValues()
 	if len(relList) == 0 {
 		return deleted, nil
@@ -705,20 +730,48 @@ func (r *RelManyToManyQuerySet[T]) ClearTargets() (int64, error) {
 		return 0, fmt.Errorf("back reference is nil, cannot clear targets")
 	}
 
-	var throughModel = newThroughProxy(r.rel.Through())
-	var throughIdsResult, err = r.qs.Select(r.qs.Meta().PrimaryKey().Name()).WithContext(r.qs.Context()).ValuesList()
+	r.setup()
+
+	var throughIds, err = r.targetIds()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get through object IDs: %w", err)
+		return 0, err
 	}
 
-	if len(throughIdsResult) == 0 {
+	if len(throughIds) == 0 {
 		return 0, errors.NoChanges.Wrap("no through objects to clear")
 	}
 
+	if err := r.sendSignal(SignalPreM2MChange, M2MActionClear, throughIds); err != nil {
+		return 0, err
+	}
+
+	deleted, err := r.deleteThroughObjects(throughIds)
+	if err != nil {
+		return 0, err
+	}
+
+	r.sendPostSignal(M2MActionClear, throughIds)
+	return deleted, nil
+}
+
+// targetIds returns the primary keys of all current targets of the relation.
+func (r *RelManyToManyQuerySet[T]) targetIds() ([]any, error) {
+	var throughIdsResult, err = r.qs.Select(r.qs.Meta().PrimaryKey().Name()).WithContext(r.qs.Context()).ValuesList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get through object IDs: %w", err)
+	}
+
 	var throughIds = make([]any, 0, len(throughIdsResult))
 	for _, id := range throughIdsResult {
 		throughIds = append(throughIds, id[0])
 	}
+	return throughIds, nil
+}
+
+// deleteThroughObjects deletes the through objects for the given targets
+// and clears the back reference values.
+func (r *RelManyToManyQuerySet[T]) deleteThroughObjects(throughIds []any) (int64, error) {
+	var throughModel = newThroughProxy(r.rel.Through())
 
 	var throughQs = GetQuerySet(throughModel.object).
 		WithContext(r.qs.Context()).
@@ -741,3 +794,51 @@ func (r *RelManyToManyQuerySet[T]) ClearTargets() (int64, error) {
 
 	return deleted, nil
 }
+
+// sendSignal sends the given many-to-many signal for the relation.
+func (r *RelManyToManyQuerySet[T]) sendSignal(sig signals.Signal[M2MSignal], action M2MAction, pks []any) error {
+	var err = sig.Send(r.qs.Context(), M2MSignal{
+		Context: r.qs.Context(),
+		Action:  action,
+		Source:  r.source.Object,
+		Field:   r.source.Field,
+		Target:  r.rel.Model(),
+		Through: r.rel.Through().Model(),
+		PKs:     pks,
+	})
+	if err != nil {
+		return fmt.Errorf("error running signal %q for %T: %w", sig.Name(), r.source.Object, err)
+	}
+	return nil
+}
+
+// sendPostSignal sends [SignalPostM2MChange] for the relation.
+//
+// The change is already made when the signal is sent, so errors
+// of the receivers are logged instead of being returned.
+func (r *RelManyToManyQuerySet[T]) sendPostSignal(action M2MAction, pks []any) {
+	if err := r.sendSignal(SignalPostM2MChange, action, pks); err != nil {
+		logger.Errorf("%v", err)
+	}
+}
+
+// targetPrimaryKeys returns the primary keys of all saved targets.
+func targetPrimaryKeys[T attrs.Definer](ctx context.Context, targets []T) []any {
+	var pks = make([]any, 0, len(targets))
+	for _, target := range targets {
+		var pk = attrs.PrimaryKey(ctx, target)
+		if pk != nil && !fields.IsZero(pk) {
+			pks = append(pks, pk)
+		}
+	}
+	return pks
+}
+
+// relationPrimaryKeys returns the primary keys of the targets of the relations.
+func relationPrimaryKeys(ctx context.Context, relations []Relation) []any {
+	var pks = make([]any, 0, len(relations))
+	for _, rel := range relations {
+		pks = append(pks, attrs.PrimaryKey(ctx, rel.Model()))
+	}
+	return pks
+}
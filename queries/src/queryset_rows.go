@@ -23,6 +23,7 @@ type Row[T any] struct {
 	ObjectFieldDefs attrs.Definitions
 	Through         attrs.Definer // The through model instance, if applicable
 	Annotations     map[string]any
+	Specific        attrs.Definer // The most specific child model of the object, see [QuerySet.Specific]
 }
 
 // A collection of Row[T] objects, where T is a type that implements attrs.Definer.
@@ -0,0 +1,23 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+)
+
+// Timeout limits the execution time of each statement executed by the queryset.
+//
+// The timeout is applied to the context of every statement separately,
+// and enforced by the database server where the driver supports it,
+// see [drivers.ContextWithQueryTimeout].
+//
+// A statement which exceeds the timeout returns an errors.StatementTimeout error.
+//
+// Passing a zero duration disables the default timeout of the database.
+func (qs *QuerySet[T]) Timeout(d time.Duration) *QuerySet[T] {
+	var nqs = qs.clone()
+	nqs.timeout = &d
+	nqs.context = drivers.ContextWithQueryTimeout(nqs.Context(), d)
+	return nqs
+}
@@ -25,6 +25,27 @@ func transactionFromContext(ctx context.Context) (tx drivers.Transaction, databa
 	return t.Transaction, t.DatabaseName, t.Transaction != nil && !t.Transaction.Finished()
 }
 
+// commitHooker is implemented by the transactions which can run functions after they are committed.
+type commitHooker interface {
+	onCommit(fn func(ctx context.Context)) bool
+}
+
+// OnCommit runs the function after the transaction stored in the context has been committed.
+//
+// If the transaction is rolled back the function is not called, if there is no
+// transaction in the context or the transaction has already finished the function is called immediately.
+//
+// This can be used for side effects which must not be visible before the changes are,
+// I.E. invalidating caches or sending emails.
+func OnCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if tx, _, ok := transactionFromContext(ctx); ok {
+		if hooker, ok := tx.(commitHooker); ok && hooker.onCommit(fn) {
+			return
+		}
+	}
+	fn(ctx)
+}
+
 func transactionToContext(ctx context.Context, tx drivers.Transaction, dbName string) context.Context {
 	if tx == nil {
 		panic("transactionToContext: transaction is nil")
@@ -38,6 +59,17 @@ func transactionToContext(ctx context.Context, tx drivers.Transaction, dbName st
 	})
 }
 
+// ContextWithTransaction stores a transaction which is managed by the caller in the context.
+//
+// Querysets for the database use the transaction when they are bound to the context with [QuerySet.WithContext],
+// [StartTransaction] returns a no-op transaction for it. The transaction is never committed or rolled back
+// through the context, and functions passed to [OnCommit] are called immediately.
+//
+// If the database name is not provided, the default database name is used.
+func ContextWithTransaction(ctx context.Context, tx drivers.Transaction, database ...string) context.Context {
+	return transactionToContext(ctx, &nullTransaction{tx}, getDatabaseName(nil, database...))
+}
+
 // StartTransaction starts a new transaction for the given database.
 //
 // If a transaction already exists in the context, it will return a no-op transaction,
@@ -155,3 +187,57 @@ func RunInTransaction[T attrs.Definer](c context.Context, fn func(ctx context.Co
 
 	return nil
 }
+
+// StartLazyTransaction is like [StartTransaction], but the transaction is only started
+// when the first query is executed in it.
+//
+// The setup function is called with the transaction right after it has been started,
+// I.E. to set the search path of the transaction. If it returns an error the transaction
+// is rolled back and the error is returned for the query.
+//
+// Committing or rolling back a transaction which was never started is a no-op,
+// so requests which do not execute any queries do not hold a transaction.
+func StartLazyTransaction(ctx context.Context, setup func(ctx context.Context, tx drivers.Transaction) error, database ...string) (context.Context, DatabaseSpecificTransaction, error) {
+	var (
+		databaseName   = getDatabaseName(nil, database...)
+		tx, dbName, ok = transactionFromContext(ctx)
+	)
+
+	if ok && (dbName == "" || dbName == databaseName) {
+		return ctx, &dbSpecificTransaction{&nullTransaction{tx}, databaseName}, nil
+	}
+
+	if !IsCommitContext(ctx) {
+		return ctx, &dbSpecificTransaction{&nullTransaction{nil}, databaseName}, nil
+	}
+
+	var compiler = Compiler(databaseName)
+	var lazy = &lazyTransaction{
+		driver: compiler.DB().Driver(),
+		begin: func(ctx context.Context) (drivers.Transaction, error) {
+			var tx, err = compiler.StartTransaction(ctx)
+			if err != nil {
+				return nil, errors.FailedStartTransaction.WithCause(fmt.Errorf(
+					"failed to start transaction for database %q: %w",
+					databaseName, err,
+				))
+			}
+
+			if setup == nil {
+				return tx, nil
+			}
+
+			if err = setup(ctx, tx); err != nil {
+				if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+					logger.Errorf("Failed to rollback transaction after setup failed: %v", rollbackErr)
+				}
+				return nil, err
+			}
+
+			return tx, nil
+		},
+	}
+
+	ctx = transactionToContext(ctx, lazy, compiler.DatabaseName())
+	return ctx, &dbSpecificTransaction{lazy, databaseName}, nil
+}
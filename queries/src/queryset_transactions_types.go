@@ -3,7 +3,9 @@ package queries
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"sync"
 
 	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
 
@@ -34,6 +36,11 @@ func (n *nullTransaction) Finished() bool {
 	return false
 }
 
+func (n *nullTransaction) onCommit(fn func(ctx context.Context)) bool {
+	var hooker, ok = n.DB.(commitHooker)
+	return ok && hooker.onCommit(fn)
+}
+
 func (n *nullTransaction) Rollback(context.Context) error {
 	return nil
 }
@@ -47,13 +54,37 @@ type dbSpecificTransaction struct {
 	dbName string
 }
 
+func (c *dbSpecificTransaction) onCommit(fn func(ctx context.Context)) bool {
+	var hooker, ok = c.Transaction.(commitHooker)
+	return ok && hooker.onCommit(fn)
+}
+
 func (c *dbSpecificTransaction) DatabaseName() string {
 	return c.dbName
 }
 
+// Started reports whether the transaction was started.
+//
+// Lazy transactions are only started when the first query is executed in them,
+// null transactions never start a transaction of their own.
+func (c *dbSpecificTransaction) Started() bool {
+	switch tx := c.Transaction.(type) {
+	case *lazyTransaction:
+		return tx.Started()
+	case *nullTransaction:
+		return false
+	}
+	return true
+}
+
 type wrappedTransaction struct {
 	drivers.Transaction
 	compiler *genericQueryBuilder
+	hooks    commitHooks
+}
+
+func (w *wrappedTransaction) onCommit(fn func(ctx context.Context)) bool {
+	return w.hooks.add(fn)
 }
 
 func (w *wrappedTransaction) Rollback(ctx context.Context) error {
@@ -63,6 +94,7 @@ func (w *wrappedTransaction) Rollback(ctx context.Context) error {
 	if w.compiler != nil {
 		w.compiler.transaction = nil
 	}
+	w.hooks.discard()
 	var err = w.Transaction.Rollback(ctx)
 	if errors.Is(err, sql.ErrTxDone) {
 		return nil
@@ -86,10 +118,171 @@ func (w *wrappedTransaction) Commit(ctx context.Context) error {
 	}
 	var err = w.Transaction.Commit(ctx)
 	if err != nil {
+		w.hooks.discard()
 		return errors.CommitFailed.WithCause(fmt.Errorf(
 			"failed to commit transaction for %s: %w",
 			w.compiler.DatabaseName(), err,
 		))
 	}
+	w.hooks.run(ctx)
+	return nil
+}
+
+// commitHooks are the functions which run after a transaction has been committed, see [OnCommit].
+type commitHooks struct {
+	mu     sync.Mutex
+	hooks  []func(ctx context.Context)
+	closed bool
+}
+
+// add adds the function to the hooks, it returns false if the
+// transaction was already committed or rolled back.
+func (h *commitHooks) add(fn func(ctx context.Context)) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.hooks = append(h.hooks, fn)
+	return true
+}
+
+func (h *commitHooks) discard() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = nil
+	h.closed = true
+}
+
+func (h *commitHooks) run(ctx context.Context) {
+	h.mu.Lock()
+	var hooks = h.hooks
+	h.hooks = nil
+	h.closed = true
+	h.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(ctx)
+	}
+}
+
+// lazyTransaction starts the transaction when the first query is executed, see [StartLazyTransaction].
+type lazyTransaction struct {
+	mu       sync.Mutex
+	driver   driver.Driver
+	begin    func(ctx context.Context) (drivers.Transaction, error)
+	tx       drivers.Transaction
+	err      error
+	finished bool
+	hooks    commitHooks
+}
+
+func (l *lazyTransaction) onCommit(fn func(ctx context.Context)) bool {
+	return l.hooks.add(fn)
+}
+
+func (l *lazyTransaction) get(ctx context.Context) (drivers.Transaction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.finished {
+		return nil, sql.ErrTxDone
+	}
+
+	if l.tx == nil && l.err == nil {
+		l.tx, l.err = l.begin(ctx)
+	}
+
+	return l.tx, l.err
+}
+
+func (l *lazyTransaction) Unwrap() any {
+	var tx, err = l.get(context.Background())
+	if err != nil {
+		return nil
+	}
+	return tx.Unwrap()
+}
+
+func (l *lazyTransaction) Driver() driver.Driver {
+	return l.driver
+}
+
+func (l *lazyTransaction) QueryContext(ctx context.Context, query string, args ...any) (drivers.SQLRows, error) {
+	var tx, err = l.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.QueryContext(ctx, query, args...)
+}
+
+func (l *lazyTransaction) QueryRowContext(ctx context.Context, query string, args ...any) drivers.SQLRow {
+	var tx, err = l.get(ctx)
+	if err != nil {
+		return &errorRow{err}
+	}
+	return tx.QueryRowContext(ctx, query, args...)
+}
+
+func (l *lazyTransaction) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var tx, err = l.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.ExecContext(ctx, query, args...)
+}
+
+// Started reports whether the transaction was started by executing a query in it.
+func (l *lazyTransaction) Started() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tx != nil
+}
+
+func (l *lazyTransaction) Finished() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.finished || l.tx != nil && l.tx.Finished()
+}
+
+func (l *lazyTransaction) Commit(ctx context.Context) error {
+	if err := l.finish(ctx, drivers.Transaction.Commit); err != nil {
+		l.hooks.discard()
+		return err
+	}
+	l.hooks.run(ctx)
 	return nil
 }
+
+func (l *lazyTransaction) Rollback(ctx context.Context) error {
+	l.hooks.discard()
+	return l.finish(ctx, drivers.Transaction.Rollback)
+}
+
+func (l *lazyTransaction) finish(ctx context.Context, fn func(drivers.Transaction, context.Context) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.finished {
+		return nil
+	}
+
+	l.finished = true
+	if l.tx == nil || l.tx.Finished() {
+		return nil
+	}
+
+	return fn(l.tx, ctx)
+}
+
+type errorRow struct {
+	err error
+}
+
+func (r *errorRow) Err() error {
+	return r.err
+}
+
+func (r *errorRow) Scan(dest ...any) error {
+	return r.err
+}
@@ -0,0 +1,225 @@
+package serializers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-django/src/core/errs"
+)
+
+// ObjectErrors holds the validation errors of a single deserialized object.
+type ObjectErrors struct {
+	// Index of the object in the input.
+	Index int
+
+	// Errors by the (dotted) path of the field.
+	Errors []errs.ValidationError[string]
+}
+
+// DeserializationError is returned by [Deserialize] if one or more fields could not be
+// deserialized or did not pass validation.
+type DeserializationError struct {
+	Objects []ObjectErrors
+}
+
+func (e *DeserializationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("failed to deserialize objects:")
+	for _, obj := range e.Objects {
+		for _, err := range obj.Errors {
+			fmt.Fprintf(&sb, " [%d] %s: %v;", obj.Index, err.Name, err.Err)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), ";")
+}
+
+func (e *DeserializationError) Unwrap() []error {
+	var errList = make([]error, 0)
+	for _, obj := range e.Objects {
+		errList = append(errList, errs.Errors(obj.Errors)...)
+	}
+	return errList
+}
+
+// FieldErrors returns the validation errors of the object at the given index.
+func (e *DeserializationError) FieldErrors(index int) []errs.ValidationError[string] {
+	for _, obj := range e.Objects {
+		if obj.Index == index {
+			return obj.Errors
+		}
+	}
+	return nil
+}
+
+// Deserialize creates a new object of the model's type for each of the values,
+// the values are the raw field values as returned by [Format.Decode] or [Object.Map].
+//
+// Related objects are deserialized from a nested map, or from the primary key of the related object.
+// Reverse and many-to-many relations are not deserialized.
+//
+// Fields which are not editable (except for the primary key) are skipped,
+// the remaining fields are validated after their value was set.
+//
+// If any of the objects could not be deserialized, all objects are returned
+// together with a [*DeserializationError].
+func Deserialize[T attrs.Definer](ctx context.Context, model T, values []map[string]any, opts Options) ([]T, error) {
+	var (
+		objects = make([]T, 0, len(values))
+		errList = make([]ObjectErrors, 0)
+	)
+
+	for i, value := range values {
+		var obj = attrs.NewObject[T](ctx, model)
+		var fieldErrs = deserializeObject(ctx, obj, value, opts, "")
+		if len(fieldErrs) > 0 {
+			errList = append(errList, ObjectErrors{
+				Index:  i,
+				Errors: fieldErrs,
+			})
+		}
+		objects = append(objects, obj)
+	}
+
+	if len(errList) > 0 {
+		return objects, &DeserializationError{Objects: errList}
+	}
+
+	return objects, nil
+}
+
+func deserializeObject(ctx context.Context, obj attrs.Definer, values map[string]any, opts Options, prefix string) []errs.ValidationError[string] {
+	var (
+		defs    = attrs.Define(ctx, obj)
+		errList = make([]errs.ValidationError[string], 0)
+		names   = make([]string, 0, len(values))
+	)
+
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var (
+			value = values[name]
+			path  = prefix + name
+		)
+
+		if !opts.includes(name) {
+			continue
+		}
+
+		var field, ok = defs.Field(name)
+		if !ok {
+			errList = append(errList, errs.NewValidationError(
+				path, fmt.Sprintf("unknown field %q", name),
+			))
+			continue
+		}
+
+		if !field.AllowEdit() && !field.IsPrimary() && !isProxyField(field) {
+			continue
+		}
+
+		var subErrs, err = deserializeField(ctx, field, value, opts, path)
+		errList = append(errList, subErrs...)
+		if err != nil {
+			errList = append(errList, errs.NewValidationError(path, err))
+			continue
+		}
+
+		if len(subErrs) > 0 {
+			continue
+		}
+
+		if err := field.Validate(); err != nil {
+			errList = append(errList, errs.NewValidationError(path, err))
+		}
+	}
+
+	return errList
+}
+
+func deserializeField(ctx context.Context, field attrs.Field, value any, opts Options, path string) ([]errs.ValidationError[string], error) {
+	if rep, ok := opts.Representations[field.Name()]; ok && rep.Deserialize != nil {
+		return nil, rep.Deserialize(ctx, field, value)
+	}
+
+	if f, ok := field.(SerializableField); ok {
+		return nil, f.DeserializeValue(ctx, value)
+	}
+
+	var rel = field.Rel()
+	if rel == nil {
+		var rv, err = decodeValue(field.Type(), value)
+		if err != nil {
+			return nil, err
+		}
+		return nil, field.SetValue(rv.Interface(), true)
+	}
+
+	switch rel.Type() {
+	case attrs.RelManyToOne, attrs.RelOneToOne:
+		if f, ok := field.(attrs.CanIsReverse); ok && f.IsReverse() {
+			return nil, nil
+		}
+
+		// an empty string is the nil value in text based formats
+		if value == nil || value == "" {
+			return nil, field.SetValue(nil, true)
+		}
+
+		var related = relatedObject(field.GetValue())
+		if related == nil || attrs.IsZero(related) {
+			related = attrs.NewObject[attrs.Definer](ctx, rel.Model())
+		}
+
+		if nested, ok := value.(map[string]any); ok {
+			var subErrs = deserializeObject(ctx, related, nested, opts.selection(field.Name()), path+".")
+			if len(subErrs) > 0 {
+				return subErrs, nil
+			}
+			return nil, setRelated(field, related)
+		}
+
+		var primary = attrs.Define(ctx, related).Primary()
+		if primary == nil {
+			return nil, fmt.Errorf("related model %T has no primary key", related)
+		}
+
+		var rv, err = decodeValue(primary.Type(), value)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := primary.SetValue(rv.Interface(), true); err != nil {
+			return nil, err
+		}
+
+		return nil, setRelated(field, related)
+	}
+
+	// reverse foreign keys and many-to-many relations
+	// are stored in other tables, they are not deserialized.
+	return nil, nil
+}
+
+// setRelated sets the related object on the field,
+// relation values (I.E. [queries.RelFK]) are updated in place.
+func setRelated(field attrs.Field, related attrs.Definer) error {
+	if v, ok := field.GetValue().(queries.RelationValue); ok && !reflect.ValueOf(v).IsNil() {
+		v.SetValue(related)
+		return nil
+	}
+	return field.SetValue(related, true)
+}
+
+func isProxyField(field attrs.Field) bool {
+	var proxy, ok = field.(queries.ProxyField)
+	return ok && proxy.IsProxy()
+}
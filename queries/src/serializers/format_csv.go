@@ -0,0 +1,168 @@
+package serializers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/elliotchance/orderedmap/v2"
+)
+
+// CSV writes the objects as rows of a CSV file, the first row holds the names of the fields.
+//
+// Fields of related objects are flattened with the dot notation, I.E. "Author.Name".
+// Lists of related objects and JSON values (I.E. maps and lists) are written as JSON.
+//
+// All values are decoded as strings, they are converted to the type of the field by [Deserialize].
+var CSV Format = &csvFormat{Comma: ','}
+
+type csvFormat struct {
+	Comma rune
+}
+
+func (f *csvFormat) Encode(w io.Writer, objects []*Object) error {
+	var (
+		header = make([]string, 0)
+		seen   = make(map[string]struct{})
+		rows   = make([]*orderedmap.OrderedMap[string, string], 0, len(objects))
+	)
+
+	for _, obj := range objects {
+		var row = orderedmap.NewOrderedMap[string, string]()
+		if err := flattenObject(row, "", obj); err != nil {
+			return err
+		}
+
+		for _, key := range row.Keys() {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				header = append(header, key)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	var cw = csv.NewWriter(w)
+	cw.Comma = f.Comma
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		var record = make([]string, len(header))
+		for i, key := range header {
+			record[i], _ = row.Get(key)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func flattenObject(row *orderedmap.OrderedMap[string, string], prefix string, obj *Object) error {
+	for head := obj.Fields.Front(); head != nil; head = head.Next() {
+		var key = prefix + head.Key
+		switch v := head.Value.(type) {
+		case nil:
+			row.Set(key, "")
+		case *Object:
+			if err := flattenObject(row, key+".", v); err != nil {
+				return err
+			}
+		case string:
+			row.Set(key, v)
+		case []*Object, map[string]any, []any:
+			var b, err = json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("failed to marshal field %q: %w", key, err)
+			}
+			row.Set(key, string(b))
+		default:
+			row.Set(key, fmt.Sprint(v))
+		}
+	}
+	return nil
+}
+
+func (f *csvFormat) Decode(r io.Reader) ([]map[string]any, error) {
+	var cr = csv.NewReader(r)
+	cr.Comma = f.Comma
+
+	var records, err = cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return []map[string]any{}, nil
+	}
+
+	var (
+		header = records[0]
+		values = make([]map[string]any, 0, len(records)-1)
+	)
+
+	for _, record := range records[1:] {
+		var value = make(map[string]any, len(header))
+		for i, key := range header {
+			if i >= len(record) {
+				break
+			}
+			unflatten(value, strings.Split(key, "."), record[i])
+		}
+		pruneEmpty(value)
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// unflatten sets the value at the (split) path, creating nested maps for related objects.
+//
+// Empty values do not replace nested maps, this happens when some rows have a related object
+// and other rows only hold the (empty) primary key.
+func unflatten(m map[string]any, path []string, value string) {
+	if len(path) == 1 {
+		if _, isMap := m[path[0]].(map[string]any); isMap && value == "" {
+			return
+		}
+		m[path[0]] = value
+		return
+	}
+
+	var nested, ok = m[path[0]].(map[string]any)
+	if !ok {
+		nested = make(map[string]any)
+		m[path[0]] = nested
+	}
+	unflatten(nested, path[1:], value)
+}
+
+// pruneEmpty replaces nested maps of which all values are empty with nil,
+// these are related objects which were not set for the row.
+func pruneEmpty(m map[string]any) (empty bool) {
+	empty = true
+	for key, value := range m {
+		switch v := value.(type) {
+		case map[string]any:
+			if pruneEmpty(v) {
+				m[key] = nil
+				continue
+			}
+		case string:
+			if v == "" {
+				continue
+			}
+		case nil:
+			continue
+		}
+		empty = false
+	}
+	return empty
+}
@@ -0,0 +1,30 @@
+package serializers
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSON writes the objects as a JSON array of objects.
+var JSON Format = &jsonFormat{Indent: "  "}
+
+type jsonFormat struct {
+	Indent string
+}
+
+func (f *jsonFormat) Encode(w io.Writer, objects []*Object) error {
+	var enc = json.NewEncoder(w)
+	enc.SetIndent("", f.Indent)
+	return enc.Encode(objects)
+}
+
+func (f *jsonFormat) Decode(r io.Reader) ([]map[string]any, error) {
+	var dec = json.NewDecoder(r)
+	dec.UseNumber()
+
+	var values = make([]map[string]any, 0)
+	if err := dec.Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,163 @@
+package serializers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// XML writes the objects as <object> elements inside of an <objects> root element.
+//
+// Each field is written as a <field name="..."> element, related objects are nested <object> elements.
+// The type attribute of a field is "object" for a related object, "list" for a list of related objects,
+// "json" for JSON values (I.E. maps and lists) and "null" for nil values.
+var XML Format = &xmlFormat{}
+
+const (
+	xmlTypeObject = "object"
+	xmlTypeList   = "list"
+	xmlTypeJSON   = "json"
+	xmlTypeNull   = "null"
+)
+
+type xmlFormat struct{}
+
+type xmlObjects struct {
+	XMLName xml.Name    `xml:"objects"`
+	Objects []xmlObject `xml:"object"`
+}
+
+type xmlObject struct {
+	Fields []xmlField `xml:"field"`
+}
+
+type xmlField struct {
+	Name    string      `xml:"name,attr"`
+	Type    string      `xml:"type,attr,omitempty"`
+	Objects []xmlObject `xml:"object,omitempty"`
+	Value   string      `xml:",chardata"`
+}
+
+func (f *xmlFormat) Encode(w io.Writer, objects []*Object) error {
+	var root = xmlObjects{
+		Objects: make([]xmlObject, 0, len(objects)),
+	}
+
+	for _, obj := range objects {
+		var xmlObj, err = toXMLObject(obj)
+		if err != nil {
+			return err
+		}
+		root.Objects = append(root.Objects, xmlObj)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	var enc = xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(root)
+}
+
+func toXMLObject(obj *Object) (xmlObject, error) {
+	var xmlObj = xmlObject{
+		Fields: make([]xmlField, 0, obj.Fields.Len()),
+	}
+
+	for head := obj.Fields.Front(); head != nil; head = head.Next() {
+		var field = xmlField{Name: head.Key}
+		switch v := head.Value.(type) {
+		case nil:
+			field.Type = xmlTypeNull
+		case *Object:
+			var nested, err = toXMLObject(v)
+			if err != nil {
+				return xmlObj, err
+			}
+			field.Type = xmlTypeObject
+			field.Objects = []xmlObject{nested}
+		case []*Object:
+			field.Type = xmlTypeList
+			field.Objects = make([]xmlObject, 0, len(v))
+			for _, item := range v {
+				var nested, err = toXMLObject(item)
+				if err != nil {
+					return xmlObj, err
+				}
+				field.Objects = append(field.Objects, nested)
+			}
+		case string:
+			field.Value = v
+		case map[string]any, []any:
+			var b, err = json.Marshal(v)
+			if err != nil {
+				return xmlObj, fmt.Errorf("failed to marshal field %q: %w", head.Key, err)
+			}
+			field.Type = xmlTypeJSON
+			field.Value = string(b)
+		default:
+			field.Value = fmt.Sprint(v)
+		}
+		xmlObj.Fields = append(xmlObj.Fields, field)
+	}
+
+	return xmlObj, nil
+}
+
+func (f *xmlFormat) Decode(r io.Reader) ([]map[string]any, error) {
+	var root xmlObjects
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, err
+	}
+
+	var values = make([]map[string]any, 0, len(root.Objects))
+	for _, obj := range root.Objects {
+		var value, err = fromXMLObject(obj)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func fromXMLObject(obj xmlObject) (map[string]any, error) {
+	var values = make(map[string]any, len(obj.Fields))
+	for _, field := range obj.Fields {
+		switch field.Type {
+		case xmlTypeNull:
+			values[field.Name] = nil
+		case xmlTypeObject:
+			if len(field.Objects) == 0 {
+				values[field.Name] = nil
+				continue
+			}
+			var nested, err = fromXMLObject(field.Objects[0])
+			if err != nil {
+				return nil, err
+			}
+			values[field.Name] = nested
+		case xmlTypeList:
+			var list = make([]any, 0, len(field.Objects))
+			for _, item := range field.Objects {
+				var nested, err = fromXMLObject(item)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, nested)
+			}
+			values[field.Name] = list
+		case xmlTypeJSON:
+			var v any
+			if err := json.Unmarshal([]byte(field.Value), &v); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal field %q: %w", field.Name, err)
+			}
+			values[field.Name] = v
+		default:
+			values[field.Name] = field.Value
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,92 @@
+package serializers
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+// Format writes serialized objects to, and reads raw field values from a specific format.
+//
+// The JSON, XML and CSV formats are registered by default.
+type Format interface {
+	// Encode writes the objects to the writer.
+	Encode(w io.Writer, objects []*Object) error
+
+	// Decode reads the field values of each object from the reader,
+	// related objects are returned as nested maps.
+	Decode(r io.Reader) ([]map[string]any, error)
+}
+
+var formats = struct {
+	mu sync.RWMutex
+	m  map[string]Format
+}{
+	m: map[string]Format{
+		"json": JSON,
+		"xml":  XML,
+		"csv":  CSV,
+	},
+}
+
+// RegisterFormat registers a format by name, an existing format with the same name is replaced.
+func RegisterFormat(name string, format Format) {
+	formats.mu.Lock()
+	defer formats.mu.Unlock()
+	formats.m[name] = format
+}
+
+// GetFormat returns the format registered under the name.
+func GetFormat(name string) (Format, bool) {
+	formats.mu.RLock()
+	defer formats.mu.RUnlock()
+	var format, ok = formats.m[name]
+	return format, ok
+}
+
+func getFormat(name string) (Format, error) {
+	var format, ok = GetFormat(name)
+	if !ok {
+		return nil, errors.NotImplemented.Wrapf(
+			"unknown serialization format %q", name,
+		)
+	}
+	return format, nil
+}
+
+// Encode serializes the objects and writes them to the writer in the given format.
+func Encode[T attrs.Definer](ctx context.Context, w io.Writer, format string, objects []T, opts Options) error {
+	var f, err = getFormat(format)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := Serialize(ctx, objects, opts)
+	if err != nil {
+		return err
+	}
+
+	return f.Encode(w, serialized)
+}
+
+// Decode reads objects in the given format from the reader and deserializes them into new objects of the model's type.
+//
+// See [Deserialize] for more information.
+func Decode[T attrs.Definer](ctx context.Context, r io.Reader, format string, model T, opts Options) ([]T, error) {
+	var f, err = getFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := f.Decode(r)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "failed to decode %s", format,
+		)
+	}
+
+	return Deserialize(ctx, model, values, opts)
+}
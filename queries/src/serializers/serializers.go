@@ -0,0 +1,322 @@
+package serializers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/elliotchance/orderedmap/v2"
+)
+
+// Representation overrides how the value of a field is serialized and deserialized.
+//
+// Either function may be nil, the default representation is then used.
+type Representation struct {
+	Serialize   func(ctx context.Context, field attrs.Field) (any, error)
+	Deserialize func(ctx context.Context, field attrs.Field, value any) error
+}
+
+// SerializableField can be implemented by fields to provide their own representation.
+//
+// Representations provided in [Options.Representations] take precedence.
+type SerializableField interface {
+	attrs.Field
+	SerializeValue(ctx context.Context) (any, error)
+	DeserializeValue(ctx context.Context, value any) error
+}
+
+type Options struct {
+	// Fields to include, all fields are included if empty.
+	//
+	// Fields of related objects are selected with the dot notation, I.E. "Author.Name".
+	// Selecting only the relation itself (I.E. "Author") includes all fields of the related object.
+	Fields []string
+
+	// Exclude fields from the result, uses the same format as Fields.
+	Exclude []string
+
+	// Depth is the number of relations to follow.
+	//
+	// At depth 0 foreign keys and one-to-one relations are represented by
+	// the primary key of the related object and reverse relations are omitted.
+	//
+	// Related objects are only serialized if they were loaded, I.E. with [queries.QuerySet.Select]
+	// or [queries.QuerySet.Preload].
+	Depth int
+
+	// Representations overrides the representation of fields by their (dotted) path.
+	Representations map[string]Representation
+}
+
+// selection returns the options for a related object of the field.
+func (o Options) selection(name string) Options {
+	var sub = Options{
+		Depth:           o.Depth - 1,
+		Fields:          subPaths(o.Fields, name),
+		Exclude:         subPaths(o.Exclude, name),
+		Representations: make(map[string]Representation),
+	}
+
+	for path, rep := range o.Representations {
+		if after, ok := strings.CutPrefix(path, name+"."); ok {
+			sub.Representations[after] = rep
+		}
+	}
+
+	return sub
+}
+
+func (o Options) includes(name string) bool {
+	if slices.Contains(o.Exclude, name) {
+		return false
+	}
+
+	if len(o.Fields) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(o.Fields, func(path string) bool {
+		return path == name || strings.HasPrefix(path, name+".")
+	})
+}
+
+// subPaths returns the paths below the name, if the name itself
+// is in the list of paths, nil is returned to select all paths.
+func subPaths(paths []string, name string) []string {
+	var sub = make([]string, 0)
+	for _, path := range paths {
+		if path == name {
+			return nil
+		}
+		if after, ok := strings.CutPrefix(path, name+"."); ok {
+			sub = append(sub, after)
+		}
+	}
+	return sub
+}
+
+// Object is the serialized representation of a model object.
+//
+// The values of the fields are either a plain value (string, number, bool, nil),
+// a JSON compatible value (I.E. the data of a [drivers.JSON] field),
+// a related *Object or a list of related objects ([]*Object).
+type Object struct {
+	Model  attrs.Definer
+	Fields *orderedmap.OrderedMap[string, any]
+}
+
+func newObject(model attrs.Definer) *Object {
+	return &Object{
+		Model:  model,
+		Fields: orderedmap.NewOrderedMap[string, any](),
+	}
+}
+
+// Map returns the fields of the object as a map,
+// related objects are converted to maps as well.
+func (o *Object) Map() map[string]any {
+	var m = make(map[string]any, o.Fields.Len())
+	for head := o.Fields.Front(); head != nil; head = head.Next() {
+		switch v := head.Value.(type) {
+		case *Object:
+			m[head.Key] = v.Map()
+		case []*Object:
+			var list = make([]any, len(v))
+			for i, obj := range v {
+				list[i] = obj.Map()
+			}
+			m[head.Key] = list
+		default:
+			m[head.Key] = v
+		}
+	}
+	return m
+}
+
+// MarshalJSON writes the fields of the object in the order of the model's field definitions.
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for head := o.Fields.Front(); head != nil; head = head.Next() {
+		if head != o.Fields.Front() {
+			buf.WriteByte(',')
+		}
+
+		var key, err = json.Marshal(head.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(head.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal field %q: %w", head.Key, err)
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Serialize returns the serialized representation of the objects, see [Options].
+func Serialize[T attrs.Definer](ctx context.Context, objects []T, opts Options) ([]*Object, error) {
+	var result = make([]*Object, 0, len(objects))
+	for _, obj := range objects {
+		var serialized, err = SerializeObject(ctx, obj, opts)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, serialized)
+	}
+	return result, nil
+}
+
+// SerializeObject returns the serialized representation of a single object, see [Options].
+func SerializeObject(ctx context.Context, obj attrs.Definer, opts Options) (*Object, error) {
+	var (
+		defs   = attrs.Define(ctx, obj)
+		result = newObject(obj)
+	)
+
+	for _, field := range defs.Fields() {
+		var name = field.Name()
+		if !opts.includes(name) || attrs.IsEmbeddedField(field) {
+			continue
+		}
+
+		var value, include, err = serializeField(ctx, field, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize field %q of %T: %w", name, obj, err)
+		}
+
+		if include {
+			result.Fields.Set(name, value)
+		}
+	}
+
+	return result, nil
+}
+
+func serializeField(ctx context.Context, field attrs.Field, opts Options) (value any, include bool, err error) {
+	if rep, ok := opts.Representations[field.Name()]; ok && rep.Serialize != nil {
+		value, err = rep.Serialize(ctx, field)
+		return value, true, err
+	}
+
+	if f, ok := field.(SerializableField); ok {
+		value, err = f.SerializeValue(ctx)
+		return value, true, err
+	}
+
+	var rel = field.Rel()
+	if rel == nil {
+		value, err = representValue(field.GetValue())
+		return value, true, err
+	}
+
+	var isProxy bool
+	if proxy, ok := field.(queries.ProxyField); ok {
+		isProxy = proxy.IsProxy()
+	}
+
+	var isReverse bool
+	if f, ok := field.(attrs.CanIsReverse); ok {
+		isReverse = f.IsReverse()
+	}
+
+	var sub = opts.selection(field.Name())
+	switch rel.Type() {
+	case attrs.RelManyToOne, attrs.RelOneToOne:
+		var related = relatedObject(field.GetValue())
+
+		// proxies are always joined and always part of the object
+		if isProxy && related != nil && !attrs.IsZero(related) {
+			sub.Depth = opts.Depth
+			value, err = SerializeObject(ctx, related, sub)
+			return value, true, err
+		}
+
+		if opts.Depth > 0 && related != nil && !attrs.IsZero(related) {
+			value, err = SerializeObject(ctx, related, sub)
+			return value, true, err
+		}
+
+		// reverse one-to-one relations are not stored on the object
+		if isReverse {
+			return nil, false, nil
+		}
+
+		if related == nil {
+			value, err = representValue(field.GetValue())
+			return value, true, err
+		}
+
+		if attrs.IsZero(related) {
+			return nil, true, nil
+		}
+
+		value, err = representValue(attrs.PrimaryKey(ctx, related))
+		return value, true, err
+
+	case attrs.RelOneToMany, attrs.RelManyToMany:
+		if opts.Depth <= 0 {
+			return nil, false, nil
+		}
+
+		var related = relatedObjects(field.GetValue())
+		if related == nil {
+			return nil, false, nil
+		}
+
+		var list = make([]*Object, 0, len(related))
+		for _, obj := range related {
+			var serialized, err = SerializeObject(ctx, obj, sub)
+			if err != nil {
+				return nil, false, err
+			}
+			list = append(list, serialized)
+		}
+		return list, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// relatedObject returns the related object of a foreign key or one-to-one relation.
+func relatedObject(value any) attrs.Definer {
+	switch v := value.(type) {
+	case queries.RelationValue:
+		return v.GetValue()
+	case queries.ThroughRelationValue:
+		var obj, _ = v.GetValue()
+		return obj
+	case attrs.Definer:
+		return v
+	}
+	return nil
+}
+
+// relatedObjects returns the related objects of a reverse foreign key or many-to-many relation.
+func relatedObjects(value any) []attrs.Definer {
+	switch v := value.(type) {
+	case queries.MultiRelationValue:
+		return v.GetValues()
+	case queries.MultiThroughRelationValue:
+		var rels = v.GetValues()
+		var objects = make([]attrs.Definer, 0, len(rels))
+		for _, rel := range rels {
+			objects = append(objects, rel.Model())
+		}
+		return objects
+	case []attrs.Definer:
+		return v
+	}
+	return nil
+}
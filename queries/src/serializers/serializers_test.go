@@ -0,0 +1,161 @@
+package serializers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/serializers"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+type serializerAuthor struct {
+	ID   int64
+	Name string
+}
+
+func (m *serializerAuthor) FieldDefs(ctx context.Context) attrs.Definitions {
+	return attrs.Make[*serializerAuthor, attrs.Field](ctx, m,
+		attrs.NewField(m, "ID", &attrs.FieldConfig{Primary: true}),
+		attrs.NewField(m, "Name", nil),
+	)
+}
+
+type serializerBook struct {
+	ID      int64
+	Title   string
+	UID     drivers.UUID
+	Key     drivers.ULID
+	Created drivers.DateTime
+	Data    drivers.JSON[map[string]any]
+	Author  *serializerAuthor
+}
+
+func (m *serializerBook) FieldDefs(ctx context.Context) attrs.Definitions {
+	return attrs.Make[*serializerBook, attrs.Field](ctx, m,
+		attrs.NewField(m, "ID", &attrs.FieldConfig{Primary: true}),
+		attrs.NewField(m, "Title", &attrs.FieldConfig{MaxLength: 16}),
+		attrs.NewField(m, "UID", nil),
+		attrs.NewField(m, "Key", nil),
+		attrs.NewField(m, "Created", nil),
+		attrs.NewField(m, "Data", nil),
+		attrs.NewField(m, "Author", &attrs.FieldConfig{
+			RelForeignKey: attrs.Relate(&serializerAuthor{}, "", nil),
+		}),
+	)
+}
+
+func newBook() *serializerBook {
+	return &serializerBook{
+		ID:      1,
+		Title:   "Go",
+		UID:     drivers.NewUUID(),
+		Key:     drivers.NewULID(),
+		Created: drivers.DateTime(time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)),
+		Data:    drivers.JSON[map[string]any]{Data: map[string]any{"pages": 300.0}},
+		Author:  &serializerAuthor{ID: 7, Name: "Rob"},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []string{"json", "xml", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			var (
+				ctx  = context.Background()
+				book = newBook()
+				buf  bytes.Buffer
+			)
+
+			if err := serializers.Encode(ctx, &buf, format, []*serializerBook{book}, serializers.Options{Depth: 1}); err != nil {
+				t.Fatalf("failed to encode: %v", err)
+			}
+
+			var books, err = serializers.Decode(ctx, &buf, format, &serializerBook{}, serializers.Options{})
+			if err != nil {
+				t.Fatalf("failed to decode: %v\n%s", err, buf.String())
+			}
+
+			if len(books) != 1 {
+				t.Fatalf("expected 1 book, got %d", len(books))
+			}
+
+			var got = books[0]
+			if got.ID != book.ID || got.Title != book.Title {
+				t.Errorf("expected %d %q, got %d %q", book.ID, book.Title, got.ID, got.Title)
+			}
+
+			if got.UID != book.UID || got.Key != book.Key {
+				t.Errorf("expected identifiers %s %s, got %s %s", book.UID, book.Key, got.UID, got.Key)
+			}
+
+			if !got.Created.Time().Equal(book.Created.Time()) {
+				t.Errorf("expected created %s, got %s", book.Created, got.Created)
+			}
+
+			if got.Data.Data["pages"] != 300.0 {
+				t.Errorf("expected JSON data to round trip, got %v", got.Data.Data)
+			}
+
+			if got.Author == nil || got.Author.ID != 7 || got.Author.Name != "Rob" {
+				t.Errorf("expected nested author, got %+v", got.Author)
+			}
+		})
+	}
+}
+
+func TestSerializeOptions(t *testing.T) {
+	var objects, err = serializers.Serialize(context.Background(), []*serializerBook{newBook()}, serializers.Options{
+		Fields: []string{"ID", "Title", "Author"},
+		Representations: map[string]serializers.Representation{
+			"Title": {
+				Serialize: func(ctx context.Context, field attrs.Field) (any, error) {
+					return "title: " + field.ToString(), nil
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to serialize: %v", err)
+	}
+
+	var values = objects[0].Map()
+	if len(values) != 3 {
+		t.Fatalf("expected 3 fields, got %v", values)
+	}
+
+	if values["Title"] != "title: Go" {
+		t.Errorf("expected custom representation, got %v", values["Title"])
+	}
+
+	if values["Author"] != int64(7) {
+		t.Errorf("expected primary key of author at depth 0, got %v", values["Author"])
+	}
+}
+
+func TestDeserializeValidation(t *testing.T) {
+	var _, err = serializers.Deserialize(context.Background(), &serializerBook{}, []map[string]any{
+		{"ID": 1, "Title": "Short"},
+		{"ID": "not a number", "Title": "A title which is far too long"},
+	}, serializers.Options{})
+
+	var deserializationErr *serializers.DeserializationError
+	if !errors.As(err, &deserializationErr) {
+		t.Fatalf("expected a deserialization error, got %v", err)
+	}
+
+	if errs := deserializationErr.FieldErrors(0); len(errs) != 0 {
+		t.Errorf("expected no errors for the first object, got %v", errs)
+	}
+
+	var names = make(map[string]bool)
+	for _, fieldErr := range deserializationErr.FieldErrors(1) {
+		names[fieldErr.Name] = true
+	}
+
+	if !names["ID"] || !names["Title"] {
+		t.Errorf("expected errors for ID and Title, got %v", deserializationErr.FieldErrors(1))
+	}
+}
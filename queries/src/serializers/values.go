@@ -0,0 +1,135 @@
+package serializers
+
+import (
+	"database/sql/driver"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+)
+
+// representValue converts the value of a field to a plain value,
+// which can be written to any of the supported formats.
+//
+// Values implementing [json.Marshaler] (I.E. [drivers.UUID], [drivers.DateTime], [drivers.JSON] or decimals)
+// are represented as their JSON value, values implementing [encoding.TextMarshaler] (I.E. [drivers.ULID])
+// as their text, times as RFC3339 strings and bytes as base64.
+func representValue(value any) (any, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case drivers.Timestamp:
+		return time.Time(v).Format(time.RFC3339Nano), nil
+	case drivers.LocalTime:
+		return time.Time(v).Format(time.RFC3339Nano), nil
+	case drivers.DateTime:
+		return time.Time(v).Format(time.RFC3339Nano), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v), nil
+	case drivers.Bytes:
+		return base64.StdEncoding.EncodeToString(v), nil
+	case drivers.BLOB:
+		return base64.StdEncoding.EncodeToString(v), nil
+	case json.Marshaler:
+		return jsonValue(v)
+	case encoding.TextMarshaler:
+		var text, err = v.MarshalText()
+		return string(text), err
+	}
+
+	var rv = reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return representValue(rv.Elem().Interface())
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		var v, err = valuer.Value()
+		if err != nil {
+			return nil, err
+		}
+		return representValue(v)
+	}
+
+	return jsonValue(value)
+}
+
+// jsonValue returns the value as it would be decoded from its JSON representation.
+func jsonValue(value any) (any, error) {
+	var b, err = json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// decodeValue converts a plain value back into a value of the given type.
+//
+// Strings are accepted for any type, this allows text based formats
+// like CSV and XML to be decoded into numbers, booleans, times, etc.
+func decodeValue(typ reflect.Type, value any) (reflect.Value, error) {
+	var ptr = reflect.New(typ)
+	if value == nil {
+		return ptr.Elem(), nil
+	}
+
+	if s, ok := value.(string); ok {
+		if u, ok := ptr.Interface().(encoding.TextUnmarshaler); ok {
+			return ptr.Elem(), u.UnmarshalText([]byte(s))
+		}
+
+		if typ.Kind() == reflect.String {
+			ptr.Elem().SetString(s)
+			return ptr.Elem(), nil
+		}
+
+		if s == "" {
+			return ptr.Elem(), nil
+		}
+
+		// the string might be a raw JSON value, I.E. a number from a CSV file
+		if err := json.Unmarshal([]byte(s), ptr.Interface()); err == nil {
+			return ptr.Elem(), nil
+		}
+		ptr = reflect.New(typ)
+	}
+
+	var b, err = json.Marshal(value)
+	if err != nil {
+		return ptr.Elem(), err
+	}
+
+	if err := json.Unmarshal(b, ptr.Interface()); err != nil {
+		return ptr.Elem(), errors.TypeMismatch.Wrapf(
+			"cannot convert %T to %s: %v", value, typ, err,
+		)
+	}
+
+	return ptr.Elem(), nil
+}
@@ -120,6 +120,58 @@ func TestContextQueryExecAndStats(t *testing.T) {
 	}
 }
 
+func TestQueryShape(t *testing.T) {
+	var tests = []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT * FROM a WHERE id = ?", "SELECT * FROM a WHERE id = ?"},
+		{"SELECT *  FROM a\n\tWHERE id = $1", "SELECT * FROM a WHERE id = ?"},
+		{"SELECT * FROM a WHERE id IN (?, ?, ?)", "SELECT * FROM a WHERE id IN (?)"},
+		{"SELECT * FROM a WHERE name = 'it''s' LIMIT 10", "SELECT * FROM a WHERE name = ? LIMIT ?"},
+		{"SELECT col1 FROM table2", "SELECT col1 FROM table2"},
+	}
+
+	for _, test := range tests {
+		var q = &drivers.Query{Query: test.query}
+		if shape := q.Shape(); shape != test.expected {
+			t.Errorf("Expected shape %q for %q, got %q", test.expected, test.query, shape)
+		}
+	}
+}
+
+func TestContextQueryDuplicates(t *testing.T) {
+	ctx, qi := drivers.ContextWithQueryInfo(context.Background())
+
+	fn := func(ctx context.Context, query string, args ...any) (int, error) {
+		return 1, nil
+	}
+
+	_, _ = drivers.ContextQueryExec(ctx, "mock", "BEGIN", nil, drivers.Q_TSTART, fn)
+	_, _ = drivers.ContextQueryExec(ctx, "mock", "SELECT * FROM posts", nil, drivers.Q_QUERY, fn)
+	for i := 0; i < 3; i++ {
+		_, _ = drivers.ContextQueryExec(ctx, "mock", "SELECT * FROM users WHERE id = ?", []any{i}, drivers.Q_QUERYROW, fn)
+	}
+	_, _ = drivers.ContextQueryExec(ctx, "mock", "COMMIT", nil, drivers.Q_TCOMMIT, fn)
+
+	if n := len(qi.Statements()); n != 4 {
+		t.Fatalf("Expected 4 statements, got %d", n)
+	}
+
+	var duplicates = qi.Duplicates(3)
+	if len(duplicates) != 1 {
+		t.Fatalf("Expected 1 duplicate query, got %d", len(duplicates))
+	}
+
+	if duplicates[0].Count() != 3 || duplicates[0].Shape != "SELECT * FROM users WHERE id = ?" {
+		t.Errorf("Expected users query to be executed 3 times, got %d: %q", duplicates[0].Count(), duplicates[0].Shape)
+	}
+
+	if callers := duplicates[0].Callers(); len(callers) != 1 {
+		t.Errorf("Expected 1 caller for duplicate query, got %v", callers)
+	}
+}
+
 func TestQueryExplainUnknownDriverPanic(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
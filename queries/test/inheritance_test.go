@@ -0,0 +1,224 @@
+package queries_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Nigel2392/go-django/djester/quest"
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/models"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+type InheritPlace struct {
+	models.Model
+	ID   int64
+	Name string
+}
+
+func (p *InheritPlace) FieldDefs(ctx context.Context) attrs.Definitions {
+	return p.Model.Define(ctx, p,
+		attrs.Unbound("ID", &attrs.FieldConfig{Primary: true}),
+		attrs.Unbound("Name"),
+	)
+}
+
+type InheritRestaurant struct {
+	models.Model
+	*InheritPlace
+	ID          int64
+	ServesPizza bool
+}
+
+func (r *InheritRestaurant) FieldDefs(ctx context.Context) attrs.Definitions {
+	return r.Model.Define(ctx, r,
+		attrs.Unbound("ID", &attrs.FieldConfig{Primary: true}),
+		attrs.Unbound("ServesPizza"),
+	)
+}
+
+type InheritPizzeria struct {
+	models.Model
+	*InheritRestaurant
+	ID    int64
+	Ovens int64
+}
+
+func (p *InheritPizzeria) FieldDefs(ctx context.Context) attrs.Definitions {
+	return p.Model.Define(ctx, p,
+		attrs.Unbound("ID", &attrs.FieldConfig{Primary: true}),
+		attrs.Unbound("Ovens"),
+	)
+}
+
+var errInheritAfterCreate = errors.New("after create failed")
+
+type InheritFailingRestaurant struct {
+	models.Model
+	*InheritPlace
+	ID int64
+}
+
+func (r *InheritFailingRestaurant) FieldDefs(ctx context.Context) attrs.Definitions {
+	return r.Model.Define(ctx, r,
+		attrs.Unbound("ID", &attrs.FieldConfig{Primary: true}),
+	)
+}
+
+// AfterCreate fails after both the parent and the child row were written.
+func (r *InheritFailingRestaurant) AfterCreate(ctx context.Context) error {
+	return errInheritAfterCreate
+}
+
+func TestMultiTableInheritance(t *testing.T) {
+	var tables = quest.Table(t,
+		&InheritPlace{},
+		&InheritRestaurant{},
+		&InheritPizzeria{},
+		&InheritFailingRestaurant{},
+	)
+	tables.Create()
+	defer tables.Drop()
+
+	var ctx = context.Background()
+	var place = models.Setup(ctx, &InheritPlace{
+		Name: "Park",
+	})
+	if err := place.Save(ctx); err != nil {
+		t.Fatalf("Failed to save place: %v", err)
+	}
+
+	var restaurant = models.Setup(ctx, &InheritRestaurant{
+		InheritPlace: &InheritPlace{
+			Name: "Restaurant",
+		},
+		ServesPizza: false,
+	})
+	if err := restaurant.Save(ctx); err != nil {
+		t.Fatalf("Failed to save restaurant: %v", err)
+	}
+
+	var pizzeria = models.Setup(ctx, &InheritPizzeria{
+		InheritRestaurant: &InheritRestaurant{
+			InheritPlace: &InheritPlace{
+				Name: "Pizzeria",
+			},
+			ServesPizza: true,
+		},
+		Ovens: 2,
+	})
+	if err := pizzeria.Save(ctx); err != nil {
+		t.Fatalf("Failed to save pizzeria: %v", err)
+	}
+
+	t.Run("ParentJoin", func(t *testing.T) {
+		var row, err = queries.GetQuerySet(&InheritRestaurant{}).
+			WithContext(ctx).
+			Filter("InheritPlace.Name", "Restaurant").
+			Get()
+		if err != nil {
+			t.Fatalf("Failed to get restaurant: %v", err)
+		}
+
+		if row.Object.InheritPlace == nil || row.Object.InheritPlace.Name != "Restaurant" {
+			t.Fatalf("Expected parent to be joined, got %+v", row.Object.InheritPlace)
+		}
+	})
+
+	t.Run("Specific", func(t *testing.T) {
+		var rows, err = queries.GetQuerySet(&InheritPlace{}).
+			WithContext(ctx).
+			Specific().
+			OrderBy("ID").
+			All()
+		if err != nil {
+			t.Fatalf("Failed to list places: %v", err)
+		}
+
+		if len(rows) != 3 {
+			t.Fatalf("Expected 3 places, got %d", len(rows))
+		}
+
+		if _, ok := rows[0].Specific.(*InheritPlace); !ok {
+			t.Errorf("Expected place without children to stay a place, got %T", rows[0].Specific)
+		}
+
+		if r, ok := rows[1].Specific.(*InheritRestaurant); !ok || r.ServesPizza {
+			t.Errorf("Expected restaurant, got %T", rows[1].Specific)
+		}
+
+		p, ok := rows[2].Specific.(*InheritPizzeria)
+		if !ok || p.Ovens != 2 {
+			t.Fatalf("Expected pizzeria with 2 ovens, got %T", rows[2].Specific)
+		}
+
+		if p.InheritRestaurant == nil || p.InheritRestaurant.InheritPlace == nil || p.Name != "Pizzeria" {
+			t.Errorf("Expected the parents of the pizzeria to be linked, got %+v", p.InheritRestaurant)
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		var implicit = queries.QUERYSET_CREATE_IMPLICIT_TRANSACTION
+		queries.QUERYSET_CREATE_IMPLICIT_TRANSACTION = false
+		defer func() {
+			queries.QUERYSET_CREATE_IMPLICIT_TRANSACTION = implicit
+		}()
+
+		var assertRolledBack = func(t *testing.T, name string) {
+			t.Helper()
+
+			var places, err = queries.GetQuerySet(&InheritPlace{}).
+				WithContext(ctx).
+				Filter("Name", name).
+				Count()
+			if err != nil {
+				t.Fatalf("Failed to count places: %v", err)
+			}
+
+			if places != 0 {
+				t.Errorf("Expected the parent row to be rolled back, got %d places", places)
+			}
+
+			children, err := queries.GetQuerySet(&InheritFailingRestaurant{}).
+				WithContext(ctx).
+				Count()
+			if err != nil {
+				t.Fatalf("Failed to count children: %v", err)
+			}
+
+			if children != 0 {
+				t.Errorf("Expected the child row to be rolled back, got %d children", children)
+			}
+		}
+
+		t.Run("Save", func(t *testing.T) {
+			var obj = models.Setup(ctx, &InheritFailingRestaurant{
+				InheritPlace: &InheritPlace{
+					Name: "RollbackSave",
+				},
+			})
+
+			if err := obj.Save(ctx); !errors.Is(err, errInheritAfterCreate) {
+				t.Fatalf("Expected save to fail with %v, got %v", errInheritAfterCreate, err)
+			}
+
+			assertRolledBack(t, "RollbackSave")
+		})
+
+		t.Run("Create", func(t *testing.T) {
+			var _, err = queries.GetQuerySet(&InheritFailingRestaurant{}).
+				WithContext(ctx).
+				Create(&InheritFailingRestaurant{
+					InheritPlace: &InheritPlace{
+						Name: "RollbackCreate",
+					},
+				})
+			if !errors.Is(err, errInheritAfterCreate) {
+				t.Fatalf("Expected create to fail with %v, got %v", errInheritAfterCreate, err)
+			}
+
+			assertRolledBack(t, "RollbackCreate")
+		})
+	})
+}
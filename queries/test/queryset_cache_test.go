@@ -0,0 +1,224 @@
+package queries_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/go-django/djester/quest"
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/queries/src/models"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+type CachedModel struct {
+	models.Model
+	ID   int64
+	Name string
+}
+
+func (m *CachedModel) FieldDefs(ctx context.Context) attrs.Definitions {
+	return m.Model.Define(ctx, m,
+		attrs.Unbound("ID", &attrs.FieldConfig{Primary: true}),
+		attrs.Unbound("Name"),
+	)
+}
+
+type mapQueryCache map[string]any
+
+func (c mapQueryCache) Get(ctx context.Context, key string) (interface{}, error) {
+	var v, ok = c[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return v, nil
+}
+
+func (c mapQueryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	c[key] = value
+	return nil
+}
+
+func (c mapQueryCache) Delete(ctx context.Context, key string) error {
+	delete(c, key)
+	return nil
+}
+
+func TestQuerySetCache(t *testing.T) {
+	var tables = quest.Table(t, &CachedModel{})
+	tables.Create()
+	defer tables.Drop()
+
+	var obj = models.Setup(context.Background(), &CachedModel{Name: "Cached"})
+	if err := obj.Save(context.Background()); err != nil {
+		t.Fatalf("Failed to create object: %v", err)
+	}
+
+	t.Run("All", func(t *testing.T) {
+		var (
+			backend = make(mapQueryCache)
+			ctx, qi = drivers.ContextWithQueryInfo(context.Background())
+			qs      = queries.GetQuerySet(&CachedModel{}).WithContext(ctx).Cache(time.Minute, backend)
+		)
+
+		for i := 0; i < 3; i++ {
+			var rows, err = qs.Filter("ID", obj.ID).All()
+			if err != nil {
+				t.Fatalf("Failed to query objects: %v", err)
+			}
+			if len(rows) != 1 || rows[0].Object.Name != "Cached" {
+				t.Fatalf("Expected 1 row named %q, got %d rows", "Cached", len(rows))
+			}
+		}
+
+		if hits, misses := qi.CacheStats(); hits != 2 || misses != 1 {
+			t.Fatalf("Expected 2 hits and 1 miss, got %d hits and %d misses", hits, misses)
+		}
+
+		if n := len(qi.Statements()); n != 1 {
+			t.Fatalf("Expected 1 executed query, got %d", n)
+		}
+
+		obj.Name = "Updated"
+		if err := obj.Save(context.Background()); err != nil {
+			t.Fatalf("Failed to update object: %v", err)
+		}
+
+		rows, err := qs.Filter("ID", obj.ID).All()
+		if err != nil {
+			t.Fatalf("Failed to query objects: %v", err)
+		}
+
+		if rows[0].Object.Name != "Updated" {
+			t.Fatalf("Expected cache to be invalidated after update, got %q", rows[0].Object.Name)
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		var qs = queries.GetQuerySet(&CachedModel{}).Cache(time.Minute, make(mapQueryCache))
+		if count, err := qs.Count(); err != nil || count != 1 {
+			t.Fatalf("Expected count of 1, got %d (%v)", count, err)
+		}
+
+		if err := queries.CreateObject(&CachedModel{Name: "Other"}); err != nil {
+			t.Fatalf("Failed to create object: %v", err)
+		}
+
+		if count, err := qs.Count(); err != nil || count != 2 {
+			t.Fatalf("Expected count of 2 after create, got %d (%v)", count, err)
+		}
+	})
+
+	t.Run("Memo", func(t *testing.T) {
+		var ctx, qi = drivers.ContextWithQueryInfo(context.Background())
+		ctx = queries.ContextWithQueryMemo(ctx)
+
+		for i := 0; i < 3; i++ {
+			var row, err = queries.GetQuerySet(&CachedModel{}).
+				WithContext(ctx).
+				Filter("ID", obj.ID).
+				Get()
+			if err != nil {
+				t.Fatalf("Failed to get object: %v", err)
+			}
+			if row.Object.ID != obj.ID {
+				t.Fatalf("Expected object %d, got %d", obj.ID, row.Object.ID)
+			}
+		}
+
+		if n := len(qi.Statements()); n != 1 {
+			t.Fatalf("Expected 1 executed query, got %d", n)
+		}
+	})
+	t.Run("AllCopies", func(t *testing.T) {
+		var qs = queries.GetQuerySet(&CachedModel{}).
+			Filter("ID", obj.ID).
+			Cache(time.Minute, make(mapQueryCache))
+
+		var all = func() *CachedModel {
+			var rows, err = qs.All()
+			if err != nil {
+				t.Fatalf("Failed to query objects: %v", err)
+			}
+			if len(rows) != 1 {
+				t.Fatalf("Expected 1 row, got %d", len(rows))
+			}
+			return rows[0].Object
+		}
+
+		var first = all()
+		var name = first.Name
+		first.Name = "Modified"
+
+		if second := all(); second == first || second.Name != name {
+			t.Fatalf("Expected a copy named %q, got %q", name, second.Name)
+		}
+	})
+
+	t.Run("MemoCopies", func(t *testing.T) {
+		var ctx = queries.ContextWithQueryMemo(context.Background())
+		var get = func() *CachedModel {
+			var row, err = queries.GetQuerySet(&CachedModel{}).
+				WithContext(ctx).
+				Filter("ID", obj.ID).
+				Get()
+			if err != nil {
+				t.Fatalf("Failed to get object: %v", err)
+			}
+			return row.Object
+		}
+
+		var first = get()
+		var name = first.Name
+		first.Name = "Modified"
+
+		if second := get(); second == first || second.Name != name {
+			t.Fatalf("Expected a copy named %q, got %q", name, second.Name)
+		}
+	})
+
+	t.Run("InvalidateOnCommit", func(t *testing.T) {
+		var (
+			ctx, qi = drivers.ContextWithQueryInfo(context.Background())
+			qs      = queries.GetQuerySet(&CachedModel{}).WithContext(ctx).Cache(time.Minute, make(mapQueryCache))
+		)
+
+		if _, err := qs.Filter("ID", obj.ID).All(); err != nil {
+			t.Fatalf("Failed to query objects: %v", err)
+		}
+
+		var txCtx, tx, err = queries.StartTransaction(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to start transaction: %v", err)
+		}
+		defer tx.Rollback(txCtx)
+
+		obj.Name = "Committed"
+		if err := obj.Save(txCtx); err != nil {
+			t.Fatalf("Failed to update object: %v", err)
+		}
+
+		if _, err := qs.Filter("ID", obj.ID).All(); err != nil {
+			t.Fatalf("Failed to query objects: %v", err)
+		}
+
+		if hits, _ := qi.CacheStats(); hits != 1 {
+			t.Fatalf("Expected the cache to be invalidated only after commit, got %d hits", hits)
+		}
+
+		if err := tx.Commit(txCtx); err != nil {
+			t.Fatalf("Failed to commit transaction: %v", err)
+		}
+
+		rows, err := qs.Filter("ID", obj.ID).All()
+		if err != nil {
+			t.Fatalf("Failed to query objects: %v", err)
+		}
+
+		if rows[0].Object.Name != "Committed" {
+			t.Fatalf("Expected cache to be invalidated after commit, got %q", rows[0].Object.Name)
+		}
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/Nigel2392/go-django/djester/quest"
 	queries "github.com/Nigel2392/go-django/queries/src"
 	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/go-signals"
 )
 
 type relationTestExpected struct {
@@ -1517,6 +1518,106 @@ var manyToManyTests = []ManyToManyTest{
 			return 0, 0, 0, 0, 0
 		},
 	},
+	{
+		Name: "TestManyToMany_RelManyToManyQuerySet_Signals",
+		Test: func(t *testing.T, profiles []*Profile, users []*User, m2m_sources []*ModelManyToMany, m2m_targets []*ModelManyToMany_Target, m2m_throughs []*ModelManyToMany_Through) (int, int, int, int, int) {
+			var obj = &ModelManyToMany{
+				Title: "TestManyToMany_Signals",
+				User:  &User{ID: users[0].ID},
+			}
+			if err := queries.CreateObject(obj); err != nil {
+				t.Fatalf("Failed to create object: %v", err)
+			}
+
+			var received = make([]string, 0)
+			var listener = func(when string) func(ctx context.Context, s signals.Signal[queries.M2MSignal], ms queries.M2MSignal) error {
+				return func(ctx context.Context, s signals.Signal[queries.M2MSignal], ms queries.M2MSignal) error {
+					if ms.Source != obj {
+						return nil
+					}
+					if _, ok := ms.Through.(*ModelManyToMany_Through); !ok {
+						t.Errorf("Expected through model to be *ModelManyToMany_Through, got %T", ms.Through)
+					}
+					received = append(received, fmt.Sprintf("%s_%s:%v", when, ms.Action, ms.PKs))
+					return nil
+				}
+			}
+
+			var pre, _ = queries.SignalPreM2MChange.Listen(t.Context(), listener("pre"))
+			var post, _ = queries.SignalPostM2MChange.Listen(t.Context(), listener("post"))
+			t.Cleanup(func() {
+				queries.SignalPreM2MChange.Disconnect(pre)
+				queries.SignalPostM2MChange.Disconnect(post)
+			})
+
+			var target = m2m_targets[0]
+			if _, err := obj.Target.Objects().AddTarget(target); err != nil {
+				t.Fatalf("Failed to add Target object: %v", err)
+			}
+
+			if _, err := obj.Target.Objects().RemoveTargets(target); err != nil {
+				t.Fatalf("Failed to remove Target object: %v", err)
+			}
+
+			// the targets which are replaced by SetTargets are removed with clear signals
+			var other = m2m_targets[1]
+			if _, err := obj.Target.Objects().AddTarget(other); err != nil {
+				t.Fatalf("Failed to add Target object: %v", err)
+			}
+
+			if _, err := obj.Target.Objects().SetTargets([]*ModelManyToMany_Target{target}); err != nil {
+				t.Fatalf("Failed to set Target objects: %v", err)
+			}
+
+			if _, err := obj.Target.Objects().ClearTargets(); err != nil {
+				t.Fatalf("Failed to clear Target objects: %v", err)
+			}
+
+			var expected = []string{
+				fmt.Sprintf("pre_add:[%v]", target.ID),
+				fmt.Sprintf("post_add:[%v]", target.ID),
+				fmt.Sprintf("pre_remove:[%v]", target.ID),
+				fmt.Sprintf("post_remove:[%v]", target.ID),
+				fmt.Sprintf("pre_add:[%v]", other.ID),
+				fmt.Sprintf("post_add:[%v]", other.ID),
+				fmt.Sprintf("pre_set:[%v]", target.ID),
+				fmt.Sprintf("pre_clear:[%v]", other.ID),
+				fmt.Sprintf("post_clear:[%v]", other.ID),
+				fmt.Sprintf("post_set:[%v]", target.ID),
+				fmt.Sprintf("pre_clear:[%v]", target.ID),
+				fmt.Sprintf("post_clear:[%v]", target.ID),
+			}
+
+			if !reflect.DeepEqual(received, expected) {
+				t.Fatalf("Expected signals %v, got %v", expected, received)
+			}
+
+			// the change is made before the post signal is sent, its errors are only logged
+			var failing, _ = queries.SignalPostM2MChange.Listen(t.Context(), func(ctx context.Context, s signals.Signal[queries.M2MSignal], ms queries.M2MSignal) error {
+				if ms.Source != obj {
+					return nil
+				}
+				return fmt.Errorf("post-change receiver failed")
+			})
+			t.Cleanup(func() {
+				queries.SignalPostM2MChange.Disconnect(failing)
+			})
+
+			if created, err := obj.Target.Objects().AddTarget(target); err != nil || !created {
+				t.Fatalf("Expected the target to be added despite the failing receiver, got %v: %v", created, err)
+			}
+
+			if _, err := obj.Target.Objects().ClearTargets(); err != nil {
+				t.Fatalf("Failed to clear Target objects: %v", err)
+			}
+
+			if _, err := queries.GetQuerySet(&ModelManyToMany{}).Filter("ID", obj.ID).Delete(); err != nil {
+				t.Fatalf("Failed to delete ModelManyToMany object: %v", err)
+			}
+
+			return 0, 0, 0, 0, 0
+		},
+	},
 	{
 		Name: "TestManyToMany_RelOneToManyQuerySet",
 		Test: func(t *testing.T, profiles []*Profile, users []*User, m2m_sources []*ModelManyToMany, m2m_targets []*ModelManyToMany_Target, m2m_throughs []*ModelManyToMany_Through) (int, int, int, int, int) {
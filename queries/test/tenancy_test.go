@@ -0,0 +1,134 @@
+package queries_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Nigel2392/go-django/djester/quest"
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/models"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+)
+
+type testTenant int64
+
+func (t testTenant) TenantID() any {
+	return int64(t)
+}
+
+type TenantScopedNote struct {
+	models.Model
+	ID       int64
+	TenantID int64
+	Text     string
+}
+
+func (m *TenantScopedNote) TenantField() string {
+	return "TenantID"
+}
+
+func (m *TenantScopedNote) FieldDefs(ctx context.Context) attrs.Definitions {
+	return m.Model.Define(ctx, m,
+		attrs.Unbound("ID", &attrs.FieldConfig{Primary: true}),
+		attrs.Unbound("TenantID"),
+		attrs.Unbound("Text"),
+	)
+}
+
+func TestTenantScopedQuerySet(t *testing.T) {
+	var tables = quest.Table(t, &TenantScopedNote{})
+	tables.Create()
+	defer tables.Drop()
+
+	var (
+		ctx1 = queries.ContextWithTenant(context.Background(), testTenant(1))
+		ctx2 = queries.ContextWithTenant(context.Background(), testTenant(2))
+	)
+
+	for _, create := range []struct {
+		ctx  context.Context
+		text string
+	}{
+		{ctx1, "tenant 1 - a"},
+		{ctx1, "tenant 1 - b"},
+		{ctx2, "tenant 2 - a"},
+	} {
+		var note, err = queries.GetQuerySet(&TenantScopedNote{}).
+			WithContext(create.ctx).
+			Create(&TenantScopedNote{Text: create.text})
+		if err != nil {
+			t.Fatalf("Failed to create note: %v", err)
+		}
+
+		var tenant, _ = queries.TenantFromContext(create.ctx)
+		if note.TenantID != tenant.TenantID() {
+			t.Fatalf("Expected tenant %v to be set on create, got %d", tenant.TenantID(), note.TenantID)
+		}
+	}
+
+	t.Run("Filter", func(t *testing.T) {
+		var rows, err = queries.GetQuerySet(&TenantScopedNote{}).
+			WithContext(ctx1).
+			All()
+		if err != nil {
+			t.Fatalf("Failed to list notes: %v", err)
+		}
+
+		if len(rows) != 2 {
+			t.Fatalf("Expected 2 notes for tenant 1, got %d", len(rows))
+		}
+
+		for _, row := range rows {
+			if row.Object.TenantID != 1 {
+				t.Errorf("Expected only notes of tenant 1, got tenant %d", row.Object.TenantID)
+			}
+		}
+
+		count, err := queries.GetQuerySet(&TenantScopedNote{}).
+			WithContext(ctx2).
+			Count()
+		if err != nil || count != 1 {
+			t.Fatalf("Expected 1 note for tenant 2, got %d (%v)", count, err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		var deleted, err = queries.GetQuerySet(&TenantScopedNote{}).
+			WithContext(ctx2).
+			Filter("Text__istartswith", "tenant").
+			Delete()
+		if err != nil {
+			t.Fatalf("Failed to delete notes: %v", err)
+		}
+
+		if deleted != 1 {
+			t.Fatalf("Expected only the note of tenant 2 to be deleted, got %d", deleted)
+		}
+
+		count, err := queries.GetQuerySet(&TenantScopedNote{}).
+			WithContext(queries.ContextWithTenant(context.Background(), nil)).
+			Count()
+		if err != nil || count != 2 {
+			t.Fatalf("Expected 2 notes to remain, got %d (%v)", count, err)
+		}
+	})
+
+	t.Run("FailClosed", func(t *testing.T) {
+		var count, err = queries.GetQuerySet(&TenantScopedNote{}).Count()
+		if err != nil || count != 0 {
+			t.Fatalf("Expected no notes without a tenant in the context, got %d (%v)", count, err)
+		}
+
+		updated, err := queries.GetQuerySet(&TenantScopedNote{}).
+			Select("Text").
+			Filter("Text__istartswith", "tenant").
+			Update(&TenantScopedNote{Text: "leaked"})
+		if err != nil || updated != 0 {
+			t.Fatalf("Expected no notes to be updated without a tenant in the context, got %d (%v)", updated, err)
+		}
+
+		if _, err = queries.GetQuerySet(&TenantScopedNote{}).Create(&TenantScopedNote{Text: "no tenant"}); err == nil {
+			t.Fatal("Expected an error when creating a note without a tenant")
+		}
+	})
+}
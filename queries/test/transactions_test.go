@@ -0,0 +1,90 @@
+package queries_test
+
+import (
+	"context"
+	"testing"
+
+	queries "github.com/Nigel2392/go-django/queries/src"
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+)
+
+func TestStartLazyTransaction(t *testing.T) {
+	var started int
+	var setup = func(ctx context.Context, tx drivers.Transaction) error {
+		started++
+		return nil
+	}
+
+	var ctx, tx, err = queries.StartLazyTransaction(context.Background(), setup)
+	if err != nil {
+		t.Fatalf("failed to start transaction: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		t.Fatalf("failed to commit transaction: %v", err)
+	}
+
+	if started != 0 {
+		t.Fatalf("expected no transaction to be started without queries, got %d", started)
+	}
+
+	ctx, tx, err = queries.StartLazyTransaction(context.Background(), setup)
+	if err != nil {
+		t.Fatalf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for i := 0; i < 2; i++ {
+		if _, err = queries.GetQuerySet(&User{}).WithContext(ctx).Count(); err != nil {
+			t.Fatalf("failed to count users: %v", err)
+		}
+	}
+
+	if started != 1 {
+		t.Fatalf("expected the transaction to be started once, got %d", started)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		t.Fatalf("failed to commit transaction: %v", err)
+	}
+}
+
+func TestOnCommit(t *testing.T) {
+	var called int
+	queries.OnCommit(context.Background(), func(context.Context) {
+		called++
+	})
+
+	if called != 1 {
+		t.Fatalf("expected the function to be called without a transaction, got %d calls", called)
+	}
+
+	for _, commit := range []bool{true, false} {
+		called = 0
+
+		var err = queries.RunInTransaction(context.Background(), func(ctx context.Context, NewQuerySet queries.ObjectsFunc[*User]) (bool, error) {
+			queries.OnCommit(ctx, func(context.Context) {
+				called++
+			})
+
+			if called != 0 {
+				t.Errorf("expected the function not to be called before the commit")
+			}
+
+			var _, err = NewQuerySet(&User{}).Count()
+			return commit, err
+		})
+		if err != nil {
+			t.Fatalf("failed to run transaction: %v", err)
+		}
+
+		var expected = 0
+		if commit {
+			expected = 1
+		}
+
+		if called != expected {
+			t.Errorf("expected %d calls when commit is %v, got %d", expected, commit, called)
+		}
+	}
+}
@@ -12,12 +12,15 @@ import (
 	"github.com/Nigel2392/go-django/src/core/command"
 	"github.com/Nigel2392/go-django/src/core/ctx"
 	"github.com/Nigel2392/go-django/src/core/filesystem/tpl"
+	"github.com/Nigel2392/go-django/src/core/metrics"
 	"github.com/Nigel2392/mux"
 )
 
 var (
 	_ django.AppConfig = (*AppConfig)(nil)
 	_ django.AppConfig = (*DBRequiredAppConfig)(nil)
+
+	_ django.MetricsAppConfig = (*AppConfig)(nil)
 )
 
 type AppConfig struct {
@@ -27,6 +30,7 @@ type AppConfig struct {
 	Cmd            []command.Command
 	Init           func(settings django.Settings) error
 	Ready          func() error
+	Metrics        func(registry *metrics.Registry) error
 	CtxProcessors  []func(ctx.ContextWithRequest)
 	TemplateConfig *tpl.Config
 	ModelObjects   []attrs.Definer
@@ -137,6 +141,13 @@ func (a *AppConfig) Initialize(settings django.Settings) error {
 	return nil
 }
 
+func (a *AppConfig) RegisterMetrics(registry *metrics.Registry) error {
+	if a.Metrics != nil {
+		return a.Metrics(registry)
+	}
+	return nil
+}
+
 func (a *AppConfig) OnReady() error {
 	var err error
 	if a.Ready != nil {
@@ -3,13 +3,17 @@ package django
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/Nigel2392/go-django/queries/src/drivers"
 	"github.com/Nigel2392/go-django/src/core/checks"
 	"github.com/Nigel2392/go-django/src/core/command"
+	"github.com/Nigel2392/go-django/src/core/secrets/safety"
 	"github.com/pkg/errors"
 )
 
@@ -149,3 +153,104 @@ var sqlShellCommand = &command.Cmd[string]{
 		return nil
 	},
 }
+
+// the parts of a setting's key which mark the setting as secret in the diffsettings command
+var secretSettingParts = []string{
+	"SECRET", "PASSWORD", "PASSWD", "TOKEN", "KEY", "CREDENTIALS", "PRIVATE", "DSN",
+}
+
+// isSecretSetting reports whether the value of the setting must be redacted when displayed.
+func isSecretSetting(key string) bool {
+	if def, ok := GetSettingDefinition(key); ok && def.IsSecret() {
+		return true
+	}
+
+	if safety.IsSecretField(context.Background(), key) {
+		return true
+	}
+
+	for _, part := range strings.Split(strings.ToUpper(key), "_") {
+		if slices.Contains(secretSettingParts, part) {
+			return true
+		}
+	}
+	return false
+}
+
+var diffSettingsCommand = &command.Cmd[bool]{
+	ID:   "diffsettings",
+	Desc: "Print the settings which differ from their defaults, secrets are redacted",
+	FlagFunc: func(m command.Manager, stored *bool, f *flag.FlagSet) error {
+		f.BoolVar(stored, "all", false, "Print all settings, including the ones using their default value")
+		return nil
+	},
+	Execute: func(m command.Manager, all bool, args []string) error {
+		var keys = make([]string, 0)
+		var s, isSettings = Global.Settings.(*settings)
+		if isSettings {
+			for key := range s.Data {
+				keys = append(keys, key)
+			}
+		}
+
+		for _, def := range SettingDefinitions() {
+			keys = append(keys, def.Key())
+		}
+
+		slices.Sort(keys)
+		keys = slices.Compact(keys)
+
+		for _, key := range keys {
+			var (
+				def, registered = GetSettingDefinition(key)
+				value, present  = Global.Settings.Get(key)
+				source          = "default"
+			)
+
+			switch {
+			case present && isSettings && s.source(key) != "":
+				source = s.source(key)
+			case present:
+				source = "settings"
+			case registered:
+				value = def.Default()
+			default:
+				continue
+			}
+
+			if !all && registered && (!present || reflect.DeepEqual(value, def.Default())) {
+				continue
+			}
+
+			var display string
+			switch {
+			case value == nil:
+				display = "nil"
+			case isSecretSetting(key) && !reflect.ValueOf(value).IsZero():
+				display = "********"
+			default:
+				display = formatSettingValue(value)
+			}
+
+			m.Logf("%s = %s  (%s)\n", key, display, source)
+		}
+
+		return command.ErrShouldExit
+	},
+}
+
+// formatSettingValue formats the value of a setting for the diffsettings command,
+// values which are not data, i.e. database connections, are displayed as their type.
+func formatSettingValue(value any) string {
+	if str, ok := value.(string); ok {
+		return strconv.Quote(str)
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Func, reflect.Chan, reflect.Struct, reflect.UnsafePointer:
+		if _, ok := value.(fmt.Stringer); !ok {
+			return fmt.Sprintf("<%T>", value)
+		}
+	}
+	return fmt.Sprintf("%v", value)
+}
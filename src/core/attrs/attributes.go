@@ -10,6 +10,7 @@ import (
 	"github.com/Nigel2392/go-django/internal/django_reflect"
 	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
 	"github.com/Nigel2392/go-django/src/core/assert"
+	"github.com/Nigel2392/go-django/src/core/logger"
 )
 
 func fieldNames(d any, exclude []string) []string {
@@ -478,10 +479,28 @@ func ForceSet(ctx context.Context, d Definer, name string, value interface{}) er
 	return set(Define(ctx, d), name, value, true)
 }
 
+// loadDeferred loads the deferred fields of the definitions' instance
+// if the field with the given name was not loaded from the database.
+//
+// Getters cannot return an error, the error is logged and the field keeps its zero value.
+// Call [CanLoadDeferred.LoadDeferred] directly to handle the error.
+func loadDeferred(ctx context.Context, defs Definitions, fieldName string) {
+	var loader, ok = defs.Instance().(CanLoadDeferred)
+	if !ok || !loader.FieldDeferred(fieldName) {
+		return
+	}
+
+	if err := loader.LoadDeferred(ctx); err != nil {
+		logger.Errorf("failed to load deferred field %q of %T: %v", fieldName, defs.Instance(), err)
+	}
+}
+
 // Get retrieves the value of a field on a Definer.
 //
 // If the field is not found, this function will panic.
 //
+// Deferred fields (see [CanLoadDeferred]) are loaded before their value is returned.
+//
 // Type assertions are used to ensure that the value is of the correct type,
 // as well as providing less work for the caller.
 func Get[T any](ctx context.Context, d any, name string) T {
@@ -506,6 +525,9 @@ func Get[T any](ctx context.Context, d any, name string) T {
 	for i := 0; i < len(path); i++ {
 		part := path[i]
 		field, ok = defs.Field(part)
+		if ok {
+			loadDeferred(ctx, defs, part)
+		}
 		if i == len(path)-1 {
 			break
 		}
@@ -185,6 +185,9 @@ const (
 
 	// AttrAutoNowKey (bool) is similar do django's auto_now.
 	AttrAutoNowKey = "field.auto_now"
+
+	// AttrChoicesKey ([]any) is the list of allowed database values for a field with choices.
+	AttrChoicesKey = "field.choices"
 )
 
 // Definer is the interface that wraps the FieldDefs method.
@@ -529,6 +532,20 @@ type CanSignalChanged interface {
 	SignalReset(f Field)
 }
 
+// CanLoadDeferred is an interface for models which can be retrieved from the database
+// without some of their fields, I.E. when using `QuerySet.Defer` or `QuerySet.Only`.
+//
+// When a deferred field is accessed through [Get] or [Definitions.Get],
+// the model is asked to load its deferred fields before the value is returned.
+type CanLoadDeferred interface {
+	// FieldDeferred reports whether the field with the given name
+	// was not loaded from the database.
+	FieldDeferred(fieldName string) bool
+
+	// LoadDeferred loads all deferred fields of the model from the database.
+	LoadDeferred(ctx context.Context) error
+}
+
 // Definitions is the interface that wraps the methods for a model's field definitions.
 //
 // This is some sort of management- interface which allows for simpler and more uniform management of model fields.
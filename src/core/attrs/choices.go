@@ -0,0 +1,230 @@
+package attrs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/Nigel2392/go-django/src/core/trans"
+	"github.com/Nigel2392/go-django/src/forms/widgets"
+)
+
+// Choice represents a single allowed value for a field with a fixed set of values.
+//
+// The label can be a string, a func(ctx context.Context) string or
+// anything else supported by [trans.GetText], i.e. [trans.S].
+type Choice[T comparable] struct {
+	Value T
+	Label any
+}
+
+// NewChoice returns a new [Choice] for the given value and label.
+func NewChoice[T comparable](value T, label any) Choice[T] {
+	return Choice[T]{
+		Value: value,
+		Label: label,
+	}
+}
+
+// ChoiceList is the non-generic interface for a list of choices.
+//
+// It is implemented by [Choices] and can be used to retrieve
+// the values and display labels of a choices field without
+// knowing the type of it's values.
+type ChoiceList interface {
+	// ChoiceValues returns the allowed values in the order they were defined.
+	ChoiceValues() []any
+
+	// ChoiceLabel returns the translated label for the given value.
+	//
+	// The value is converted to the type of the choices if possible.
+	// False is returned if the value is not a valid choice.
+	ChoiceLabel(ctx context.Context, value any) (string, bool)
+}
+
+// CanChoices is an interface for fields which only allow a fixed set of values.
+//
+// This is used to render the field as a select in forms, display the
+// label of the value in list views and to build filters for the field.
+type CanChoices interface {
+	Field
+	Choices() ChoiceList
+}
+
+// Choices is an ordered list of allowed values for a field.
+//
+// Example:
+//
+//	type Status string
+//
+//	const (
+//		StatusDraft     Status = "draft"
+//		StatusPublished Status = "published"
+//	)
+//
+//	var StatusChoices = attrs.NewChoices(
+//		attrs.NewChoice(StatusDraft, trans.S("Draft")),
+//		attrs.NewChoice(StatusPublished, trans.S("Published")),
+//	)
+type Choices[T comparable] []Choice[T]
+
+// NewChoices returns a new [Choices] list for the given choices.
+func NewChoices[T comparable](choices ...Choice[T]) Choices[T] {
+	return Choices[T](choices)
+}
+
+// Get returns the choice for the given value.
+func (c Choices[T]) Get(value T) (Choice[T], bool) {
+	for _, choice := range c {
+		if choice.Value == value {
+			return choice, true
+		}
+	}
+	return Choice[T]{}, false
+}
+
+// Contains reports whether the value is one of the choices.
+func (c Choices[T]) Contains(value T) bool {
+	var _, ok = c.Get(value)
+	return ok
+}
+
+// Values returns the values of the choices in the order they were defined.
+func (c Choices[T]) Values() []T {
+	var values = make([]T, len(c))
+	for i, choice := range c {
+		values[i] = choice.Value
+	}
+	return values
+}
+
+// Label returns the translated label for the given value.
+//
+// If the value is not a valid choice, the string representation of the value is returned.
+func (c Choices[T]) Label(ctx context.Context, value T) string {
+	var choice, ok = c.Get(value)
+	if !ok {
+		return ToString(value)
+	}
+
+	if label, ok := trans.GetText(ctx, choice.Label); ok {
+		return label
+	}
+
+	return ToString(value)
+}
+
+// Convert converts the given value to the type of the choices.
+//
+// This is useful for values which were read from the database
+// or a form, I.E. an int64 for a choices list of a custom int type.
+func (c Choices[T]) Convert(value any) (T, bool) {
+	if v, ok := value.(T); ok {
+		return v, true
+	}
+
+	var (
+		zero T
+		rV   = reflect.ValueOf(value)
+		rT   = reflect.TypeOf(zero)
+	)
+
+	if !rV.IsValid() || rT == nil {
+		return zero, false
+	}
+
+	if rV.Kind() == reflect.Ptr {
+		if rV.IsNil() {
+			return zero, false
+		}
+		rV = rV.Elem()
+	}
+
+	if rV.Type().ConvertibleTo(rT) && rV.Kind() == rT.Kind() {
+		return rV.Convert(rT).Interface().(T), true
+	}
+
+	// Allow for converting between different sizes of
+	// numbers, i.e. int64 from the database to an int8 enum.
+	if isNumberKind(rV.Kind()) && isNumberKind(rT.Kind()) {
+		var converted, ok = convertNumber(rV, rT)
+		if !ok {
+			return zero, false
+		}
+		return converted.Interface().(T), true
+	}
+
+	// Allow for values coming from forms or query parameters.
+	if rV.Kind() == reflect.String {
+		var str = rV.String()
+		for _, choice := range c {
+			if fmt.Sprint(choice.Value) == str {
+				return choice.Value, true
+			}
+		}
+	}
+
+	return zero, false
+}
+
+func (c Choices[T]) ChoiceValues() []any {
+	var values = make([]any, len(c))
+	for i, choice := range c {
+		values[i] = choice.Value
+	}
+	return values
+}
+
+func (c Choices[T]) ChoiceLabel(ctx context.Context, value any) (string, bool) {
+	var v, ok = c.Convert(value)
+	if !ok || !c.Contains(v) {
+		return "", false
+	}
+	return c.Label(ctx, v), true
+}
+
+// ChoiceOptions returns the choices as a list of [widgets.Option] to be used in a select or radio widget.
+//
+// The option values are formatted the same way as the widget formats the field's value.
+func ChoiceOptions(ctx context.Context, choices ChoiceList) []widgets.Option {
+	var values = choices.ChoiceValues()
+	var options = make([]widgets.Option, 0, len(values))
+	for _, value := range values {
+		var label, _ = choices.ChoiceLabel(ctx, value)
+		options = append(options, &widgets.FormOption{
+			OptValue: fmt.Sprint(value),
+			OptLabel: label,
+		})
+	}
+	return options
+}
+
+// convertNumber converts the number to the given type,
+// it returns false if the value would change, i.e. 3.7 to an int or 300 to an int8.
+func convertNumber(rV reflect.Value, rT reflect.Type) (reflect.Value, bool) {
+	var converted = rV.Convert(rT)
+	if isNegative(converted) != isNegative(rV) || !converted.Convert(rV.Type()).Equal(rV) {
+		return reflect.Value{}, false
+	}
+	return converted, true
+}
+
+func isNegative(v reflect.Value) bool {
+	switch {
+	case v.CanInt():
+		return v.Int() < 0
+	case v.CanFloat():
+		return v.Float() < 0
+	}
+	return false
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -182,6 +182,7 @@ func (d *ObjectDefinitions) Get(name string) interface{} {
 			d.Object, name, d.Object,
 		)
 	}
+	loadDeferred(d.Context(), d, name)
 	return f.GetValue()
 }
 
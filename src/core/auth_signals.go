@@ -11,6 +11,7 @@ package core
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/Nigel2392/go-django/src/core/attrs"
 	"github.com/Nigel2392/go-signals"
@@ -27,6 +28,25 @@ type UserWithRequest struct {
 	Req  *http.Request
 }
 
+// Lockout is sent with [SIGNAL_LOCKED_OUT] when a client is locked out after too many failures,
+// i.e. failed login attempts.
+type Lockout struct {
+	// The request which caused the lockout.
+	R *http.Request
+
+	// The name of the lockout policy, i.e. "login".
+	Name string
+
+	// The key the failures were counted by, i.e. the IP address of the client.
+	Key string
+
+	// The number of failures which caused the lockout.
+	Failures int
+
+	// The time the lockout ends.
+	Until time.Time
+}
+
 // import "github.com/Nigel2392/go-signals"
 //
 // /*
@@ -42,6 +62,7 @@ var (
 	user_signal_pool = signals.NewPool[User]()
 	user_req_pool    = signals.NewPool[UserWithRequest]()
 	any_signals      = signals.NewPool[any]()
+	lockout_pool     = signals.NewPool[*Lockout]()
 
 	SIGNAL_BEFORE_USER_CREATE = user_signal_pool.Get("user.before_create") // -> Send(auth.User) (Returned error unused!)
 	SIGNAL_AFTER_USER_CREATE  = user_signal_pool.Get("user.after_create")  // -> Send(auth.User) (Returned error unused!)
@@ -55,4 +76,5 @@ var (
 	SIGNAL_USER_LOGGED_IN  = user_req_pool.Get("auth.logged_in")      // -> Send(auth.User)		  (Returned error unused!)
 	SIGNAL_USER_LOGGED_OUT = user_req_pool.Get("auth.logged_out")     // -> Send(auth.User(nil))		  (Returned error unused!)
 	SIGNAL_LOGIN_FAILED    = url_values_pool.Get("auth.login_failed") // -> Send(auth.User, error) (Returned error unused!)
+	SIGNAL_LOCKED_OUT      = lockout_pool.Get("auth.locked_out")      // -> Send(*Lockout) (Returned error unused!)
 )
@@ -234,6 +234,7 @@ const (
 	TagSecurity Tag = "security"
 	TagCommands Tag = "commands"
 	TagModels   Tag = "models"
+	TagDatabase Tag = "database"
 )
 
 var (
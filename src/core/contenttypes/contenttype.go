@@ -1,6 +1,7 @@
 package contenttypes
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
@@ -192,6 +193,8 @@ func (c *BaseContentType[T]) ShortTypeName() string {
 // Scan implements the sql.Scanner interface.
 // It supports scanning a string into a BaseContentType.
 // The string must be a valid type name.
+//
+// If an [IDStore] is configured, the persisted ID of a content type can also be scanned.
 func (c *BaseContentType[T]) Scan(src interface{}) error {
 	if c == nil {
 		return errors.ValueError.Wrap("BaseContentType is nil")
@@ -213,6 +216,14 @@ func (c *BaseContentType[T]) Scan(src interface{}) error {
 		return nil
 	case []byte:
 		return c.Scan(string(src))
+	case int64:
+		var typeName, err = TypeForID(context.Background(), src)
+		if err != nil {
+			return errors.Wrapf(
+				err, "failed to scan content type ID %d", src,
+			)
+		}
+		return c.Scan(typeName)
 	default:
 		return errors.Wrapf(
 			ErrInvalidScanType,
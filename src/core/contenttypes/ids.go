@@ -0,0 +1,94 @@
+package contenttypes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers/errors"
+)
+
+// IDStore resolves stable integer IDs for content types.
+//
+// The in-memory registry only knows about the type names of the registered models,
+// an IDStore is set by the contenttypes app when content types are persisted in the database.
+type IDStore interface {
+	// ContentTypeID returns the ID for the given (full) type name.
+	ContentTypeID(ctx context.Context, typeName string) (int64, error)
+
+	// ContentTypeName returns the (full) type name for the given ID.
+	ContentTypeName(ctx context.Context, id int64) (string, error)
+}
+
+// IDCache can be implemented by an [IDStore] which caches the IDs of the content types.
+type IDCache interface {
+	// CachedContentTypeID returns the cached ID for the given (full) type name, it never queries the database.
+	CachedContentTypeID(typeName string) (int64, bool)
+}
+
+var idStore struct {
+	mu    sync.RWMutex
+	store IDStore
+}
+
+// SetIDStore sets the store used to resolve content type IDs, a nil store disables ID lookups.
+func SetIDStore(store IDStore) {
+	idStore.mu.Lock()
+	defer idStore.mu.Unlock()
+	idStore.store = store
+}
+
+func getIDStore() (IDStore, error) {
+	idStore.mu.RLock()
+	defer idStore.mu.RUnlock()
+	if idStore.store == nil {
+		return nil, errors.NotImplemented.Wrap(
+			"no content type ID store is configured, is the contenttypes app installed?",
+		)
+	}
+	return idStore.store, nil
+}
+
+// HasIDStore reports whether content type IDs can be resolved.
+func HasIDStore() bool {
+	var _, err = getIDStore()
+	return err == nil
+}
+
+// IDForType returns the persisted ID of the content type with the given type name or alias.
+func IDForType(ctx context.Context, typeName string) (int64, error) {
+	var store, err = getIDStore()
+	if err != nil {
+		return 0, err
+	}
+	return store.ContentTypeID(ctx, ReverseAlias(typeName))
+}
+
+// CachedIDForType returns the ID of the content type with the given type name or alias
+// if it is cached by the [IDStore], see [IDCache].
+//
+// Unlike [IDForType] it never queries the database, it is used while compiling queries.
+func CachedIDForType(typeName string) (int64, bool) {
+	var store, err = getIDStore()
+	if err != nil {
+		return 0, false
+	}
+	var cache, ok = store.(IDCache)
+	if !ok {
+		return 0, false
+	}
+	return cache.CachedContentTypeID(ReverseAlias(typeName))
+}
+
+// IDForObject returns the persisted ID of the content type for the given object.
+func IDForObject(ctx context.Context, obj any) (int64, error) {
+	return IDForType(ctx, NewContentType(obj).TypeName())
+}
+
+// TypeForID returns the type name of the content type with the given persisted ID.
+func TypeForID(ctx context.Context, id int64) (string, error) {
+	var store, err = getIDStore()
+	if err != nil {
+		return "", err
+	}
+	return store.ContentTypeName(ctx, id)
+}
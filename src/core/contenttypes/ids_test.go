@@ -0,0 +1,84 @@
+package contenttypes_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Nigel2392/go-django/src/core/contenttypes"
+)
+
+type idTestModel struct {
+	ID int
+}
+
+type mapIDStore map[string]int64
+
+func (s mapIDStore) ContentTypeID(ctx context.Context, typeName string) (int64, error) {
+	if id, ok := s[typeName]; ok {
+		return id, nil
+	}
+	return 0, fmt.Errorf("unknown content type %q", typeName)
+}
+
+func (s mapIDStore) ContentTypeName(ctx context.Context, id int64) (string, error) {
+	for typeName, storedID := range s {
+		if storedID == id {
+			return typeName, nil
+		}
+	}
+	return "", fmt.Errorf("unknown content type ID %d", id)
+}
+
+func (s mapIDStore) CachedContentTypeID(typeName string) (int64, bool) {
+	var id, ok = s[typeName]
+	return id, ok
+}
+
+func TestIDStore(t *testing.T) {
+	var ctx = context.Background()
+	var cType = contenttypes.NewContentType(&idTestModel{})
+	contenttypes.Register(&contenttypes.ContentTypeDefinition{
+		ContentObject: &idTestModel{},
+		Aliases:       []string{"github.com/old/pkg.idTestModel"},
+	})
+
+	contenttypes.SetIDStore(nil)
+	if _, err := contenttypes.IDForType(ctx, cType.TypeName()); err == nil {
+		t.Fatal("expected an error when no ID store is configured")
+	}
+
+	contenttypes.SetIDStore(mapIDStore{cType.TypeName(): 42})
+	defer contenttypes.SetIDStore(nil)
+
+	t.Run("IDForAlias", func(t *testing.T) {
+		var id, err = contenttypes.IDForType(ctx, "github.com/old/pkg.idTestModel")
+		if err != nil {
+			t.Fatalf("failed to get ID: %v", err)
+		}
+		if id != 42 {
+			t.Errorf("expected ID 42, got %d", id)
+		}
+	})
+
+	t.Run("CachedIDForAlias", func(t *testing.T) {
+		var id, ok = contenttypes.CachedIDForType("github.com/old/pkg.idTestModel")
+		if !ok || id != 42 {
+			t.Errorf("expected cached ID 42, got %d (%v)", id, ok)
+		}
+
+		if _, ok = contenttypes.CachedIDForType("github.com/unknown/pkg.Model"); ok {
+			t.Error("expected no cached ID for an unknown content type")
+		}
+	})
+
+	t.Run("ScanID", func(t *testing.T) {
+		var scanned = &contenttypes.BaseContentType[any]{}
+		if err := scanned.Scan(int64(42)); err != nil {
+			t.Fatalf("failed to scan ID: %v", err)
+		}
+		if scanned.TypeName() != cType.TypeName() {
+			t.Errorf("expected %q, got %q", cType.TypeName(), scanned.TypeName())
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package contenttypes
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Reference is a field of a model which stores the type name of a content type as a string.
+type Reference struct {
+	// The model the field belongs to, this must be an attrs.Definer.
+	Model any
+
+	// The name of the field which stores the type name.
+	Field string
+}
+
+var references = struct {
+	mu   sync.RWMutex
+	list []Reference
+}{}
+
+// RegisterReference registers a field which stores content type names as a string.
+//
+// When a persisted content type is renamed by the contenttypes app,
+// the type names stored in the registered fields are rewritten.
+func RegisterReference(model any, field string) {
+	references.mu.Lock()
+	defer references.mu.Unlock()
+
+	var rt = reflect.TypeOf(model)
+	for _, ref := range references.list {
+		if reflect.TypeOf(ref.Model) == rt && ref.Field == field {
+			return
+		}
+	}
+
+	references.list = append(references.list, Reference{
+		Model: model,
+		Field: field,
+	})
+}
+
+// References returns the registered content type references.
+func References() []Reference {
+	references.mu.RLock()
+	defer references.mu.RUnlock()
+	var list = make([]Reference, len(references.list))
+	copy(list, references.list)
+	return list
+}
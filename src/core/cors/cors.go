@@ -0,0 +1,412 @@
+/*
+Package cors implements Cross-Origin Resource Sharing for handlers which are requested from other origins,
+i.e. JSON endpoints used by a single page application hosted on another domain.
+
+A [Policy] is created from a [Config] and can be used as a middleware for a route or a group of routes:
+
+	route.Use(cors.Middleware(cors.Config{
+		AllowedOrigins: []string{"https://app.example.com", "https://*.example.com"},
+	}))
+
+Preflight requests are answered by the middleware, so the route must also match OPTIONS requests,
+[Policy.FallbackHandler] answers the preflight requests of the routes which do not apply a policy of their own.
+*/
+package cors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Nigel2392/go-django/src/core/assert"
+	"github.com/Nigel2392/go-django/src/core/errs"
+)
+
+const (
+	ErrCredentialsWithAnyOrigin errs.Error = "credentials cannot be allowed for any origin"
+	ErrInvalidOrigin            errs.Error = "invalid origin pattern"
+)
+
+// The methods allowed if [Config.AllowedMethods] is empty.
+var DefaultAllowedMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// The request headers allowed if [Config.AllowedHeaders] is empty.
+//
+// X-CSRF-Token is the header the CSRF token is read from by the CSRF middleware.
+var DefaultAllowedHeaders = []string{
+	"Accept",
+	"Accept-Language",
+	"Authorization",
+	"Content-Type",
+	"X-CSRF-Token",
+	"X-Requested-With",
+}
+
+// Config configures which origins may access a resource and how.
+//
+// The config can be loaded from settings files, the JSON keys are the snake_case names of the fields.
+type Config struct {
+	// The origins which may access the resource, i.e. "https://example.com".
+	//
+	// A "*" in place of the subdomains allows all subdomains, i.e. "https://*.example.com",
+	// a single "*" allows any origin.
+	AllowedOrigins []string `json:"allowed_origins"`
+
+	// The methods which may be used for the resource, defaults to [DefaultAllowedMethods].
+	AllowedMethods []string `json:"allowed_methods"`
+
+	// The request headers which may be sent, defaults to [DefaultAllowedHeaders].
+	// A single "*" allows all headers.
+	AllowedHeaders []string `json:"allowed_headers"`
+
+	// The response headers which may be read by the client, in addition to the CORS-safelisted headers.
+	ExposedHeaders []string `json:"exposed_headers"`
+
+	// Allow cookies and HTTP authentication to be sent with requests.
+	AllowCredentials bool `json:"allow_credentials"`
+
+	// The number of seconds the result of a preflight request may be cached by the client,
+	// the Access-Control-Max-Age header is omitted if zero.
+	MaxAge int `json:"max_age"`
+
+	// Exempt unsafe requests from allowed origins from the CSRF checks.
+	//
+	// Origins allowed by a single "*" are never exempt.
+	// The exemption is applied by the CORS middleware of the application, which runs before the CSRF middleware.
+	CSRFExempt bool `json:"csrf_exempt"`
+}
+
+// Validate reports whether the config can be used to create a [Policy].
+func (c Config) Validate() error {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				return ErrCredentialsWithAnyOrigin
+			}
+			continue
+		}
+
+		var scheme, host, ok = strings.Cut(origin, "://")
+		if !ok || scheme == "" || host == "" || strings.Contains(host, "/") {
+			return fmt.Errorf("%w: %q", ErrInvalidOrigin, origin)
+		}
+
+		if strings.Contains(host, "*") && (!strings.HasPrefix(host, "*.") || strings.Count(host, "*") > 1) {
+			return fmt.Errorf("%w: %q", ErrInvalidOrigin, origin)
+		}
+	}
+	return nil
+}
+
+// Policy applies a [Config] to requests.
+type Policy struct {
+	config    Config
+	anyOrigin bool
+	origins   []string
+	patterns  []originPattern
+	methods   []string
+	headers   []string
+	anyHeader bool
+	allowed   string
+	exposed   string
+	maxAge    string
+}
+
+// New creates a policy from the config, it panics if the config is invalid.
+func New(config Config) *Policy {
+	var err = config.Validate()
+	assert.True(err == nil, "invalid CORS config: %v", err)
+
+	var p = &Policy{
+		config:  config,
+		methods: config.AllowedMethods,
+		headers: config.AllowedHeaders,
+	}
+
+	if len(p.methods) == 0 {
+		p.methods = DefaultAllowedMethods
+	}
+
+	if len(p.headers) == 0 {
+		p.headers = DefaultAllowedHeaders
+	}
+
+	for _, origin := range config.AllowedOrigins {
+		origin = strings.ToLower(origin)
+		switch {
+		case origin == "*":
+			p.anyOrigin = true
+		case strings.Contains(origin, "*"):
+			p.patterns = append(p.patterns, newOriginPattern(origin))
+		default:
+			p.origins = append(p.origins, origin)
+		}
+	}
+
+	var headers = make([]string, 0, len(p.headers))
+	for _, header := range p.headers {
+		if header == "*" {
+			p.anyHeader = true
+			continue
+		}
+		headers = append(headers, strings.ToLower(header))
+	}
+	p.headers = headers
+
+	p.allowed = strings.Join(p.methods, ", ")
+	p.exposed = strings.Join(config.ExposedHeaders, ", ")
+	if config.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(config.MaxAge)
+	}
+
+	return p
+}
+
+// Middleware returns a middleware which applies a policy created from the config, see [New].
+func Middleware(config Config) func(next http.Handler) http.Handler {
+	return New(config).Handler
+}
+
+// Config returns the config of the policy.
+func (p *Policy) Config() Config {
+	return p.config
+}
+
+// Handler answers preflight requests and adds the CORS headers to the responses of the next handler.
+func (p *Policy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsPreflight(r) {
+			p.ServePreflight(w, r)
+			return
+		}
+
+		p.SetHeaders(w, r)
+		next.ServeHTTP(w, r)
+	})
+}
+
+type preflightContextKey struct{}
+
+type preflightState struct {
+	answered bool
+}
+
+// FallbackHandler answers the preflight requests which are not answered by a [Policy] of the route they are routed to,
+// and adds the CORS headers to the responses of the next handler.
+//
+// Preflight requests are passed to the next handler first, the policy answers them only
+// if no policy further down the chain did, the response of the route is discarded in that case.
+func (p *Policy) FallbackHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !IsPreflight(r) {
+			p.SetHeaders(w, r)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var state = &preflightState{}
+		var fw = &fallbackWriter{ResponseWriter: w, state: state, header: make(http.Header)}
+		next.ServeHTTP(fw, r.WithContext(context.WithValue(r.Context(), preflightContextKey{}, state)))
+
+		if !state.answered {
+			p.ServePreflight(w, r)
+		}
+	})
+}
+
+// fallbackWriter passes the response through if a policy answered the preflight request,
+// otherwise the response is discarded.
+type fallbackWriter struct {
+	http.ResponseWriter
+	state       *preflightState
+	header      http.Header
+	wroteHeader bool
+}
+
+func (w *fallbackWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *fallbackWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if !w.state.answered {
+		return
+	}
+
+	var header = w.ResponseWriter.Header()
+	for key, values := range w.header {
+		header[key] = values
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *fallbackWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.state.answered {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *fallbackWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// IsPreflight reports whether the request is a CORS preflight request.
+func IsPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// AllowsOrigin reports whether the origin may access the resource.
+func (p *Policy) AllowsOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	return p.anyOrigin || p.matchesOrigin(strings.ToLower(origin))
+}
+
+// CSRFExempt reports whether the request is exempt from the CSRF checks, see [Config.CSRFExempt].
+func (p *Policy) CSRFExempt(r *http.Request) bool {
+	var origin = r.Header.Get("Origin")
+	return p.config.CSRFExempt && origin != "" && p.matchesOrigin(strings.ToLower(origin))
+}
+
+func (p *Policy) matchesOrigin(origin string) bool {
+	if slices.Contains(p.origins, origin) {
+		return true
+	}
+	for _, pattern := range p.patterns {
+		if pattern.matches(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetHeaders adds the CORS headers for a request which is not a preflight request.
+//
+// The headers are only added if the origin of the request is allowed.
+func (p *Policy) SetHeaders(w http.ResponseWriter, r *http.Request) {
+	var header = w.Header()
+	addVary(header, "Origin")
+
+	var origin = r.Header.Get("Origin")
+	if !p.AllowsOrigin(origin) {
+		return
+	}
+
+	p.setOrigin(header, origin)
+	if p.exposed != "" {
+		header.Set("Access-Control-Expose-Headers", p.exposed)
+	}
+}
+
+// ServePreflight answers a preflight request with a 204 No Content response.
+//
+// The CORS headers are only added if the origin, the method and the headers of the request are allowed,
+// otherwise the client blocks the request.
+func (p *Policy) ServePreflight(w http.ResponseWriter, r *http.Request) {
+	if state, ok := r.Context().Value(preflightContextKey{}).(*preflightState); ok {
+		state.answered = true
+	}
+
+	var header = w.Header()
+	addVary(header, "Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers")
+
+	var (
+		origin  = r.Header.Get("Origin")
+		method  = r.Header.Get("Access-Control-Request-Method")
+		headers = parseHeaderList(r.Header.Get("Access-Control-Request-Headers"))
+	)
+
+	if !p.AllowsOrigin(origin) || !slices.Contains(p.methods, method) || !p.allowsHeaders(headers) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	p.setOrigin(header, origin)
+	header.Set("Access-Control-Allow-Methods", p.allowed)
+	if len(headers) > 0 {
+		header.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+	}
+	if p.maxAge != "" {
+		header.Set("Access-Control-Max-Age", p.maxAge)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *Policy) setOrigin(header http.Header, origin string) {
+	if p.anyOrigin && !p.config.AllowCredentials {
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+	}
+
+	if p.config.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (p *Policy) allowsHeaders(headers []string) bool {
+	if p.anyHeader {
+		return true
+	}
+	for _, header := range headers {
+		if !slices.Contains(p.headers, header) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseHeaderList parses the lowercased, comma separated list of header names of the Access-Control-Request-Headers header.
+func parseHeaderList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	var headers = make([]string, 0, strings.Count(value, ",")+1)
+	for header := range strings.SplitSeq(value, ",") {
+		if header = strings.TrimSpace(header); header != "" {
+			headers = append(headers, strings.ToLower(header))
+		}
+	}
+	return headers
+}
+
+// addVary adds the values to the Vary header if they are not present yet.
+func addVary(header http.Header, values ...string) {
+	var vary = header.Values("Vary")
+	for _, value := range values {
+		var present = slices.ContainsFunc(vary, func(v string) bool {
+			for field := range strings.SplitSeq(v, ",") {
+				if strings.EqualFold(strings.TrimSpace(field), value) {
+					return true
+				}
+			}
+			return false
+		})
+		if !present {
+			header.Add("Vary", value)
+		}
+	}
+}
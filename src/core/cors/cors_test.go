@@ -0,0 +1,235 @@
+package cors_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/go-django/src/core/cors"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok"))
+})
+
+func serve(h http.Handler, method, origin string, header map[string]string) *httptest.ResponseRecorder {
+	var r = httptest.NewRequest(method, "/api/menu", nil)
+	if origin != "" {
+		r.Header.Set("Origin", origin)
+	}
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	var w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestValidate(t *testing.T) {
+	var tests = []struct {
+		name   string
+		config cors.Config
+		err    error
+	}{
+		{"origin", cors.Config{AllowedOrigins: []string{"https://example.com"}}, nil},
+		{"subdomains", cors.Config{AllowedOrigins: []string{"https://*.example.com:8443"}}, nil},
+		{"any", cors.Config{AllowedOrigins: []string{"*"}}, nil},
+		{"any with credentials", cors.Config{AllowedOrigins: []string{"*"}, AllowCredentials: true}, cors.ErrCredentialsWithAnyOrigin},
+		{"no scheme", cors.Config{AllowedOrigins: []string{"example.com"}}, cors.ErrInvalidOrigin},
+		{"path", cors.Config{AllowedOrigins: []string{"https://example.com/api"}}, cors.ErrInvalidOrigin},
+		{"partial wildcard", cors.Config{AllowedOrigins: []string{"https://api*.example.com"}}, cors.ErrInvalidOrigin},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var err = test.config.Validate()
+			if !errors.Is(err, test.err) {
+				t.Errorf("expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestAllowsOrigin(t *testing.T) {
+	var p = cors.New(cors.Config{
+		AllowedOrigins: []string{"https://example.com", "https://*.example.org"},
+	})
+
+	var tests = map[string]bool{
+		"https://example.com":           true,
+		"HTTPS://EXAMPLE.COM":           true,
+		"http://example.com":            false,
+		"https://example.com:8443":      false,
+		"https://app.example.org":       true,
+		"https://a.b.example.org":       true,
+		"https://example.org":           false,
+		"https://evil.com/.example.org": false,
+		"https://app.example.org.evil":  false,
+		"":                              false,
+	}
+
+	for origin, allowed := range tests {
+		if p.AllowsOrigin(origin) != allowed {
+			t.Errorf("expected AllowsOrigin(%q) to be %v", origin, allowed)
+		}
+	}
+}
+
+func TestActualRequest(t *testing.T) {
+	var h = cors.Middleware(cors.Config{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		ExposedHeaders:   []string{"X-Total-Count"},
+		AllowCredentials: true,
+	})(okHandler)
+
+	var w = serve(h, http.MethodGet, "https://app.example.com", nil)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("expected the origin to be allowed, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("expected credentials to be allowed, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Expose-Headers"); got != "X-Total-Count" {
+		t.Errorf("expected exposed headers, got %q", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("expected Vary: Origin, got %q", got)
+	}
+	if w.Body.String() != "ok" {
+		t.Errorf("expected the handler to be called")
+	}
+
+	w = serve(h, http.MethodGet, "https://evil.com", nil)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no CORS headers for a disallowed origin, got %q", got)
+	}
+	if w.Body.String() != "ok" {
+		t.Errorf("expected the handler to be called for a disallowed origin")
+	}
+}
+
+func TestAnyOrigin(t *testing.T) {
+	var h = cors.Middleware(cors.Config{AllowedOrigins: []string{"*"}})(okHandler)
+	var w = serve(h, http.MethodGet, "https://anywhere.com", nil)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("expected *, got %q", got)
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	var h = cors.Middleware(cors.Config{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		MaxAge:         600,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the handler must not be called for a preflight request")
+	}))
+
+	var w = serve(h, http.MethodOptions, "https://app.example.com", map[string]string{
+		"Access-Control-Request-Method":  "POST",
+		"Access-Control-Request-Headers": "Content-Type, X-CSRF-Token",
+	})
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", w.Code)
+	}
+
+	var expected = map[string]string{
+		"Access-Control-Allow-Origin":  "https://app.example.com",
+		"Access-Control-Allow-Methods": "GET, POST",
+		"Access-Control-Allow-Headers": "content-type, x-csrf-token",
+		"Access-Control-Max-Age":       "600",
+	}
+	for k, v := range expected {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("expected %s: %q, got %q", k, v, got)
+		}
+	}
+
+	var rejected = []map[string]string{
+		{"Access-Control-Request-Method": "DELETE"},
+		{"Access-Control-Request-Method": "POST", "Access-Control-Request-Headers": "X-Secret"},
+	}
+	for _, header := range rejected {
+		w = serve(h, http.MethodOptions, "https://app.example.com", header)
+		if w.Code != http.StatusNoContent {
+			t.Errorf("expected 204, got %d", w.Code)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("expected the preflight for %v to be rejected, got %q", header, got)
+		}
+	}
+}
+
+func TestCSRFExempt(t *testing.T) {
+	var newRequest = func(origin string) *http.Request {
+		var r = httptest.NewRequest(http.MethodPost, "/", nil)
+		r.Header.Set("Origin", origin)
+		return r
+	}
+
+	var p = cors.New(cors.Config{
+		AllowedOrigins: []string{"https://app.example.com", "*"},
+		CSRFExempt:     true,
+	})
+
+	if !p.CSRFExempt(newRequest("https://app.example.com")) {
+		t.Error("expected an explicitly allowed origin to be exempt")
+	}
+
+	if p.CSRFExempt(newRequest("https://evil.com")) {
+		t.Error("expected an origin allowed by * not to be exempt")
+	}
+
+	p = cors.New(cors.Config{AllowedOrigins: []string{"https://app.example.com"}})
+	if p.CSRFExempt(newRequest("https://app.example.com")) {
+		t.Error("expected no exemption without CSRFExempt")
+	}
+}
+
+func TestFallbackHandler(t *testing.T) {
+	var fallback = cors.New(cors.Config{AllowedOrigins: []string{"https://app.example.com"}})
+	var route = cors.Middleware(cors.Config{AllowedOrigins: []string{"https://other.example.com"}})
+
+	var mux = http.NewServeMux()
+	mux.Handle("/api/", route(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the handler must not be called for a preflight request")
+	})))
+	mux.HandleFunc("GET /page", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("page"))
+	})
+
+	var h = fallback.FallbackHandler(mux)
+	var serve = func(method, origin, path string) *httptest.ResponseRecorder {
+		var r = httptest.NewRequest(method, path, nil)
+		r.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			r.Header.Set("Access-Control-Request-Method", "GET")
+		}
+		var w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	var w = serve(http.MethodOptions, "https://other.example.com", "/api/items")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://other.example.com" {
+		t.Errorf("expected the preflight to be answered by the route policy, got %q", got)
+	}
+
+	w = serve(http.MethodOptions, "https://app.example.com", "/page")
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("expected the preflight to be answered by the fallback policy, got %q", got)
+	}
+	if w.Body.Len() > 0 {
+		t.Errorf("expected the response of the route to be discarded, got %q", w.Body.String())
+	}
+
+	w = serve(http.MethodGet, "https://app.example.com", "/page")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" || w.Body.String() != "page" {
+		t.Errorf("expected the fallback headers on the response, got %q: %q", got, w.Body.String())
+	}
+}
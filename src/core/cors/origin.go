@@ -0,0 +1,33 @@
+package cors
+
+import "strings"
+
+// originPattern matches the subdomains of an origin, i.e. "https://*.example.com".
+type originPattern struct {
+	prefix string // "https://"
+	suffix string // ".example.com", including the port if any
+}
+
+func newOriginPattern(origin string) originPattern {
+	var prefix, suffix, _ = strings.Cut(origin, "*")
+	return originPattern{prefix: prefix, suffix: suffix}
+}
+
+// matches reports whether the lowercased origin is a subdomain of the pattern,
+// the pattern does not match the domain itself.
+func (p originPattern) matches(origin string) bool {
+	if len(origin) <= len(p.prefix)+len(p.suffix) ||
+		!strings.HasPrefix(origin, p.prefix) ||
+		!strings.HasSuffix(origin, p.suffix) {
+		return false
+	}
+
+	var subdomain = origin[len(p.prefix) : len(origin)-len(p.suffix)]
+	for i := 0; i < len(subdomain); i++ {
+		var c = subdomain[i]
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '.' {
+			return false
+		}
+	}
+	return subdomain[0] != '.' && subdomain[len(subdomain)-1] != '.'
+}
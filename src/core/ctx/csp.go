@@ -0,0 +1,23 @@
+package ctx
+
+import (
+	gocontext "context"
+
+	"github.com/a-h/templ"
+)
+
+// WithCSPNonce returns a copy of the context which carries the Content-Security-Policy nonce of the request.
+//
+// The nonce is stored with [templ.WithNonce], so the scripts rendered by templ components carry it as well.
+func WithCSPNonce(c gocontext.Context, nonce string) gocontext.Context {
+	return templ.WithNonce(c, nonce)
+}
+
+// CSPNonce returns the Content-Security-Policy nonce of the request, or an empty string if there is none.
+//
+// Inline scripts and styles must carry the nonce to be allowed by the policy:
+//
+//	<script nonce="{{ csp_nonce }}">...</script>
+func CSPNonce(c gocontext.Context) string {
+	return templ.GetNonce(c)
+}
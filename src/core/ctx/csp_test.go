@@ -0,0 +1,48 @@
+package ctx_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/go-django/src/core/ctx"
+	"github.com/a-h/templ"
+)
+
+func TestCSPNonce(t *testing.T) {
+	if nonce := ctx.CSPNonce(context.Background()); nonce != "" {
+		t.Errorf("expected no nonce, got %q", nonce)
+	}
+
+	var c = ctx.WithCSPNonce(context.Background(), "abc123")
+	if nonce := ctx.CSPNonce(c); nonce != "abc123" {
+		t.Errorf("expected %q, got %q", "abc123", nonce)
+	}
+
+	if nonce := templ.GetNonce(c); nonce != "abc123" {
+		t.Errorf("expected templ nonce %q, got %q", "abc123", nonce)
+	}
+}
+
+func TestRequestContextCSPNonce(t *testing.T) {
+	var r = httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(ctx.WithCSPNonce(r.Context(), "abc123"))
+
+	var c = ctx.RequestContext(r)
+	if c.CSPNonce() != "abc123" {
+		t.Errorf("expected %q, got %q", "abc123", c.CSPNonce())
+	}
+
+	if v := c.Get("csp_nonce"); v != "abc123" {
+		t.Errorf("expected %q, got %v", "abc123", v)
+	}
+
+	var clone, err = c.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := clone.Get("CSPNonce"); v != "abc123" {
+		t.Errorf("expected cloned nonce %q, got %v", "abc123", v)
+	}
+}
@@ -11,6 +11,7 @@ type HTTPRequestContext struct {
 	Context
 	HttpRequest *http.Request
 	CsrfToken   string
+	CspNonce    string
 }
 
 func RequestContext(r *http.Request) *HTTPRequestContext {
@@ -18,6 +19,7 @@ func RequestContext(r *http.Request) *HTTPRequestContext {
 		HttpRequest: r,
 		Context:     NewContext(nil),
 		CsrfToken:   nosurf.Token(r),
+		CspNonce:    CSPNonce(r.Context()),
 	}
 
 	return request
@@ -31,6 +33,10 @@ func (c *HTTPRequestContext) CSRFToken() string {
 	return c.CsrfToken
 }
 
+func (c *HTTPRequestContext) CSPNonce() string {
+	return c.CspNonce
+}
+
 func (c *HTTPRequestContext) Set(key string, value any) {
 	if v, ok := value.(Editor); ok {
 		v.EditContext(key, c)
@@ -44,6 +50,8 @@ func (c *HTTPRequestContext) Get(key string) any {
 	switch key {
 	case "csrf_token", "CsrfToken", "CSRFToken":
 		return c.CsrfToken
+	case "csp_nonce", "CspNonce", "CSPNonce":
+		return c.CspNonce
 	case "request", "Request":
 		return c.HttpRequest
 	}
@@ -56,6 +64,7 @@ func (c *HTTPRequestContext) Clone(values ...interface{}) (Context, error) {
 		Context:     subCopy,
 		HttpRequest: c.HttpRequest.Clone(c.HttpRequest.Context()),
 		CsrfToken:   c.CsrfToken,
+		CspNonce:    c.CspNonce,
 	}
 
 	var other, err = TemplateDictFunc(values...)
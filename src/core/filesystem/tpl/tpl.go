@@ -16,6 +16,7 @@ import (
 	"github.com/Nigel2392/go-django/src/core/assert"
 	"github.com/Nigel2392/go-django/src/core/ctx"
 	"github.com/Nigel2392/go-django/src/core/filesystem"
+	"github.com/Nigel2392/go-django/src/core/trace"
 	"github.com/Nigel2392/go-signals"
 	"github.com/pkg/errors"
 )
@@ -499,3 +500,14 @@ func getTemplateName(path string) string {
 	)
 	return name
 }
+
+// startRenderSpan starts the "template.render" span of a template,
+// it is a child of the span of the request if the template is rendered for a request.
+func startRenderSpan(request *http.Request, name string, args ...any) *trace.Span {
+	var ctx = context.Background()
+	if request != nil {
+		ctx = request.Context()
+	}
+	var _, span = trace.Start(ctx, "template.render", append([]any{"template", name}, args...)...)
+	return span
+}
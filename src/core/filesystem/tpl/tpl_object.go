@@ -95,5 +95,10 @@ func (t *templateObject) Execute(w io.Writer, data any) error {
 		}
 	}
 
-	return tmpl.ExecuteTemplate(w, target, context)
+	var span = startRenderSpan(request, t.allPaths[0], "template.target", target)
+	defer span.End()
+
+	err = tmpl.ExecuteTemplate(w, target, context)
+	span.SetError(err)
+	return err
 }
@@ -41,5 +41,10 @@ func (t *stringTemplateObject) Execute(w io.Writer, data any) error {
 		return errors.Wrapf(err, "failed to get template from string for %s", t.name)
 	}
 
-	return tmpl.ExecuteTemplate(w, t.name, context)
+	var span = startRenderSpan(request, t.name)
+	defer span.End()
+
+	err = tmpl.ExecuteTemplate(w, t.name, context)
+	span.SetError(err)
+	return err
 }
@@ -0,0 +1,237 @@
+/*
+Package health runs probes which report whether the application and the services it depends on are healthy.
+
+A probe is a named function which returns an error when the service it checks is unhealthy,
+i.e. a database which cannot be pinged or a session store which cannot be reached.
+
+Probes are run in parallel by a [Checker], each with their own timeout.
+The results of expensive probes can be cached with [Probe.CacheFor].
+*/
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is the timeout of a probe which does not define its own timeout.
+var DefaultTimeout = 5 * time.Second
+
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusFailing Status = "failing"
+)
+
+// Probe is a named health check.
+type Probe struct {
+	// The name of the probe, i.e. "database.DATABASE".
+	Name string
+
+	// Check returns an error if the probe is unhealthy.
+	//
+	// The context is cancelled after the timeout of the probe.
+	Check func(ctx context.Context) error
+
+	// The maximum duration of the check, defaults to [DefaultTimeout].
+	Timeout time.Duration
+
+	// The duration the result of the probe is cached for, the result is not cached if zero.
+	//
+	// This should be set for expensive probes, i.e. probes which write to a storage backend.
+	CacheFor time.Duration
+
+	// Whether the probe is part of the liveness check.
+	//
+	// Liveness probes should only fail when the application must be restarted,
+	// the services the application depends on are part of the readiness check.
+	Liveness bool
+}
+
+// Result is the result of running a single probe.
+type Result struct {
+	Name      string        `json:"name"`
+	Status    Status        `json:"status"`
+	Latency   time.Duration `json:"-"`
+	LatencyMS float64       `json:"latency_ms"`
+	Error     string        `json:"error,omitempty"`
+	Cached    bool          `json:"cached,omitempty"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// Healthy reports whether the probe passed.
+func (r Result) Healthy() bool {
+	return r.Status == StatusOK
+}
+
+// Report is the result of running a set of probes.
+type Report struct {
+	Status Status   `json:"status"`
+	Probes []Result `json:"probes"`
+}
+
+// Healthy reports whether all probes passed.
+func (r Report) Healthy() bool {
+	return r.Status == StatusOK
+}
+
+type cachedResult struct {
+	result  Result
+	expires time.Time
+}
+
+// Checker runs a set of probes and caches their results.
+type Checker struct {
+	probes []Probe
+	mu     sync.Mutex
+	cache  map[string]cachedResult
+}
+
+// NewChecker returns a checker for the probes.
+//
+// Probes with an empty name or without a check function are ignored.
+func NewChecker(probes ...Probe) *Checker {
+	var c = &Checker{
+		probes: make([]Probe, 0, len(probes)),
+		cache:  make(map[string]cachedResult),
+	}
+
+	for _, probe := range probes {
+		if probe.Name == "" || probe.Check == nil {
+			continue
+		}
+		c.probes = append(c.probes, probe)
+	}
+
+	slices.SortStableFunc(c.probes, func(a, b Probe) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return c
+}
+
+// Probes returns the probes of the checker sorted by name.
+func (c *Checker) Probes() []Probe {
+	return slices.Clone(c.probes)
+}
+
+// Run runs the probes for which the filter returns true in parallel, all probes are run if the filter is nil.
+func (c *Checker) Run(ctx context.Context, filter func(Probe) bool) Report {
+	var probes = make([]Probe, 0, len(c.probes))
+	for _, probe := range c.probes {
+		if filter == nil || filter(probe) {
+			probes = append(probes, probe)
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		report = Report{
+			Status: StatusOK,
+			Probes: make([]Result, len(probes)),
+		}
+	)
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Probes[i] = c.run(ctx, probe)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Probes {
+		if !result.Healthy() {
+			report.Status = StatusFailing
+		}
+	}
+	return report
+}
+
+// Liveness runs the liveness probes.
+func (c *Checker) Liveness(ctx context.Context) Report {
+	return c.Run(ctx, func(p Probe) bool {
+		return p.Liveness
+	})
+}
+
+// Readiness runs all probes.
+func (c *Checker) Readiness(ctx context.Context) Report {
+	return c.Run(ctx, nil)
+}
+
+func (c *Checker) run(ctx context.Context, probe Probe) Result {
+	if probe.CacheFor > 0 {
+		c.mu.Lock()
+		var cached, ok = c.cache[probe.Name]
+		c.mu.Unlock()
+
+		if ok && time.Now().Before(cached.expires) {
+			cached.result.Cached = true
+			return cached.result
+		}
+	}
+
+	var result = Check(ctx, probe)
+	if probe.CacheFor > 0 {
+		c.mu.Lock()
+		c.cache[probe.Name] = cachedResult{
+			result:  result,
+			expires: result.CheckedAt.Add(probe.CacheFor),
+		}
+		c.mu.Unlock()
+	}
+	return result
+}
+
+// Check runs a single probe with its timeout, without caching the result.
+//
+// A panic in the check function is recovered and reported as a failing probe.
+func Check(ctx context.Context, probe Probe) (result Result) {
+	var timeout = probe.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result = Result{
+		Name:      probe.Name,
+		Status:    StatusOK,
+		CheckedAt: time.Now(),
+	}
+
+	var errCh = make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("probe panicked: %v", r)
+			}
+		}()
+		errCh <- probe.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("probe timed out after %s", timeout)
+		}
+	}
+
+	result.Latency = time.Since(result.CheckedAt)
+	result.LatencyMS = float64(result.Latency.Microseconds()) / 1000
+	if err != nil {
+		result.Status = StatusFailing
+		result.Error = err.Error()
+	}
+	return result
+}
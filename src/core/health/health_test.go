@@ -0,0 +1,75 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/go-django/src/core/health"
+)
+
+func TestCheckerRun(t *testing.T) {
+	var checker = health.NewChecker(
+		health.Probe{Name: "ok", Liveness: true, Check: func(ctx context.Context) error { return nil }},
+		health.Probe{Name: "failing", Check: func(ctx context.Context) error { return errors.New("unreachable") }},
+		health.Probe{Name: "timeout", Timeout: 10 * time.Millisecond, Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}},
+		health.Probe{Name: "panic", Check: func(ctx context.Context) error { panic("boom") }},
+		health.Probe{Name: "", Check: func(ctx context.Context) error { return nil }},
+	)
+
+	var report = checker.Readiness(context.Background())
+	if report.Healthy() {
+		t.Fatalf("expected the report to be failing")
+	}
+
+	var expected = map[string]health.Status{
+		"failing": health.StatusFailing,
+		"ok":      health.StatusOK,
+		"panic":   health.StatusFailing,
+		"timeout": health.StatusFailing,
+	}
+
+	if len(report.Probes) != len(expected) {
+		t.Fatalf("expected %d probes, got %d", len(expected), len(report.Probes))
+	}
+
+	for _, result := range report.Probes {
+		if result.Status != expected[result.Name] {
+			t.Errorf("expected probe %q to be %s, got %s (%s)", result.Name, expected[result.Name], result.Status, result.Error)
+		}
+	}
+
+	var liveness = checker.Liveness(context.Background())
+	if !liveness.Healthy() || len(liveness.Probes) != 1 || liveness.Probes[0].Name != "ok" {
+		t.Fatalf("expected only the healthy liveness probe, got %+v", liveness.Probes)
+	}
+}
+
+func TestCheckerCache(t *testing.T) {
+	var calls atomic.Int32
+	var checker = health.NewChecker(health.Probe{
+		Name:     "expensive",
+		CacheFor: time.Minute,
+		Check: func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		},
+	})
+
+	var first = checker.Readiness(context.Background())
+	var second = checker.Readiness(context.Background())
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected the probe to run once, ran %d times", calls.Load())
+	}
+
+	if first.Probes[0].Cached || !second.Probes[0].Cached {
+		t.Fatalf("expected only the second result to be cached")
+	}
+}
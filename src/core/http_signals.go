@@ -13,8 +13,36 @@ type HttpSignal struct {
 	H mux.Handler
 }
 
+// CSPViolation is a Content-Security-Policy violation reported by a browser to the CSP report endpoint.
+type CSPViolation struct {
+	// The request which delivered the report.
+	R *http.Request
+
+	// The URL of the document in which the violation occurred.
+	DocumentURL string
+
+	// The URL of the resource which was blocked, or "inline" / "eval".
+	BlockedURL string
+
+	// The directive whose enforcement caused the violation.
+	Directive string
+
+	// Either "enforce" or "report", depending on whether the policy was report-only.
+	Disposition string
+
+	// The location of the script which caused the violation, if known.
+	SourceFile string
+	LineNumber int
+
+	// The report as sent by the browser.
+	Report map[string]any
+}
+
 var (
 	handlerSignalPool     = signals.NewPool[*HttpSignal]()
 	SIGNAL_BEFORE_REQUEST = handlerSignalPool.Get("http.before_request") // -> Send(HttpSignal)
 	SIGNAL_AFTER_REQUEST  = handlerSignalPool.Get("http.after_request")  // -> Send(HttpSignal)
+
+	cspSignalPool        = signals.NewPool[*CSPViolation]()
+	SIGNAL_CSP_VIOLATION = cspSignalPool.Get("http.csp_violation") // -> Send(CSPViolation)
 )
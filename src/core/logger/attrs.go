@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type contextAttrsKey struct{}
+
+// contextAttrs holds the attributes of a context, it is shared by all contexts derived from it
+// so attributes added further down a request are part of the log lines written afterwards.
+type contextAttrs struct {
+	mu    sync.RWMutex
+	attrs []slog.Attr
+}
+
+// ContextWithAttrs returns a context which holds the key/value attributes,
+// together with the attributes of the parent context.
+//
+// The attributes are added to the messages of the loggers returned by [Ctx] and [WithContext],
+// and to the records handled by a [ContextHandler].
+func ContextWithAttrs(ctx context.Context, args ...any) context.Context {
+	return context.WithValue(ctx, contextAttrsKey{}, &contextAttrs{
+		attrs: appendAttrs(ContextAttrs(ctx), args...),
+	})
+}
+
+// AddContextAttrs adds the key/value attributes to the attributes held by the context,
+// replacing attributes with the same key.
+//
+// Unlike [ContextWithAttrs] this does not create a new context, the attributes become visible to all
+// holders of the context, i.e. the request middleware which writes a log line once the request is served.
+//
+// It returns false if the context holds no attributes.
+func AddContextAttrs(ctx context.Context, args ...any) bool {
+	var c, ok = ctx.Value(contextAttrsKey{}).(*contextAttrs)
+	if !ok {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, attr := range appendAttrs(nil, args...) {
+		var idx = slices.IndexFunc(c.attrs, func(a slog.Attr) bool { return a.Key == attr.Key })
+		if idx >= 0 {
+			c.attrs[idx] = attr
+			continue
+		}
+		c.attrs = append(c.attrs, attr)
+	}
+	return true
+}
+
+// ContextAttrs returns a copy of the attributes held by the context.
+func ContextAttrs(ctx context.Context) []slog.Attr {
+	var c, ok = ctx.Value(contextAttrsKey{}).(*contextAttrs)
+	if !ok {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.attrs)
+}
+
+// WithContext returns the logger with the attributes held by the context, see [WithAttrs].
+func WithContext(l Log, ctx context.Context) Log {
+	var attrs = ContextAttrs(ctx)
+	if len(attrs) == 0 {
+		return l
+	}
+
+	var args = make([]any, len(attrs))
+	for i, attr := range attrs {
+		args[i] = attr
+	}
+	return WithAttrs(l, args...)
+}
+
+// appendAttrs converts the key/value arguments to attributes like [slog.Logger.With] does.
+func appendAttrs(attrs []slog.Attr, args ...any) []slog.Attr {
+	if len(args) == 0 {
+		return attrs
+	}
+	return append(attrs, slog.Group("", args...).Value.Group()...)
+}
+
+// writeTextAttr writes the attribute as a key=value pair, groups are flattened with dotted keys.
+func writeTextAttr(w io.StringWriter, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	var key = attr.Key
+	if prefix != "" && key != "" {
+		key = prefix + "." + key
+	} else if key == "" {
+		key = prefix
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, a := range attr.Value.Group() {
+			writeTextAttr(w, key, a)
+		}
+		return
+	}
+
+	var value = attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+		value = strconv.Quote(value)
+	}
+
+	w.WriteString(" ")
+	w.WriteString(key)
+	w.WriteString("=")
+	w.WriteString(value)
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -32,6 +33,7 @@ var (
 	Writer          = func(level LogLevel) io.Writer { return os.Stdout }
 	PWriter         = func(label string, level LogLevel) io.Writer { return prefixerwriter{label, level, os.Stdout} }
 	NameSpace       = func(label string) Log { return nil }
+	With            = func(args ...any) Log { return (&Logger{}).With(args...) }
 
 	SetOutput func(level LogLevel, w io.Writer) = func(level LogLevel, w io.Writer) {}
 	SetLevel  func(level LogLevel)              = func(level LogLevel) { defaultLogLevel = level }
@@ -52,10 +54,18 @@ var (
 	}
 )
 
+// Ctx returns the global logger with the attributes held by the context, see [ContextWithAttrs].
+//
+// It should be used to log messages while serving a request, so they carry the request ID and the other request attributes.
+func Ctx(ctx context.Context) Log {
+	return WithContext(With(), ctx)
+}
+
 func Setup(logger Log) {
 	Writer = logger.Writer
 	PWriter = logger.PWriter
 	NameSpace = logger.NameSpace
+	With = func(args ...any) Log { return WithAttrs(logger, args...) }
 
 	SetOutput = logger.SetOutput
 	SetLevel = logger.SetLevel
@@ -7,8 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -183,6 +185,28 @@ type Log interface {
 	WriteString(s string) (n int, err error)
 }
 
+// AttrsLog is implemented by loggers which can add key/value attributes to their messages,
+// such as [Logger] and [SlogLogger].
+//
+// It is not part of [Log] so existing implementations keep satisfying it, use [WithAttrs] to add attributes to any [Log].
+type AttrsLog interface {
+	Log
+
+	// With returns a new Log which adds the key/value attributes to each message.
+	//
+	// The arguments are handled like the arguments of [slog.Logger.With].
+	With(args ...any) Log
+}
+
+// WithAttrs returns the logger with the key/value attributes if it implements [AttrsLog],
+// otherwise the logger is returned as is.
+func WithAttrs(l Log, args ...any) Log {
+	if al, ok := l.(AttrsLog); ok {
+		return al.With(args...)
+	}
+	return l
+}
+
 // The Logger type is used to log messages at different log levels.
 //
 // It is possible to set the log level, prefix, suffix, and outputs for the log messages.
@@ -212,6 +236,9 @@ type Logger struct {
 	// based on the LogLevel.
 	WrapPrefix func(context.Context, LogLevel, string) string
 	Context    context.Context
+
+	// attributes added with [Logger.With], written as key=value pairs after the message.
+	attrs []slog.Attr
 }
 
 // SetOutput sets the output for the given log level.
@@ -303,9 +330,17 @@ func (l *Logger) Copy() *Logger {
 		OutputWarn:  l.OutputWarn,
 		OutputError: l.OutputError,
 		Context:     l.Context,
+		attrs:       l.attrs,
 	}
 }
 
+// With returns a new Log which writes the key/value attributes after each message.
+func (l *Logger) With(args ...any) Log {
+	var logger = l.Copy()
+	logger.attrs = appendAttrs(slices.Clip(l.attrs), args...)
+	return logger
+}
+
 // NameSpace returns a new Log with the given label as the prefix.
 func (l *Logger) NameSpace(label string) Log {
 	var logger = l.Copy()
@@ -472,6 +507,9 @@ func (l *Logger) log(level LogLevel, args ...interface{}) (int, error) {
 	var b = new(bytes.Buffer)
 	l.writePrefix(level, b)
 	fmt.Fprint(b, args...)
+	for _, attr := range l.attrs {
+		writeTextAttr(b, "", attr)
+	}
 	l.writeSuffix(b)
 
 	var message = b.String()
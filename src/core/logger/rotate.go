@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultMaxFileSize is the size at which a [RotatingFile] without a maximum size is rotated.
+const DefaultMaxFileSize int64 = 10 << 20
+
+// RotatingFile is an io.Writer which writes to a file and rotates it once it grows beyond a maximum size.
+//
+// When the file is rotated, "app.log" is renamed to "app.log.1", "app.log.1" to "app.log.2" and so on.
+// Backups beyond the maximum number of backups are removed.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotatingFile opens or creates the file at the path for appending, creating its directory if needed.
+//
+// The file is rotated once writing to it would exceed maxSize bytes, which defaults to [DefaultMaxFileSize].
+// At most maxBackups rotated files are kept, no rotated files are kept if it is zero.
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFileSize
+	}
+
+	var f = &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: max(maxBackups, 0),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Path returns the path of the file which is written to.
+func (f *RotatingFile) Path() string {
+	return f.path
+}
+
+func (f *RotatingFile) open() error {
+	var file, err = os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// Write writes the bytes to the file, the file is rotated first if the bytes do not fit in it.
+//
+// A single write larger than the maximum size is written to an empty file.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, fs.ErrClosed
+	}
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	var n, err = f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the file, regardless of its size.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+func (f *RotatingFile) rotate() error {
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return fmt.Errorf("failed to close log file: %w", err)
+		}
+		f.file = nil
+	}
+
+	if f.maxBackups == 0 {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove log file: %w", err)
+		}
+		return f.open()
+	}
+
+	// the oldest backup is overwritten by the rename
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		var err = os.Rename(f.backupPath(i), f.backupPath(i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+
+	if err := os.Rename(f.path, f.backupPath(1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	return f.open()
+}
+
+func (f *RotatingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", f.path, n)
+}
+
+// Close closes the file, writing to a closed file returns [fs.ErrClosed].
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	var err = f.file.Close()
+	f.file = nil
+	return err
+}
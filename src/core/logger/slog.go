@@ -0,0 +1,277 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	_ AttrsLog = (*Logger)(nil)
+	_ AttrsLog = (*SlogLogger)(nil)
+)
+
+// LevelCritical is the slog level of [CRIT] messages.
+const LevelCritical = slog.LevelError + 4
+
+// SlogLevel returns the slog level for the log level.
+func (l LogLevel) SlogLevel() slog.Level {
+	switch {
+	case l <= DBG:
+		return slog.LevelDebug
+	case l == INF:
+		return slog.LevelInfo
+	case l == WRN:
+		return slog.LevelWarn
+	case l == ERR:
+		return slog.LevelError
+	}
+	return LevelCritical
+}
+
+// ContextHandler wraps the handler so the attributes held by the context
+// of a record are added to the record, see [ContextWithAttrs].
+func ContextHandler(h slog.Handler) slog.Handler {
+	if _, ok := h.(*contextHandler); ok {
+		return h
+	}
+	return &contextHandler{Handler: h}
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := ContextAttrs(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// handlerOptions returns the options for the built-in handlers,
+// critical messages are written with the "CRITICAL" level.
+func handlerOptions(level slog.Leveler) *slog.HandlerOptions {
+	return &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.LevelKey {
+				if level, ok := a.Value.Any().(slog.Level); ok && level >= LevelCritical {
+					a.Value = slog.StringValue(levelMap[CRIT])
+				}
+			}
+			return a
+		},
+	}
+}
+
+// NewJSONLogger returns a logger which writes each message as a JSON object to the writer.
+func NewJSONLogger(w io.Writer, level LogLevel) *SlogLogger {
+	return newSlogLogger(level, func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+		return slog.NewJSONHandler(w, opts)
+	}, w)
+}
+
+// NewLogfmtLogger returns a logger which writes each message as logfmt key=value pairs to the writer.
+func NewLogfmtLogger(w io.Writer, level LogLevel) *SlogLogger {
+	return newSlogLogger(level, func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+		return slog.NewTextHandler(w, opts)
+	}, w)
+}
+
+// NewSlogLogger returns a logger which writes its messages to the handler.
+//
+// The handler is wrapped with [ContextHandler]. Messages below the level of the logger are dropped
+// before they reach the handler, [SlogLogger.SetOutput] has no effect on a logger created with a handler.
+func NewSlogLogger(h slog.Handler, level LogLevel) *SlogLogger {
+	var l = &SlogLogger{base: &slogBase{
+		handler: ContextHandler(h),
+		level:   new(slog.LevelVar),
+	}}
+	l.SetLevel(level)
+	return l
+}
+
+func newSlogLogger(level LogLevel, newHandler func(io.Writer, *slog.HandlerOptions) slog.Handler, w io.Writer) *SlogLogger {
+	var l = &SlogLogger{base: &slogBase{
+		level:      new(slog.LevelVar),
+		newHandler: newHandler,
+	}}
+	l.SetLevel(level)
+	l.SetOutput(OutputAll, w)
+	return l
+}
+
+type slogBase struct {
+	mu         sync.RWMutex
+	handler    slog.Handler
+	level      *slog.LevelVar
+	logLevel   LogLevel
+	newHandler func(io.Writer, *slog.HandlerOptions) slog.Handler
+}
+
+// SlogLogger is a [Log] which writes structured records to a [slog.Handler].
+//
+// The namespace of the logger is added as the "logger" attribute,
+// attributes added with [SlogLogger.With] are added to each record.
+//
+// Loggers returned by [SlogLogger.NameSpace] and [SlogLogger.With] share
+// the handler and the level of the logger they were created from.
+type SlogLogger struct {
+	base      *slogBase
+	namespace string
+	attrs     []slog.Attr
+}
+
+// Handler returns the handler of the logger, it adds the attributes held by the context of a record.
+func (l *SlogLogger) Handler() slog.Handler {
+	l.base.mu.RLock()
+	defer l.base.mu.RUnlock()
+	return l.base.handler
+}
+
+// SetOutput replaces the output of all log levels, a logger writes all levels to a single output.
+func (l *SlogLogger) SetOutput(level LogLevel, w io.Writer) {
+	if l.base.newHandler == nil {
+		return
+	}
+
+	l.base.mu.Lock()
+	defer l.base.mu.Unlock()
+	l.base.handler = ContextHandler(l.base.newHandler(w, handlerOptions(l.base.level)))
+}
+
+func (l *SlogLogger) SetLevel(level LogLevel) {
+	l.base.mu.Lock()
+	defer l.base.mu.Unlock()
+	l.base.logLevel = level
+	l.base.level.Set(level.SlogLevel())
+}
+
+func (l *SlogLogger) GetLevel() LogLevel {
+	l.base.mu.RLock()
+	defer l.base.mu.RUnlock()
+	return l.base.logLevel
+}
+
+func (l *SlogLogger) copy() *SlogLogger {
+	return &SlogLogger{
+		base:      l.base,
+		namespace: l.namespace,
+		attrs:     l.attrs,
+	}
+}
+
+// NameSpace returns a new Log which writes the label as the "logger" attribute.
+func (l *SlogLogger) NameSpace(label string) Log {
+	var logger = l.copy()
+	if l.namespace != "" {
+		label = fmt.Sprintf("%s / %s", l.namespace, label)
+	}
+	logger.namespace = label
+	return logger
+}
+
+func (l *SlogLogger) With(args ...any) Log {
+	var logger = l.copy()
+	logger.attrs = appendAttrs(slices.Clip(l.attrs), args...)
+	return logger
+}
+
+func (l *SlogLogger) Writer(level LogLevel) io.Writer {
+	return &slogWriter{logger: l, level: level}
+}
+
+func (l *SlogLogger) PWriter(label string, level LogLevel) io.Writer {
+	return &slogWriter{logger: l.NameSpace(label).(*SlogLogger), level: level}
+}
+
+func (l *SlogLogger) log(level LogLevel, msg string) {
+	if level < l.GetLevel() {
+		return
+	}
+
+	var handler = l.Handler()
+	var ctx = context.Background()
+	var slogLevel = level.SlogLevel()
+	if handler == nil || !handler.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	var r = slog.NewRecord(time.Now(), slogLevel, msg, 0)
+	if l.namespace != "" {
+		r.AddAttrs(slog.String("logger", l.namespace))
+	}
+	r.AddAttrs(l.attrs...)
+	_ = handler.Handle(ctx, r)
+}
+
+func (l *SlogLogger) Debug(args ...interface{}) { l.log(DBG, fmt.Sprint(args...)) }
+func (l *SlogLogger) Info(args ...interface{})  { l.log(INF, fmt.Sprint(args...)) }
+func (l *SlogLogger) Warn(args ...interface{})  { l.log(WRN, fmt.Sprint(args...)) }
+func (l *SlogLogger) Error(args ...interface{}) { l.log(ERR, fmt.Sprint(args...)) }
+
+func (l *SlogLogger) Fatal(errorcode int, args ...interface{}) {
+	l.log(CRIT, fmt.Sprint(args...))
+	os.Exit(errorcode)
+}
+
+func (l *SlogLogger) Debugf(format string, args ...interface{}) {
+	l.log(DBG, fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) Infof(format string, args ...interface{}) {
+	l.log(INF, fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) Warnf(format string, args ...interface{}) {
+	l.log(WRN, fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) Errorf(format string, args ...interface{}) {
+	l.log(ERR, fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) Fatalf(errorcode int, format string, args ...interface{}) {
+	l.log(CRIT, fmt.Sprintf(format, args...))
+	os.Exit(errorcode)
+}
+
+func (l *SlogLogger) Log(level LogLevel, args ...interface{}) {
+	l.log(level, fmt.Sprint(args...))
+}
+
+func (l *SlogLogger) Logf(level LogLevel, format string, args ...interface{}) {
+	l.log(level, fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) WriteString(s string) (n int, err error) {
+	l.log(INF, s)
+	return len(s), nil
+}
+
+// slogWriter writes each call to Write as a message.
+type slogWriter struct {
+	logger *SlogLogger
+	level  LogLevel
+}
+
+func (w *slogWriter) Write(p []byte) (int, error) {
+	w.logger.log(w.level, strings.TrimRight(string(p), "\r\n"))
+	return len(p), nil
+}
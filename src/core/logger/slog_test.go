@@ -0,0 +1,133 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nigel2392/go-django/src/core/logger"
+)
+
+func TestJSONLogger(t *testing.T) {
+	var buf = new(bytes.Buffer)
+	var log = logger.NewJSONLogger(buf, logger.INF)
+
+	var ctx = logger.ContextWithAttrs(context.Background(), "request_id", "abc")
+	logger.AddContextAttrs(ctx, "user_id", 1)
+
+	logger.WithContext(log.NameSpace("SQL"), ctx).Infof("query %d", 1)
+	log.Debug("not written")
+	log.Log(logger.CRIT, "critical")
+
+	var lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), buf.String())
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("failed to decode record: %v", err)
+	}
+
+	var expected = map[string]any{
+		"level":      "INFO",
+		"msg":        "query 1",
+		"logger":     "SQL",
+		"request_id": "abc",
+		"user_id":    float64(1),
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, record[key])
+		}
+	}
+
+	if !strings.Contains(lines[1], `"level":"CRITICAL"`) {
+		t.Errorf("expected a critical level, got %s", lines[1])
+	}
+}
+
+func TestContextHandler(t *testing.T) {
+	var buf = new(bytes.Buffer)
+	var log = slog.New(logger.ContextHandler(slog.NewTextHandler(buf, nil)))
+
+	var ctx = logger.ContextWithAttrs(context.Background(), "request_id", "abc")
+	log.InfoContext(ctx, "served")
+
+	if !strings.Contains(buf.String(), "request_id=abc") {
+		t.Fatalf("expected the context attributes, got %s", buf.String())
+	}
+}
+
+func TestTextLoggerAttrs(t *testing.T) {
+	var buf = new(bytes.Buffer)
+	var log = &logger.Logger{Level: logger.DBG, OutputDebug: buf}
+
+	log.With("request_id", "abc", slog.Group("user", "id", 1), "path", "/a b").Info("served")
+
+	var expected = "[INFO]: served request_id=abc user.id=1 path=\"/a b\"\n"
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "logs", "app.log")
+	var f, err = logger.NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	var expected = map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for p, content := range expected {
+		var b, err = os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", p, err)
+		}
+		if string(b) != content {
+			t.Errorf("expected %s to contain %q, got %q", p, content, string(b))
+		}
+	}
+
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups to be kept")
+	}
+}
+
+type baseLog = logger.Log
+
+// plainLog implements [logger.Log] without [logger.AttrsLog].
+type plainLog struct {
+	baseLog
+}
+
+func TestWithAttrs(t *testing.T) {
+	var buf = new(bytes.Buffer)
+	var log = &logger.Logger{Level: logger.DBG, OutputDebug: buf}
+
+	logger.WithAttrs(log, "request_id", "abc").Info("served")
+	if !strings.Contains(buf.String(), "request_id=abc") {
+		t.Fatalf("expected the attributes, got %q", buf.String())
+	}
+
+	var plain = plainLog{baseLog: log}
+	if got := logger.WithAttrs(plain, "request_id", "abc"); got != logger.Log(plain) {
+		t.Fatalf("expected a logger without attribute support to be returned as is, got %T", got)
+	}
+}
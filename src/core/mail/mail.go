@@ -1,10 +1,14 @@
 package mail
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/smtp"
 	"os"
 
+	"github.com/Nigel2392/go-django/src/core/metrics"
+	"github.com/Nigel2392/go-django/src/core/trace"
 	"github.com/jordan-wright/email"
 )
 
@@ -18,6 +22,12 @@ const (
 	DefaultBackend = "default"
 )
 
+var messagesTotal = metrics.Default.Counter(metrics.Opts{
+	Name:   "django_mail_messages_total",
+	Help:   "The number of emails sent with Send, by status.",
+	Labels: []string{"status"},
+})
+
 func init() {
 	Register(DefaultBackend, NewConsoleBackend(
 		os.Stdout,
@@ -30,6 +40,14 @@ type OpenableEmailBackend interface {
 	Close() error
 }
 
+// PingableEmailBackend is implemented by backends which can check whether their mail server is reachable,
+// without using or opening the connections the emails are sent with.
+//
+// It is used by the mail health probe of the application.
+type PingableEmailBackend interface {
+	Ping(ctx context.Context) error
+}
+
 type EmailBackend interface {
 	Send(e *email.Email) error
 }
@@ -82,20 +100,52 @@ func backendByNameOrInstance(backendOrName ...interface{}) EmailBackend {
 	return Default()
 }
 
+// Send sends the email with the backend passed by name or instance, or with the default backend.
+//
+// Send is not traced because it has no context, use [SendContext] with the context
+// of the request so that the "mail.send" span is a child of the request span.
 func Send(e *email.Email, backendOrName ...interface{}) error {
+	return send(nil, e, backendOrName...)
+}
+
+// SendContext sends the email like [Send], the "mail.send" span is a child of the span in the context.
+func SendContext(ctx context.Context, e *email.Email, backendOrName ...interface{}) (err error) {
+	var _, span = trace.Start(ctx, "mail.send")
+	if span != nil && e != nil {
+		span.SetAttributes("mail.recipients", len(e.To)+len(e.Cc)+len(e.Bcc))
+	}
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+
+	return send(span, e, backendOrName...)
+}
+
+func send(span *trace.Span, e *email.Email, backendOrName ...interface{}) error {
 	var backend = backendByNameOrInstance(
 		backendOrName...,
 	)
 	if backend == nil {
+		messagesTotal.Inc("failed")
 		return ErrBackendNotFound
 	}
+	span.SetAttributes("mail.backend", fmt.Sprintf("%T", backend))
+
 	if openable, ok := backend.(OpenableEmailBackend); ok && !openable.IsOpen() {
 		if err := openable.Open(); err != nil {
+			messagesTotal.Inc("failed")
 			return err
 		}
 	}
 
-	return backend.Send(e)
+	if err := backend.Send(e); err != nil {
+		messagesTotal.Inc("failed")
+		return err
+	}
+
+	messagesTotal.Inc("sent")
+	return nil
 }
 
 func Close[T string | interface{}](backendOrName T) error {
@@ -107,3 +157,23 @@ func Close[T string | interface{}](backendOrName T) error {
 	}
 	return nil
 }
+
+// CloseAll closes all registered backends which are open.
+//
+// It is called when the application's server shuts down.
+func CloseAll() error {
+	// the default backend is also registered under its own name,
+	// backends which are already closed are skipped.
+	var errs []error
+	for _, backend := range registry.backends {
+		var openable, ok = backend.(OpenableEmailBackend)
+		if !ok || !openable.IsOpen() {
+			continue
+		}
+
+		if err := openable.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -1,11 +1,18 @@
 package mail_test
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"net"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
 	mailpkg "github.com/Nigel2392/go-django/src/core/mail" // Import your mail package here
+	"github.com/Nigel2392/go-django/src/core/trace"
 	"github.com/jordan-wright/email"
 )
 
@@ -118,6 +125,126 @@ func TestCloseBackend(t *testing.T) {
 	}
 }
 
+// Test Close All Backends
+func TestCloseAllBackends(t *testing.T) {
+	var first, second = &MockOpenableBackend{}, &MockOpenableBackend{}
+	mailpkg.Register("firstBackend", first)
+	mailpkg.Register("secondBackend", second)
+	defer mailpkg.Unregister("firstBackend")
+	defer mailpkg.Unregister("secondBackend")
+
+	// Getting a backend opens it
+	mailpkg.Get("firstBackend")
+	mailpkg.Get("secondBackend")
+
+	if err := mailpkg.CloseAll(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if first.IsOpen() || second.IsOpen() {
+		t.Fatalf("expected all backends to be closed")
+	}
+}
+
+// Test SendContext traces the email as a child of the span in the context
+func TestSendContext_Span(t *testing.T) {
+	var exporter = trace.NewMemoryExporter(0)
+	trace.Default.AddExporter(exporter)
+	trace.Default.SetEnabled(true)
+	defer func() {
+		trace.Default.SetEnabled(false)
+		trace.Default.RemoveExporter(exporter)
+	}()
+
+	var e = &email.Email{
+		From:    "test@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Test Email",
+		Text:    []byte("This is a test email."),
+	}
+
+	var ctx, request = trace.Start(context.Background(), "GET /")
+	if err := mailpkg.SendContext(ctx, e, mailpkg.NewConsoleBackend(&bytes.Buffer{})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	request.End()
+
+	if err := mailpkg.Send(e, mailpkg.NewConsoleBackend(&bytes.Buffer{})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var spans = exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected only the request and the mail span, got %d spans", len(spans))
+	}
+
+	if spans[0].Name != "mail.send" || spans[0].ParentID != request.SpanContext().SpanID {
+		t.Errorf("expected the mail span to be a child of the request span, got %+v", spans[0])
+	}
+}
+
+// Test Pooled Backend Ping
+func TestPooledBackend_Ping(t *testing.T) {
+	var listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	var commands = make(chan []string, 1)
+	go func() {
+		var conn, err = listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var received []string
+		var reader = bufio.NewReader(conn)
+		conn.Write([]byte("220 localhost ESMTP\r\n"))
+		for {
+			var line, err = reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			var cmd = strings.Fields(line)[0]
+			received = append(received, cmd)
+			if cmd == "QUIT" {
+				conn.Write([]byte("221 Bye\r\n"))
+				break
+			}
+			conn.Write([]byte("250 localhost\r\n"))
+		}
+		commands <- received
+	}()
+
+	var host, port, _ = net.SplitHostPort(listener.Addr().String())
+	var portNum, _ = strconv.Atoi(port)
+	backend, err := mailpkg.NewPooledEmailBackend(1, &mailpkg.Config{
+		Host:     host,
+		Port:     portNum,
+		Username: "user",
+		Password: "password",
+		MailFrom: "test@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := backend.(mailpkg.PingableEmailBackend).Ping(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if cmds := <-commands; !slices.Equal(cmds, []string{"EHLO", "QUIT"}) {
+		t.Fatalf("expected the ping to only greet the server and quit, got %v", cmds)
+	}
+
+	listener.Close()
+	if err := backend.(mailpkg.PingableEmailBackend).Ping(context.Background()); err == nil {
+		t.Fatal("expected an error when the server is unreachable")
+	}
+}
+
 // Mock implementation of OpenableEmailBackend for testing
 type MockOpenableBackend struct {
 	isOpen bool
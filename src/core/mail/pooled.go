@@ -1,9 +1,12 @@
 package mail
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/smtp"
+	"strconv"
 	"time"
 
 	"github.com/Nigel2392/go-django/src/core/assert"
@@ -70,6 +73,36 @@ func (m *pooledManager) Open() error {
 	return nil
 }
 
+// Ping connects to the mail server on a new connection, waits for its greeting and quits.
+//
+// The connections of the pool are not used, so a ping never delays or breaks the sending of emails.
+func (m *pooledManager) Ping(ctx context.Context) error {
+	var dialer = &net.Dialer{Timeout: m.cnf.Timeout}
+	var conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.cnf.Host, strconv.Itoa(m.cnf.Port)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if m.cnf.UseSSL {
+		var tlsConfig = m.cnf.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: m.cnf.Host}
+		}
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, m.cnf.Host)
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
 func (m *pooledManager) Send(e *email.Email) error {
 	return m.pool.Send(e, m.cnf.Timeout)
 }
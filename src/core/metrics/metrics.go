@@ -0,0 +1,422 @@
+/*
+Package metrics provides counters, gauges and histograms which are exposed in the OpenMetrics text format.
+
+Metrics are registered in a [Registry], the [Default] registry is exposed by go-django's metrics endpoint.
+
+A metric can have labels, the values of the labels are passed in the order
+of [Opts.Labels] when the metric is updated:
+
+	var views = metrics.Default.Counter(metrics.Opts{
+		Name:   "blog_posts_viewed_total",
+		Help:   "The number of times a blog post was viewed.",
+		Labels: []string{"category"},
+	})
+
+	views.Inc("news")
+*/
+package metrics
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Nigel2392/go-django/src/core/assert"
+)
+
+type Type string
+
+const (
+	TypeCounter   Type = "counter"
+	TypeGauge     Type = "gauge"
+	TypeHistogram Type = "histogram"
+)
+
+// DefaultBuckets are the upper bounds of the buckets of a histogram without buckets, suited to measure durations in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Opts are the options used to create a metric.
+type Opts struct {
+	// The name of the metric, i.e. "django_http_requests_total".
+	//
+	// The "_total" suffix of a counter is added automatically when the metric is exposed.
+	Name string
+
+	// A description of the metric.
+	Help string
+
+	// The names of the labels of the metric.
+	Labels []string
+
+	// The upper bounds of the buckets of a histogram, defaults to [DefaultBuckets].
+	Buckets []float64
+}
+
+// Desc describes a metric.
+type Desc struct {
+	Name   string
+	Help   string
+	Type   Type
+	Labels []string
+}
+
+// Sample is a single value of a metric.
+type Sample struct {
+	// The suffix added to the name of the metric, i.e. "_total" or "_bucket".
+	Suffix string
+
+	// The names and values of the labels of the sample.
+	Labels []Label
+
+	Value float64
+}
+
+// Label is the name and the value of a label.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Metric is a metric which can be registered in a [Registry].
+type Metric interface {
+	// Desc returns the description of the metric.
+	Desc() Desc
+
+	// Samples returns the current values of the metric.
+	Samples() []Sample
+}
+
+// atomicFloat is a float64 which can be updated atomically.
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) Add(v float64) {
+	for {
+		var old = f.bits.Load()
+		var next = math.Float64bits(math.Float64frombits(old) + v)
+		if f.bits.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) Set(v float64) {
+	f.bits.Store(math.Float64bits(v))
+}
+
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+// series holds the values of a metric for each combination of label values.
+type series[T any] struct {
+	name   string
+	labels []string
+	create func() *T
+	mu     sync.RWMutex
+	values map[string]*labeledValue[T]
+}
+
+type labeledValue[T any] struct {
+	labels []string
+	value  *T
+}
+
+func newSeries[T any](name string, labels []string, create func() *T) *series[T] {
+	var s = &series[T]{
+		name:   name,
+		labels: slices.Clone(labels),
+		create: create,
+		values: make(map[string]*labeledValue[T]),
+	}
+
+	// metrics without labels are exposed with their zero value
+	if len(labels) == 0 {
+		s.get(nil)
+	}
+	return s
+}
+
+func (s *series[T]) get(labelValues []string) *T {
+	assert.True(
+		len(labelValues) == len(s.labels),
+		"metrics: %s expects %d label values, got %d",
+		s.name, len(s.labels), len(labelValues),
+	)
+
+	var key = strings.Join(labelValues, "\xff")
+	s.mu.RLock()
+	var v, ok = s.values[key]
+	s.mu.RUnlock()
+	if ok {
+		return v.value
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok = s.values[key]; !ok {
+		v = &labeledValue[T]{labels: slices.Clone(labelValues), value: s.create()}
+		s.values[key] = v
+	}
+	return v.value
+}
+
+// each calls fn for each combination of label values, sorted by the label values.
+func (s *series[T]) each(fn func(labels []Label, value *T)) {
+	s.mu.RLock()
+	var keys = make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var values = make([]*labeledValue[T], len(keys))
+	for i, key := range keys {
+		values[i] = s.values[key]
+	}
+	s.mu.RUnlock()
+
+	for _, v := range values {
+		var labels = make([]Label, len(s.labels))
+		for i, name := range s.labels {
+			labels[i] = Label{Name: name, Value: v.labels[i]}
+		}
+		fn(labels, v.value)
+	}
+}
+
+// Counter is a metric which only goes up, i.e. the number of requests served.
+type Counter struct {
+	desc     Desc
+	series   *series[atomicFloat]
+	registry *Registry
+}
+
+// NewCounter creates a counter which is not registered in any registry.
+func NewCounter(opts Opts) *Counter {
+	var name = strings.TrimSuffix(opts.Name, "_total")
+	return &Counter{
+		desc:   Desc{Name: name, Help: opts.Help, Type: TypeCounter, Labels: opts.Labels},
+		series: newSeries(name, opts.Labels, func() *atomicFloat { return new(atomicFloat) }),
+	}
+}
+
+// Inc increments the counter for the label values by 1.
+func (c *Counter) Inc(labelValues ...string) {
+	if c.registry.ignores() {
+		return
+	}
+	c.series.get(labelValues).Add(1)
+}
+
+// Add adds the value to the counter for the label values, the value must not be negative.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	assert.False(v < 0, "metrics: counter %s cannot decrease", c.desc.Name)
+	if c.registry.ignores() {
+		return
+	}
+	c.series.get(labelValues).Add(v)
+}
+
+// Value returns the value of the counter for the label values.
+func (c *Counter) Value(labelValues ...string) float64 {
+	return c.series.get(labelValues).Load()
+}
+
+func (c *Counter) Desc() Desc {
+	return c.desc
+}
+
+func (c *Counter) Samples() []Sample {
+	var samples = make([]Sample, 0)
+	c.series.each(func(labels []Label, value *atomicFloat) {
+		samples = append(samples, Sample{Suffix: "_total", Labels: labels, Value: value.Load()})
+	})
+	return samples
+}
+
+// Gauge is a metric which can go up and down, i.e. the number of open connections.
+type Gauge struct {
+	desc     Desc
+	series   *series[atomicFloat]
+	registry *Registry
+}
+
+// NewGauge creates a gauge which is not registered in any registry.
+func NewGauge(opts Opts) *Gauge {
+	return &Gauge{
+		desc:   Desc{Name: opts.Name, Help: opts.Help, Type: TypeGauge, Labels: opts.Labels},
+		series: newSeries(opts.Name, opts.Labels, func() *atomicFloat { return new(atomicFloat) }),
+	}
+}
+
+// Set sets the gauge for the label values to the value.
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	if g.registry.ignores() {
+		return
+	}
+	g.series.get(labelValues).Set(v)
+}
+
+// Add adds the value to the gauge for the label values, the value can be negative.
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	if g.registry.ignores() {
+		return
+	}
+	g.series.get(labelValues).Add(v)
+}
+
+// Inc increments the gauge for the label values by 1.
+func (g *Gauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec decrements the gauge for the label values by 1.
+func (g *Gauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// Value returns the value of the gauge for the label values.
+func (g *Gauge) Value(labelValues ...string) float64 {
+	return g.series.get(labelValues).Load()
+}
+
+func (g *Gauge) Desc() Desc {
+	return g.desc
+}
+
+func (g *Gauge) Samples() []Sample {
+	var samples = make([]Sample, 0)
+	g.series.each(func(labels []Label, value *atomicFloat) {
+		samples = append(samples, Sample{Labels: labels, Value: value.Load()})
+	})
+	return samples
+}
+
+// GaugeFunc is a gauge without labels whose value is computed when the metrics are collected.
+type GaugeFunc struct {
+	desc Desc
+	fn   func() float64
+}
+
+// NewGaugeFunc creates a gauge function which is not registered in any registry.
+func NewGaugeFunc(opts Opts, fn func() float64) *GaugeFunc {
+	return &GaugeFunc{
+		desc: Desc{Name: opts.Name, Help: opts.Help, Type: TypeGauge},
+		fn:   fn,
+	}
+}
+
+func (g *GaugeFunc) Desc() Desc {
+	return g.desc
+}
+
+func (g *GaugeFunc) Samples() []Sample {
+	return []Sample{{Value: g.fn()}}
+}
+
+// Histogram counts observations in buckets, i.e. the duration of requests.
+type Histogram struct {
+	desc     Desc
+	buckets  []float64
+	series   *series[histogramValue]
+	registry *Registry
+}
+
+type histogramValue struct {
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram creates a histogram which is not registered in any registry.
+func NewHistogram(opts Opts) *Histogram {
+	var buckets = slices.Clone(opts.Buckets)
+	if len(buckets) == 0 {
+		buckets = slices.Clone(DefaultBuckets)
+	}
+	slices.Sort(buckets)
+	buckets = slices.Compact(buckets)
+
+	return &Histogram{
+		desc:    Desc{Name: opts.Name, Help: opts.Help, Type: TypeHistogram, Labels: opts.Labels},
+		buckets: buckets,
+		series: newSeries(opts.Name, opts.Labels, func() *histogramValue {
+			return &histogramValue{counts: make([]uint64, len(buckets))}
+		}),
+	}
+}
+
+// Observe adds an observation of the value to the histogram for the label values.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	if h.registry.ignores() {
+		return
+	}
+
+	var hv = h.series.get(labelValues)
+	var idx, _ = slices.BinarySearch(h.buckets, v)
+
+	hv.mu.Lock()
+	defer hv.mu.Unlock()
+	if idx < len(hv.counts) {
+		hv.counts[idx]++
+	}
+	hv.count++
+	hv.sum += v
+}
+
+// ObserveSince observes the number of seconds passed since the start time.
+func (h *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	if h.registry.ignores() {
+		return
+	}
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// Count returns the number of observations of the histogram for the label values.
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	var hv = h.series.get(labelValues)
+	hv.mu.Lock()
+	defer hv.mu.Unlock()
+	return hv.count
+}
+
+func (h *Histogram) Desc() Desc {
+	return h.desc
+}
+
+func (h *Histogram) Samples() []Sample {
+	var samples = make([]Sample, 0)
+	h.series.each(func(labels []Label, value *histogramValue) {
+		value.mu.Lock()
+		var (
+			counts = slices.Clone(value.counts)
+			count  = value.count
+			sum    = value.sum
+		)
+		value.mu.Unlock()
+
+		// buckets are cumulative
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += counts[i]
+			samples = append(samples, Sample{
+				Suffix: "_bucket",
+				Labels: append(slices.Clone(labels), Label{Name: "le", Value: formatFloat(upper)}),
+				Value:  float64(cumulative),
+			})
+		}
+
+		samples = append(samples,
+			Sample{Suffix: "_bucket", Labels: append(slices.Clone(labels), Label{Name: "le", Value: "+Inf"}), Value: float64(count)},
+			Sample{Suffix: "_count", Labels: labels, Value: float64(count)},
+			Sample{Suffix: "_sum", Labels: labels, Value: sum},
+		)
+	})
+	return samples
+}
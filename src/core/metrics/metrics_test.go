@@ -0,0 +1,135 @@
+package metrics_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Nigel2392/go-django/src/core/metrics"
+)
+
+func TestRegistryWriteTo(t *testing.T) {
+	var registry = metrics.NewRegistry()
+	var requests = registry.Counter(metrics.Opts{
+		Name:   "http_requests_total",
+		Help:   "Requests served.",
+		Labels: []string{"method", "path"},
+	})
+	var inFlight = registry.Gauge(metrics.Opts{
+		Name: "http_in_flight",
+	})
+	var duration = registry.Histogram(metrics.Opts{
+		Name:    "http_duration_seconds",
+		Help:    "Request duration.\nIn seconds.",
+		Buckets: []float64{1, 0.1, 0.1},
+	})
+
+	requests.Inc("GET", "/")
+	requests.Add(2, "POST", `/a"b`)
+	inFlight.Set(3)
+	inFlight.Dec()
+	duration.Observe(0.05)
+	duration.Observe(0.5)
+	duration.Observe(5)
+
+	var b strings.Builder
+	if _, err := registry.WriteTo(&b); err != nil {
+		t.Fatalf("failed to write metrics: %v", err)
+	}
+
+	var expected = strings.Join([]string{
+		"# TYPE http_duration_seconds histogram",
+		`# HELP http_duration_seconds Request duration.\nIn seconds.`,
+		`http_duration_seconds_bucket{le="0.1"} 1`,
+		`http_duration_seconds_bucket{le="1"} 2`,
+		`http_duration_seconds_bucket{le="+Inf"} 3`,
+		`http_duration_seconds_count 3`,
+		`http_duration_seconds_sum 5.55`,
+		"# TYPE http_in_flight gauge",
+		"http_in_flight 2",
+		"# TYPE http_requests counter",
+		"# HELP http_requests Requests served.",
+		`http_requests_total{method="GET",path="/"} 1`,
+		`http_requests_total{method="POST",path="/a\"b"} 2`,
+		"# EOF",
+		"",
+	}, "\n")
+
+	if b.String() != expected {
+		t.Fatalf("unexpected output:\n%s\nexpected:\n%s", b.String(), expected)
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	var registry = metrics.NewRegistry()
+	var counter = registry.Counter(metrics.Opts{Name: "jobs_total"})
+	if registry.Counter(metrics.Opts{Name: "jobs"}) != counter {
+		t.Fatalf("expected the registered counter to be returned")
+	}
+
+	if err := registry.Register(metrics.NewGauge(metrics.Opts{Name: "jobs"})); !errors.Is(err, metrics.ErrDuplicateMetric) {
+		t.Fatalf("expected %v, got %v", metrics.ErrDuplicateMetric, err)
+	}
+
+	if err := registry.Register(metrics.NewGauge(metrics.Opts{Name: "invalid-name"})); !errors.Is(err, metrics.ErrInvalidName) {
+		t.Fatalf("expected %v, got %v", metrics.ErrInvalidName, err)
+	}
+
+	if err := registry.Register(metrics.NewGauge(metrics.Opts{Name: "queue", Labels: []string{"__name"}})); !errors.Is(err, metrics.ErrInvalidName) {
+		t.Fatalf("expected %v, got %v", metrics.ErrInvalidName, err)
+	}
+
+	if !registry.Unregister("jobs") {
+		t.Fatalf("expected the counter to be unregistered")
+	}
+}
+
+func TestRegistryServeHTTP(t *testing.T) {
+	var registry = metrics.NewRegistry()
+	registry.GaugeFunc(metrics.Opts{Name: "answer"}, func() float64 { return 42 })
+
+	var rec = httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != metrics.ContentType {
+		t.Fatalf("expected content type %q, got %q", metrics.ContentType, ct)
+	}
+
+	if body := rec.Body.String(); !strings.Contains(body, "answer 42\n") || !strings.HasSuffix(body, "# EOF\n") {
+		t.Fatalf("unexpected body:\n%s", body)
+	}
+}
+
+func TestRegistrySetEnabled(t *testing.T) {
+	var registry = metrics.NewRegistry()
+	var requests = registry.Counter(metrics.Opts{Name: "requests_total"})
+	var duration = registry.Histogram(metrics.Opts{Name: "duration_seconds"})
+	var standalone = metrics.NewCounter(metrics.Opts{Name: "standalone_total"})
+
+	registry.SetEnabled(false)
+	requests.Inc()
+	duration.Observe(1)
+	standalone.Inc()
+
+	if v := requests.Value(); v != 0 {
+		t.Errorf("expected a disabled registry to ignore updates, got %v", v)
+	}
+	if c := duration.Count(); c != 0 {
+		t.Errorf("expected a disabled registry to ignore observations, got %d", c)
+	}
+	if v := standalone.Value(); v != 1 {
+		t.Errorf("expected a metric without a registry to be updated, got %v", v)
+	}
+
+	registry.SetEnabled(true)
+	requests.Inc()
+	if v := requests.Value(); v != 1 {
+		t.Errorf("expected an enabled registry to record updates, got %v", v)
+	}
+
+	if metrics.Default.Enabled() {
+		t.Error("expected the default registry to be disabled until the application enables it")
+	}
+}
@@ -0,0 +1,285 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Nigel2392/go-django/src/core/assert"
+	"github.com/Nigel2392/go-django/src/core/errs"
+)
+
+// ContentType is the content type of the OpenMetrics text format.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+const (
+	ErrDuplicateMetric errs.Error = "metric is already registered"
+	ErrInvalidName     errs.Error = "invalid metric or label name"
+)
+
+var nameRegex = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// Default is the registry exposed by go-django's metrics endpoint,
+// the built-in metrics are registered in it.
+//
+// It is disabled until the application enables it with the METRICS setting,
+// so the built-in instrumentation costs nothing if the metrics are not exposed.
+var Default = newDisabledRegistry()
+
+// Registry holds a set of metrics by their name.
+type Registry struct {
+	mu       sync.RWMutex
+	metrics  map[string]Metric
+	disabled atomic.Bool
+}
+
+func newDisabledRegistry() *Registry {
+	var r = NewRegistry()
+	r.disabled.Store(true)
+	return r
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		metrics: make(map[string]Metric),
+	}
+}
+
+// SetEnabled enables or disables the registry, a new registry is enabled.
+//
+// The counters, gauges and histograms created with the methods of a disabled registry
+// ignore updates, metrics registered with [Registry.Register] are not affected.
+func (r *Registry) SetEnabled(enabled bool) {
+	r.disabled.Store(!enabled)
+}
+
+// Enabled reports whether the metrics created with the registry are updated.
+func (r *Registry) Enabled() bool {
+	return !r.disabled.Load()
+}
+
+// ignores reports whether updates of the metrics created with the registry are ignored,
+// metrics which were not created with a registry are always updated.
+func (r *Registry) ignores() bool {
+	return r != nil && r.disabled.Load()
+}
+
+// Register registers the metric.
+//
+// An error is returned if the name of the metric or its labels are invalid,
+// or if a metric with the same name is already registered.
+func (r *Registry) Register(m Metric) error {
+	var desc = m.Desc()
+	if !nameRegex.MatchString(desc.Name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, desc.Name)
+	}
+
+	for _, label := range desc.Labels {
+		if !nameRegex.MatchString(label) || strings.HasPrefix(label, "__") {
+			return fmt.Errorf("%w: label %q of %q", ErrInvalidName, label, desc.Name)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.metrics[desc.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateMetric, desc.Name)
+	}
+
+	r.metrics[desc.Name] = m
+	return nil
+}
+
+// MustRegister registers the metrics and panics if any of them cannot be registered.
+func (r *Registry) MustRegister(metrics ...Metric) {
+	for _, m := range metrics {
+		if err := r.Register(m); err != nil {
+			assert.Fail("metrics: %v", err)
+		}
+	}
+}
+
+// Unregister removes the metric with the name from the registry.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var _, ok = r.metrics[name]
+	delete(r.metrics, name)
+	return ok
+}
+
+// Get returns the metric registered with the name.
+func (r *Registry) Get(name string) (Metric, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var m, ok = r.metrics[name]
+	return m, ok
+}
+
+// getOrRegister returns the metric registered with the name of the created metric,
+// or registers the created metric if no metric with the name exists.
+func getOrRegister[T Metric](r *Registry, name string, create func() T) T {
+	r.mu.RLock()
+	var existing, ok = r.metrics[name]
+	r.mu.RUnlock()
+	if ok {
+		var m, isType = existing.(T)
+		assert.True(isType, "metrics: %q is already registered as %s", name, existing.Desc().Type)
+		return m
+	}
+
+	var m = create()
+	if err := r.Register(m); err != nil {
+		// registered concurrently
+		if existing, ok := r.Get(m.Desc().Name); ok {
+			if m, isType := existing.(T); isType {
+				return m
+			}
+		}
+		assert.Fail("metrics: %v", err)
+	}
+	return m
+}
+
+// Counter returns the counter registered with the name of the options,
+// the counter is created and registered if it does not exist yet.
+func (r *Registry) Counter(opts Opts) *Counter {
+	return getOrRegister(r, strings.TrimSuffix(opts.Name, "_total"), func() *Counter {
+		var c = NewCounter(opts)
+		c.registry = r
+		return c
+	})
+}
+
+// Gauge returns the gauge registered with the name of the options,
+// the gauge is created and registered if it does not exist yet.
+func (r *Registry) Gauge(opts Opts) *Gauge {
+	return getOrRegister(r, opts.Name, func() *Gauge {
+		var g = NewGauge(opts)
+		g.registry = r
+		return g
+	})
+}
+
+// Histogram returns the histogram registered with the name of the options,
+// the histogram is created and registered if it does not exist yet.
+func (r *Registry) Histogram(opts Opts) *Histogram {
+	return getOrRegister(r, opts.Name, func() *Histogram {
+		var h = NewHistogram(opts)
+		h.registry = r
+		return h
+	})
+}
+
+// GaugeFunc returns the gauge function registered with the name of the options,
+// the gauge function is created and registered if it does not exist yet.
+func (r *Registry) GaugeFunc(opts Opts, fn func() float64) *GaugeFunc {
+	return getOrRegister(r, opts.Name, func() *GaugeFunc {
+		return NewGaugeFunc(opts, fn)
+	})
+}
+
+// WriteTo writes the metrics sorted by name in the OpenMetrics text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	var metrics = make([]Metric, 0, len(r.metrics))
+	for _, m := range r.metrics {
+		metrics = append(metrics, m)
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(metrics, func(a, b Metric) int {
+		return strings.Compare(a.Desc().Name, b.Desc().Name)
+	})
+
+	var cw = &countingWriter{w: w}
+	var buf = bufio.NewWriter(cw)
+	for _, m := range metrics {
+		writeMetric(buf, m)
+	}
+	buf.WriteString("# EOF\n")
+
+	var err = buf.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP serves the metrics in the OpenMetrics text format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	r.WriteTo(w)
+}
+
+func writeMetric(w *bufio.Writer, m Metric) {
+	var desc = m.Desc()
+	fmt.Fprintf(w, "# TYPE %s %s\n", desc.Name, desc.Type)
+	if desc.Help != "" {
+		fmt.Fprintf(w, "# HELP %s %s\n", desc.Name, escapeHelp(desc.Help))
+	}
+
+	for _, sample := range m.Samples() {
+		w.WriteString(desc.Name)
+		w.WriteString(sample.Suffix)
+		if len(sample.Labels) > 0 {
+			w.WriteByte('{')
+			for i, label := range sample.Labels {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				w.WriteString(label.Name)
+				w.WriteString(`="`)
+				w.WriteString(escapeLabelValue(label.Value))
+				w.WriteByte('"')
+			}
+			w.WriteByte('}')
+		}
+		w.WriteByte(' ')
+		w.WriteString(formatFloat(sample.Value))
+		w.WriteByte('\n')
+	}
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelReplacer.Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	var n, err = c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// bucketState is the state of a key limited with the [TokenBucket] algorithm.
+type bucketState struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// windowState is the state of a key limited with the [SlidingWindow] algorithm.
+type windowState struct {
+	Start    time.Time
+	Current  int
+	Previous int
+}
+
+// encodeState encodes the state of a key as JSON before it is stored,
+// backends which serialize their values do not return the stored Go type.
+func encodeState(state any) (string, error) {
+	var data, err = json.Marshal(state)
+	return string(data), err
+}
+
+// decodeState decodes a state stored with [encodeState], ok is false if no state was stored.
+func decodeState[T any](value any) (state T, ok bool, err error) {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return state, false, nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return state, false, fmt.Errorf("unexpected state of type %T", value)
+	}
+
+	if err = json.Unmarshal(data, &state); err != nil {
+		return state, false, fmt.Errorf("invalid state: %w", err)
+	}
+	return state, true, nil
+}
+
+func (l *Limiter) tokenBucket(state bucketState, now time.Time) (Result, bucketState) {
+	var capacity = float64(l.limit.Burst)
+
+	state.Tokens = min(capacity, state.Tokens+now.Sub(state.Updated).Seconds()*l.rate)
+	state.Updated = now
+
+	var result = Result{Limit: l.limit.Burst}
+	if state.Tokens >= 1 {
+		state.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.refill(1 - state.Tokens)
+	}
+
+	result.Remaining = int(math.Floor(state.Tokens))
+	result.Reset = l.refill(capacity - state.Tokens)
+	return result, state
+}
+
+// refill returns the time it takes to refill the bucket with the tokens.
+func (l *Limiter) refill(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+func (l *Limiter) slidingWindow(state windowState, now time.Time) (Result, windowState) {
+	var (
+		period = l.limit.Period
+		start  = now.Truncate(period)
+		rate   = l.limit.Rate
+	)
+
+	if !state.Start.Equal(start) {
+		if state.Start.Equal(start.Add(-period)) {
+			state.Previous = state.Current
+		} else {
+			state.Previous = 0
+		}
+		state.Current = 0
+		state.Start = start
+	}
+
+	var (
+		elapsed = now.Sub(start)
+		end     = period - elapsed
+		weight  = 1 - elapsed.Seconds()/period.Seconds()
+		count   = float64(state.Previous)*weight + float64(state.Current)
+		result  = Result{Limit: rate}
+	)
+
+	switch {
+	case count+1 <= float64(rate):
+		state.Current++
+		count++
+		result.Allowed = true
+	case state.Current+1 <= rate:
+		// wait until enough requests of the previous period have left the window
+		var overlap = 1 - float64(rate-1-state.Current)/float64(state.Previous)
+		result.RetryAfter = time.Duration(overlap*float64(period)) - elapsed
+	default:
+		// wait until enough requests of the current period have left the window of the next period
+		var overlap = 1 - float64(rate-1)/float64(state.Current)
+		result.RetryAfter = end + time.Duration(overlap*float64(period))
+	}
+
+	result.Remaining = max(int(math.Floor(float64(rate)-count)), 0)
+	result.Reset = end
+	if state.Current > 0 {
+		result.Reset += period
+	}
+
+	return result, state
+}
@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+
+	django "github.com/Nigel2392/go-django/src"
+	"github.com/Nigel2392/go-django/src/core/attrs"
+	"github.com/Nigel2392/mux/middleware/authentication"
+)
+
+// KeyFunc returns the key requests are counted by, requests with an empty key are not limited.
+type KeyFunc func(r *http.Request) string
+
+// ByIP counts requests by the IP address of the client.
+//
+// The address is read from the proxy headers if [django.APPVAR_REQUESTS_PROXIED] is set, see [django.GetIP].
+func ByIP(r *http.Request) string {
+	return fmt.Sprintf("ip:%s", django.GetIP(r))
+}
+
+// ByUser counts requests by the primary key of the authenticated user,
+// requests from anonymous users and users which are not an [attrs.Definer] are not limited.
+func ByUser(r *http.Request) string {
+	var user = authentication.Retrieve(r)
+	if user == nil || !user.IsAuthenticated() {
+		return ""
+	}
+
+	var definer, ok = user.(attrs.Definer)
+	if !ok {
+		return ""
+	}
+
+	var pk = attrs.PrimaryKey(r.Context(), definer)
+	if pk == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("user:%v", pk)
+}
+
+// ByUserOrIP counts requests by the authenticated user, or by the IP address for anonymous users.
+func ByUserOrIP(r *http.Request) string {
+	if key := ByUser(r); key != "" {
+		return key
+	}
+	return ByIP(r)
+}
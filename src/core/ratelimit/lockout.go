@@ -0,0 +1,259 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Nigel2392/cache"
+	core "github.com/Nigel2392/go-django/src/core"
+	"github.com/Nigel2392/go-django/src/core/assert"
+	"github.com/Nigel2392/go-django/src/core/logger"
+)
+
+const (
+	// The default of [Lockout.MaxFailures].
+	DefaultLockoutFailures = 5
+
+	// The default of [Lockout.Window].
+	DefaultLockoutWindow = 15 * time.Minute
+
+	// The default of [Lockout.Duration].
+	DefaultLockoutDuration = 15 * time.Minute
+)
+
+// Lockout configures a [LockoutPolicy].
+type Lockout struct {
+	// The name of the lockout, it prefixes the cache keys of the lockout and must be unique.
+	Name string
+
+	// The number of failures after which the client is locked out, defaults to [DefaultLockoutFailures].
+	MaxFailures int
+
+	// The period in which the failures are counted, defaults to [DefaultLockoutWindow].
+	Window time.Duration
+
+	// The time the client is locked out for, defaults to [DefaultLockoutDuration].
+	Duration time.Duration
+
+	// The key the failures are counted by, defaults to [ByIP].
+	Key KeyFunc
+
+	// The methods which are rejected while locked out, all methods are rejected if empty.
+	Methods []string
+
+	// The name of the cache backend the failures are stored in, defaults to the default backend.
+	Backend string
+
+	// The handler which responds to requests while locked out, defaults to a plain 429 Too Many Requests response.
+	//
+	// The Retry-After header is set before the handler is called.
+	OnLocked http.Handler
+}
+
+// LockoutPolicy counts failures per key and locks the key out after too many failures.
+//
+// Failures are recorded with [LockoutPolicy.Fail], i.e. when a login attempt fails,
+// each lockout is sent with [core.SIGNAL_LOCKED_OUT] and recorded in the audit logs.
+type LockoutPolicy struct {
+	lockout Lockout
+	backend []string
+	locks   keyLocks
+}
+
+type lockoutState struct {
+	Failures int
+	First    time.Time
+	Until    time.Time
+}
+
+// NewLockout creates a lockout policy, it panics if the lockout is invalid.
+func NewLockout(lockout Lockout) *LockoutPolicy {
+	assert.False(lockout.Name == "", "lockout must have a name")
+
+	if lockout.MaxFailures <= 0 {
+		lockout.MaxFailures = DefaultLockoutFailures
+	}
+
+	if lockout.Window <= 0 {
+		lockout.Window = DefaultLockoutWindow
+	}
+
+	if lockout.Duration <= 0 {
+		lockout.Duration = DefaultLockoutDuration
+	}
+
+	if lockout.Key == nil {
+		lockout.Key = ByIP
+	}
+
+	var p = &LockoutPolicy{lockout: lockout}
+	if lockout.Backend != "" {
+		p.backend = []string{lockout.Backend}
+	}
+
+	return p
+}
+
+// Lockout returns the configuration of the policy.
+func (p *LockoutPolicy) Lockout() Lockout {
+	return p.lockout
+}
+
+// Locked reports whether the client of the request is locked out and until when.
+//
+// An error is returned if the stored failures of the client cannot be decoded.
+func (p *LockoutPolicy) Locked(r *http.Request) (until time.Time, locked bool, err error) {
+	var key = p.lockout.Key(r)
+	if key == "" {
+		return time.Time{}, false, nil
+	}
+
+	value, err := cache.GetCache(p.backend...).Get(r.Context(), p.cacheKey(key))
+	if err != nil {
+		return time.Time{}, false, nil
+	}
+
+	state, _, err := decodeState[lockoutState](value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("ratelimit: read failures of %q: %w", p.lockout.Name, err)
+	}
+
+	return state.Until, time.Now().Before(state.Until), nil
+}
+
+// Fail records a failure for the client of the request and locks it out after too many failures.
+func (p *LockoutPolicy) Fail(r *http.Request) error {
+	var key = p.lockout.Key(r)
+	if key == "" {
+		return nil
+	}
+
+	var mu = p.locks.get(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	var (
+		ctx      = r.Context()
+		now      = time.Now()
+		backend  = cache.GetCache(p.backend...)
+		cacheKey = p.cacheKey(key)
+		value, _ = backend.Get(ctx, cacheKey)
+	)
+
+	var state, _, err = decodeState[lockoutState](value)
+	if err != nil {
+		return fmt.Errorf("ratelimit: read failures of %q: %w", p.lockout.Name, err)
+	}
+
+	if now.Before(state.Until) {
+		return nil
+	}
+
+	if state.Failures == 0 || now.Sub(state.First) > p.lockout.Window || !state.Until.IsZero() {
+		state = lockoutState{First: now}
+	}
+
+	state.Failures++
+
+	var ttl = p.lockout.Window - now.Sub(state.First)
+	if state.Failures >= p.lockout.MaxFailures {
+		state.Until = now.Add(p.lockout.Duration)
+		ttl = p.lockout.Duration
+	}
+
+	encoded, err := encodeState(state)
+	if err != nil {
+		return fmt.Errorf("ratelimit: encode failures of %q: %w", p.lockout.Name, err)
+	}
+
+	if err := backend.Set(ctx, cacheKey, encoded, ttl); err != nil {
+		return fmt.Errorf("ratelimit: store failures of %q: %w", p.lockout.Name, err)
+	}
+
+	if state.Until.IsZero() {
+		return nil
+	}
+
+	logger.Warnf(
+		"Locked out %s for %s after %d failures of %q",
+		key, p.lockout.Duration, state.Failures, p.lockout.Name,
+	)
+
+	return core.SIGNAL_LOCKED_OUT.Send(ctx, &core.Lockout{
+		R:        r,
+		Name:     p.lockout.Name,
+		Key:      key,
+		Failures: state.Failures,
+		Until:    state.Until,
+	})
+}
+
+// Reset removes the failures of the client of the request, i.e. after a successful login.
+//
+// A client which is locked out stays locked out until the lockout ends.
+func (p *LockoutPolicy) Reset(r *http.Request) error {
+	var key = p.lockout.Key(r)
+	if key == "" {
+		return nil
+	}
+
+	var mu = p.locks.get(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	var (
+		backend  = cache.GetCache(p.backend...)
+		cacheKey = p.cacheKey(key)
+		value, _ = backend.Get(r.Context(), cacheKey)
+	)
+
+	var state, _, err = decodeState[lockoutState](value)
+	if err != nil {
+		return fmt.Errorf("ratelimit: read failures of %q: %w", p.lockout.Name, err)
+	}
+
+	if time.Now().Before(state.Until) {
+		return nil
+	}
+
+	return backend.Delete(r.Context(), cacheKey)
+}
+
+// Unlock removes the failures and the lockout of a key returned by [Lockout.Key], i.e. from a management command.
+func (p *LockoutPolicy) Unlock(ctx context.Context, key string) error {
+	var mu = p.locks.get(key)
+	mu.Lock()
+	defer mu.Unlock()
+	return cache.GetCache(p.backend...).Delete(ctx, p.cacheKey(key))
+}
+
+// Handler rejects the requests of locked out clients and passes all other requests to the next handler.
+func (p *LockoutPolicy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(p.lockout.Methods) > 0 && !slices.Contains(p.lockout.Methods, r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var until, locked, err = p.Locked(r)
+		if err != nil {
+			logger.Warnf("Failed to apply the lockout %q: %v", p.lockout.Name, err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if locked {
+			tooManyRequests(w, r, time.Until(until), p.lockout.OnLocked)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (p *LockoutPolicy) cacheKey(key string) string {
+	return fmt.Sprintf("ratelimit.lockout.%s.%s", p.lockout.Name, key)
+}
@@ -0,0 +1,212 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	django "github.com/Nigel2392/go-django/src"
+	core "github.com/Nigel2392/go-django/src/core"
+	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-signals"
+)
+
+const (
+	// The name of the cache backend the login rate limit and lockout are stored in, empty for the default backend.
+	APPVAR_RATE_LIMIT_CACHE = "RATE_LIMIT_CACHE" // string
+
+	// The number of login attempts allowed per IP address per [APPVAR_LOGIN_RATE_LIMIT_PERIOD], 0 disables the limit.
+	APPVAR_LOGIN_RATE_LIMIT = "LOGIN_RATE_LIMIT" // int
+
+	// The period of the [APPVAR_LOGIN_RATE_LIMIT].
+	APPVAR_LOGIN_RATE_LIMIT_PERIOD = "LOGIN_RATE_LIMIT_PERIOD" // time.Duration
+
+	// The number of failed login attempts after which an IP address is locked out, 0 disables the lockout.
+	APPVAR_LOGIN_LOCKOUT_FAILURES = "LOGIN_LOCKOUT_FAILURES" // int
+
+	// The period in which the failed login attempts are counted.
+	APPVAR_LOGIN_LOCKOUT_WINDOW = "LOGIN_LOCKOUT_WINDOW" // time.Duration
+
+	// The time an IP address is locked out for.
+	APPVAR_LOGIN_LOCKOUT_DURATION = "LOGIN_LOCKOUT_DURATION" // time.Duration
+)
+
+const (
+	// The default of [APPVAR_LOGIN_RATE_LIMIT].
+	DefaultLoginRateLimit = 10
+
+	// The default of [APPVAR_LOGIN_RATE_LIMIT_PERIOD].
+	DefaultLoginRateLimitPeriod = time.Minute
+)
+
+func nonNegative(key string) func(int) error {
+	return func(v int) error {
+		if v < 0 {
+			return fmt.Errorf("%s must not be negative, got %d", key, v)
+		}
+		return nil
+	}
+}
+
+var (
+	_ = django.DefineSetting(django.SettingSchema[string]{Key: APPVAR_RATE_LIMIT_CACHE, Description: "The cache backend the login rate limit and lockout are stored in"})
+	_ = django.DefineSetting(django.SettingSchema[int]{
+		Key:         APPVAR_LOGIN_RATE_LIMIT,
+		Default:     DefaultLoginRateLimit,
+		Description: "The number of login attempts allowed per IP address per LOGIN_RATE_LIMIT_PERIOD, 0 disables the limit",
+		Validators:  []func(int) error{nonNegative(APPVAR_LOGIN_RATE_LIMIT)},
+	})
+	_ = django.DefineSetting(django.SettingSchema[time.Duration]{
+		Key:         APPVAR_LOGIN_RATE_LIMIT_PERIOD,
+		Default:     DefaultLoginRateLimitPeriod,
+		Description: "The period of the login rate limit",
+		Validators: []func(time.Duration) error{
+			func(d time.Duration) error {
+				if d <= 0 {
+					return errors.New("LOGIN_RATE_LIMIT_PERIOD must be positive")
+				}
+				return nil
+			},
+		},
+	})
+	_ = django.DefineSetting(django.SettingSchema[int]{
+		Key:         APPVAR_LOGIN_LOCKOUT_FAILURES,
+		Default:     DefaultLockoutFailures,
+		Description: "The number of failed login attempts after which an IP address is locked out, 0 disables the lockout",
+		Validators:  []func(int) error{nonNegative(APPVAR_LOGIN_LOCKOUT_FAILURES)},
+	})
+	_ = django.DefineSetting(django.SettingSchema[time.Duration]{Key: APPVAR_LOGIN_LOCKOUT_WINDOW, Default: DefaultLockoutWindow, Description: "The period in which failed login attempts are counted"})
+	_ = django.DefineSetting(django.SettingSchema[time.Duration]{Key: APPVAR_LOGIN_LOCKOUT_DURATION, Default: DefaultLockoutDuration, Description: "The time an IP address is locked out for after too many failed login attempts"})
+)
+
+// LoginProtection throttles login views and locks out IP addresses after too many failed login attempts.
+//
+// It is configured with the LOGIN_* settings and used by the login views of the auth and admin apps
+// and the callbacks of the openauth2 app, see [ProtectLogin].
+type LoginProtection struct {
+	// The rate limit of login attempts, only POST requests are counted, nil if disabled.
+	Limiter *Limiter
+
+	// The lockout after failed login attempts, nil if disabled.
+	Lockout *LockoutPolicy
+}
+
+var (
+	// The login protection of each application, built from its settings the first time it is used.
+	loginProtections sync.Map // map[*django.Application]*LoginProtection
+
+	// The login protection with the default settings, used when no settings are loaded.
+	defaultLoginProtection = sync.OnceValue(func() *LoginProtection {
+		return newLoginProtection(nil)
+	})
+)
+
+func newLoginProtection(settings django.Settings) *LoginProtection {
+	var (
+		p       = &LoginProtection{}
+		backend = django.ConfigGet(settings, APPVAR_RATE_LIMIT_CACHE, "")
+	)
+
+	if rate := django.ConfigGet(settings, APPVAR_LOGIN_RATE_LIMIT, DefaultLoginRateLimit); rate > 0 {
+		p.Limiter = New(Limit{
+			Name:      "login",
+			Rate:      rate,
+			Period:    django.ConfigGet(settings, APPVAR_LOGIN_RATE_LIMIT_PERIOD, DefaultLoginRateLimitPeriod),
+			Algorithm: SlidingWindow,
+			Methods:   []string{http.MethodPost},
+			Backend:   backend,
+		})
+	}
+
+	if failures := django.ConfigGet(settings, APPVAR_LOGIN_LOCKOUT_FAILURES, DefaultLockoutFailures); failures > 0 {
+		p.Lockout = NewLockout(Lockout{
+			Name:        "login",
+			MaxFailures: failures,
+			Window:      django.ConfigGet(settings, APPVAR_LOGIN_LOCKOUT_WINDOW, DefaultLockoutWindow),
+			Duration:    django.ConfigGet(settings, APPVAR_LOGIN_LOCKOUT_DURATION, DefaultLockoutDuration),
+			Backend:     backend,
+		})
+	}
+
+	return p
+}
+
+// LoginFor returns the login protection configured in the settings of the given application.
+//
+// The protection is built once per application, the first time it is called after the settings of the application are loaded.
+// Before that, or for a nil application, the protection with the default settings is returned.
+func LoginFor(app *django.Application) *LoginProtection {
+	if app == nil || app.Settings == nil {
+		return defaultLoginProtection()
+	}
+
+	if p, ok := loginProtections.Load(app); ok {
+		return p.(*LoginProtection)
+	}
+
+	var p, _ = loginProtections.LoadOrStore(app, newLoginProtection(app.Settings))
+	return p.(*LoginProtection)
+}
+
+// Login returns the login protection configured in the settings of the global application, see [LoginFor].
+func Login() *LoginProtection {
+	return LoginFor(django.Global)
+}
+
+// Handler rejects requests from locked out IP addresses and login attempts over the rate limit.
+func (p *LoginProtection) Handler(next http.Handler) http.Handler {
+	if p.Lockout != nil {
+		next = p.Lockout.Handler(next)
+	}
+	if p.Limiter != nil {
+		next = p.Limiter.Handler(next)
+	}
+	return next
+}
+
+// ProtectLogin applies the [Login] protection to a login view, it should wrap the view which handles the login form submission.
+//
+// The failed login attempts are counted when [core.SIGNAL_LOGIN_FAILED] is sent, a successful login
+// sent with [core.SIGNAL_USER_LOGGED_IN] resets the count of the IP address.
+//
+// The protection of the global application is looked up on each request, see [Login].
+func ProtectLogin(next http.Handler) http.Handler {
+	var handlers sync.Map // map[*LoginProtection]http.Handler
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p = Login()
+		var handler, ok = handlers.Load(p)
+		if !ok {
+			handler, _ = handlers.LoadOrStore(p, p.Handler(next))
+		}
+		handler.(http.Handler).ServeHTTP(w, r)
+	})
+}
+
+func init() {
+	core.SIGNAL_LOGIN_FAILED.Listen(context.Background(), func(ctx context.Context, s signals.Signal[*http.Request], r *http.Request) error {
+		var lockout = Login().Lockout
+		if lockout == nil || r == nil {
+			return nil
+		}
+
+		if err := lockout.Fail(r); err != nil {
+			logger.Warnf("Failed to record the failed login attempt: %v", err)
+		}
+		return nil
+	})
+
+	core.SIGNAL_USER_LOGGED_IN.Listen(context.Background(), func(ctx context.Context, s signals.Signal[core.UserWithRequest], u core.UserWithRequest) error {
+		var lockout = Login().Lockout
+		if lockout == nil || u.Req == nil {
+			return nil
+		}
+
+		if err := lockout.Reset(u.Req); err != nil {
+			logger.Warnf("Failed to reset the failed login attempts: %v", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,316 @@
+/*
+Package ratelimit throttles requests with limits stored in the backends of `github.com/Nigel2392/cache`.
+
+A [Limiter] is created from a [Limit] and can be used as a middleware or to decorate a single view:
+
+	route.Use(ratelimit.Middleware(ratelimit.Limit{
+		Name:   "api",
+		Rate:   100,
+		Period: time.Minute,
+	}))
+
+	mux.NewHandler(ratelimit.Decorate(ratelimit.Limit{
+		Name:      "contact",
+		Rate:      5,
+		Period:    time.Hour,
+		Algorithm: ratelimit.SlidingWindow,
+	}, viewContact))
+
+Requests over the limit receive a 429 Too Many Requests response with a Retry-After header,
+all limited responses carry the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers.
+
+Repeated failures, i.e. failed login attempts, can lock a client out with a [Lockout].
+*/
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/cache"
+	"github.com/Nigel2392/go-django/src/core/assert"
+	"github.com/Nigel2392/go-django/src/core/logger"
+)
+
+// Algorithm is the algorithm a [Limiter] uses to count requests.
+type Algorithm string
+
+const (
+	// TokenBucket allows bursts of up to [Limit.Burst] requests,
+	// the bucket is refilled with [Limit.Rate] tokens per [Limit.Period].
+	TokenBucket Algorithm = "token_bucket"
+
+	// SlidingWindow allows [Limit.Rate] requests in any [Limit.Period],
+	// the requests of the previous period are weighted by how much of it still overlaps the window.
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+// Limit configures a [Limiter].
+type Limit struct {
+	// The name of the limit, it prefixes the cache keys of the limit and must be unique.
+	Name string
+
+	// The number of requests allowed per period.
+	Rate int
+
+	// The period in which Rate requests are allowed.
+	Period time.Duration
+
+	// The number of requests which can be made at once with the [TokenBucket] algorithm, defaults to Rate.
+	Burst int
+
+	// The algorithm used to count the requests, defaults to [TokenBucket].
+	Algorithm Algorithm
+
+	// The key the requests are counted by, defaults to [ByIP].
+	Key KeyFunc
+
+	// The methods which are limited, all methods are limited if empty.
+	Methods []string
+
+	// The name of the cache backend the state of the limit is stored in, defaults to the default backend.
+	Backend string
+
+	// The handler which responds to limited requests, defaults to a plain 429 Too Many Requests response.
+	//
+	// The Retry-After and RateLimit-* headers are set before the handler is called.
+	OnLimited http.Handler
+}
+
+// Result is the outcome of counting a request.
+type Result struct {
+	// Whether the request is allowed.
+	Allowed bool
+
+	// The number of requests which can be made at once.
+	Limit int
+
+	// The number of requests which can still be made.
+	Remaining int
+
+	// The time until the limit is fully replenished.
+	Reset time.Duration
+
+	// The time until the next request is allowed, zero if the request is allowed.
+	RetryAfter time.Duration
+}
+
+// Limiter counts requests per key and rejects the requests over its [Limit].
+//
+// The state of each key is read and written under a lock, so a key is counted atomically within the process.
+// When multiple processes share a cache backend the counts of concurrent requests may be slightly off.
+type Limiter struct {
+	limit   Limit
+	rate    float64
+	policy  string
+	backend []string
+	locks   keyLocks
+}
+
+// New creates a limiter, it panics if the limit is invalid.
+func New(limit Limit) *Limiter {
+	assert.False(limit.Name == "", "rate limit must have a name")
+	assert.True(limit.Rate > 0, "rate limit %q must have a positive rate", limit.Name)
+	assert.True(limit.Period > 0, "rate limit %q must have a positive period", limit.Name)
+
+	if limit.Algorithm == "" {
+		limit.Algorithm = TokenBucket
+	}
+
+	assert.True(
+		limit.Algorithm == TokenBucket || limit.Algorithm == SlidingWindow,
+		"rate limit %q has an unknown algorithm %q", limit.Name, limit.Algorithm,
+	)
+
+	if limit.Burst <= 0 {
+		limit.Burst = limit.Rate
+	}
+
+	if limit.Key == nil {
+		limit.Key = ByIP
+	}
+
+	var l = &Limiter{
+		limit:  limit,
+		rate:   float64(limit.Rate) / limit.Period.Seconds(),
+		policy: fmt.Sprintf("%d;w=%d", limit.Rate, int(math.Ceil(limit.Period.Seconds()))),
+	}
+
+	if limit.Algorithm == TokenBucket && limit.Burst != limit.Rate {
+		l.policy = fmt.Sprintf("%s;burst=%d", l.policy, limit.Burst)
+	}
+
+	if limit.Backend != "" {
+		l.backend = []string{limit.Backend}
+	}
+
+	return l
+}
+
+// Middleware returns a middleware which applies a limiter created from the limit, see [New].
+func Middleware(limit Limit) func(next http.Handler) http.Handler {
+	return New(limit).Handler
+}
+
+// Decorate applies a limiter created from the limit to a single view, see [New].
+func Decorate(limit Limit, view http.HandlerFunc) http.HandlerFunc {
+	return New(limit).HandlerFunc(view)
+}
+
+// Limit returns the limit of the limiter.
+func (l *Limiter) Limit() Limit {
+	return l.limit
+}
+
+// Allow counts a request for the key and reports whether it is allowed.
+func (l *Limiter) Allow(ctx context.Context, key string) (Result, error) {
+	var mu = l.locks.get(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	var (
+		now      = time.Now()
+		backend  = cache.GetCache(l.backend...)
+		cacheKey = l.cacheKey(key)
+		value, _ = backend.Get(ctx, cacheKey)
+		result   Result
+		ttl      time.Duration
+	)
+
+	switch l.limit.Algorithm {
+	case SlidingWindow:
+		var state, _, err = decodeState[windowState](value)
+		if err != nil {
+			return result, fmt.Errorf("ratelimit: read state of %q: %w", l.limit.Name, err)
+		}
+		result, state = l.slidingWindow(state, now)
+		value, ttl = state, 2*l.limit.Period
+	default:
+		var state, ok, err = decodeState[bucketState](value)
+		if err != nil {
+			return result, fmt.Errorf("ratelimit: read state of %q: %w", l.limit.Name, err)
+		}
+		if !ok {
+			state = bucketState{Tokens: float64(l.limit.Burst), Updated: now}
+		}
+		result, state = l.tokenBucket(state, now)
+		value, ttl = state, time.Duration(float64(l.limit.Burst)/l.rate*float64(time.Second))
+	}
+
+	var encoded, err = encodeState(value)
+	if err != nil {
+		return result, fmt.Errorf("ratelimit: encode state of %q: %w", l.limit.Name, err)
+	}
+
+	if err := backend.Set(ctx, cacheKey, encoded, ttl); err != nil {
+		return result, fmt.Errorf("ratelimit: store state of %q: %w", l.limit.Name, err)
+	}
+
+	return result, nil
+}
+
+// AllowRequest counts the request and reports whether it is allowed.
+//
+// A nil result is returned if the request is not limited, i.e. because its method is not limited
+// or the [KeyFunc] of the limit returned an empty key.
+func (l *Limiter) AllowRequest(r *http.Request) (*Result, error) {
+	if len(l.limit.Methods) > 0 && !slices.Contains(l.limit.Methods, r.Method) {
+		return nil, nil
+	}
+
+	var key = l.limit.Key(r)
+	if key == "" {
+		return nil, nil
+	}
+
+	var result, err = l.Allow(r.Context(), key)
+	return &result, err
+}
+
+// Reset removes the state of the key, i.e. after a successful login.
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	var mu = l.locks.get(key)
+	mu.Lock()
+	defer mu.Unlock()
+	return cache.GetCache(l.backend...).Delete(ctx, l.cacheKey(key))
+}
+
+// Handler rejects the requests over the limit and passes all other requests to the next handler.
+//
+// Requests are allowed if the state of the limit cannot be stored, the error is logged.
+func (l *Limiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result, err = l.AllowRequest(r)
+		if err != nil {
+			logger.Warnf("Failed to apply the rate limit %q: %v", l.limit.Name, err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if result == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		l.SetHeaders(w, *result)
+
+		if !result.Allowed {
+			tooManyRequests(w, r, result.RetryAfter, l.limit.OnLimited)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// HandlerFunc is like [Limiter.Handler] for a single view.
+func (l *Limiter) HandlerFunc(view http.HandlerFunc) http.HandlerFunc {
+	return l.Handler(view).ServeHTTP
+}
+
+// SetHeaders sets the RateLimit-* headers of the result on the response.
+func (l *Limiter) SetHeaders(w http.ResponseWriter, result Result) {
+	var header = w.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+	header.Set("RateLimit-Policy", l.policy)
+}
+
+func (l *Limiter) cacheKey(key string) string {
+	return fmt.Sprintf("ratelimit.%s.%s", l.limit.Name, key)
+}
+
+// keyLocks serializes the reads and writes of the state of a key within the process.
+type keyLocks [64]sync.Mutex
+
+func (k *keyLocks) get(key string) *sync.Mutex {
+	var hash = fnv.New32a()
+	hash.Write([]byte(key))
+	return &k[hash.Sum32()%uint32(len(k))]
+}
+
+// tooManyRequests sets the Retry-After header and responds with the handler,
+// or with a plain 429 Too Many Requests response if the handler is nil.
+func tooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter time.Duration, handler http.Handler) {
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds(retryAfter), 1)))
+
+	if handler != nil {
+		handler.ServeHTTP(w, r)
+		return
+	}
+
+	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+}
+
+// seconds rounds the duration up to whole seconds.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
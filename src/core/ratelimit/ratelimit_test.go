@@ -0,0 +1,268 @@
+package ratelimit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Nigel2392/cache"
+	django "github.com/Nigel2392/go-django/src"
+	core "github.com/Nigel2392/go-django/src/core"
+	"github.com/Nigel2392/go-django/src/core/ratelimit"
+	"github.com/Nigel2392/go-signals"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok"))
+})
+
+func byHeader(r *http.Request) string {
+	return r.Header.Get("X-Client")
+}
+
+func serve(h http.Handler, method, client string) *httptest.ResponseRecorder {
+	var r = httptest.NewRequest(method, "/login", nil)
+	if client != "" {
+		r.Header.Set("X-Client", client)
+	}
+	var w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestTokenBucket(t *testing.T) {
+	var h = ratelimit.Middleware(ratelimit.Limit{
+		Name:   "test.bucket",
+		Rate:   2,
+		Period: time.Minute,
+		Key:    byHeader,
+	})(okHandler)
+
+	for i := 0; i < 2; i++ {
+		var w = serve(h, http.MethodPost, "a")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected request %d to be allowed, got %d", i, w.Code)
+		}
+		if got := w.Header().Get("RateLimit-Remaining"); got != strconv.Itoa(1-i) {
+			t.Errorf("expected %d remaining, got %q", 1-i, got)
+		}
+	}
+
+	var w = serve(h, http.MethodPost, "a")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+
+	var expected = map[string]string{
+		"Retry-After":         "30",
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Policy":    "2;w=60",
+	}
+	for k, v := range expected {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("expected %s: %q, got %q", k, v, got)
+		}
+	}
+
+	if w = serve(h, http.MethodPost, "b"); w.Code != http.StatusOK {
+		t.Errorf("expected another key to be allowed, got %d", w.Code)
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	var l = ratelimit.New(ratelimit.Limit{
+		Name:   "test.refill",
+		Rate:   10,
+		Period: 100 * time.Millisecond,
+		Burst:  1,
+	})
+
+	var ctx = context.Background()
+	if result, _ := l.Allow(ctx, "a"); !result.Allowed {
+		t.Fatal("expected the first request to be allowed")
+	}
+	if result, _ := l.Allow(ctx, "a"); result.Allowed {
+		t.Fatal("expected the burst to be exhausted")
+	}
+
+	time.Sleep(15 * time.Millisecond)
+
+	if result, _ := l.Allow(ctx, "a"); !result.Allowed {
+		t.Error("expected the bucket to be refilled")
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	var l = ratelimit.New(ratelimit.Limit{
+		Name:      "test.window",
+		Rate:      3,
+		Period:    time.Hour,
+		Algorithm: ratelimit.SlidingWindow,
+	})
+
+	var ctx = context.Background()
+	for i := 0; i < 3; i++ {
+		if result, err := l.Allow(ctx, "a"); err != nil || !result.Allowed {
+			t.Fatalf("expected request %d to be allowed: %v", i, err)
+		}
+	}
+
+	var result, _ = l.Allow(ctx, "a")
+	if result.Allowed {
+		t.Fatal("expected the fourth request to be limited")
+	}
+	if result.RetryAfter <= 0 || result.RetryAfter > 2*time.Hour {
+		t.Errorf("expected a retry after the window has moved, got %s", result.RetryAfter)
+	}
+
+	if err := l.Reset(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ = l.Allow(ctx, "a"); !result.Allowed {
+		t.Error("expected the request to be allowed after a reset")
+	}
+}
+
+func TestInvalidState(t *testing.T) {
+	var l = ratelimit.New(ratelimit.Limit{
+		Name:   "test.invalid",
+		Rate:   1,
+		Period: time.Minute,
+	})
+
+	var ctx = context.Background()
+	if err := cache.GetCache().Set(ctx, "ratelimit.test.invalid.a", 1, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.Allow(ctx, "a"); err == nil {
+		t.Fatal("expected an error for a state of an unexpected type")
+	}
+
+	if err := l.Reset(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := l.Allow(ctx, "a"); err != nil || !result.Allowed {
+		t.Fatalf("expected the request to be allowed after a reset: %v", err)
+	}
+}
+
+func TestNotLimited(t *testing.T) {
+	var h = ratelimit.Middleware(ratelimit.Limit{
+		Name:    "test.methods",
+		Rate:    1,
+		Period:  time.Minute,
+		Key:     byHeader,
+		Methods: []string{http.MethodPost},
+	})(okHandler)
+
+	for i := 0; i < 3; i++ {
+		var w = serve(h, http.MethodGet, "a")
+		if w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+			t.Errorf("expected GET requests not to be limited, got %d", w.Code)
+		}
+
+		if w = serve(h, http.MethodPost, ""); w.Code != http.StatusOK {
+			t.Errorf("expected requests without a key not to be limited, got %d", w.Code)
+		}
+	}
+}
+
+func TestLockout(t *testing.T) {
+	var p = ratelimit.NewLockout(ratelimit.Lockout{
+		Name:        "test.lockout",
+		MaxFailures: 3,
+		Duration:    time.Minute,
+		Key:         byHeader,
+	})
+
+	var locked *core.Lockout
+	core.SIGNAL_LOCKED_OUT.Listen(context.Background(), func(ctx context.Context, s signals.Signal[*core.Lockout], l *core.Lockout) error {
+		if l.Name == "test.lockout" {
+			locked = l
+		}
+		return nil
+	})
+
+	var r = httptest.NewRequest(http.MethodPost, "/login", nil)
+	r.Header.Set("X-Client", "a")
+
+	for i := 0; i < 2; i++ {
+		if err := p.Fail(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, ok, err := p.Locked(r); err != nil || ok || locked != nil {
+		t.Fatal("expected the client not to be locked out yet")
+	}
+
+	if err := p.Fail(r); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := p.Locked(r); err != nil || !ok {
+		t.Fatalf("expected the client to be locked out: %v", err)
+	}
+
+	if locked == nil || locked.Key != "a" || locked.Failures != 3 {
+		t.Fatalf("expected the lockout signal to be sent, got %+v", locked)
+	}
+
+	var h = p.Handler(okHandler)
+	var w = serve(h, http.MethodPost, "a")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "60" {
+		t.Errorf("expected 429 with Retry-After: 60, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	if w = serve(h, http.MethodPost, "b"); w.Code != http.StatusOK {
+		t.Errorf("expected another client not to be locked out, got %d", w.Code)
+	}
+
+	if err := p.Reset(r); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := p.Locked(r); err != nil || !ok {
+		t.Errorf("expected a reset not to end the lockout: %v", err)
+	}
+
+	if err := p.Unlock(r.Context(), "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := p.Locked(r); err != nil || ok {
+		t.Errorf("expected the client to be unlocked: %v", err)
+	}
+}
+
+func TestLoginFor(t *testing.T) {
+	var (
+		strict = &django.Application{Settings: django.Config(map[string]interface{}{
+			ratelimit.APPVAR_LOGIN_RATE_LIMIT:       3,
+			ratelimit.APPVAR_LOGIN_LOCKOUT_FAILURES: 0,
+		})}
+		disabled = &django.Application{Settings: django.Config(map[string]interface{}{
+			ratelimit.APPVAR_LOGIN_RATE_LIMIT: 0,
+		})}
+	)
+
+	var p = ratelimit.LoginFor(strict)
+	if p != ratelimit.LoginFor(strict) {
+		t.Fatal("expected the login protection to be built once per application")
+	}
+
+	if p.Limiter == nil || p.Lockout != nil {
+		t.Fatalf("expected only the rate limit to be enabled, got %+v", p)
+	}
+
+	if q := ratelimit.LoginFor(disabled); q == p || q.Limiter != nil || q.Lockout == nil {
+		t.Fatalf("expected the settings of the second application to be used, got %+v", q)
+	}
+
+	if d := ratelimit.LoginFor(nil); d.Limiter == nil || d.Lockout == nil {
+		t.Fatalf("expected the default settings without an application, got %+v", d)
+	}
+}
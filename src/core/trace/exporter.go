@@ -0,0 +1,172 @@
+package trace
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"sync"
+)
+
+// Exporter receives the spans of a tracer once they end.
+//
+// ExportSpan is called synchronously from [Span.End], exporters which send
+// spans over the network should buffer them and send them in the background.
+type Exporter interface {
+	ExportSpan(span *SpanData) error
+}
+
+// ExporterFunc is a function which implements [Exporter].
+type ExporterFunc func(span *SpanData) error
+
+func (f ExporterFunc) ExportSpan(span *SpanData) error {
+	return f(span)
+}
+
+// JSONExporter writes each span as a JSON object on its own line.
+type JSONExporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONExporter returns an exporter which writes the spans to the writer, i.e. [os.Stdout].
+func NewJSONExporter(w io.Writer) *JSONExporter {
+	return &JSONExporter{enc: json.NewEncoder(w)}
+}
+
+func (e *JSONExporter) ExportSpan(span *SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.enc.Encode(span)
+}
+
+// Trace holds the ended spans of a single trace in the order they started.
+type Trace struct {
+	ID    TraceID
+	Spans []SpanData
+
+	// The number of spans which were not kept because the trace has too many spans.
+	Dropped int
+}
+
+// Root returns the span without a parent in the trace, or the first span if the parent lives in another process.
+func (t *Trace) Root() *SpanData {
+	if len(t.Spans) == 0 {
+		return nil
+	}
+
+	var ids = make(map[SpanID]struct{}, len(t.Spans))
+	for _, span := range t.Spans {
+		ids[span.SpanID] = struct{}{}
+	}
+
+	for i, span := range t.Spans {
+		if _, ok := ids[span.ParentID]; !ok {
+			return &t.Spans[i]
+		}
+	}
+	return &t.Spans[0]
+}
+
+// DefaultMaxSpansPerTrace is the number of spans a [MemoryExporter] keeps for a single trace by default.
+const DefaultMaxSpansPerTrace = 1000
+
+// MemoryExporter keeps the ended spans in memory, grouped by trace.
+//
+// It is meant for tests and for inspecting recent requests.
+// The number of spans kept per trace is limited, so a request which i.e. executes a query in a loop
+// cannot grow the memory of the exporter without bounds, see [MemoryExporter.SetMaxSpans].
+type MemoryExporter struct {
+	mu        sync.Mutex
+	maxTraces int
+	maxSpans  int
+	order     []TraceID
+	traces    map[TraceID][]SpanData
+	dropped   map[TraceID]int
+}
+
+// NewMemoryExporter returns an exporter which keeps the spans of the most recent maxTraces traces,
+// all traces are kept if maxTraces is zero or less.
+//
+// At most [DefaultMaxSpansPerTrace] spans are kept per trace.
+func NewMemoryExporter(maxTraces int) *MemoryExporter {
+	return &MemoryExporter{
+		maxTraces: maxTraces,
+		maxSpans:  DefaultMaxSpansPerTrace,
+		traces:    make(map[TraceID][]SpanData),
+		dropped:   make(map[TraceID]int),
+	}
+}
+
+// SetMaxSpans sets the number of spans kept per trace, all spans are kept if max is zero or less.
+//
+// Spans which end after the limit is reached are dropped and counted in [Trace.Dropped].
+func (e *MemoryExporter) SetMaxSpans(max int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.maxSpans = max
+}
+
+func (e *MemoryExporter) ExportSpan(span *SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var spans, ok = e.traces[span.TraceID]
+	if !ok {
+		e.order = append(e.order, span.TraceID)
+		if e.maxTraces > 0 && len(e.order) > e.maxTraces {
+			delete(e.traces, e.order[0])
+			delete(e.dropped, e.order[0])
+			e.order = slices.Delete(e.order, 0, 1)
+		}
+	}
+
+	if e.maxSpans > 0 && len(spans) >= e.maxSpans {
+		e.dropped[span.TraceID]++
+		return nil
+	}
+
+	e.traces[span.TraceID] = append(spans, *span)
+	return nil
+}
+
+// Spans returns all kept spans in the order they ended.
+func (e *MemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var spans = make([]SpanData, 0)
+	for _, id := range e.order {
+		spans = append(spans, e.traces[id]...)
+	}
+	slices.SortStableFunc(spans, func(a, b SpanData) int {
+		return a.End.Compare(b.End)
+	})
+	return spans
+}
+
+// Traces returns the kept traces, the most recent trace first.
+//
+// The spans of each trace are sorted by their start time.
+func (e *MemoryExporter) Traces() []Trace {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var traces = make([]Trace, 0, len(e.order))
+	for i := len(e.order) - 1; i >= 0; i-- {
+		var spans = slices.Clone(e.traces[e.order[i]])
+		slices.SortStableFunc(spans, func(a, b SpanData) int {
+			return a.Start.Compare(b.Start)
+		})
+		traces = append(traces, Trace{ID: e.order[i], Spans: spans, Dropped: e.dropped[e.order[i]]})
+	}
+	return traces
+}
+
+// Reset removes all kept spans.
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.order = nil
+	e.traces = make(map[TraceID][]SpanData)
+	e.dropped = make(map[TraceID]int)
+}
@@ -0,0 +1,89 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceparentHeader is the W3C trace context header which identifies the parent span of a request.
+//
+// See https://www.w3.org/TR/trace-context/#traceparent-header
+const TraceparentHeader = "traceparent"
+
+const flagSampled = 0x01
+
+// ParseTraceparent parses the value of a traceparent header:
+//
+//	00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+//
+// Future versions are accepted as long as they start with the fields of version 00.
+func ParseTraceparent(value string) (SpanContext, error) {
+	var sc SpanContext
+	var parts = strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return sc, fmt.Errorf("%w: expected 4 fields, got %d", ErrInvalidTraceparent, len(parts))
+	}
+
+	var version, flags [1]byte
+	if err := decodeID(version[:], parts[0]); err != nil || version[0] == 0xff {
+		return sc, fmt.Errorf("%w: invalid version %q", ErrInvalidTraceparent, parts[0])
+	}
+
+	if version[0] == 0 && len(parts) != 4 {
+		return sc, fmt.Errorf("%w: expected 4 fields for version 00, got %d", ErrInvalidTraceparent, len(parts))
+	}
+
+	if err := decodeID(sc.TraceID[:], parts[1]); err != nil || !sc.TraceID.IsValid() {
+		return SpanContext{}, fmt.Errorf("%w: invalid trace ID %q", ErrInvalidTraceparent, parts[1])
+	}
+
+	if err := decodeID(sc.SpanID[:], parts[2]); err != nil || !sc.SpanID.IsValid() {
+		return SpanContext{}, fmt.Errorf("%w: invalid parent ID %q", ErrInvalidTraceparent, parts[2])
+	}
+
+	if err := decodeID(flags[:], parts[3]); err != nil {
+		return SpanContext{}, fmt.Errorf("%w: invalid flags %q", ErrInvalidTraceparent, parts[3])
+	}
+
+	sc.Sampled = flags[0]&flagSampled != 0
+	return sc, nil
+}
+
+// Traceparent formats the span context as the value of a traceparent header.
+func (sc SpanContext) Traceparent() string {
+	var flags byte
+	if sc.Sampled {
+		flags = flagSampled
+	}
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, flags)
+}
+
+// Extract returns a context which holds the remote span of the traceparent header,
+// the context is returned as-is if the header is missing or invalid.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	var value = header.Get(TraceparentHeader)
+	if value == "" {
+		return ctx
+	}
+
+	var sc, err = ParseTraceparent(value)
+	if err != nil {
+		return ctx
+	}
+
+	return ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Inject sets the traceparent header to the current span of the context,
+// so that a request made with the header continues the trace.
+//
+// Nothing is set if the context holds no span.
+func Inject(ctx context.Context, header http.Header) {
+	var sc = SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	header.Set(TraceparentHeader, sc.Traceparent())
+}
@@ -0,0 +1,217 @@
+/*
+Package trace provides lightweight request tracing.
+
+A span measures a unit of work, like serving a request or executing a query.
+Spans started with the context of another span are its children, all spans
+started while serving a request share the trace ID of the request span.
+
+	ctx, span := trace.Start(ctx, "blog.render_post", "post_id", post.ID)
+	defer span.End()
+
+Spans are only recorded when the tracer is enabled and the remote parent of the trace, if any, is sampled,
+otherwise [Start] returns a nil span.
+All methods of a nil span are no-ops, so the result of [Start] never has to be checked.
+
+Ended spans are passed to the exporters of the tracer, see [Exporter].
+*/
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// TraceID identifies a trace, it is shared by all spans of the trace.
+type TraceID [16]byte
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+func (t TraceID) IsValid() bool  { return t != TraceID{} }
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+func (t TraceID) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *TraceID) UnmarshalText(b []byte) error {
+	return decodeID(t[:], string(b))
+}
+
+func (s SpanID) IsValid() bool  { return s != SpanID{} }
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+func (s SpanID) MarshalText() ([]byte, error) {
+	if !s.IsValid() {
+		return []byte{}, nil
+	}
+	return []byte(s.String()), nil
+}
+
+func (s *SpanID) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*s = SpanID{}
+		return nil
+	}
+	return decodeID(s[:], string(b))
+}
+
+func decodeID(dst []byte, s string) error {
+	if len(s) != len(dst)*2 {
+		return fmt.Errorf("%w: expected %d hex characters, got %d", ErrInvalidID, len(dst)*2, len(s))
+	}
+	if _, err := hex.Decode(dst, []byte(s)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+	return nil
+}
+
+func newTraceID() (id TraceID) {
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() (id SpanID) {
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// SpanContext identifies a span across process boundaries, see [ParseTraceparent].
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid reports whether both the trace and the span ID are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Status is the outcome of the work measured by a span.
+type Status string
+
+const (
+	StatusUnset Status = ""
+	StatusOK    Status = "ok"
+	StatusError Status = "error"
+)
+
+// SpanData is the immutable record of an ended span which is passed to the exporters.
+type SpanData struct {
+	Name       string         `json:"name"`
+	TraceID    TraceID        `json:"trace_id"`
+	SpanID     SpanID         `json:"span_id"`
+	ParentID   SpanID         `json:"parent_id,omitempty"`
+	Start      time.Time      `json:"start"`
+	End        time.Time      `json:"end"`
+	Status     Status         `json:"status,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// Duration returns the time between the start and the end of the span.
+func (d *SpanData) Duration() time.Duration {
+	return d.End.Sub(d.Start)
+}
+
+// Span measures a unit of work, it is created with [Start] or [Tracer.Start].
+//
+// A span is safe for concurrent use, the methods of a nil span do nothing.
+type Span struct {
+	tracer *Tracer
+
+	mu    sync.Mutex
+	data  SpanData
+	attrs []slog.Attr
+	ended bool
+}
+
+// SpanContext returns the identity of the span, the zero value is returned for a nil span.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return SpanContext{
+		TraceID: s.data.TraceID,
+		SpanID:  s.data.SpanID,
+		Sampled: true,
+	}
+}
+
+// SetName replaces the name of the span.
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Name = name
+}
+
+// SetAttributes adds key/value attributes to the span, the arguments are converted like [slog.Logger.With] does.
+//
+// Attributes with the same key replace earlier attributes.
+func (s *Span) SetAttributes(args ...any) {
+	if s == nil || len(args) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, slog.Group("", args...).Value.Group()...)
+}
+
+// SetStatus sets the status of the span.
+func (s *Span) SetStatus(status Status) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Status = status
+}
+
+// SetError marks the span as failed with the error, a nil error is ignored.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Status = StatusError
+	s.data.Error = err.Error()
+}
+
+// End ends the span and passes it to the exporters of its tracer, only the first call has an effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+
+	var data = s.data
+	if len(s.attrs) > 0 {
+		data.Attributes = make(map[string]any, len(s.attrs))
+		for _, attr := range s.attrs {
+			data.Attributes[attr.Key] = attr.Value.Resolve().Any()
+		}
+	}
+	s.mu.Unlock()
+
+	s.tracer.export(&data)
+}
@@ -0,0 +1,232 @@
+package trace_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Nigel2392/go-django/src/core/trace"
+)
+
+func newTracer() (*trace.Tracer, *trace.MemoryExporter) {
+	var tracer = trace.NewTracer()
+	var exporter = trace.NewMemoryExporter(0)
+	tracer.AddExporter(exporter)
+	tracer.SetEnabled(true)
+	return tracer, exporter
+}
+
+func TestTracerDisabled(t *testing.T) {
+	var tracer = trace.NewTracer()
+	var ctx, span = tracer.Start(context.Background(), "disabled")
+	if span != nil {
+		t.Fatalf("expected a nil span")
+	}
+
+	// methods of a nil span are no-ops
+	span.SetAttributes("key", "value")
+	span.SetError(errors.New("failed"))
+	span.End()
+
+	if trace.SpanFromContext(ctx) != nil {
+		t.Fatalf("expected no span in the context")
+	}
+}
+
+func TestTracerSpans(t *testing.T) {
+	var tracer, exporter = newTracer()
+
+	var ctx, root = tracer.Start(context.Background(), "GET /", "method", "GET")
+	var _, child = tracer.Start(ctx, "db.query")
+	child.SetError(errors.New("failed"))
+	child.End()
+	root.SetAttributes("status", 200)
+	root.End()
+	root.End()
+
+	var traces = exporter.Traces()
+	if len(traces) != 1 {
+		t.Fatalf("expected 1 trace, got %d", len(traces))
+	}
+
+	var spans = traces[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	if spans[0].Name != "GET /" || spans[1].Name != "db.query" {
+		t.Fatalf("expected the spans in start order, got %q and %q", spans[0].Name, spans[1].Name)
+	}
+
+	if spans[1].ParentID != spans[0].SpanID || spans[1].TraceID != spans[0].TraceID {
+		t.Errorf("expected the query span to be a child of the request span")
+	}
+
+	if spans[1].Status != trace.StatusError || spans[1].Error != "failed" {
+		t.Errorf("expected the query span to have failed, got %q %q", spans[1].Status, spans[1].Error)
+	}
+
+	if spans[0].Attributes["method"] != "GET" || spans[0].Attributes["status"] != int64(200) {
+		t.Errorf("unexpected attributes %v", spans[0].Attributes)
+	}
+
+	var rows = traces[0].Waterfall()
+	if len(rows) != 2 || rows[0].Depth != 0 || rows[1].Depth != 1 {
+		t.Fatalf("unexpected waterfall %+v", rows)
+	}
+}
+
+func TestMemoryExporterLimit(t *testing.T) {
+	var tracer = trace.NewTracer()
+	var exporter = trace.NewMemoryExporter(2)
+	tracer.AddExporter(exporter)
+	tracer.SetEnabled(true)
+
+	for _, name := range []string{"first", "second", "third"} {
+		var _, span = tracer.Start(context.Background(), name)
+		span.End()
+	}
+
+	var traces = exporter.Traces()
+	if len(traces) != 2 {
+		t.Fatalf("expected 2 traces, got %d", len(traces))
+	}
+
+	if traces[0].Spans[0].Name != "third" || traces[1].Spans[0].Name != "second" {
+		t.Errorf("expected the most recent traces first, got %q and %q", traces[0].Spans[0].Name, traces[1].Spans[0].Name)
+	}
+}
+
+func TestMemoryExporterMaxSpans(t *testing.T) {
+	var tracer, exporter = newTracer()
+	exporter.SetMaxSpans(3)
+
+	var ctx, root = tracer.Start(context.Background(), "root")
+	for i := 0; i < 5; i++ {
+		var _, span = tracer.Start(ctx, "db.query")
+		span.End()
+	}
+	root.End()
+
+	var traces = exporter.Traces()
+	if len(traces) != 1 {
+		t.Fatalf("expected 1 trace, got %d", len(traces))
+	}
+
+	if len(traces[0].Spans) != 3 || traces[0].Dropped != 3 {
+		t.Errorf("expected 3 spans and 3 dropped spans, got %d and %d", len(traces[0].Spans), traces[0].Dropped)
+	}
+
+	exporter.Reset()
+	var _, span = tracer.Start(context.Background(), "other")
+	span.End()
+	if traces = exporter.Traces(); len(traces) != 1 || traces[0].Dropped != 0 {
+		t.Errorf("expected the dropped spans to be reset, got %+v", traces)
+	}
+}
+
+func TestTraceparent(t *testing.T) {
+	const header = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	var sc, err = trace.ParseTraceparent(header)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" || !sc.Sampled {
+		t.Fatalf("unexpected span context %+v", sc)
+	}
+
+	if sc.Traceparent() != header {
+		t.Errorf("expected %q, got %q", header, sc.Traceparent())
+	}
+
+	var invalid = []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+	}
+	for _, value := range invalid {
+		if _, err := trace.ParseTraceparent(value); !errors.Is(err, trace.ErrInvalidTraceparent) {
+			t.Errorf("expected %q to be invalid, got %v", value, err)
+		}
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	var tracer, exporter = newTracer()
+
+	var incoming = http.Header{}
+	incoming.Set(trace.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	var ctx, span = tracer.Start(trace.Extract(context.Background(), incoming), "GET /")
+
+	var outgoing = http.Header{}
+	trace.Inject(ctx, outgoing)
+	span.End()
+
+	var spans = exporter.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || spans[0].ParentID.String() != "00f067aa0ba902b7" {
+		t.Errorf("expected the span to continue the remote trace, got %+v", spans[0])
+	}
+
+	var expected = "00-4bf92f3577b34da6a3ce929d0e0e4736-" + spans[0].SpanID.String() + "-01"
+	if outgoing.Get(trace.TraceparentHeader) != expected {
+		t.Errorf("expected %q, got %q", expected, outgoing.Get(trace.TraceparentHeader))
+	}
+}
+
+func TestPropagationNotSampled(t *testing.T) {
+	var tracer, exporter = newTracer()
+
+	var incoming = http.Header{}
+	incoming.Set(trace.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+	var ctx, span = tracer.Start(trace.Extract(context.Background(), incoming), "GET /")
+	if span != nil {
+		t.Fatalf("expected no span for a trace which is not sampled, got %+v", span.SpanContext())
+	}
+
+	var _, child = tracer.Start(ctx, "db.query")
+	child.End()
+
+	if spans := exporter.Spans(); len(spans) != 0 {
+		t.Errorf("expected no spans to be recorded, got %d", len(spans))
+	}
+
+	var outgoing = http.Header{}
+	trace.Inject(ctx, outgoing)
+	if outgoing.Get(trace.TraceparentHeader) != incoming.Get(trace.TraceparentHeader) {
+		t.Errorf("expected the unsampled trace to be passed on, got %q", outgoing.Get(trace.TraceparentHeader))
+	}
+}
+
+func TestJSONExporter(t *testing.T) {
+	var buf = new(bytes.Buffer)
+	var tracer = trace.NewTracer()
+	tracer.AddExporter(trace.NewJSONExporter(buf))
+	tracer.SetEnabled(true)
+
+	var _, span = tracer.Start(context.Background(), "mail.send", "backend", "smtp")
+	span.End()
+
+	var data trace.SpanData
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		t.Fatalf("failed to decode span: %v", err)
+	}
+
+	if data.Name != "mail.send" || !data.TraceID.IsValid() || data.ParentID.IsValid() || data.Attributes["backend"] != "smtp" {
+		t.Errorf("unexpected span %+v", data)
+	}
+}
@@ -0,0 +1,146 @@
+package trace
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Nigel2392/go-django/src/core/errs"
+	"github.com/Nigel2392/go-django/src/core/logger"
+)
+
+const (
+	ErrInvalidID          errs.Error = "invalid trace or span ID"
+	ErrInvalidTraceparent errs.Error = "invalid traceparent header"
+)
+
+// Default is the tracer used by go-django's built-in spans.
+//
+// It is disabled until tracing is enabled with the "TRACING" setting or [Tracer.SetEnabled].
+var Default = NewTracer()
+
+// Start starts a span with the [Default] tracer, see [Tracer.Start].
+func Start(ctx context.Context, name string, args ...any) (context.Context, *Span) {
+	return Default.Start(ctx, name, args...)
+}
+
+type spanContextKey struct{}
+type remoteContextKey struct{}
+
+// SpanFromContext returns the span stored in the context, or nil if there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	var span, _ = ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// ContextWithSpan returns a context which holds the span, spans started with the context are its children.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// ContextWithRemoteSpanContext returns a context which holds the span of another process,
+// the first span started with the context is its child, see [Extract].
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	if !sc.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the identity of the current span of the context,
+// or of the remote span if no span was started with the context.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.SpanContext()
+	}
+	var sc, _ = ctx.Value(remoteContextKey{}).(SpanContext)
+	return sc
+}
+
+// Tracer starts spans and passes them to its exporters once they end.
+type Tracer struct {
+	enabled   atomic.Bool
+	mu        sync.RWMutex
+	exporters []Exporter
+}
+
+// NewTracer returns a disabled tracer without exporters.
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// SetEnabled enables or disables the recording of spans.
+func (t *Tracer) SetEnabled(enabled bool) {
+	t.enabled.Store(enabled)
+}
+
+// Enabled reports whether the tracer records spans.
+func (t *Tracer) Enabled() bool {
+	return t.enabled.Load()
+}
+
+// AddExporter adds an exporter to the tracer, ended spans are passed to all exporters in order.
+func (t *Tracer) AddExporter(exporter Exporter) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.exporters = append(t.exporters, exporter)
+}
+
+// RemoveExporter removes an exporter which was added with [Tracer.AddExporter].
+func (t *Tracer) RemoveExporter(exporter Exporter) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.exporters = slices.DeleteFunc(slices.Clone(t.exporters), func(e Exporter) bool {
+		return e == exporter
+	})
+}
+
+// Start starts a span with the key/value attributes and returns a context which holds it.
+//
+// The span is a child of the span stored in the context, or of the remote span stored with
+// [ContextWithRemoteSpanContext]. Otherwise it starts a new trace.
+//
+// If the tracer is disabled, or the remote parent is not sampled, the context is returned
+// as-is together with a nil span. The span must be ended with [Span.End].
+func (t *Tracer) Start(ctx context.Context, name string, args ...any) (context.Context, *Span) {
+	if !t.Enabled() {
+		return ctx, nil
+	}
+
+	var parent = SpanContextFromContext(ctx)
+	if parent.IsValid() && !parent.Sampled {
+		return ctx, nil
+	}
+
+	var span = &Span{tracer: t}
+	span.data.Name = name
+	span.data.SpanID = newSpanID()
+	span.data.Start = time.Now()
+
+	if parent.IsValid() {
+		span.data.TraceID = parent.TraceID
+		span.data.ParentID = parent.SpanID
+	} else {
+		span.data.TraceID = newTraceID()
+	}
+
+	span.SetAttributes(args...)
+	return ContextWithSpan(ctx, span), span
+}
+
+func (t *Tracer) export(data *SpanData) {
+	t.mu.RLock()
+	var exporters = t.exporters
+	t.mu.RUnlock()
+
+	for _, exporter := range exporters {
+		if err := exporter.ExportSpan(data); err != nil {
+			logger.Warnf("Failed to export span %q: %v", data.Name, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package trace
+
+import "time"
+
+// WaterfallRow is a span of a trace laid out on the timeline of the trace.
+type WaterfallRow struct {
+	Span SpanData
+
+	// The number of ancestors of the span within the trace.
+	Depth int
+
+	// The start of the span and its duration as a percentage of the duration of the trace.
+	Offset float64
+	Width  float64
+}
+
+// Duration returns the time between the start of the first span and the end of the last span of the trace.
+func (t *Trace) Duration() time.Duration {
+	var start, end = t.bounds()
+	return end.Sub(start)
+}
+
+func (t *Trace) bounds() (start, end time.Time) {
+	for i, span := range t.Spans {
+		if i == 0 || span.Start.Before(start) {
+			start = span.Start
+		}
+		if i == 0 || span.End.After(end) {
+			end = span.End
+		}
+	}
+	return start, end
+}
+
+// Waterfall returns the spans of the trace with each span directly followed by its children.
+//
+// Spans whose parent is not part of the trace are treated as roots.
+func (t *Trace) Waterfall() []WaterfallRow {
+	var (
+		start, end = t.bounds()
+		total      = float64(end.Sub(start))
+		ids        = make(map[SpanID]struct{}, len(t.Spans))
+		children   = make(map[SpanID][]int, len(t.Spans))
+		roots      = make([]int, 0, 1)
+		rows       = make([]WaterfallRow, 0, len(t.Spans))
+	)
+
+	for _, span := range t.Spans {
+		ids[span.SpanID] = struct{}{}
+	}
+
+	// the spans are sorted by their start time, so are the children
+	for i, span := range t.Spans {
+		if _, ok := ids[span.ParentID]; ok && span.ParentID != span.SpanID {
+			children[span.ParentID] = append(children[span.ParentID], i)
+			continue
+		}
+		roots = append(roots, i)
+	}
+
+	var walk func(idx, depth int)
+	walk = func(idx, depth int) {
+		var span = t.Spans[idx]
+		var row = WaterfallRow{Span: span, Depth: depth, Width: 100}
+		if total > 0 {
+			row.Offset = float64(span.Start.Sub(start)) / total * 100
+			row.Width = float64(span.Duration()) / total * 100
+		}
+		rows = append(rows, row)
+
+		for _, child := range children[span.SpanID] {
+			walk(child, depth+1)
+		}
+	}
+
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	return rows
+}
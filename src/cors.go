@@ -0,0 +1,92 @@
+package django
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Nigel2392/go-django/src/core/cors"
+)
+
+type corsCSRFExemptContextKey struct{}
+
+type corsRoute struct {
+	prefix  string
+	policy  *cors.Policy
+	handler http.Handler
+}
+
+// matches reports whether the path is the prefix or lies below it,
+// i.e. "/api" matches "/api" and "/api/items", but not "/apiary".
+func (c corsRoute) matches(path string) bool {
+	if path == c.prefix {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(c.prefix, "/")+"/")
+}
+
+// corsPolicies returns the CORS policies of the [APPVAR_CORS_ROUTES] setting, longest prefix first,
+// followed by the policy of the [APPVAR_CORS] setting for all other paths.
+func (a *Application) corsPolicies() []corsRoute {
+	var (
+		routes   = ConfigGet[map[string]cors.Config](a.Settings, APPVAR_CORS_ROUTES, nil)
+		config   = ConfigGet(a.Settings, APPVAR_CORS, cors.Config{})
+		policies = make([]corsRoute, 0, len(routes)+1)
+	)
+
+	for prefix, routeConfig := range routes {
+		policies = append(policies, corsRoute{prefix: prefix, policy: cors.New(routeConfig)})
+	}
+
+	slices.SortFunc(policies, func(a, b corsRoute) int {
+		return len(b.prefix) - len(a.prefix)
+	})
+
+	if len(config.AllowedOrigins) > 0 {
+		policies = append(policies, corsRoute{prefix: "/", policy: cors.New(config)})
+	}
+
+	return policies
+}
+
+// corsMiddleware applies the CORS policy of the route group the request path belongs to.
+//
+// It runs before the router and the CSRF middleware, preflight requests are passed on to the router
+// so a route's own [cors.Middleware] answers them, the policy of the route group answers them otherwise,
+// i.e. for routes which only match GET or POST requests, see [cors.Policy.FallbackHandler].
+// Requests from origins trusted by a policy with [cors.Config.CSRFExempt] are exempt from the CSRF checks.
+//
+// Routes can override the headers set by this middleware with [cors.Middleware].
+func (a *Application) corsMiddleware(next http.Handler) http.Handler {
+	var policies = a.corsPolicies()
+	if len(policies) == 0 {
+		return next
+	}
+
+	for i := range policies {
+		policies[i].handler = policies[i].policy.FallbackHandler(next)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var idx = slices.IndexFunc(policies, func(route corsRoute) bool {
+			return route.matches(r.URL.Path)
+		})
+		if idx == -1 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if policies[idx].policy.CSRFExempt(r) {
+			r = r.WithContext(context.WithValue(r.Context(), corsCSRFExemptContextKey{}, true))
+		}
+
+		policies[idx].handler.ServeHTTP(w, r)
+	})
+}
+
+// isCORSCSRFExempt reports whether the request was exempted from the CSRF checks by the CORS middleware.
+func isCORSCSRFExempt(r *http.Request) bool {
+	var exempt, _ = r.Context().Value(corsCSRFExemptContextKey{}).(bool)
+	return exempt
+}
@@ -0,0 +1,54 @@
+package django
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+)
+
+// setupDatabases applies the pool configuration, the query timeouts and the retry configuration
+// to each of the databases listed in the [APPVAR_DATABASES] setting.
+//
+// Each database is wrapped with [drivers.WithName] so its statements are recorded under its name in the query metrics.
+func (a *Application) setupDatabases(ctx context.Context) error {
+	var (
+		names          = ConfigGet(a.Settings, APPVAR_DATABASES, []string{APPVAR_DATABASE})
+		pools          = ConfigGet[map[string]drivers.PoolConfig](a.Settings, APPVAR_DATABASE_POOLS)
+		timeouts       = ConfigGet[map[string]time.Duration](a.Settings, APPVAR_DATABASE_QUERY_TIMEOUTS)
+		retry, retryOK = ConfigGetOK[drivers.RetryConfig](a.Settings, APPVAR_DATABASE_RETRY)
+	)
+
+	if threshold, ok := ConfigGetOK[time.Duration](a.Settings, APPVAR_DATABASE_SLOW_QUERY_THRESHOLD); ok {
+		drivers.SLOW_QUERY_THRESHOLD = threshold
+	}
+
+	for _, name := range names {
+		var db, exists = ConfigGetOK[drivers.Database](a.Settings, name)
+		if !exists {
+			continue
+		}
+
+		if config, hasPool := pools[name]; hasPool {
+			if err := drivers.ConfigurePool(ctx, db, config); err != nil {
+				return fmt.Errorf("failed to configure pool for database %q: %w", name, err)
+			}
+		}
+
+		db = drivers.WithName(db, name)
+		a.Settings.Set(name, db)
+
+		// each retried attempt is limited to the timeout separately
+		if timeout, hasTimeout := timeouts[name]; hasTimeout {
+			db = drivers.WithQueryTimeout(db, timeout)
+			a.Settings.Set(name, db)
+		}
+
+		if retryOK {
+			a.Settings.Set(name, drivers.WithRetry(db, retry))
+		}
+	}
+
+	return nil
+}
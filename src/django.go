@@ -3,7 +3,6 @@ package django
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -11,9 +10,11 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"reflect"
 	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -33,7 +34,10 @@ import (
 	"github.com/Nigel2392/go-django/src/core/filesystem/mediafiles/mhttp"
 	"github.com/Nigel2392/go-django/src/core/filesystem/staticfiles"
 	"github.com/Nigel2392/go-django/src/core/filesystem/tpl"
+	"github.com/Nigel2392/go-django/src/core/health"
 	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-django/src/core/metrics"
+	"github.com/Nigel2392/go-django/src/core/trace"
 	"github.com/Nigel2392/go-django/src/core/trans"
 	"github.com/Nigel2392/go-django/src/permissions"
 	utils_text "github.com/Nigel2392/go-django/src/utils/text"
@@ -150,6 +154,10 @@ type Application struct {
 	flags       AppFlag
 	quitter     func() error
 	initialized *atomic.Bool
+	phase       *atomic.Int32
+	health      func() *health.Checker
+	logFile     *logger.RotatingFile
+	traces      *trace.MemoryExporter
 }
 
 type Option func(*Application) error
@@ -209,9 +217,12 @@ func App(opts ...Option) *Application {
 				flag.ContinueOnError,
 			),
 			initialized: new(atomic.Bool),
+			phase:       new(atomic.Int32),
 			models:      make(map[reflect.Type]string),
 		}
 
+		Global.health = sync.OnceValue(Global.newHealthChecker)
+
 		AppInstalled = Global.AppInstalled
 		Reverse = Global.Reverse
 		Static = Global.Static
@@ -311,7 +322,7 @@ func (a *Application) ServerError(err error, w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	a.Log.Errorf(
+	logger.WithContext(a.Log, r.Context()).Errorf(
 		"Error serving request (%d: %s) %s",
 		serverError.StatusCode(),
 		utils_text.Trunc(r.URL.String(), 75),
@@ -322,7 +333,7 @@ func (a *Application) ServerError(err error, w http.ResponseWriter, r *http.Requ
 }
 
 func (a *Application) veryBadServerError(err error, w http.ResponseWriter, r *http.Request) {
-	a.Log.Errorf("An unexpected error occurred: %s (%s)", err, r.URL.String())
+	logger.WithContext(a.Log, r.Context()).Errorf("An unexpected error occurred: %s (%s)", err, r.URL.String())
 	http.Error(w, "An unexpected error occurred", http.StatusInternalServerError)
 }
 
@@ -529,18 +540,12 @@ func (a *Application) Initialize() error {
 	}
 
 	if a.Log == nil {
-		a.Log = &logger.Logger{
-			Level:      logger.GetLevel(),
-			OutputTime: true,
-			Prefix:     "django",
-			WrapPrefix: logger.ColoredLogWrapper,
+		var log, err = a.newLogger()
+		if err != nil {
+			return err
 		}
 
-		a.Log.SetOutput(
-			logger.OutputAll,
-			os.Stdout,
-		)
-
+		a.Log = log
 		logger.Setup(a.Log)
 	}
 
@@ -549,12 +554,20 @@ func (a *Application) Initialize() error {
 	}
 
 	var c = context.Background()
+	if err := a.setupDatabases(c); err != nil {
+		return err
+	}
+
+	a.setupMetrics()
+	a.setupTracing()
+
 	var shouldErr bool
 	if !a.Flagged(FlagSkipChecks) {
 		shouldErr = a.logCheckMessages(
 			c, "Startup checks",
 			checks.RunCheck(c, checks.TagSettings, a, a.Settings),
 			checks.RunCheck(c, checks.TagSecurity, a, a.Settings),
+			checks.RunCheck(c, checks.TagDatabase, a, a.Settings),
 		)
 		if shouldErr {
 			return errors.New("Startup checks failed")
@@ -574,6 +587,7 @@ func (a *Application) Initialize() error {
 		),
 		a.loggerMiddleware,         // logs routes when enabled
 		ContextDataStoreMiddleware, // basically attaches a map[string]interface{} to the request.
+		a.queryStatsMiddleware,     // records queries per request when enabled
 	)
 
 	if ConfigGet(a.Settings, APPVAR_RECOVERER, true) {
@@ -754,6 +768,9 @@ func (a *Application) Initialize() error {
 					cookie, nosurf.Token(req),
 				))
 			},
+			"csp_nonce": func() string {
+				return ctx.CSPNonce(req.Context())
+			},
 			"request": func() *http.Request {
 				return req
 			},
@@ -786,6 +803,8 @@ func (a *Application) Initialize() error {
 
 	a.Commands.Register(sqlShellCommand)
 	a.Commands.Register(runChecksCommand)
+	a.Commands.Register(diffSettingsCommand)
+	a.Commands.Register(healthCheckCommand)
 
 	for _, appFunc := range a.apps {
 		var app, err = appFunc()
@@ -862,6 +881,12 @@ func (a *Application) Initialize() error {
 			return errors.Wrapf(err, "Error initializing app %s", app.Name())
 		}
 
+		if metricsApp, ok := app.(MetricsAppConfig); ok {
+			if err := metricsApp.RegisterMetrics(metrics.Default); err != nil {
+				return errors.Wrapf(err, "Error registering metrics for app %s", app.Name())
+			}
+		}
+
 		var commands = app.Commands()
 		for _, cmd := range commands {
 			a.Commands.Register(cmd)
@@ -934,6 +959,9 @@ func (a *Application) Initialize() error {
 		// get assigned a middleware to set up the app- specific request context variables.
 		h.Value.BuildRouting(a.Mux.Namespace(mux.NamespaceOptions{
 			OnRouteServe: func(r *http.Request) *http.Request {
+				setRequestMetricsApp(r, h.Value.Name())
+				logger.AddContextAttrs(r.Context(), "app", h.Value.Name())
+				trace.SpanFromContext(r.Context()).SetAttributes("app", h.Value.Name())
 				return r.WithContext(ContextWithApp(
 					r.Context(), h.Value,
 				))
@@ -1010,10 +1038,26 @@ func (a *Application) middlewareBuiltins() []func(http.Handler) http.Handler {
 			for _, hook := range hooks {
 				hook(a, hnd)
 			}
+
+			// nosurf holds a single exempt func, a copy of the handler keeps
+			// the exemptions of the hooks so they are chained with the CORS exemption.
+			var hooked = *hnd
+			hnd.ExemptFunc(func(r *http.Request) bool {
+				return isCORSCSRFExempt(r) || hooked.IsExempt(r)
+			})
 			return hnd
 		})
 	}
 
+	// preflight requests are answered before the CSRF checks and the router
+	mw = append(mw, a.corsMiddleware)
+
+	// the security headers are set on CSRF failures too
+	mw = append(mw, a.securityMiddleware)
+
+	// the health, metrics and trace debug endpoints are served before any other middleware
+	mw = append(mw, a.healthMiddleware, a.metricsMiddleware, a.tracingMiddleware)
+
 	return mw
 }
 
@@ -1036,78 +1080,28 @@ func (a *Application) Serve() error {
 	//	})
 
 	var (
-		HOST        = ConfigGet(a.Settings, APPVAR_HOST, "localhost")
-		PORT        = ConfigGet(a.Settings, APPVAR_PORT, "8080")
-		TLS_PORT    = ConfigGet(a.Settings, APPVAR_TLS_PORT, "")
-		TLSCert     = ConfigGet[string](a.Settings, APPVAR_TLS_CERT)
-		TLSKey      = ConfigGet[string](a.Settings, APPVAR_TLS_KEY)
-		TLSConfig   = ConfigGet[*tls.Config](a.Settings, APPVAR_TLS_CONFIG)
-		addr_http   = fmt.Sprintf("%s:%s", HOST, PORT)
-		addr_https  = fmt.Sprintf("%s:%s", HOST, TLS_PORT)
-		server_http = &http.Server{
-			Addr:      addr_http,
-			Handler:   httpHandler,
-			TLSConfig: TLSConfig,
-		}
-		server_https = &http.Server{
-			Addr:      addr_https,
-			Handler:   httpHandler,
-			TLSConfig: TLSConfig,
-		}
+		HOST            = ConfigGet(a.Settings, APPVAR_HOST, "localhost")
+		PORT            = ConfigGet(a.Settings, APPVAR_PORT, "8080")
+		TLS_PORT        = ConfigGet(a.Settings, APPVAR_TLS_PORT, "")
+		TLSCert         = ConfigGet[string](a.Settings, APPVAR_TLS_CERT)
+		TLSKey          = ConfigGet[string](a.Settings, APPVAR_TLS_KEY)
+		addr_http       = fmt.Sprintf("%s:%s", HOST, PORT)
+		addr_https      = fmt.Sprintf("%s:%s", HOST, TLS_PORT)
+		server_http     = a.newServer(addr_http, httpHandler)
+		server_https    = a.newServer(addr_https, httpHandler)
 		listening_https = TLSCert != "" && TLSKey != "" && TLS_PORT != ""
 		listening_http  = PORT != "" && PORT != "0"
+		servers         = make([]*http.Server, 0, 2)
 	)
 
+	a.setPhase(PhaseStarting)
+
 	for _, h := range goldcrest.Get[DjangoHook](HOOK_SERVER_STARTUP) {
 		if err := h(a); err != nil {
 			return err
 		}
 	}
 
-	a.quitter = func() (err error) {
-		var (
-			err1, err2 error
-		)
-
-		if listening_https {
-			a.Log.Logf(logger.INF, "Shutting down http server")
-			err1 = server_http.Shutdown(context.Background())
-		}
-
-		if err1 != nil {
-			err = errors.Wrap(err1, "Error closing http server")
-			// err1 is now err
-		}
-
-		if listening_http {
-			a.Log.Logf(logger.INF, "Shutting down https server")
-			err2 = server_https.Shutdown(context.Background())
-		}
-
-		if err2 != nil {
-			// err = errors.Wrap(err2, "Error closing https server")
-			if err != nil {
-				err = goErrs.Join(err, err2)
-			} else {
-				err = err2
-			}
-		}
-
-		for _, hook := range goldcrest.Get[DjangoHook](HOOK_SERVER_SHUTDOWN) {
-			e := hook(a) // func(*Application) error
-			if e != nil {
-				if err != nil {
-					err = goErrs.Join(err, e)
-				} else {
-					err = e
-				}
-			}
-		}
-
-		a.quitter = nil
-		return err
-	}
-
 	var (
 		chanCt = 0
 		errCh  = make(chan error, 2)
@@ -1115,6 +1109,7 @@ func (a *Application) Serve() error {
 
 	if listening_https {
 		chanCt++
+		servers = append(servers, server_https)
 
 		a.Log.Logf(logger.INF, "Listening on https://%s (TLS)", addr_https)
 
@@ -1122,22 +1117,19 @@ func (a *Application) Serve() error {
 			a.Log.Fatalf(1, "TLS_PORT must be set to a valid port number, got %q", TLS_PORT)
 		}
 
-		// Start https server, if it exits close http server
 		go func() {
 			errCh <- server_https.ListenAndServeTLS(TLSCert, TLSKey)
-			server_http.Close()
 		}()
 	}
 
 	if listening_http {
 		chanCt++
+		servers = append(servers, server_http)
 
 		a.Log.Logf(logger.INF, "Listening on http://%s", addr_http)
 
-		// Start http server, if it exits close https server
 		go func() {
 			errCh <- server_http.ListenAndServe()
-			server_https.Close()
 		}()
 	}
 
@@ -1145,13 +1137,36 @@ func (a *Application) Serve() error {
 		a.Log.Fatalf(1, "Server cannot be started, no valid ports found: %q, %q", PORT, TLS_PORT)
 	}
 
-	// Wait for both servers to exit
+	a.quitter = a.newQuitter(servers)
+	a.setPhase(PhaseServing)
+
+	var sigCh = make(chan os.Signal, 1)
+	signal.Notify(sigCh, ConfigGet(a.Settings, APPVAR_SHUTDOWN_SIGNALS, DefaultShutdownSignals)...)
+	defer signal.Stop(sigCh)
+
+	// Wait for all servers to exit, the servers are shut down gracefully
+	// when a shutdown signal is received or if any of the servers fails.
 	var err error
-	for i := 0; i < chanCt; i++ {
-		if e := <-errCh; e != nil {
-			err = e
+	for chanCt > 0 {
+		select {
+		case sig := <-sigCh:
+			a.Log.Logf(logger.INF, "Received %s, shutting down gracefully", sig)
+			go a.Quit()
+
+		case e := <-errCh:
+			chanCt--
+			if e != nil && !goErrs.Is(e, http.ErrServerClosed) {
+				err = goErrs.Join(err, e)
+				go a.Quit()
+			}
 		}
 	}
 
+	// The servers exit as soon as the shutdown starts, wait for in-flight requests
+	// and the shutdown hooks to finish, or shut down if the servers exited on their own.
+	if e := a.Quit(); e != nil {
+		err = goErrs.Join(err, e)
+	}
+
 	return err
 }
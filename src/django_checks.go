@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"os"
 	"reflect"
+	"time"
 
 	"github.com/Nigel2392/go-django/queries/src/drivers"
 	"github.com/Nigel2392/go-django/src/core/checks"
@@ -96,30 +98,16 @@ func typeStr(v any) []byte {
 
 var _ = checks.Register(checks.TagSettings, func(ctx context.Context, app *Application, settings Settings) (messages []checks.Message) {
 
+	// Settings which can be loaded from files and environment variables
+	// are registered with [DefineSetting] and checked by [checkSettingDefinitions].
 	var settingsChecks = []_settingsCheck{
-		_settingsCheckImpl[bool]{setting: APPVAR_DEBUG},
-		_settingsCheckImpl[[]string]{setting: APPVAR_ALLOWED_HOSTS, isPresent: func(setting []string) []string {
-			if len(setting) > 0 {
-				return nil
-			}
-			return []string{"ALLOWED_HOSTS setting must not be empty"}
-		}},
-		_settingsCheckImpl[bool]{setting: APPVAR_RECOVERER},
-		_settingsCheckImpl[string]{setting: APPVAR_HOST},
-		_settingsCheckImpl[string]{setting: APPVAR_PORT},
-		_settingsCheckImpl[string]{setting: APPVAR_STATIC_URL},
-		_settingsCheckImpl[string]{setting: APPVAR_TLS_PORT},
-		_settingsCheckImpl[string]{setting: APPVAR_TLS_CERT},
-		_settingsCheckImpl[string]{setting: APPVAR_TLS_KEY},
 		_settingsCheckImpl[*tls.Config]{setting: APPVAR_TLS_CONFIG},
+		_settingsCheckImpl[[]os.Signal]{setting: APPVAR_SHUTDOWN_SIGNALS},
 		_settingsCheckImpl[drivers.Database]{setting: APPVAR_DATABASE},
-		_settingsCheckImpl[bool]{setting: APPVAR_CONTINUE_AFTER_COMMANDS},
-		_settingsCheckImpl[bool]{setting: APPVAR_ROUTE_LOGGING_ENABLED},
-		_settingsCheckImpl[bool]{setting: APPVAR_STATIC_ROUTE_LOGGING_ENABLED},
-		_settingsCheckImpl[bool]{setting: APPVAR_REQUESTS_PROXIED},
+		_settingsCheckImpl[map[string]drivers.PoolConfig]{setting: APPVAR_DATABASE_POOLS},
+		_settingsCheckImpl[drivers.RetryConfig]{setting: APPVAR_DATABASE_RETRY},
+		_settingsCheckImpl[map[string]time.Duration]{setting: APPVAR_DATABASE_QUERY_TIMEOUTS},
 		_settingsCheckImpl[*scs.SessionManager]{setting: APPVAR_SESSION_MANAGER},
-		_settingsCheckImpl[bool]{setting: APPVAR_DISABLE_NOSURF},
-		_settingsCheckImpl[string]{setting: APPVAR_TRANSLATIONS_DEFAULT_LOCALE},
 	}
 
 	for _, check := range settingsChecks {
@@ -174,6 +162,78 @@ var _ = checks.Register(checks.TagSettings, func(ctx context.Context, app *Appli
 	return messages
 })
 
+var _ = checks.Register(checks.TagSettings, checkSettingDefinitions)
+
+// checkSettingDefinitions checks the settings registered with [DefineSetting].
+//
+// Required settings must be present, and present settings must be of the registered
+// type and pass the validators of the setting.
+func checkSettingDefinitions(ctx context.Context, app *Application, settings Settings) (messages []checks.Message) {
+	for _, def := range SettingDefinitions() {
+		var (
+			key     = def.Key()
+			checkID = fmt.Sprintf("settings.matches.%s", key)
+			hint    = fmt.Sprintf("please set %q in the settings to the correct type %s", key, typeStr(def.Type()))
+		)
+
+		var value, present = settings.Get(key)
+		if !present || value == nil {
+			if def.IsRequired() {
+				messages = append(messages, checks.Critical(
+					checkID,
+					fmt.Sprintf("setting %q was not found in the settings", key),
+					nil, hint,
+				))
+			}
+			continue
+		}
+
+		// strings are parsed to the type of the setting by [ConfigGet],
+		// any other value must be of the setting's type.
+		var converted, err = def.Convert(value)
+		if _, isStr := value.(string); err == nil && !isStr && reflect.TypeOf(value) != def.Type() {
+			err = fmt.Errorf("setting %q (%s) is not of type %s", key, typeStr(value), typeStr(def.Type()))
+		}
+
+		if err != nil {
+			messages = append(messages, checks.Critical(
+				checkID, err.Error(), nil, hint,
+			))
+			continue
+		}
+
+		if err = def.Validate(converted); err != nil {
+			messages = append(messages, checks.Critical(
+				fmt.Sprintf("settings.invalid.%s", key),
+				fmt.Sprintf("setting %q is invalid: %v", key, err),
+				nil, def.Description(),
+			))
+		}
+	}
+	return messages
+}
+
+var _ = checks.Register(checks.TagDatabase, func(ctx context.Context, app *Application, settings Settings) (messages []checks.Message) {
+	var timeout = ConfigGet(settings, APPVAR_DATABASE_PING_TIMEOUT, drivers.DefaultPingTimeout)
+	for _, name := range ConfigGet(settings, APPVAR_DATABASES, []string{APPVAR_DATABASE}) {
+		var db, ok = ConfigGetOK[drivers.Database](settings, name)
+		if !ok {
+			continue
+		}
+
+		var health = drivers.CheckHealth(ctx, db, timeout)
+		if !health.Healthy {
+			messages = append(messages, checks.Criticalf(
+				fmt.Sprintf("database.unreachable.%s", name),
+				"database %q is unreachable: %v", nil,
+				"make sure the database server is running and the connection settings are correct",
+				name, health.Error,
+			))
+		}
+	}
+	return messages
+})
+
 var _ = checks.Register(checks.TagCommands, func(ctx context.Context, app *Application, settings Settings, commands []command.Command) (messages []checks.Message) {
 	for _, cmd := range commands {
 		if checker, ok := cmd.(checks.Checker); ok {
@@ -0,0 +1,255 @@
+package django
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/src/core/command"
+	"github.com/Nigel2392/go-django/src/core/filesystem/mediafiles"
+	"github.com/Nigel2392/go-django/src/core/health"
+	"github.com/Nigel2392/go-django/src/core/mail"
+	"github.com/Nigel2392/goldcrest"
+	"github.com/pkg/errors"
+)
+
+// HealthProbesHook returns the health probes contributed by an app, see [HOOK_HEALTH_PROBES].
+type HealthProbesHook func(app *Application) []health.Probe
+
+// HOOK_HEALTH_PROBES is ran once when the health checker of the application is first used,
+// the probes returned by the hooks are part of the liveness and readiness endpoints and the healthcheck command.
+const HOOK_HEALTH_PROBES = "django.HealthProbes"
+
+const (
+	// The default URL of the liveness endpoint.
+	DefaultHealthLivenessURL = "/healthz"
+
+	// The default URL of the readiness endpoint.
+	DefaultHealthReadinessURL = "/readyz"
+
+	// The duration the result of the media backend probe is cached for, it writes a file to the backend.
+	mediaProbeCacheFor = time.Minute
+)
+
+// HealthChecker returns the health checker of the application.
+//
+// The checker is built the first time it is used from the built-in probes
+// and the probes returned by the [HOOK_HEALTH_PROBES] hooks.
+func (a *Application) HealthChecker() *health.Checker {
+	return a.health()
+}
+
+func (a *Application) newHealthChecker() *health.Checker {
+	var timeout = ConfigGet(a.Settings, APPVAR_HEALTH_PROBE_TIMEOUT, health.DefaultTimeout)
+	var probes = a.builtinHealthProbes()
+	for _, hook := range goldcrest.Get[HealthProbesHook](HOOK_HEALTH_PROBES) {
+		probes = append(probes, hook(a)...)
+	}
+
+	for i := range probes {
+		if probes[i].Timeout <= 0 {
+			probes[i].Timeout = timeout
+		}
+	}
+
+	return health.NewChecker(probes...)
+}
+
+// builtinHealthProbes returns the liveness probes of the server and the probes for the
+// configured databases, the default mail backend and the default media backend.
+func (a *Application) builtinHealthProbes() []health.Probe {
+	var probes = []health.Probe{{
+		Name:     "server",
+		Liveness: true,
+		Check: func(ctx context.Context) error {
+			if a.Phase() == PhaseStopped {
+				return errors.New("server has stopped")
+			}
+			return nil
+		},
+	}}
+
+	if limit := ConfigGet(a.Settings, APPVAR_HEALTH_MAX_GOROUTINES, 0); limit > 0 {
+		probes = append(probes, health.Probe{
+			Name:     "goroutines",
+			Liveness: true,
+			Check: func(ctx context.Context) error {
+				if n := runtime.NumGoroutine(); n > limit {
+					return fmt.Errorf("%d goroutines are running, more than the limit of %d", n, limit)
+				}
+				return nil
+			},
+		})
+	}
+
+	for _, name := range ConfigGet(a.Settings, APPVAR_DATABASES, []string{APPVAR_DATABASE}) {
+		var db, ok = ConfigGetOK[drivers.Database](a.Settings, name)
+		if !ok {
+			continue
+		}
+
+		probes = append(probes, health.Probe{
+			Name: fmt.Sprintf("database.%s", name),
+			Check: func(ctx context.Context) error {
+				return drivers.Ping(ctx, db, 0)
+			},
+		})
+	}
+
+	// the mail server is pinged on its own connection, the backend itself is never opened or closed by the probe
+	if backend, ok := mail.Default().(mail.PingableEmailBackend); ok {
+		probes = append(probes, health.Probe{
+			Name:  "mail",
+			Check: backend.Ping,
+		})
+	}
+
+	if mediafiles.GetDefault() != nil && ConfigGet(a.Settings, APPVAR_HEALTH_MEDIA_PROBE, true) {
+		probes = append(probes, health.Probe{
+			Name:     "media",
+			CacheFor: mediaProbeCacheFor,
+			Check: func(ctx context.Context) error {
+				var backend = mediafiles.GetDefault()
+				var path, err = backend.Save(".healthcheck", bytes.NewReader([]byte("ok")))
+				if err != nil {
+					return errors.Wrap(err, "media backend is not writable")
+				}
+				return backend.Delete(path)
+			},
+		})
+	}
+
+	return probes
+}
+
+type healthResponse struct {
+	health.Report
+	Phase string `json:"phase"`
+}
+
+// healthMiddleware serves the liveness and readiness endpoints.
+//
+// The endpoints are served before any other middleware, so they are not
+// subject to the allowed hosts, CSRF protection or request logging.
+func (a *Application) healthMiddleware(next http.Handler) http.Handler {
+	if !ConfigGet(a.Settings, APPVAR_HEALTH_ENDPOINTS, true) {
+		return next
+	}
+
+	var (
+		livenessURL  = ConfigGet(a.Settings, APPVAR_HEALTH_LIVENESS_URL, DefaultHealthLivenessURL)
+		readinessURL = ConfigGet(a.Settings, APPVAR_HEALTH_READINESS_URL, DefaultHealthReadinessURL)
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch r.URL.Path {
+		case livenessURL:
+			a.serveHealth(w, r, a.livenessReport(r.Context()))
+		case readinessURL:
+			a.serveHealth(w, r, a.readinessReport(r.Context()))
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// livenessReport runs the liveness probes, the built-in "server" probe fails once the server has stopped.
+func (a *Application) livenessReport(ctx context.Context) health.Report {
+	return a.HealthChecker().Liveness(ctx)
+}
+
+// readinessReport runs all probes, the application is not ready while the server is starting or draining.
+//
+// The probes are not run when the application is not ready.
+func (a *Application) readinessReport(ctx context.Context) health.Report {
+	if !a.Ready() {
+		return health.Report{
+			Status: health.StatusFailing,
+			Probes: []health.Result{},
+		}
+	}
+	return a.HealthChecker().Readiness(ctx)
+}
+
+// serveHealth writes the report, the errors of failing probes are logged and only included in the response in debug mode.
+//
+// The errors can contain connection strings, hostnames or other details of the infrastructure,
+// which should not be exposed on an endpoint which is served without authentication.
+func (a *Application) serveHealth(w http.ResponseWriter, r *http.Request, report health.Report) {
+	var status = http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+
+	if !ConfigGet(a.Settings, APPVAR_DEBUG, true) {
+		var probes = make([]health.Result, len(report.Probes))
+		for i, result := range report.Probes {
+			if result.Error != "" {
+				a.Log.Warnf("Health probe %q failed: %s", result.Name, result.Error)
+				result.Error = ""
+			}
+			probes[i] = result
+		}
+		report.Probes = probes
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	var err = json.NewEncoder(w).Encode(healthResponse{
+		Report: report,
+		Phase:  a.Phase().String(),
+	})
+	if err != nil {
+		a.Log.Errorf("Failed to write health report: %v", err)
+	}
+}
+
+var healthCheckCommand = &command.Cmd[bool]{
+	ID:   "healthcheck",
+	Desc: "Run the health probes of the application, i.e. to verify a deployment can reach its services",
+	FlagFunc: func(m command.Manager, stored *bool, f *flag.FlagSet) error {
+		f.BoolVar(stored, "liveness", false, "Only run the liveness probes")
+		return nil
+	},
+	Execute: func(m command.Manager, liveness bool, args []string) error {
+		var checker = Global.HealthChecker()
+		var report health.Report
+		if liveness {
+			report = checker.Liveness(context.Background())
+		} else {
+			report = checker.Readiness(context.Background())
+		}
+
+		for _, result := range report.Probes {
+			if result.Healthy() {
+				m.Logf("[%s] %s (%s)\n", result.Status, result.Name, result.Latency)
+				continue
+			}
+			m.Logf("[%s] %s (%s): %s\n", result.Status, result.Name, result.Latency, result.Error)
+		}
+
+		if !report.Healthy() {
+			return errors.New("Health checks failed")
+		}
+
+		m.Logf("All %d health probes passed\n", len(report.Probes))
+		return command.ErrShouldExit
+	},
+}
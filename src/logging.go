@@ -0,0 +1,140 @@
+package django
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-django/src/core/trace"
+	"github.com/pkg/errors"
+)
+
+// The formats of the application logger, see [APPVAR_LOG_FORMAT].
+const (
+	LogFormatText   = "text"
+	LogFormatJSON   = "json"
+	LogFormatLogfmt = "logfmt"
+)
+
+// The default number of rotated log files to keep, see [APPVAR_LOG_FILE_MAX_BACKUPS].
+const DefaultLogFileMaxBackups = 5
+
+// RequestIDHeader is the header the request ID is taken from and written to.
+const RequestIDHeader = "X-Request-ID"
+
+// The maximum length of a request ID taken from the [RequestIDHeader], longer IDs are replaced.
+const maxRequestIDLength = 128
+
+type requestIDContextKey struct{}
+
+// newLogger creates the application logger from the [APPVAR_LOG_FORMAT] and [APPVAR_LOG_FILE] settings.
+//
+// The json and logfmt loggers are also installed as the default [slog] logger,
+// so records logged with [slog.InfoContext] and the like carry the request attributes.
+func (a *Application) newLogger() (logger.Log, error) {
+	var (
+		format           = ConfigGet(a.Settings, APPVAR_LOG_FORMAT, LogFormatText)
+		path             = ConfigGet(a.Settings, APPVAR_LOG_FILE, "")
+		out    io.Writer = os.Stdout
+	)
+
+	if path != "" {
+		var file, err = logger.NewRotatingFile(
+			path,
+			ConfigGet(a.Settings, APPVAR_LOG_FILE_MAX_SIZE, logger.DefaultMaxFileSize),
+			ConfigGet(a.Settings, APPVAR_LOG_FILE_MAX_BACKUPS, DefaultLogFileMaxBackups),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error opening log file")
+		}
+		a.logFile = file
+		out = file
+	}
+
+	switch format {
+	case LogFormatJSON, LogFormatLogfmt:
+		var log *logger.SlogLogger
+		if format == LogFormatJSON {
+			log = logger.NewJSONLogger(out, logger.GetLevel())
+		} else {
+			log = logger.NewLogfmtLogger(out, logger.GetLevel())
+		}
+		slog.SetDefault(slog.New(log.Handler()))
+		return log.NameSpace("django"), nil
+	}
+
+	var log = &logger.Logger{
+		Level:      logger.GetLevel(),
+		OutputTime: true,
+		Prefix:     "django",
+	}
+
+	// colors are only written to the terminal
+	if path == "" {
+		log.WrapPrefix = logger.ColoredLogWrapper
+	}
+
+	log.SetOutput(logger.OutputAll, out)
+	return log, nil
+}
+
+// RequestID returns the ID of the request, it is set by the request logging middleware.
+//
+// The ID is taken from the [RequestIDHeader] of the request if present, otherwise it is generated.
+func RequestID(r *http.Request) string {
+	var id, _ = r.Context().Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// withRequestAttrs returns the request with the request ID and the remote address stored in its context,
+// they are added to each message logged with [logger.Ctx] while the request is served.
+// When the request is traced, the trace ID is added as well and the request ID is added to the request span.
+//
+// The request ID is also written to the [RequestIDHeader] of the response.
+func (a *Application) withRequestAttrs(w http.ResponseWriter, r *http.Request, remoteAddr string) *http.Request {
+	var id = r.Header.Get(RequestIDHeader)
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+
+	w.Header().Set(RequestIDHeader, id)
+
+	var args = []any{
+		"request_id", id,
+		"remote_ip", remoteAddr,
+	}
+
+	if span := trace.SpanFromContext(r.Context()); span != nil {
+		span.SetAttributes("request_id", id)
+		args = append(args, "trace_id", span.SpanContext().TraceID.String())
+	}
+
+	var ctx = context.WithValue(r.Context(), requestIDContextKey{}, id)
+	ctx = logger.ContextWithAttrs(ctx, args...)
+	return r.WithContext(ctx)
+}
+
+// validRequestID reports whether the request ID taken from a header can be used,
+// the ID must be printable ASCII so it cannot be used to forge log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b = make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
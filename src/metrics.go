@@ -0,0 +1,270 @@
+package django
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	core "github.com/Nigel2392/go-django/src/core"
+	"github.com/Nigel2392/go-django/src/core/checks"
+	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-django/src/core/metrics"
+	"github.com/Nigel2392/go-django/src/core/trace"
+	"github.com/Nigel2392/go-signals"
+	"github.com/Nigel2392/mux"
+)
+
+// MetricsAppConfig can be implemented by an [AppConfig] to register its own metrics.
+//
+// RegisterMetrics is called with [metrics.Default] after the app has been initialized,
+// the metrics registered in it are exposed by the metrics endpoint.
+type MetricsAppConfig interface {
+	AppConfig
+	RegisterMetrics(registry *metrics.Registry) error
+}
+
+// The default URL of the metrics endpoint.
+const DefaultMetricsURL = "/metrics"
+
+// The label value for requests which were not served by a route of an app, or by a named route.
+const metricsNoApp = "none"
+
+var requestMetricsContextKey = &struct{ name string }{"django.requestMetrics"}
+
+var (
+	httpRequestsTotal = metrics.Default.Counter(metrics.Opts{
+		Name:   "django_http_requests_total",
+		Help:   "The number of requests served, by app, route, method and status code.",
+		Labels: []string{"app", "route", "method", "status"},
+	})
+	httpRequestDuration = metrics.Default.Histogram(metrics.Opts{
+		Name:   "django_http_request_duration_seconds",
+		Help:   "The time taken to serve a request, by app, route and method.",
+		Labels: []string{"app", "route", "method"},
+	})
+
+	listenRequestMetrics sync.Once
+)
+
+// requestMetrics holds the state of a single request for the request metrics.
+//
+// It is stored in the request context before the request is served,
+// the app is set once the request is routed to a route of an app
+// and the route once the route sets its name with [SetRouteName].
+type requestMetrics struct {
+	app   string
+	route string
+	start time.Time
+}
+
+func setRequestMetricsApp(r *http.Request, app string) {
+	if m, ok := r.Context().Value(requestMetricsContextKey).(*requestMetrics); ok {
+		m.app = app
+	}
+}
+
+// SetRouteName sets the name of the route which serves the request, it is the route label
+// of the request metrics and the route attribute of the request logs and the request span.
+//
+// The router does not expose the route a request was matched to, so the name is set by the route itself,
+// usually with the [RouteNameMiddleware] of the route. Requests of routes without a name are recorded with the route "none".
+func SetRouteName(r *http.Request, name string) {
+	if m, ok := r.Context().Value(requestMetricsContextKey).(*requestMetrics); ok {
+		m.route = name
+	}
+	logger.AddContextAttrs(r.Context(), "route", name)
+	trace.SpanFromContext(r.Context()).SetAttributes("route", name)
+}
+
+// RouteNameMiddleware returns a route middleware which sets the name of the route with [SetRouteName].
+//
+//	var route = mux.Get("/posts/<<id>>", mux.NewHandler(viewPost), "posts:detail")
+//	route.Use(django.RouteNameMiddleware("posts:detail"))
+func RouteNameMiddleware(name string) mux.Middleware {
+	return func(next mux.Handler) mux.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			SetRouteName(r, name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// setupMetrics records the request metrics with the request signals if metrics are enabled.
+//
+// The [metrics.Default] registry is only enabled if metrics are enabled, so the built-in
+// instrumentation of the queries, mail, sessions and caches records nothing otherwise.
+// The signals are only listened to once, even if the application is initialized multiple times.
+func (a *Application) setupMetrics() {
+	var enabled = ConfigGet(a.Settings, APPVAR_METRICS, false)
+	metrics.Default.SetEnabled(enabled)
+	if !enabled {
+		return
+	}
+
+	listenRequestMetrics.Do(func() {
+		core.SIGNAL_BEFORE_REQUEST.Listen(context.Background(), func(ctx context.Context, s signals.Signal[*core.HttpSignal], signal *core.HttpSignal) error {
+			var m = &requestMetrics{app: metricsNoApp, route: metricsNoApp, start: time.Now()}
+			signal.R = signal.R.WithContext(context.WithValue(
+				signal.R.Context(), requestMetricsContextKey, m,
+			))
+			if _, ok := signal.W.(*statusResponseWriter); !ok {
+				signal.W = &statusResponseWriter{ResponseWriter: signal.W, status: http.StatusOK}
+			}
+			return nil
+		})
+
+		core.SIGNAL_AFTER_REQUEST.Listen(context.Background(), func(ctx context.Context, s signals.Signal[*core.HttpSignal], signal *core.HttpSignal) error {
+			var m, ok = signal.R.Context().Value(requestMetricsContextKey).(*requestMetrics)
+			if !ok {
+				return nil
+			}
+
+			var status = http.StatusOK
+			if w, ok := signal.W.(*statusResponseWriter); ok {
+				status = w.status
+			}
+
+			httpRequestsTotal.Inc(m.app, m.route, signal.R.Method, strconv.Itoa(status))
+			httpRequestDuration.ObserveSince(m.start, m.app, m.route, signal.R.Method)
+			return nil
+		})
+	})
+}
+
+// metricsMiddleware serves the metrics endpoint.
+//
+// Like the health endpoints, the endpoint is served before any other middleware
+// and is not counted in the request metrics.
+// Only the addresses in [APPVAR_METRICS_ALLOWED_IPS] may request it if the setting is not empty,
+// and only with the bearer token of [APPVAR_METRICS_TOKEN] if it is set.
+func (a *Application) metricsMiddleware(next http.Handler) http.Handler {
+	if !ConfigGet(a.Settings, APPVAR_METRICS, false) {
+		return next
+	}
+
+	var (
+		metricsURL = ConfigGet(a.Settings, APPVAR_METRICS_URL, DefaultMetricsURL)
+		token      = ConfigGet(a.Settings, APPVAR_METRICS_TOKEN, "")
+		proxied    = ConfigGet(a.Settings, APPVAR_REQUESTS_PROXIED, false)
+	)
+
+	// invalid addresses are reported by the settings check
+	var allowed, _ = parseIPPrefixes(ConfigGet[[]string](a.Settings, APPVAR_METRICS_ALLOWED_IPS, nil))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != metricsURL || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(allowed) > 0 && !ipAllowed(allowed, mux.GetIP(r, proxied)) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		if token != "" && !bearerTokenMatches(r, token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		metrics.Default.ServeHTTP(w, r)
+	})
+}
+
+var _ = checks.Register(checks.TagSecurity, func(ctx context.Context, app *Application, settings Settings) []checks.Message {
+	if !ConfigGet(settings, APPVAR_METRICS, false) ||
+		len(ConfigGet[[]string](settings, APPVAR_METRICS_ALLOWED_IPS, nil)) > 0 ||
+		ConfigGet(settings, APPVAR_METRICS_TOKEN, "") != "" {
+		return nil
+	}
+
+	return []checks.Message{checks.Warning(
+		"metrics.unprotected",
+		"the metrics endpoint can be requested by anyone",
+		nil,
+		fmt.Sprintf("set %q or %q to restrict access to the metrics endpoint", APPVAR_METRICS_ALLOWED_IPS, APPVAR_METRICS_TOKEN),
+	)}
+})
+
+// parseIPPrefixes parses IP addresses and CIDR ranges, a single address is parsed as a range of one address.
+func parseIPPrefixes(values []string) ([]netip.Prefix, error) {
+	var prefixes = make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if strings.Contains(value, "/") {
+			var prefix, err = netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR range %q: %w", value, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		var addr, err = netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address %q: %w", value, err)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+func ipAllowed(prefixes []netip.Prefix, ip string) bool {
+	var addr, err = netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerTokenMatches(r *http.Request, token string) bool {
+	var value, ok = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(strings.TrimSpace(value)), []byte(token)) == 1
+}
+
+// statusResponseWriter records the status code of the response for the request metrics and the request span.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusResponseWriter) Flush() {
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
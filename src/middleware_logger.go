@@ -19,17 +19,30 @@ import (
 // The message might be prefixed and / or suffixed with additional information.
 //
 // If the request is a static route request, it will log the request with a debug level.
+//
+// Regardless of whether request logging is enabled, the request ID and the remote address are
+// stored in the context of the request, see [RequestID] and [logger.Ctx].
 func (a *Application) loggerMiddleware(next mux.Handler) mux.Handler {
-	var logggingEnabled = ConfigGet(a.Settings, APPVAR_ROUTE_LOGGING_ENABLED,
-		ConfigGet(a.Settings, APPVAR_DEBUG, true),
+	var (
+		proxied         = ConfigGet(a.Settings, APPVAR_REQUESTS_PROXIED, false)
+		logggingEnabled = ConfigGet(a.Settings, APPVAR_ROUTE_LOGGING_ENABLED,
+			ConfigGet(a.Settings, APPVAR_DEBUG, true),
+		)
 	)
 	if !logggingEnabled {
-		return next
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, a.withRequestAttrs(w, r, mux.GetIP(r, proxied)))
+		})
 	}
 
 	var log = a.Log.NameSpace("HTTP")
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var startTime = time.Now()
+		var (
+			startTime  = time.Now()
+			remoteAddr = mux.GetIP(r, proxied)
+		)
+
+		r = a.withRequestAttrs(w, r, remoteAddr)
 
 		next.ServeHTTP(w, r)
 
@@ -42,13 +55,8 @@ func (a *Application) loggerMiddleware(next mux.Handler) mux.Handler {
 		}
 
 		var (
-			timeTaken  = time.Since(startTime)
-			remoteAddr = mux.GetIP(
-				r, ConfigGet(
-					a.Settings, APPVAR_REQUESTS_PROXIED, false,
-				),
-			)
-			pathBuf = new(strings.Builder)
+			timeTaken = time.Since(startTime)
+			pathBuf   = new(strings.Builder)
 		)
 
 		pathBuf.WriteString(r.URL.Path)
@@ -58,7 +66,7 @@ func (a *Application) loggerMiddleware(next mux.Handler) mux.Handler {
 			pathBuf.WriteString(r.URL.RawQuery)
 		}
 
-		log.Logf(
+		logger.WithContext(log, r.Context()).Logf(
 			logLevel,
 			"%s %s %s %s",
 			logger.Colorize(
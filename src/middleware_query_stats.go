@@ -0,0 +1,223 @@
+package django
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/mux"
+)
+
+const (
+	// HeaderQueryCount is the response header containing the number of queries executed for the request.
+	HeaderQueryCount = "X-Query-Count"
+
+	// HeaderQueryTime is the response header containing the total time spent executing queries for the request.
+	HeaderQueryTime = "X-Query-Time"
+
+	// HeaderQueryDuplicates is the response header containing the number of duplicated query shapes.
+	HeaderQueryDuplicates = "X-Query-Duplicates"
+
+	// HeaderQueryCacheHits is the response header containing the number of queryset results served from the cache.
+	HeaderQueryCacheHits = "X-Query-Cache-Hits"
+
+	// HeaderQueryCacheMisses is the response header containing the number of cacheable queryset results retrieved from the database.
+	HeaderQueryCacheMisses = "X-Query-Cache-Misses"
+)
+
+// queryStatsMiddleware records all queries executed with the request's context.
+//
+// It is only enabled if [APPVAR_QUERY_STATS] is true.
+//
+// The number of queries, the time spent executing them and the number of duplicated
+// query shapes (see [drivers.QueryInformation.Duplicates]) are added to the response headers.
+//
+// Duplicated queries are logged as a warning, they often signal N+1 queries through lazy relations.
+//
+// If [APPVAR_DEBUG] and [APPVAR_QUERY_STATS_PANEL] are true, a panel listing the queries
+// is added to the end of HTML responses.
+//
+// Only HTML responses are buffered and only when the panel is shown, the headers of other responses
+// are added when the response is first written and only count the queries executed up to that point.
+func (a *Application) queryStatsMiddleware(next mux.Handler) mux.Handler {
+	if !ConfigGet(a.Settings, APPVAR_QUERY_STATS, false) {
+		return next
+	}
+
+	var (
+		threshold = ConfigGet(a.Settings, APPVAR_QUERY_STATS_DUPLICATES, 3)
+		showPanel = ConfigGet(a.Settings, APPVAR_DEBUG, true) && ConfigGet(a.Settings, APPVAR_QUERY_STATS_PANEL, false)
+		log       = a.Log.NameSpace("SQL")
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsStaticRouteRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var ctx, qi = drivers.ContextWithQueryInfo(r.Context())
+		var rw = &queryStatsWriter{
+			ResponseWriter: w,
+			bufferHTML:     showPanel,
+			setHeaders: func(header http.Header) {
+				setQueryStatsHeaders(header, qi, threshold)
+			},
+		}
+
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		var (
+			statements = qi.Statements()
+			duplicates = qi.Duplicates(threshold)
+		)
+
+		for _, dup := range duplicates {
+			log.Warnf(
+				"%s %s: query executed %d times (possible N+1): %s %v",
+				r.Method, r.URL.Path, dup.Count(), dup.Shape, dup.Callers(),
+			)
+		}
+
+		if !rw.buffering {
+			if !rw.wroteHeader {
+				// nothing was written by the handler, the headers are complete
+				rw.WriteHeader(http.StatusOK)
+			}
+			return
+		}
+
+		var body = injectQueryPanel(rw.buf.Bytes(), statements, duplicates)
+		w.Header().Del("Content-Length")
+
+		setQueryStatsHeaders(w.Header(), qi, threshold)
+		w.WriteHeader(rw.status)
+		w.Write(body)
+	})
+}
+
+func setQueryStatsHeaders(header http.Header, qi *drivers.QueryInformation, threshold int) {
+	header.Set(HeaderQueryCount, strconv.Itoa(len(qi.Statements())))
+	header.Set(HeaderQueryTime, qi.TotalTime().String())
+	header.Set(HeaderQueryDuplicates, strconv.Itoa(len(qi.Duplicates(threshold))))
+
+	var hits, misses = qi.CacheStats()
+	header.Set(HeaderQueryCacheHits, strconv.FormatInt(hits, 10))
+	header.Set(HeaderQueryCacheMisses, strconv.FormatInt(misses, 10))
+}
+
+// queryStatsWriter buffers HTML responses if bufferHTML is true and passes all other responses through.
+//
+// Whether the response is buffered is decided by its content type when the header is written.
+type queryStatsWriter struct {
+	http.ResponseWriter
+	bufferHTML  bool
+	setHeaders  func(http.Header)
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	buffering   bool
+}
+
+func (w *queryStatsWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+	w.status = status
+
+	var contentType = w.Header().Get("Content-Type")
+	if w.bufferHTML && strings.Contains(contentType, "text/html") {
+		w.buffering = true
+		return
+	}
+
+	w.setHeaders(w.Header())
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *queryStatsWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.buffering {
+		return w.buf.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends a buffered response as-is and stops buffering it, the query panel is not added.
+func (w *queryStatsWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.buffering {
+		w.buffering = false
+		w.setHeaders(w.Header())
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *queryStatsWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// injectQueryPanel adds a panel with the executed queries before the closing body tag.
+func injectQueryPanel(body []byte, statements []*drivers.Query, duplicates []drivers.DuplicateQuery) []byte {
+	var panel bytes.Buffer
+	var total time.Duration
+	for _, q := range statements {
+		total += q.TimeTaken
+	}
+
+	fmt.Fprintf(&panel,
+		`<details id="django-query-stats" style="position:fixed;bottom:0;right:0;z-index:99999;max-width:50%%;max-height:50%%;overflow:auto;background:#fff;color:#000;border:1px solid #ccc;padding:0.5em;font:12px monospace">`+
+			`<summary>%d queries in %s, %d duplicated</summary>`,
+		len(statements), total, len(duplicates),
+	)
+
+	if len(duplicates) > 0 {
+		panel.WriteString(`<h4>Duplicated queries</h4><ul>`)
+		for _, dup := range duplicates {
+			fmt.Fprintf(&panel, `<li><strong>%dx</strong> %s<br><small>%s</small></li>`,
+				dup.Count(), html.EscapeString(dup.Shape), html.EscapeString(strings.Join(dup.Callers(), ", ")),
+			)
+		}
+		panel.WriteString(`</ul>`)
+	}
+
+	panel.WriteString(`<h4>Queries</h4><ol>`)
+	for _, q := range statements {
+		fmt.Fprintf(&panel, `<li>%s <small>(%s, %d rows)</small><br><small>%s</small></li>`,
+			html.EscapeString(q.Query), q.TimeTaken, q.Rows.Load(), html.EscapeString(q.Caller),
+		)
+	}
+	panel.WriteString(`</ol></details>`)
+
+	var idx = bytes.LastIndex(body, []byte("</body>"))
+	if idx == -1 {
+		return append(body, panel.Bytes()...)
+	}
+
+	var result = make([]byte, 0, len(body)+panel.Len())
+	result = append(result, body[:idx]...)
+	result = append(result, panel.Bytes()...)
+	result = append(result, body[idx:]...)
+	return result
+}
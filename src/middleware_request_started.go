@@ -2,10 +2,12 @@ package django
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	core "github.com/Nigel2392/go-django/src/core"
 	"github.com/Nigel2392/go-django/src/core/errs"
+	"github.com/Nigel2392/go-django/src/core/trace"
 	"github.com/Nigel2392/mux"
 )
 
@@ -23,8 +25,29 @@ const ErrServeCanceled errs.Error = "Serve cancelled, signal hijacked response"
 // The signal it sends is of type *core.HttpSignal.
 //
 // This can be used to initialize and / or clean up resources before and after a request is served.
+//
+// When tracing is enabled, the request is served within the request span, see [trace.Start].
+// The span continues the trace of the W3C traceparent header of the request, if present.
 func RequestSignalMiddleware(next mux.Handler) mux.Handler {
 	return mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {
+		var ctx, span = trace.Start(
+			trace.Extract(r.Context(), r.Header),
+			fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+			"http.method", r.Method,
+			"http.path", r.URL.Path,
+		)
+		if span != nil {
+			var sw = &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				span.SetAttributes("http.status", sw.status)
+				if sw.status >= http.StatusInternalServerError {
+					span.SetStatus(trace.StatusError)
+				}
+				span.End()
+			}()
+			w, r = sw, r.WithContext(ctx)
+		}
+
 		var signal = &core.HttpSignal{W: w, R: r, H: next}
 
 		if err := core.SIGNAL_BEFORE_REQUEST.Send(r.Context(), signal); err != nil {
@@ -51,3 +51,17 @@ func Configure(m map[string]interface{}) func(*Application) error {
 		return s.Bind(a)
 	}
 }
+
+// LoadConfig loads the settings from the sources with [LoadSettings].
+//
+// It can be used instead of [Configure] to load the settings from files and environment variables.
+func LoadConfig(sources ...SettingsSource) func(*Application) error {
+	return func(a *Application) error {
+		var s, err = LoadSettings(sources...)
+		if err != nil {
+			return err
+		}
+		a.Settings = s
+		return s.Bind(a)
+	}
+}
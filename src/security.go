@@ -0,0 +1,368 @@
+package django
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	core "github.com/Nigel2392/go-django/src/core"
+	"github.com/Nigel2392/go-django/src/core/ctx"
+	"github.com/Nigel2392/go-django/src/core/errs"
+	"github.com/Nigel2392/go-django/src/core/logger"
+)
+
+const (
+	// The default Referrer-Policy, see [APPVAR_SECURE_REFERRER_POLICY].
+	DefaultReferrerPolicy = "same-origin"
+
+	// The default Cross-Origin-Opener-Policy, see [APPVAR_SECURE_CROSS_ORIGIN_OPENER_POLICY].
+	DefaultCrossOriginOpenerPolicy = "same-origin"
+
+	// The default URL of the endpoint which collects Content-Security-Policy violation reports.
+	DefaultCSPReportURL = "/csp-report"
+)
+
+// CSPNonceSource is replaced with the nonce of the request in the sources of the [APPVAR_CSP] directives.
+//
+//	django.APPVAR_CSP: map[string][]string{
+//		"default-src": {"'self'"},
+//		"script-src":  {"'self'", django.CSPNonceSource},
+//	}
+//
+// The nonce is available to templates with the csp_nonce function and to templ components with templ.GetNonce.
+const CSPNonceSource = "'nonce'"
+
+// ErrInvalidCSPReport is returned when a Content-Security-Policy violation report cannot be decoded.
+const ErrInvalidCSPReport errs.Error = "invalid Content-Security-Policy report"
+
+const (
+	// The maximum size of the body of a Content-Security-Policy violation report.
+	maxCSPReportSize = 64 << 10
+
+	// The time in which a violation is only reported once, see [cspReportFilter].
+	cspReportWindow = 10 * time.Minute
+
+	// The maximum number of distinct violations reported per window.
+	cspReportMaxViolations = 1000
+)
+
+type securityHeader struct {
+	name  string
+	value string
+
+	// only sent over HTTPS, i.e. Strict-Transport-Security
+	secureOnly bool
+}
+
+// securityHeaders returns the security headers configured in the settings.
+func (a *Application) securityHeaders() []securityHeader {
+	var headers = make([]securityHeader, 0, 5)
+	if !ConfigGet(a.Settings, APPVAR_SECURITY_HEADERS, true) {
+		return headers
+	}
+
+	if seconds := ConfigGet(a.Settings, APPVAR_SECURE_HSTS_SECONDS, 0); seconds > 0 {
+		var hsts = fmt.Sprintf("max-age=%d", seconds)
+		if ConfigGet(a.Settings, APPVAR_SECURE_HSTS_INCLUDE_SUBDOMAINS, false) {
+			hsts += "; includeSubDomains"
+		}
+		if ConfigGet(a.Settings, APPVAR_SECURE_HSTS_PRELOAD, false) {
+			hsts += "; preload"
+		}
+		headers = append(headers, securityHeader{"Strict-Transport-Security", hsts, true})
+	}
+
+	if ConfigGet(a.Settings, APPVAR_SECURE_CONTENT_TYPE_NOSNIFF, true) {
+		headers = append(headers, securityHeader{"X-Content-Type-Options", "nosniff", false})
+	}
+
+	var optional = []securityHeader{
+		{"Referrer-Policy", ConfigGet(a.Settings, APPVAR_SECURE_REFERRER_POLICY, DefaultReferrerPolicy), false},
+		{"Cross-Origin-Opener-Policy", ConfigGet(a.Settings, APPVAR_SECURE_CROSS_ORIGIN_OPENER_POLICY, DefaultCrossOriginOpenerPolicy), false},
+		{"Permissions-Policy", ConfigGet(a.Settings, APPVAR_SECURE_PERMISSIONS_POLICY, ""), false},
+	}
+
+	for _, header := range optional {
+		if header.value != "" {
+			headers = append(headers, header)
+		}
+	}
+
+	return headers
+}
+
+// contentSecurityPolicy builds the Content-Security-Policy from the directives,
+// a report-uri directive pointing to the report URL is added if the directives do not define one.
+//
+// The directives are sorted so the policy is the same for each request.
+func contentSecurityPolicy(directives map[string][]string, reportURL string) string {
+	if len(directives) == 0 {
+		return ""
+	}
+
+	var names = make([]string, 0, len(directives)+1)
+	for name := range directives {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var policy = make([]string, 0, len(names)+1)
+	for _, name := range names {
+		policy = append(policy, strings.TrimSpace(
+			fmt.Sprintf("%s %s", name, strings.Join(directives[name], " ")),
+		))
+	}
+
+	if _, ok := directives["report-uri"]; !ok && reportURL != "" {
+		policy = append(policy, fmt.Sprintf("report-uri %s", reportURL))
+	}
+
+	return strings.Join(policy, "; ")
+}
+
+// securityMiddleware sets the security headers and the Content-Security-Policy on each response.
+//
+// When a policy is configured the request context carries a new nonce for each request, see [ctx.CSPNonce],
+// responses which contain the nonce are not stored by the HTTP cache of the views package,
+// and violation reports sent by browsers to [APPVAR_CSP_REPORT_URL] are logged and sent with [core.SIGNAL_CSP_VIOLATION].
+//
+// The middleware runs before the CSRF middleware, browsers do not send a CSRF token with their reports.
+func (a *Application) securityMiddleware(next http.Handler) http.Handler {
+	var (
+		headers   = a.securityHeaders()
+		reportURL = ConfigGet(a.Settings, APPVAR_CSP_REPORT_URL, DefaultCSPReportURL)
+		policy    = contentSecurityPolicy(
+			ConfigGet[map[string][]string](a.Settings, APPVAR_CSP, nil),
+			reportURL,
+		)
+		policyHeader = "Content-Security-Policy"
+	)
+
+	if ConfigGet(a.Settings, APPVAR_CSP_REPORT_ONLY, false) {
+		policyHeader = "Content-Security-Policy-Report-Only"
+	}
+
+	if policy == "" {
+		reportURL = ""
+	}
+
+	if len(headers) == 0 && policy == "" {
+		return next
+	}
+
+	var reports = newCSPReportFilter(cspReportWindow, cspReportMaxViolations)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reportURL != "" && r.URL.Path == reportURL {
+			a.serveCSPReport(w, r, reports)
+			return
+		}
+
+		var header = w.Header()
+		for _, h := range headers {
+			if h.secureOnly && !a.isSecureRequest(r) {
+				continue
+			}
+			header.Set(h.name, h.value)
+		}
+
+		if policy != "" {
+			var nonce = newCSPNonce()
+			header.Set(policyHeader, strings.ReplaceAll(
+				policy, CSPNonceSource, fmt.Sprintf("'nonce-%s'", nonce),
+			))
+			r = r.WithContext(ctx.WithCSPNonce(r.Context(), nonce))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isSecureRequest reports whether the request was made over HTTPS,
+// the X-Forwarded-Proto header is only trusted if [APPVAR_REQUESTS_PROXIED] is set.
+func (a *Application) isSecureRequest(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	return ConfigGet(a.Settings, APPVAR_REQUESTS_PROXIED, false) &&
+		strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// serveCSPReport logs the Content-Security-Policy violation reports of the request
+// and sends them with [core.SIGNAL_CSP_VIOLATION], i.e. to store them in the audit logs.
+//
+// The endpoint cannot be authenticated, browsers send the reports without credentials,
+// so violations which were already reported recently are dropped by the filter.
+func (a *Application) serveCSPReport(w http.ResponseWriter, r *http.Request, filter *cspReportFilter) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var log = logger.WithContext(a.Log, r.Context())
+	var violations, err = parseCSPReports(http.MaxBytesReader(w, r.Body, maxCSPReportSize))
+	if err != nil {
+		log.Debugf("Failed to decode the Content-Security-Policy report: %v", err)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var now = time.Now()
+	for _, violation := range violations {
+		violation.R = r
+
+		if !filter.allow(violation, now) {
+			log.Debugf(
+				"Dropped repeated Content-Security-Policy violation on %s: %q blocked by %q",
+				violation.DocumentURL, violation.BlockedURL, violation.Directive,
+			)
+			continue
+		}
+
+		log.Warnf(
+			"Content-Security-Policy violation on %s: %q blocked by %q",
+			violation.DocumentURL, violation.BlockedURL, violation.Directive,
+		)
+
+		if err := core.SIGNAL_CSP_VIOLATION.Send(r.Context(), violation); err != nil {
+			log.Errorf("Failed to send the Content-Security-Policy violation signal: %v", err)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// cspReportFilter drops violations which were already reported within the window,
+// so a page which violates the policy on every request does not send a signal for each request.
+//
+// At most max distinct violations are reported per window, which bounds the signals sent
+// for reports of made up violations.
+type cspReportFilter struct {
+	mu     sync.Mutex
+	window time.Duration
+	max    int
+	seen   map[string]time.Time
+}
+
+func newCSPReportFilter(window time.Duration, max int) *cspReportFilter {
+	return &cspReportFilter{
+		window: window,
+		max:    max,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// allow reports whether the violation should be reported, it records the violation if so.
+func (f *cspReportFilter) allow(v *core.CSPViolation, now time.Time) bool {
+	var key = strings.Join([]string{
+		v.DocumentURL, v.BlockedURL, v.Directive, v.SourceFile, strconv.Itoa(v.LineNumber),
+	}, "\x00")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if reported, ok := f.seen[key]; ok && now.Sub(reported) < f.window {
+		return false
+	}
+
+	if len(f.seen) >= f.max {
+		for k, reported := range f.seen {
+			if now.Sub(reported) >= f.window {
+				delete(f.seen, k)
+			}
+		}
+		if len(f.seen) >= f.max {
+			return false
+		}
+	}
+
+	f.seen[key] = now
+	return true
+}
+
+// parseCSPReports decodes the violation reports of both the report-uri (application/csp-report)
+// and the Reporting API (application/reports+json) formats.
+func parseCSPReports(body io.Reader) ([]*core.CSPViolation, error) {
+	var data, err = io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCSPReport, err)
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var reports []struct {
+			Type string         `json:"type"`
+			Body map[string]any `json:"body"`
+		}
+		if err = json.Unmarshal(data, &reports); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCSPReport, err)
+		}
+
+		var violations = make([]*core.CSPViolation, 0, len(reports))
+		for _, report := range reports {
+			if report.Type == "csp-violation" && report.Body != nil {
+				violations = append(violations, newCSPViolation(report.Body))
+			}
+		}
+		return violations, nil
+	}
+
+	var report struct {
+		Body map[string]any `json:"csp-report"`
+	}
+	if err = json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCSPReport, err)
+	}
+
+	if report.Body == nil {
+		return nil, fmt.Errorf("%w: missing csp-report", ErrInvalidCSPReport)
+	}
+
+	return []*core.CSPViolation{newCSPViolation(report.Body)}, nil
+}
+
+// newCSPViolation creates a violation from a report,
+// the report-uri format uses kebab-case keys and the Reporting API camelCase keys.
+func newCSPViolation(report map[string]any) *core.CSPViolation {
+	var field = func(keys ...string) string {
+		for _, key := range keys {
+			if s, ok := report[key].(string); ok && s != "" {
+				return s
+			}
+		}
+		return ""
+	}
+
+	var line int
+	for _, key := range []string{"line-number", "lineNumber"} {
+		if n, ok := report[key].(float64); ok {
+			line = int(n)
+			break
+		}
+	}
+
+	return &core.CSPViolation{
+		DocumentURL: field("document-uri", "documentURL"),
+		BlockedURL:  field("blocked-uri", "blockedURL"),
+		Directive:   field("effective-directive", "effectiveDirective", "violated-directive"),
+		Disposition: field("disposition"),
+		SourceFile:  field("source-file", "sourceFile"),
+		LineNumber:  line,
+		Report:      report,
+	}
+}
+
+func newCSPNonce() string {
+	var b = make([]byte, 16)
+	_, _ = rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
+}
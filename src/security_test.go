@@ -0,0 +1,393 @@
+package django_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	django "github.com/Nigel2392/go-django/src"
+	core "github.com/Nigel2392/go-django/src/core"
+	"github.com/Nigel2392/go-django/src/core/ctx"
+	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-signals"
+
+	_ "unsafe"
+)
+
+//go:linkname contentSecurityPolicy github.com/Nigel2392/go-django/src.contentSecurityPolicy
+func contentSecurityPolicy(directives map[string][]string, reportURL string) string
+
+//go:linkname parseCSPReports github.com/Nigel2392/go-django/src.parseCSPReports
+func parseCSPReports(body io.Reader) ([]*core.CSPViolation, error)
+
+//go:linkname securityMiddleware github.com/Nigel2392/go-django/src.(*Application).securityMiddleware
+func securityMiddleware(a *django.Application, next http.Handler) http.Handler
+
+//go:linkname isSecureRequest github.com/Nigel2392/go-django/src.(*Application).isSecureRequest
+func isSecureRequest(a *django.Application, r *http.Request) bool
+
+func newSecurityApp(settings map[string]any) *django.Application {
+	return &django.Application{
+		Settings: django.Config(settings),
+		Log:      &logger.Logger{Level: logger.ERR, OutputError: io.Discard},
+	}
+}
+
+func TestContentSecurityPolicy(t *testing.T) {
+	var tests = []struct {
+		name       string
+		directives map[string][]string
+		reportURL  string
+		expected   string
+	}{
+		{
+			name:     "Empty",
+			expected: "",
+		},
+		{
+			name: "Sorted",
+			directives: map[string][]string{
+				"script-src":  {"'self'", django.CSPNonceSource},
+				"default-src": {"'self'"},
+			},
+			expected: "default-src 'self'; script-src 'self' 'nonce'",
+		},
+		{
+			name: "ReportURL",
+			directives: map[string][]string{
+				"default-src": {"'self'"},
+			},
+			reportURL: "/csp-report",
+			expected:  "default-src 'self'; report-uri /csp-report",
+		},
+		{
+			name: "OwnReportURI",
+			directives: map[string][]string{
+				"default-src": {"'self'"},
+				"report-uri":  {"https://reports.example.com"},
+			},
+			reportURL: "/csp-report",
+			expected:  "default-src 'self'; report-uri https://reports.example.com",
+		},
+		{
+			name: "NoSources",
+			directives: map[string][]string{
+				"upgrade-insecure-requests": nil,
+			},
+			expected: "upgrade-insecure-requests",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := contentSecurityPolicy(test.directives, test.reportURL); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+// cspViolation holds the comparable fields of a [core.CSPViolation].
+type cspViolation struct {
+	DocumentURL string
+	BlockedURL  string
+	Directive   string
+	Disposition string
+	SourceFile  string
+	LineNumber  int
+}
+
+func TestParseCSPReports(t *testing.T) {
+	var tests = []struct {
+		name     string
+		body     string
+		expected []cspViolation
+		err      bool
+	}{
+		{
+			name: "ReportURI",
+			body: `{"csp-report": {"document-uri": "https://example.com/", "blocked-uri": "inline", "effective-directive": "script-src", "disposition": "enforce", "source-file": "https://example.com/app.js", "line-number": 12}}`,
+			expected: []cspViolation{{
+				DocumentURL: "https://example.com/",
+				BlockedURL:  "inline",
+				Directive:   "script-src",
+				Disposition: "enforce",
+				SourceFile:  "https://example.com/app.js",
+				LineNumber:  12,
+			}},
+		},
+		{
+			name: "ViolatedDirective",
+			body: `{"csp-report": {"document-uri": "https://example.com/", "blocked-uri": "eval", "violated-directive": "script-src 'self'"}}`,
+			expected: []cspViolation{{
+				DocumentURL: "https://example.com/",
+				BlockedURL:  "eval",
+				Directive:   "script-src 'self'",
+			}},
+		},
+		{
+			name: "ReportingAPI",
+			body: `[
+				{"type": "csp-violation", "body": {"documentURL": "https://example.com/a", "blockedURL": "https://cdn.example.com/x.js", "effectiveDirective": "script-src-elem", "disposition": "report", "lineNumber": 3}},
+				{"type": "deprecation", "body": {"id": "old-api"}},
+				{"type": "csp-violation", "body": {"documentURL": "https://example.com/b", "blockedURL": "inline", "effectiveDirective": "style-src"}}
+			]`,
+			expected: []cspViolation{
+				{
+					DocumentURL: "https://example.com/a",
+					BlockedURL:  "https://cdn.example.com/x.js",
+					Directive:   "script-src-elem",
+					Disposition: "report",
+					LineNumber:  3,
+				},
+				{
+					DocumentURL: "https://example.com/b",
+					BlockedURL:  "inline",
+					Directive:   "style-src",
+				},
+			},
+		},
+		{
+			name: "MissingReport",
+			body: `{"report": {}}`,
+			err:  true,
+		},
+		{
+			name: "InvalidJSON",
+			body: `{"csp-report":`,
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var violations, err = parseCSPReports(strings.NewReader(test.body))
+			if test.err {
+				if !errors.Is(err, django.ErrInvalidCSPReport) {
+					t.Fatalf("expected %v, got %v", django.ErrInvalidCSPReport, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to parse the report: %v", err)
+			}
+
+			if len(violations) != len(test.expected) {
+				t.Fatalf("expected %d violations, got %d", len(test.expected), len(violations))
+			}
+
+			for i, v := range violations {
+				var got = cspViolation{
+					DocumentURL: v.DocumentURL,
+					BlockedURL:  v.BlockedURL,
+					Directive:   v.Directive,
+					Disposition: v.Disposition,
+					SourceFile:  v.SourceFile,
+					LineNumber:  v.LineNumber,
+				}
+				if got != test.expected[i] {
+					t.Errorf("expected violation %+v, got %+v", test.expected[i], got)
+				}
+				if v.Report == nil {
+					t.Errorf("expected the raw report to be kept")
+				}
+			}
+		})
+	}
+}
+
+func TestIsSecureRequest(t *testing.T) {
+	var tests = []struct {
+		name     string
+		proxied  bool
+		tls      bool
+		proto    string
+		expected bool
+	}{
+		{name: "Plain"},
+		{name: "TLS", tls: true, expected: true},
+		{name: "UntrustedProto", proto: "https"},
+		{name: "ProxiedProto", proxied: true, proto: "HTTPS", expected: true},
+		{name: "ProxiedPlain", proxied: true, proto: "http"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var app = newSecurityApp(map[string]any{
+				django.APPVAR_REQUESTS_PROXIED: test.proxied,
+			})
+
+			var r = httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+			if test.proto != "" {
+				r.Header.Set("X-Forwarded-Proto", test.proto)
+			}
+
+			if got := isSecureRequest(app, r); got != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestSecurityMiddleware(t *testing.T) {
+	var tests = []struct {
+		name     string
+		settings map[string]any
+		tls      bool
+		expected map[string]string
+	}{
+		{
+			name: "Defaults",
+			expected: map[string]string{
+				"X-Content-Type-Options":     "nosniff",
+				"Referrer-Policy":            django.DefaultReferrerPolicy,
+				"Cross-Origin-Opener-Policy": django.DefaultCrossOriginOpenerPolicy,
+				"Strict-Transport-Security":  "",
+				"Content-Security-Policy":    "",
+			},
+		},
+		{
+			name: "Disabled",
+			settings: map[string]any{
+				django.APPVAR_SECURITY_HEADERS: false,
+			},
+			expected: map[string]string{
+				"X-Content-Type-Options": "",
+				"Referrer-Policy":        "",
+			},
+		},
+		{
+			name: "HSTSOverPlainHTTP",
+			settings: map[string]any{
+				django.APPVAR_SECURE_HSTS_SECONDS: 3600,
+			},
+			expected: map[string]string{
+				"Strict-Transport-Security": "",
+			},
+		},
+		{
+			name: "HSTSOverTLS",
+			settings: map[string]any{
+				django.APPVAR_SECURE_HSTS_SECONDS:            3600,
+				django.APPVAR_SECURE_HSTS_INCLUDE_SUBDOMAINS: true,
+				django.APPVAR_SECURE_HSTS_PRELOAD:            true,
+			},
+			tls: true,
+			expected: map[string]string{
+				"Strict-Transport-Security": "max-age=3600; includeSubDomains; preload",
+			},
+		},
+		{
+			name: "ReportOnly",
+			settings: map[string]any{
+				django.APPVAR_CSP:             map[string][]string{"default-src": {"'self'"}},
+				django.APPVAR_CSP_REPORT_ONLY: true,
+			},
+			expected: map[string]string{
+				"Content-Security-Policy":             "",
+				"Content-Security-Policy-Report-Only": "default-src 'self'; report-uri " + django.DefaultCSPReportURL,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var handler = securityMiddleware(newSecurityApp(test.settings), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			var r = httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+
+			var w = httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			for name, value := range test.expected {
+				if got := w.Header().Get(name); got != value {
+					t.Errorf("expected %s: %q, got %q", name, value, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSecurityMiddlewareNonce(t *testing.T) {
+	var nonce string
+	var handler = securityMiddleware(newSecurityApp(map[string]any{
+		django.APPVAR_CSP: map[string][]string{"script-src": {"'self'", django.CSPNonceSource}},
+	}), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce = ctx.CSPNonce(r.Context())
+	}))
+
+	var w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if nonce == "" {
+		t.Fatal("expected a nonce in the request context")
+	}
+
+	var expected = "script-src 'self' 'nonce-" + nonce + "'; report-uri " + django.DefaultCSPReportURL
+	if got := w.Header().Get("Content-Security-Policy"); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestSecurityMiddlewareCSPReport(t *testing.T) {
+	var (
+		listening atomic.Bool
+		received  atomic.Int32
+	)
+
+	listening.Store(true)
+	t.Cleanup(func() { listening.Store(false) })
+
+	core.SIGNAL_CSP_VIOLATION.Listen(t.Context(), func(ctx context.Context, s signals.Signal[*core.CSPViolation], v *core.CSPViolation) error {
+		if listening.Load() {
+			received.Add(1)
+		}
+		return nil
+	})
+
+	var handler = securityMiddleware(newSecurityApp(map[string]any{
+		django.APPVAR_CSP: map[string][]string{"default-src": {"'self'"}},
+	}), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the report must not reach the next handler")
+	}))
+
+	var report = func(method, blocked string) int {
+		var body = `{"csp-report": {"document-uri": "https://example.com/", "blocked-uri": "` + blocked + `", "effective-directive": "script-src"}}`
+		var w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, django.DefaultCSPReportURL, strings.NewReader(body)))
+		return w.Code
+	}
+
+	if code := report(http.MethodGet, "inline"); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for a GET request, got %d", code)
+	}
+
+	for range 3 {
+		if code := report(http.MethodPost, "inline"); code != http.StatusNoContent {
+			t.Errorf("expected 204, got %d", code)
+		}
+	}
+
+	if code := report(http.MethodPost, "eval"); code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", code)
+	}
+
+	if n := received.Load(); n != 2 {
+		t.Errorf("expected repeated violations to be sent once, got %d signals", n)
+	}
+}
@@ -1,9 +1,7 @@
 package django
 
 import (
-	"fmt"
 	"reflect"
-	"strconv"
 
 	"github.com/Nigel2392/go-django/src/core/assert"
 )
@@ -18,6 +16,9 @@ type Settings interface {
 type settings struct {
 	Data map[string]any
 	a    *Application
+
+	// the name of the source each setting was loaded from, see [LoadSettings]
+	sources map[string]string
 }
 
 func Config(m map[string]interface{}) Settings {
@@ -53,14 +54,29 @@ func ConfigGet[T any](s Settings, key string, default_ ...T) T {
 	return value
 }
 
+// ConfigGetOK returns the value of the setting and whether it was present in the settings.
+//
+// If the setting is not present the default is returned, if no default is provided
+// the default of the setting registered with [DefineSetting] is used instead.
+//
+// String values are parsed to the requested type, i.e. when loaded from environment variables.
+// A value which cannot be parsed or does not match the requested type is treated as not present,
+// the default is returned and the value is reported by the settings check.
 func ConfigGetOK[T any](s Settings, key string, default_ ...T) (T, bool) {
+	assert.Lt(default_, 2, "Too many arguments")
+
+	if len(default_) == 0 {
+		if def, ok := GetSettingDefinition(key); ok {
+			if v, ok := def.Default().(T); ok {
+				default_ = []T{v}
+			}
+		}
+	}
+
 	if s == nil && len(default_) == 0 {
 		return *(new(T)), false
 	}
 
-	assert.Lt(default_, 2, "Too many arguments")
-	assert.Gte(default_, 0, "Too few arguments")
-
 	if s == nil {
 		return default_[0], false
 	}
@@ -72,47 +88,31 @@ func ConfigGetOK[T any](s Settings, key string, default_ ...T) (T, bool) {
 		return *(new(T)), false
 	}
 
-	var rTyp = reflect.TypeFor[T]()
-	if s, ok := value.(string); ok {
-		if s == "" {
-			return default_[0], true
-		}
+	var str, isStr = value.(string)
+	if isStr && str == "" && len(default_) > 0 {
+		return default_[0], true
+	} else if isStr && str == "" {
+		return *(new(T)), true
+	}
 
-		if rTyp == nil {
-			return value.(T), true
-		}
+	if v, ok := value.(T); ok {
+		return v, true
+	}
 
-		if rTyp.Kind() == reflect.String {
-			v, ok := value.(T)
-			assert.True(ok, "Invalid type for key %s", key)
-			return v, true
-		}
+	if !isStr {
+		return defaultOrZero(default_), false
+	}
 
-		switch rTyp.Kind() {
-		case reflect.String:
-			return value.(T), true
-		case reflect.Bool:
-			var v, err = strconv.ParseBool(s)
-			assert.ErrNil(fmt.Errorf("invalid value for key %s: %v (%v)", key, v, err))
-			return any(v).(T), true
-		case reflect.Int:
-			var v, err = strconv.Atoi(s)
-			assert.ErrNil(fmt.Errorf("invalid value for key %s: %v (%v)", key, v, err))
-			return any(v).(T), true
-		case reflect.Int64:
-			var v, err = strconv.ParseInt(s, 10, 64)
-			assert.ErrNil(fmt.Errorf("invalid value for key %s: %v (%v)", key, v, err))
-			return any(v).(T), true
-		case reflect.Float64:
-			var v, err = strconv.ParseFloat(s, 64)
-			assert.ErrNil(fmt.Errorf("invalid value for key %s: %v (%v)", key, v, err))
-			return any(v).(T), true
-		default:
-			assert.Fail("Invalid type for key %s", key)
-		}
+	var v, err = parseSettingString(reflect.TypeFor[T](), str)
+	if err != nil {
+		return defaultOrZero(default_), false
 	}
+	return v.Interface().(T), true
+}
 
-	v, ok := value.(T)
-	assert.True(ok, "Invalid type for key %s", key)
-	return v, true
+func defaultOrZero[T any](default_ []T) T {
+	if len(default_) > 0 {
+		return default_[0]
+	}
+	return *(new(T))
 }
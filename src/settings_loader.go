@@ -0,0 +1,190 @@
+package django
+
+import (
+	"encoding/json"
+	goErrs "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Nigel2392/go-django/pkg/yml"
+)
+
+// SettingsSource is a layer of settings loaded by [LoadSettings].
+type SettingsSource interface {
+	// The name of the source, shown by the diffsettings command.
+	Name() string
+
+	// Load returns the settings of the source.
+	Load() (map[string]any, error)
+}
+
+type defaultsSource map[string]any
+
+// DefaultsSource returns a settings source for the given map of settings,
+// it is usually the first layer of the settings to provide project-wide defaults.
+func DefaultsSource(m map[string]any) SettingsSource {
+	return defaultsSource(m)
+}
+
+func (s defaultsSource) Name() string {
+	return "defaults"
+}
+
+func (s defaultsSource) Load() (map[string]any, error) {
+	return s, nil
+}
+
+type fileSource struct {
+	path     string
+	optional bool
+}
+
+// FileSource returns a settings source which loads the settings from a file.
+//
+// The format is determined by the extension of the file, YAML (.yml, .yaml), JSON (.json) and TOML (.toml) are supported.
+// The top-level keys of the file are the keys of the settings.
+func FileSource(path string) SettingsSource {
+	return &fileSource{path: path}
+}
+
+// OptionalFileSource is like [FileSource], but does not fail if the file does not exist.
+func OptionalFileSource(path string) SettingsSource {
+	return &fileSource{path: path, optional: true}
+}
+
+func (s *fileSource) Name() string {
+	return s.path
+}
+
+func (s *fileSource) Load() (map[string]any, error) {
+	if s.optional {
+		if _, err := os.Stat(s.path); goErrs.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+	}
+
+	var m = make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(s.path)); ext {
+	case ".yml", ".yaml":
+		if err := yml.Unmarshal(s.path, &m, false); err != nil && !goErrs.Is(err, io.EOF) {
+			return nil, err
+		}
+	case ".json":
+		var data, err = os.ReadFile(s.path)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON file %s: %w", s.path, err)
+		}
+	case ".toml":
+		if _, err := toml.DecodeFile(s.path, &m); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal TOML file %s: %w", s.path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported settings file format %q", ext)
+	}
+	return m, nil
+}
+
+type envSource struct {
+	prefix string
+}
+
+// EnvSource returns a settings source which loads the settings from environment variables.
+//
+// Only variables starting with the prefix are loaded, the prefix is stripped from the key,
+// i.e. with the prefix "DJANGO_" the variable DJANGO_DEBUG sets the DEBUG setting.
+//
+// Variables ending in "_FILE" are read from the file they point to, which is how
+// secrets are usually provided to containers, i.e. DJANGO_SECRET_KEY_FILE=/run/secrets/secret_key
+// sets the SECRET_KEY setting to the trimmed contents of the file.
+//
+// The values are strings, they are converted to the type of registered settings by [LoadSettings].
+func EnvSource(prefix string) SettingsSource {
+	return &envSource{prefix: prefix}
+}
+
+func (s *envSource) Name() string {
+	return "env"
+}
+
+func (s *envSource) Load() (map[string]any, error) {
+	var m = make(map[string]any)
+	for _, env := range os.Environ() {
+		var name, value, _ = strings.Cut(env, "=")
+		var key, ok = strings.CutPrefix(name, s.prefix)
+		if !ok || key == "" {
+			continue
+		}
+
+		var fileKey, isFile = strings.CutSuffix(key, "_FILE")
+		if !isFile || fileKey == "" {
+			m[key] = value
+			continue
+		}
+
+		if _, exists := os.LookupEnv(s.prefix + fileKey); exists {
+			return nil, fmt.Errorf("both %s and %s are set", s.prefix+fileKey, name)
+		}
+
+		var data, err = os.ReadFile(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		m[fileKey] = strings.TrimSpace(string(data))
+	}
+	return m, nil
+}
+
+// LoadSettings loads the settings from the sources in order, later sources override earlier ones.
+//
+// Values of settings registered with [DefineSetting] are converted to the type of the setting,
+// i.e. the string "true" from an environment variable is converted to a bool.
+// Values which cannot be converted are kept as they are and reported by the settings checks.
+//
+// Settings which are not present in any of the sources use the default of their registered definition.
+//
+//	settings, err := django.LoadSettings(
+//		django.DefaultsSource(map[string]any{
+//			django.APPVAR_ALLOWED_HOSTS: []string{"*"},
+//		}),
+//		django.OptionalFileSource("config.yml"),
+//		django.EnvSource("DJANGO_"),
+//	)
+func LoadSettings(sources ...SettingsSource) (Settings, error) {
+	var s = &settings{
+		Data:    make(map[string]any),
+		sources: make(map[string]string),
+	}
+
+	for _, source := range sources {
+		var values, err = source.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load settings from %s: %w", source.Name(), err)
+		}
+
+		for key, value := range values {
+			if def, ok := GetSettingDefinition(key); ok && value != nil && reflect.TypeOf(value) != def.Type() {
+				if v, err := def.Convert(value); err == nil {
+					value = v
+				}
+			}
+
+			s.Data[key] = value
+			s.sources[key] = source.Name()
+		}
+	}
+
+	return s, nil
+}
+
+// source returns the name of the source the setting was loaded from.
+func (s *settings) source(key string) string {
+	return s.sources[key]
+}
@@ -0,0 +1,307 @@
+package django
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/go-django/src/core/assert"
+)
+
+// SettingDefinition describes a setting registered with [DefineSetting].
+//
+// Registered settings are converted to their type when loaded with [LoadSettings],
+// validated by the settings checks and listed by the diffsettings command.
+type SettingDefinition interface {
+	// The key of the setting in the settings, e.g. "DEBUG".
+	Key() string
+
+	// The type the value of the setting must have.
+	Type() reflect.Type
+
+	// A human readable description of the setting.
+	Description() string
+
+	// The value used when the setting is not present in the settings.
+	Default() any
+
+	// Whether the setting must be present in the settings.
+	IsRequired() bool
+
+	// Whether the value of the setting must be redacted when displayed.
+	IsSecret() bool
+
+	// Convert converts a value loaded from a settings source, i.e. a string
+	// from an environment variable or a value decoded from a file, to the type of the setting.
+	Convert(value any) (any, error)
+
+	// Validate runs the validators of the setting on a value of the setting's type.
+	Validate(value any) error
+}
+
+// SettingSchema is the schema of a setting registered with [DefineSetting].
+type SettingSchema[T any] struct {
+	// The key of the setting in the settings.
+	Key string
+
+	// The value used when the setting is not present in the settings.
+	Default T
+
+	// A human readable description of the setting.
+	Description string
+
+	// Whether the setting must be present in the settings.
+	Required bool
+
+	// Whether the value must be redacted when displayed, e.g. in the diffsettings command.
+	Secret bool
+
+	// Validators are run on the value of the setting by the settings checks.
+	Validators []func(value T) error
+}
+
+// Setting is a typed setting registered with [DefineSetting].
+type Setting[T any] struct {
+	schema SettingSchema[T]
+}
+
+var (
+	settingDefinitionsMu sync.RWMutex
+	settingDefinitions   = make(map[string]SettingDefinition)
+)
+
+// DefineSetting registers the schema of a setting and returns a typed accessor for it.
+//
+// Settings are usually defined as package level variables, so they are registered
+// before the settings are loaded:
+//
+//	var SETTING_PAGE_SIZE = django.DefineSetting(django.SettingSchema[int]{
+//		Key:         "BLOG_PAGE_SIZE",
+//		Default:     10,
+//		Description: "The amount of blog posts to show per page",
+//		Validators: []func(int) error{
+//			func(v int) error {
+//				if v <= 0 {
+//					return errors.New("page size must be positive")
+//				}
+//				return nil
+//			},
+//		},
+//	})
+//
+// Defining a setting with a key that is already registered panics.
+func DefineSetting[T any](schema SettingSchema[T]) *Setting[T] {
+	assert.False(schema.Key == "", "setting key must not be empty")
+
+	settingDefinitionsMu.Lock()
+	defer settingDefinitionsMu.Unlock()
+
+	var _, exists = settingDefinitions[schema.Key]
+	assert.False(exists, "setting %q is already defined", schema.Key)
+
+	var setting = &Setting[T]{schema: schema}
+	settingDefinitions[schema.Key] = setting
+	return setting
+}
+
+// GetSettingDefinition returns the definition of the setting registered with the key.
+func GetSettingDefinition(key string) (SettingDefinition, bool) {
+	settingDefinitionsMu.RLock()
+	defer settingDefinitionsMu.RUnlock()
+	var def, ok = settingDefinitions[key]
+	return def, ok
+}
+
+// SettingDefinitions returns all registered setting definitions sorted by key.
+func SettingDefinitions() []SettingDefinition {
+	settingDefinitionsMu.RLock()
+	defer settingDefinitionsMu.RUnlock()
+
+	var defs = make([]SettingDefinition, 0, len(settingDefinitions))
+	for _, def := range settingDefinitions {
+		defs = append(defs, def)
+	}
+	slices.SortFunc(defs, func(a, b SettingDefinition) int {
+		return strings.Compare(a.Key(), b.Key())
+	})
+	return defs
+}
+
+// Get returns the value of the setting, or the default of the setting if it is not present.
+func (s *Setting[T]) Get(settings Settings) T {
+	return ConfigGet(settings, s.schema.Key, s.schema.Default)
+}
+
+// GetOK returns the value of the setting and whether it was present in the settings.
+func (s *Setting[T]) GetOK(settings Settings) (T, bool) {
+	return ConfigGetOK(settings, s.schema.Key, s.schema.Default)
+}
+
+func (s *Setting[T]) Key() string {
+	return s.schema.Key
+}
+
+func (s *Setting[T]) Type() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (s *Setting[T]) Description() string {
+	return s.schema.Description
+}
+
+func (s *Setting[T]) Default() any {
+	return s.schema.Default
+}
+
+func (s *Setting[T]) IsRequired() bool {
+	return s.schema.Required
+}
+
+func (s *Setting[T]) IsSecret() bool {
+	return s.schema.Secret
+}
+
+func (s *Setting[T]) Convert(value any) (any, error) {
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+
+	var rVal, err = convertSettingValue(reflect.TypeFor[T](), value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for setting %q: %w", s.schema.Key, err)
+	}
+	return rVal.Interface(), nil
+}
+
+func (s *Setting[T]) Validate(value any) error {
+	var v, ok = value.(T)
+	if !ok && value == nil {
+		return fmt.Errorf("setting %q must not be nil", s.schema.Key)
+	}
+
+	if !ok {
+		return fmt.Errorf(
+			"setting %q (%s) is not of type %s",
+			s.schema.Key, typeStr(value), typeStr(reflect.TypeFor[T]()),
+		)
+	}
+
+	for _, validator := range s.schema.Validators {
+		if err := validator(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// convertSettingValue converts a value loaded from a settings source to the given type.
+//
+// Strings are parsed with [parseSettingString], numeric values are converted
+// between numeric types and any other values are converted by round-tripping them
+// through JSON, i.e. to convert a []any decoded from a YAML file to a []string.
+func convertSettingValue(typ reflect.Type, value any) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(typ), nil
+	}
+
+	if s, ok := value.(string); ok {
+		return parseSettingString(typ, s)
+	}
+
+	var rVal = reflect.ValueOf(value)
+	if rVal.Type().AssignableTo(typ) {
+		var v = reflect.New(typ).Elem()
+		v.Set(rVal)
+		return v, nil
+	}
+
+	// A bare number has no unit, it would silently be read as nanoseconds.
+	if typ == durationType && isNumericKind(rVal.Kind()) {
+		return reflect.Value{}, fmt.Errorf("cannot convert %s to %s: durations must be strings with a unit, i.e. \"30s\"", typeStr(value), typeStr(typ))
+	}
+
+	if isNumericKind(rVal.Kind()) && isNumericKind(typ.Kind()) {
+		return rVal.Convert(typ), nil
+	}
+
+	var data, err = json.Marshal(value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	var v = reflect.New(typ)
+	if err = json.Unmarshal(data, v.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("cannot convert %s to %s: %w", typeStr(value), typeStr(typ), err)
+	}
+	return v.Elem(), nil
+}
+
+// parseSettingString parses a string, i.e. from an environment variable, to the given type.
+//
+// Durations are parsed with [time.ParseDuration], string slices are comma separated
+// and types which are not a scalar are decoded as JSON.
+func parseSettingString(typ reflect.Type, s string) (reflect.Value, error) {
+	var v = reflect.New(typ).Elem()
+	if err := parseSettingInto(v, s); err != nil {
+		return reflect.Value{}, fmt.Errorf("cannot parse %q as %s: %w", s, typeStr(typ), err)
+	}
+	return v, nil
+}
+
+func parseSettingInto(v reflect.Value, s string) (err error) {
+	var typ = v.Type()
+	if typ == durationType {
+		var d time.Duration
+		d, err = time.ParseDuration(s)
+		v.SetInt(int64(d))
+		return err
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, typ.Bits())
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, typ.Bits())
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, typ.Bits())
+		v.SetFloat(f)
+	case reflect.Slice:
+		if typ.Elem().Kind() != reflect.String || strings.HasPrefix(strings.TrimSpace(s), "[") {
+			err = json.Unmarshal([]byte(s), v.Addr().Interface())
+			break
+		}
+
+		var parts = strings.Split(s, ",")
+		v.Set(reflect.MakeSlice(typ, 0, len(parts)))
+		for _, part := range parts {
+			if part = strings.TrimSpace(part); part != "" {
+				v.Set(reflect.Append(v, reflect.ValueOf(part).Convert(typ.Elem())))
+			}
+		}
+	default:
+		err = json.Unmarshal([]byte(s), v.Addr().Interface())
+	}
+	return err
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
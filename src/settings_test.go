@@ -0,0 +1,214 @@
+package django_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	django "github.com/Nigel2392/go-django/src"
+	"github.com/Nigel2392/go-django/src/core/checks"
+)
+
+var (
+	SETTING_TEST_PAGE_SIZE = django.DefineSetting(django.SettingSchema[int]{
+		Key:         "TEST_PAGE_SIZE",
+		Default:     10,
+		Description: "The amount of items to show per page",
+		Validators: []func(int) error{
+			func(v int) error {
+				if v <= 0 {
+					return errors.New("page size must be positive")
+				}
+				return nil
+			},
+		},
+	})
+	SETTING_TEST_API_TOKEN = django.DefineSetting(django.SettingSchema[string]{
+		Key:      "TEST_API_TOKEN",
+		Required: true,
+		Secret:   true,
+	})
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	var path = filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadSettings(t *testing.T) {
+	var config = writeFile(t, "config.yml", "TEST_PAGE_SIZE: 25\nALLOWED_HOSTS:\n  - example.com\nDEBUG: true\n")
+	var token = writeFile(t, "token", "secret-token\n")
+
+	t.Setenv("TEST_DJANGO_DEBUG", "false")
+	t.Setenv("TEST_DJANGO_SHUTDOWN_TIMEOUT", "5s")
+	t.Setenv("TEST_DJANGO_TEST_API_TOKEN_FILE", token)
+
+	var settings, err = django.LoadSettings(
+		django.DefaultsSource(map[string]any{
+			django.APPVAR_HOST:  "0.0.0.0",
+			django.APPVAR_DEBUG: true,
+		}),
+		django.FileSource(config),
+		django.OptionalFileSource(filepath.Join(t.TempDir(), "missing.yml")),
+		django.EnvSource("TEST_DJANGO_"),
+	)
+	if err != nil {
+		t.Fatalf("failed to load settings: %v", err)
+	}
+
+	if v := SETTING_TEST_PAGE_SIZE.Get(settings); v != 25 {
+		t.Errorf("expected TEST_PAGE_SIZE 25, got %d", v)
+	}
+
+	if v := django.ConfigGet[[]string](settings, django.APPVAR_ALLOWED_HOSTS); !slices.Equal(v, []string{"example.com"}) {
+		t.Errorf("expected ALLOWED_HOSTS [example.com], got %v", v)
+	}
+
+	if v := django.ConfigGet(settings, django.APPVAR_DEBUG, true); v {
+		t.Errorf("expected DEBUG to be overridden by the environment")
+	}
+
+	if v := django.ConfigGet[time.Duration](settings, django.APPVAR_SHUTDOWN_TIMEOUT); v != 5*time.Second {
+		t.Errorf("expected SHUTDOWN_TIMEOUT 5s, got %s", v)
+	}
+
+	if v := django.ConfigGet[string](settings, django.APPVAR_HOST); v != "0.0.0.0" {
+		t.Errorf("expected HOST 0.0.0.0, got %q", v)
+	}
+
+	if v := SETTING_TEST_API_TOKEN.Get(settings); v != "secret-token" {
+		t.Errorf("expected TEST_API_TOKEN from file, got %q", v)
+	}
+
+	if v := django.ConfigGet[string](settings, django.APPVAR_PORT); v != "8080" {
+		t.Errorf("expected PORT to use the registered default, got %q", v)
+	}
+}
+
+func TestLoadSettingsTOML(t *testing.T) {
+	var config = writeFile(t, "config.toml", "TEST_PAGE_SIZE = 30\nALLOWED_HOSTS = [\"example.com\"]\nSHUTDOWN_TIMEOUT = \"10s\"\n")
+
+	var settings, err = django.LoadSettings(django.FileSource(config))
+	if err != nil {
+		t.Fatalf("failed to load settings: %v", err)
+	}
+
+	if v := SETTING_TEST_PAGE_SIZE.Get(settings); v != 30 {
+		t.Errorf("expected TEST_PAGE_SIZE 30, got %d", v)
+	}
+
+	if v := django.ConfigGet[[]string](settings, django.APPVAR_ALLOWED_HOSTS); !slices.Equal(v, []string{"example.com"}) {
+		t.Errorf("expected ALLOWED_HOSTS [example.com], got %v", v)
+	}
+
+	if v := django.ConfigGet[time.Duration](settings, django.APPVAR_SHUTDOWN_TIMEOUT); v != 10*time.Second {
+		t.Errorf("expected SHUTDOWN_TIMEOUT 10s, got %s", v)
+	}
+}
+
+func TestConfigGetInvalid(t *testing.T) {
+	var settings = django.Config(map[string]any{
+		django.APPVAR_DEBUG: "not a bool",
+		django.APPVAR_HOST:  1234,
+	})
+
+	if v, ok := django.ConfigGetOK(settings, django.APPVAR_DEBUG, true); !v || ok {
+		t.Errorf("expected the default for an unparsable value, got %v, %v", v, ok)
+	}
+
+	if v, ok := django.ConfigGetOK[string](settings, django.APPVAR_HOST); v != "localhost" || ok {
+		t.Errorf("expected the registered default for a mistyped value, got %q, %v", v, ok)
+	}
+}
+
+func TestLoadSettingsErrors(t *testing.T) {
+	t.Run("UnsupportedFile", func(t *testing.T) {
+		var _, err = django.LoadSettings(django.FileSource(writeFile(t, "config.ini", "")))
+		if err == nil {
+			t.Fatal("expected an error for an unsupported file format")
+		}
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		var _, err = django.LoadSettings(django.FileSource(filepath.Join(t.TempDir(), "missing.yml")))
+		if err == nil {
+			t.Fatal("expected an error for a missing file")
+		}
+	})
+
+	t.Run("ValueAndFile", func(t *testing.T) {
+		t.Setenv("TEST_DJANGO_TEST_API_TOKEN", "token")
+		t.Setenv("TEST_DJANGO_TEST_API_TOKEN_FILE", writeFile(t, "token", "token"))
+
+		var _, err = django.LoadSettings(django.EnvSource("TEST_DJANGO_"))
+		if err == nil {
+			t.Fatal("expected an error when both the value and the file are set")
+		}
+	})
+}
+
+func TestSettingDefinitionChecks(t *testing.T) {
+	var tests = []struct {
+		name     string
+		settings map[string]any
+		expected []string
+	}{
+		{
+			name: "Valid",
+			settings: map[string]any{
+				django.APPVAR_ALLOWED_HOSTS: []string{"*"},
+				"TEST_API_TOKEN":            "token",
+				"TEST_PAGE_SIZE":            "20",
+			},
+		},
+		{
+			name:     "Required",
+			settings: map[string]any{},
+			expected: []string{"settings.matches.ALLOWED_HOSTS", "settings.matches.TEST_API_TOKEN"},
+		},
+		{
+			name: "Invalid",
+			settings: map[string]any{
+				django.APPVAR_ALLOWED_HOSTS:    []string{},
+				django.APPVAR_DEBUG:            "not a bool",
+				"TEST_API_TOKEN":               1234,
+				"TEST_PAGE_SIZE":               -1,
+				django.APPVAR_SHUTDOWN_TIMEOUT: 30,
+			},
+			expected: []string{
+				"settings.invalid.ALLOWED_HOSTS",
+				"settings.matches.DEBUG",
+				"settings.matches.TEST_API_TOKEN",
+				"settings.invalid.TEST_PAGE_SIZE",
+				"settings.matches.SHUTDOWN_TIMEOUT",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var messages = checks.RunCheck(
+				context.Background(), checks.TagSettings,
+				(*django.Application)(nil), django.Config(test.settings),
+			)
+
+			var ids = make([]string, 0, len(messages))
+			for _, msg := range messages {
+				ids = append(ids, msg.ID)
+			}
+
+			slices.Sort(ids)
+			slices.Sort(test.expected)
+			if !slices.Equal(ids, test.expected) {
+				t.Errorf("expected messages %v, got %v", test.expected, ids)
+			}
+		})
+	}
+}
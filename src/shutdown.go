@@ -0,0 +1,182 @@
+package django
+
+import (
+	"context"
+	"crypto/tls"
+	goErrs "errors"
+	"net/http"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-django/src/core/mail"
+	"github.com/Nigel2392/goldcrest"
+)
+
+// LifecyclePhase is the phase of the application's server, see [Application.Phase].
+type LifecyclePhase int32
+
+const (
+	// The server has not started listening yet.
+	PhaseStarting LifecyclePhase = iota
+
+	// The server is accepting and serving requests.
+	PhaseServing
+
+	// The server stopped accepting new connections and waits for in-flight requests to finish.
+	PhaseDraining
+
+	// The server has shut down and the shutdown hooks have run.
+	PhaseStopped
+)
+
+func (p LifecyclePhase) String() string {
+	switch p {
+	case PhaseStarting:
+		return "starting"
+	case PhaseServing:
+		return "serving"
+	case PhaseDraining:
+		return "draining"
+	case PhaseStopped:
+		return "stopped"
+	}
+	return "unknown"
+}
+
+const (
+	// The default deadline for in-flight requests to finish when shutting down the server.
+	DefaultShutdownTimeout = 30 * time.Second
+
+	// The default maximum duration for reading the request headers.
+	DefaultReadHeaderTimeout = 10 * time.Second
+
+	// The default maximum amount of time to wait for the next request on a keep-alive connection.
+	DefaultIdleTimeout = 2 * time.Minute
+)
+
+// DefaultShutdownSignals are the signals which gracefully shut down the server started by [Application.Serve].
+var DefaultShutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// Phase returns the current phase of the application's server.
+func (a *Application) Phase() LifecyclePhase {
+	return LifecyclePhase(a.phase.Load())
+}
+
+// Ready reports whether the application is serving requests.
+//
+// It returns false while the server is starting, and as soon as
+// the server starts draining connections during the shutdown,
+// so load balancers can stop routing traffic to the application.
+func (a *Application) Ready() bool {
+	return a.initialized.Load() && a.Phase() == PhaseServing
+}
+
+func (a *Application) setPhase(phase LifecyclePhase) {
+	var old = LifecyclePhase(a.phase.Swap(int32(phase)))
+	if old != phase {
+		a.Log.Logf(logger.DBG, "Server phase changed from %s to %s", old, phase)
+	}
+}
+
+// newServer creates a http server with the timeouts configured in the settings.
+func (a *Application) newServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		TLSConfig:         ConfigGet[*tls.Config](a.Settings, APPVAR_TLS_CONFIG),
+		ReadTimeout:       ConfigGet(a.Settings, APPVAR_SERVER_READ_TIMEOUT, time.Duration(0)),
+		ReadHeaderTimeout: ConfigGet(a.Settings, APPVAR_SERVER_READ_HEADER_TIMEOUT, DefaultReadHeaderTimeout),
+		WriteTimeout:      ConfigGet(a.Settings, APPVAR_SERVER_WRITE_TIMEOUT, time.Duration(0)),
+		IdleTimeout:       ConfigGet(a.Settings, APPVAR_SERVER_IDLE_TIMEOUT, DefaultIdleTimeout),
+	}
+}
+
+// newQuitter returns the function which gracefully shuts down the servers.
+//
+// The servers are only shut down once, concurrent and subsequent calls
+// wait for the shutdown to complete and return the same error.
+func (a *Application) newQuitter(servers []*http.Server) func() error {
+	var (
+		once sync.Once
+		err  error
+	)
+	return func() error {
+		once.Do(func() {
+			err = a.shutdown(servers)
+		})
+		return err
+	}
+}
+
+// shutdown gracefully shuts down the servers.
+//
+// The application is marked as not ready first, after the [APPVAR_SHUTDOWN_DRAIN_DELAY] the servers stop
+// accepting new connections and in-flight requests are given until the [APPVAR_SHUTDOWN_TIMEOUT] to finish.
+//
+// Afterwards the [HOOK_SERVER_SHUTDOWN] hooks are run in order,
+// the mail backends are closed, the database pools are closed and finally the log file is closed.
+func (a *Application) shutdown(servers []*http.Server) error {
+	a.setPhase(PhaseDraining)
+
+	var delay = ConfigGet(a.Settings, APPVAR_SHUTDOWN_DRAIN_DELAY, time.Duration(0))
+	if delay > 0 {
+		a.Log.Logf(logger.INF, "Marked as not ready, waiting %s before draining connections", delay)
+		time.Sleep(delay)
+	}
+
+	var timeout = ConfigGet(a.Settings, APPVAR_SHUTDOWN_TIMEOUT, DefaultShutdownTimeout)
+	var ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(servers))
+	)
+	for i, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.Log.Logf(logger.INF, "Shutting down server on %s", server.Addr)
+			var err = server.Shutdown(ctx)
+			if goErrs.Is(err, context.DeadlineExceeded) {
+				a.Log.Logf(logger.WRN, "Requests to %s did not finish within %s, closing remaining connections", server.Addr, timeout)
+				err = goErrs.Join(err, server.Close())
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	var err = goErrs.Join(errs...)
+	for _, hook := range goldcrest.Get[DjangoHook](HOOK_SERVER_SHUTDOWN) {
+		err = goErrs.Join(err, hook(a))
+	}
+
+	if e := mail.CloseAll(); e != nil {
+		err = goErrs.Join(err, e)
+	}
+
+	for _, name := range ConfigGet(a.Settings, APPVAR_DATABASES, []string{APPVAR_DATABASE}) {
+		var db, ok = ConfigGetOK[drivers.Database](a.Settings, name)
+		if !ok {
+			continue
+		}
+		a.Log.Logf(logger.DBG, "Closing database %q", name)
+		if e := db.Close(); e != nil {
+			err = goErrs.Join(err, e)
+		}
+	}
+
+	a.setPhase(PhaseStopped)
+
+	if a.logFile != nil {
+		if e := a.logFile.Close(); e != nil {
+			err = goErrs.Join(err, e)
+		}
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+package django
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Nigel2392/go-django/src/core/trace"
+)
+
+const (
+	// The default URL of the trace debug page.
+	DefaultTracingDebugURL = "/__debug__/traces"
+
+	// The default number of recent traces kept for the trace debug page.
+	DefaultTracingDebugTraces = 50
+)
+
+// setupTracing enables the [trace.Default] tracer if tracing is enabled.
+//
+// Spans are written to stdout if [APPVAR_TRACING_STDOUT] is set,
+// in DEBUG mode the recent traces are kept in memory for the trace debug page.
+func (a *Application) setupTracing() {
+	if !ConfigGet(a.Settings, APPVAR_TRACING, false) {
+		return
+	}
+
+	if ConfigGet(a.Settings, APPVAR_TRACING_STDOUT, false) {
+		trace.Default.AddExporter(trace.NewJSONExporter(os.Stdout))
+	}
+
+	if ConfigGet(a.Settings, APPVAR_DEBUG, true) {
+		a.traces = trace.NewMemoryExporter(
+			ConfigGet(a.Settings, APPVAR_TRACING_DEBUG_TRACES, DefaultTracingDebugTraces),
+		)
+		a.traces.SetMaxSpans(
+			ConfigGet(a.Settings, APPVAR_TRACING_DEBUG_SPANS, trace.DefaultMaxSpansPerTrace),
+		)
+		trace.Default.AddExporter(a.traces)
+	}
+
+	trace.Default.SetEnabled(true)
+}
+
+// tracingMiddleware serves the trace debug page in DEBUG mode.
+//
+// The page is served before any other middleware so requests to it are not traced.
+func (a *Application) tracingMiddleware(next http.Handler) http.Handler {
+	if a.traces == nil {
+		return next
+	}
+
+	var debugURL = ConfigGet(a.Settings, APPVAR_TRACING_DEBUG_URL, DefaultTracingDebugURL)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != debugURL || r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if err := traceDebugTemplate.Execute(w, a.traces.Traces()); err != nil {
+			a.Log.Errorf("Failed to render the trace debug page: %v", err)
+		}
+	})
+}
+
+var traceDebugTemplate = template.Must(template.New("traces").Funcs(template.FuncMap{
+	"duration": func(d time.Duration) string {
+		return d.Round(time.Microsecond).String()
+	},
+	"percent": func(f float64) template.CSS {
+		return template.CSS(fmt.Sprintf("%.3f%%", f))
+	},
+	"indent": func(depth int) template.CSS {
+		return template.CSS(fmt.Sprintf("%.1fem", float64(depth)*1.2))
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Recent traces</title>
+<style>
+body { font: 13px monospace; margin: 1em; }
+details { border-bottom: 1px solid #ddd; padding: 0.4em 0; }
+summary { cursor: pointer; }
+table { width: 100%; border-collapse: collapse; margin-top: 0.5em; }
+td { padding: 2px 4px; white-space: nowrap; vertical-align: middle; }
+td.name { width: 30%; overflow: hidden; text-overflow: ellipsis; max-width: 0; }
+td.duration { width: 8%; text-align: right; }
+td.timeline { position: relative; }
+.bar { position: relative; height: 12px; min-width: 1px; background: #4a90d9; }
+.bar.error { background: #d9534f; }
+.muted { color: #888; }
+</style>
+</head>
+<body>
+<h1>Recent traces</h1>
+{{ if not . }}<p class="muted">No traces have been recorded yet.</p>{{ end }}
+{{ range . }}{{ $root := .Root }}
+<details>
+<summary><strong>{{ $root.Name }}</strong> {{ duration .Duration }} <span class="muted">{{ len .Spans }} spans{{ if .Dropped }} ({{ .Dropped }} dropped){{ end }}, {{ $root.Start.Format "15:04:05.000" }}, trace {{ .ID }}</span></summary>
+<table>
+{{ range .Waterfall }}
+<tr title="{{ range $key, $value := .Span.Attributes }}{{ $key }}={{ $value }}&#10;{{ end }}{{ .Span.Error }}">
+<td class="name"><span style="padding-left: {{ indent .Depth }}">{{ .Span.Name }}</span></td>
+<td class="duration">{{ duration .Span.Duration }}</td>
+<td class="timeline"><div class="bar{{ if eq .Span.Status "error" }} error{{ end }}" style="left: {{ percent .Offset }}; width: {{ percent .Width }}"></div></td>
+</tr>
+{{ end }}
+</table>
+</details>
+{{ end }}
+</body>
+</html>`))
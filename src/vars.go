@@ -1,6 +1,17 @@
 package django
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Nigel2392/go-django/queries/src/drivers"
+	"github.com/Nigel2392/go-django/src/core/cors"
+	"github.com/Nigel2392/go-django/src/core/health"
+	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-django/src/core/trace"
+)
 
 const (
 
@@ -40,9 +51,54 @@ const (
 	// A custom TLS configuration for the application
 	APPVAR_TLS_CONFIG = "TLS_CONFIG" // *tls.Config
 
+	// The maximum duration for reading an entire request, including the body. Disabled by default.
+	APPVAR_SERVER_READ_TIMEOUT = "SERVER_READ_TIMEOUT" // time.Duration
+
+	// The maximum duration for reading the request headers, defaults to 10 seconds.
+	APPVAR_SERVER_READ_HEADER_TIMEOUT = "SERVER_READ_HEADER_TIMEOUT" // time.Duration
+
+	// The maximum duration before timing out writes of the response. Disabled by default.
+	APPVAR_SERVER_WRITE_TIMEOUT = "SERVER_WRITE_TIMEOUT" // time.Duration
+
+	// The maximum amount of time to wait for the next request on a keep-alive connection, defaults to 2 minutes.
+	APPVAR_SERVER_IDLE_TIMEOUT = "SERVER_IDLE_TIMEOUT" // time.Duration
+
+	// The deadline for in-flight requests to finish when the server shuts down, defaults to 30 seconds.
+	APPVAR_SHUTDOWN_TIMEOUT = "SHUTDOWN_TIMEOUT" // time.Duration
+
+	// The time between marking the application as not ready and draining connections when the server shuts down.
+	//
+	// This gives load balancers time to notice the application is not ready and stop routing traffic to it.
+	APPVAR_SHUTDOWN_DRAIN_DELAY = "SHUTDOWN_DRAIN_DELAY" // time.Duration
+
+	// The signals which gracefully shut down the server, defaults to SIGINT and SIGTERM.
+	APPVAR_SHUTDOWN_SIGNALS = "SHUTDOWN_SIGNALS" // []os.Signal
+
 	// The database connection for the application
 	APPVAR_DATABASE = "DATABASE" // drivers.Database
 
+	// The names of the settings which hold a drivers.Database, defaults to [APPVAR_DATABASE].
+	//
+	// The pool configuration, retries and startup health checks are applied to each of these databases.
+	APPVAR_DATABASES = "DATABASES" // []string
+
+	// The connection pool configuration for each database, keyed by the name of the database's setting.
+	APPVAR_DATABASE_POOLS = "DATABASE_POOLS" // map[string]drivers.PoolConfig
+
+	// Retry statements which fail with a transient error, the databases are wrapped with drivers.WithRetry.
+	APPVAR_DATABASE_RETRY = "DATABASE_RETRY" // drivers.RetryConfig
+
+	// The timeout used when pinging the databases during the startup checks, defaults to 5 seconds.
+	APPVAR_DATABASE_PING_TIMEOUT = "DATABASE_PING_TIMEOUT" // time.Duration
+
+	// The default timeout of each statement, keyed by the name of the database's setting.
+	//
+	// The databases are wrapped with drivers.WithQueryTimeout, querysets can override it with QuerySet.Timeout.
+	APPVAR_DATABASE_QUERY_TIMEOUTS = "DATABASE_QUERY_TIMEOUTS" // map[string]time.Duration
+
+	// Statements which take longer than this duration are logged as a warning, see drivers.SLOW_QUERY_THRESHOLD.
+	APPVAR_DATABASE_SLOW_QUERY_THRESHOLD = "DATABASE_SLOW_QUERY_THRESHOLD" // time.Duration
+
 	// Continue running the application after executing cli- commands
 	APPVAR_CONTINUE_AFTER_COMMANDS = "CONTINUE_AFTER_COMMAND" // bool
 
@@ -52,6 +108,20 @@ const (
 	// Log static routes that are accessed
 	APPVAR_STATIC_ROUTE_LOGGING_ENABLED = "STATIC_ROUTE_LOGGING_ENABLED" // bool
 
+	// The format of the log output of the application logger, one of "text" (default), "json" or "logfmt".
+	//
+	// Only used when no logger is provided with [AppLogger].
+	APPVAR_LOG_FORMAT = "LOG_FORMAT" // string
+
+	// The file the application logger writes to instead of stdout.
+	APPVAR_LOG_FILE = "LOG_FILE" // string
+
+	// The size in bytes at which the log file is rotated, defaults to 10MB.
+	APPVAR_LOG_FILE_MAX_SIZE = "LOG_FILE_MAX_SIZE" // int64
+
+	// The number of rotated log files to keep, defaults to 5.
+	APPVAR_LOG_FILE_MAX_BACKUPS = "LOG_FILE_MAX_BACKUPS" // int
+
 	// Set the maxage on the cache for staticfiles
 	// If 0, no caching headers will be sent.
 	APPVAR_STATIC_ROUTE_CACHING_MAXAGE = "STATIC_ROUTE_CACHING_MAXAGE" // int
@@ -67,10 +137,246 @@ const (
 	// Disable nosurf middleware for the application
 	APPVAR_DISABLE_NOSURF = "DISABLE_NOSURF" // bool
 
+	// Record the queries executed for each request and add the statistics to the response headers.
+	// Duplicated queries (possible N+1 queries) are logged as a warning.
+	APPVAR_QUERY_STATS = "QUERY_STATS" // bool
+
+	// The number of times a query with the same shape must be executed in a request to be reported as duplicated, defaults to 3.
+	APPVAR_QUERY_STATS_DUPLICATES = "QUERY_STATS_DUPLICATES" // int
+
+	// Add a panel listing the executed queries to HTML responses, only used in debug mode.
+	APPVAR_QUERY_STATS_PANEL = "QUERY_STATS_PANEL" // bool
+
 	// APPVAR_TRANSLATIONS_DEFAULT_LOCALE
 	APPVAR_TRANSLATIONS_DEFAULT_LOCALE = "TRANSLATIONS_DEFAULT_LOCALE" // string
+
+	// Serve the liveness and readiness endpoints, defaults to true.
+	APPVAR_HEALTH_ENDPOINTS = "HEALTH_ENDPOINTS" // bool
+
+	// The URL of the liveness endpoint, defaults to "/healthz".
+	APPVAR_HEALTH_LIVENESS_URL = "HEALTH_LIVENESS_URL" // string
+
+	// The URL of the readiness endpoint, defaults to "/readyz".
+	APPVAR_HEALTH_READINESS_URL = "HEALTH_READINESS_URL" // string
+
+	// The timeout of health probes which do not define their own timeout, defaults to 5 seconds.
+	APPVAR_HEALTH_PROBE_TIMEOUT = "HEALTH_PROBE_TIMEOUT" // time.Duration
+
+	// Probe whether the default media backend is writable by saving and deleting a file, defaults to true.
+	APPVAR_HEALTH_MEDIA_PROBE = "HEALTH_MEDIA_PROBE" // bool
+
+	// Fail the liveness probe when more goroutines are running, i.e. because requests are stuck on a deadlock, 0 (the default) disables the probe.
+	APPVAR_HEALTH_MAX_GOROUTINES = "HEALTH_MAX_GOROUTINES" // int
+
+	// Serve the metrics endpoint and record the built-in request metrics, defaults to false.
+	APPVAR_METRICS = "METRICS" // bool
+
+	// The URL of the metrics endpoint, defaults to "/metrics".
+	APPVAR_METRICS_URL = "METRICS_URL" // string
+
+	// The IP addresses and CIDR ranges allowed to request the metrics endpoint, all addresses are allowed if empty.
+	APPVAR_METRICS_ALLOWED_IPS = "METRICS_ALLOWED_IPS" // []string
+
+	// The bearer token required to request the metrics endpoint, no token is required if empty.
+	APPVAR_METRICS_TOKEN = "METRICS_TOKEN" // string
+
+	// Record spans for requests, queries, template rendering, cache lookups and sent mail, defaults to false.
+	APPVAR_TRACING = "TRACING" // bool
+
+	// Write each span as a JSON line to stdout, defaults to false.
+	APPVAR_TRACING_STDOUT = "TRACING_STDOUT" // bool
+
+	// In DEBUG mode, the URL of the page with the span waterfall of recent requests, defaults to "/__debug__/traces".
+	APPVAR_TRACING_DEBUG_URL = "TRACING_DEBUG_URL" // string
+
+	// The number of recent traces shown on the debug page, defaults to 50.
+	APPVAR_TRACING_DEBUG_TRACES = "TRACING_DEBUG_TRACES" // int
+
+	// The number of spans kept per trace for the debug page, defaults to 1000.
+	APPVAR_TRACING_DEBUG_SPANS = "TRACING_DEBUG_SPANS" // int
+
+	// Set the security headers below on each response, defaults to true.
+	APPVAR_SECURITY_HEADERS = "SECURITY_HEADERS" // bool
+
+	// The max-age of the Strict-Transport-Security header, the header is only sent over HTTPS if it is larger than 0.
+	APPVAR_SECURE_HSTS_SECONDS = "SECURE_HSTS_SECONDS" // int
+
+	// Add the includeSubDomains directive to the Strict-Transport-Security header.
+	APPVAR_SECURE_HSTS_INCLUDE_SUBDOMAINS = "SECURE_HSTS_INCLUDE_SUBDOMAINS" // bool
+
+	// Add the preload directive to the Strict-Transport-Security header.
+	APPVAR_SECURE_HSTS_PRELOAD = "SECURE_HSTS_PRELOAD" // bool
+
+	// Set the "X-Content-Type-Options: nosniff" header, defaults to true.
+	APPVAR_SECURE_CONTENT_TYPE_NOSNIFF = "SECURE_CONTENT_TYPE_NOSNIFF" // bool
+
+	// The Referrer-Policy header, defaults to "same-origin". An empty string omits the header.
+	APPVAR_SECURE_REFERRER_POLICY = "SECURE_REFERRER_POLICY" // string
+
+	// The Cross-Origin-Opener-Policy header, defaults to "same-origin". An empty string omits the header.
+	APPVAR_SECURE_CROSS_ORIGIN_OPENER_POLICY = "SECURE_CROSS_ORIGIN_OPENER_POLICY" // string
+
+	// The Permissions-Policy header, i.e. "camera=(), geolocation=(self)". Omitted by default.
+	APPVAR_SECURE_PERMISSIONS_POLICY = "SECURE_PERMISSIONS_POLICY" // string
+
+	// The directives of the Content-Security-Policy mapped to their sources, no policy is sent if empty.
+	//
+	// The [CSPNonceSource] is replaced with the nonce of the request.
+	APPVAR_CSP = "CSP" // map[string][]string
+
+	// Send the policy as Content-Security-Policy-Report-Only, violations are reported but not blocked.
+	APPVAR_CSP_REPORT_ONLY = "CSP_REPORT_ONLY" // bool
+
+	// The URL violation reports are sent to, defaults to "/csp-report". An empty string disables the endpoint.
+	APPVAR_CSP_REPORT_URL = "CSP_REPORT_URL" // string
+
+	// The CORS policy of all routes which are not matched by [APPVAR_CORS_ROUTES], no CORS headers are sent if it allows no origins.
+	APPVAR_CORS = "CORS" // cors.Config
+
+	// The CORS policies of route groups mapped to the path prefix of the group, i.e. "/api/".
+	// The policy of the longest matching prefix is used instead of the [APPVAR_CORS] policy.
+	APPVAR_CORS_ROUTES = "CORS_ROUTES" // map[string]cors.Config
 )
 
 func APPVAR_ErrorCode(code int) string {
 	return fmt.Sprintf("HandleErrorCode%d", code)
 }
+
+// The schemas of the built-in settings which can be loaded from files and environment variables.
+//
+// Settings which hold Go values, i.e. [APPVAR_DATABASE] or [APPVAR_TLS_CONFIG],
+// are not registered and can only be provided by the defaults or with [Configure].
+var (
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_DEBUG, Default: true, Description: "Run the application in debug mode"})
+	_ = DefineSetting(SettingSchema[[]string]{
+		Key:         APPVAR_ALLOWED_HOSTS,
+		Description: "Allowed hosts for the application",
+		Required:    true,
+		Validators: []func([]string) error{
+			func(hosts []string) error {
+				if len(hosts) == 0 {
+					return errors.New("ALLOWED_HOSTS setting must not be empty")
+				}
+				return nil
+			},
+		},
+	})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_RECOVERER, Default: true, Description: "Use recoverer middleware to recover from panics"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_HOST, Default: "localhost", Description: "Host address to bind the application's server to"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_PORT, Default: "8080", Description: "Port to bind the application's server to"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_STATIC_URL, Description: "The URL to serve static files from"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_TLS_PORT, Description: "The port to bind the application's server to for TLS"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_TLS_CERT, Description: "The path to the certificate file for TLS"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_TLS_KEY, Description: "The path to the key file for TLS"})
+	_ = DefineSetting(SettingSchema[time.Duration]{Key: APPVAR_SERVER_READ_TIMEOUT, Description: "The maximum duration for reading an entire request"})
+	_ = DefineSetting(SettingSchema[time.Duration]{Key: APPVAR_SERVER_READ_HEADER_TIMEOUT, Default: DefaultReadHeaderTimeout, Description: "The maximum duration for reading the request headers"})
+	_ = DefineSetting(SettingSchema[time.Duration]{Key: APPVAR_SERVER_WRITE_TIMEOUT, Description: "The maximum duration before timing out writes of the response"})
+	_ = DefineSetting(SettingSchema[time.Duration]{Key: APPVAR_SERVER_IDLE_TIMEOUT, Default: DefaultIdleTimeout, Description: "The maximum amount of time to wait for the next request on a keep-alive connection"})
+	_ = DefineSetting(SettingSchema[time.Duration]{Key: APPVAR_SHUTDOWN_TIMEOUT, Default: DefaultShutdownTimeout, Description: "The deadline for in-flight requests to finish when the server shuts down"})
+	_ = DefineSetting(SettingSchema[time.Duration]{Key: APPVAR_SHUTDOWN_DRAIN_DELAY, Description: "The time between marking the application as not ready and draining connections"})
+	_ = DefineSetting(SettingSchema[[]string]{Key: APPVAR_DATABASES, Default: []string{APPVAR_DATABASE}, Description: "The keys of the database settings to open, check and close"})
+	_ = DefineSetting(SettingSchema[time.Duration]{Key: APPVAR_DATABASE_PING_TIMEOUT, Default: drivers.DefaultPingTimeout, Description: "The timeout for pinging the databases in the startup checks"})
+	_ = DefineSetting(SettingSchema[time.Duration]{Key: APPVAR_DATABASE_SLOW_QUERY_THRESHOLD, Description: "The duration after which a statement is logged as a slow query"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_CONTINUE_AFTER_COMMANDS, Description: "Continue running the application after a command has run"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_ROUTE_LOGGING_ENABLED, Description: "Log each request to the application"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_STATIC_ROUTE_LOGGING_ENABLED, Description: "Log requests for static files"})
+	_ = DefineSetting(SettingSchema[string]{
+		Key:         APPVAR_LOG_FORMAT,
+		Default:     LogFormatText,
+		Description: "The format of the log output, one of text, json or logfmt",
+		Validators: []func(string) error{
+			func(format string) error {
+				switch format {
+				case LogFormatText, LogFormatJSON, LogFormatLogfmt:
+					return nil
+				}
+				return fmt.Errorf("LOG_FORMAT must be one of %q, %q or %q, got %q", LogFormatText, LogFormatJSON, LogFormatLogfmt, format)
+			},
+		},
+	})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_LOG_FILE, Description: "The file the application logger writes to instead of stdout"})
+	_ = DefineSetting(SettingSchema[int64]{Key: APPVAR_LOG_FILE_MAX_SIZE, Default: logger.DefaultMaxFileSize, Description: "The size in bytes at which the log file is rotated"})
+	_ = DefineSetting(SettingSchema[int]{Key: APPVAR_LOG_FILE_MAX_BACKUPS, Default: DefaultLogFileMaxBackups, Description: "The number of rotated log files to keep"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_REQUESTS_PROXIED, Description: "Whether the webserver is behind a proxy"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_DISABLE_NOSURF, Description: "Disable the nosurf middleware"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_TRANSLATIONS_DEFAULT_LOCALE, Description: "The default locale for translations"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_HEALTH_ENDPOINTS, Default: true, Description: "Serve the liveness and readiness endpoints"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_HEALTH_LIVENESS_URL, Default: DefaultHealthLivenessURL, Description: "The URL of the liveness endpoint"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_HEALTH_READINESS_URL, Default: DefaultHealthReadinessURL, Description: "The URL of the readiness endpoint"})
+	_ = DefineSetting(SettingSchema[time.Duration]{Key: APPVAR_HEALTH_PROBE_TIMEOUT, Default: health.DefaultTimeout, Description: "The timeout of health probes which do not define their own timeout"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_HEALTH_MEDIA_PROBE, Default: true, Description: "Probe whether the default media backend is writable"})
+	_ = DefineSetting(SettingSchema[int]{
+		Key:         APPVAR_HEALTH_MAX_GOROUTINES,
+		Description: "The number of goroutines above which the liveness probe fails, 0 disables the probe",
+		Validators: []func(int) error{
+			func(n int) error {
+				if n < 0 {
+					return fmt.Errorf("HEALTH_MAX_GOROUTINES must not be negative, got %d", n)
+				}
+				return nil
+			},
+		},
+	})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_METRICS, Description: "Serve the metrics endpoint and record the request metrics"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_METRICS_URL, Default: DefaultMetricsURL, Description: "The URL of the metrics endpoint"})
+	_ = DefineSetting(SettingSchema[[]string]{
+		Key:         APPVAR_METRICS_ALLOWED_IPS,
+		Description: "The IP addresses and CIDR ranges allowed to request the metrics endpoint",
+		Validators: []func([]string) error{
+			func(ips []string) error {
+				var _, err = parseIPPrefixes(ips)
+				return err
+			},
+		},
+	})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_METRICS_TOKEN, Secret: true, Description: "The bearer token required to request the metrics endpoint"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_TRACING, Description: "Record spans for requests, queries, templates, cache lookups and mail"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_TRACING_STDOUT, Description: "Write each span as a JSON line to stdout"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_TRACING_DEBUG_URL, Default: DefaultTracingDebugURL, Description: "The URL of the trace debug page, only served in DEBUG mode"})
+	_ = DefineSetting(SettingSchema[int]{Key: APPVAR_TRACING_DEBUG_TRACES, Default: DefaultTracingDebugTraces, Description: "The number of recent traces shown on the debug page"})
+	_ = DefineSetting(SettingSchema[int]{Key: APPVAR_TRACING_DEBUG_SPANS, Default: trace.DefaultMaxSpansPerTrace, Description: "The number of spans kept per trace for the debug page"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_SECURITY_HEADERS, Default: true, Description: "Set the security headers on each response"})
+	_ = DefineSetting(SettingSchema[int]{
+		Key:         APPVAR_SECURE_HSTS_SECONDS,
+		Description: "The max-age of the Strict-Transport-Security header, 0 omits the header",
+		Validators: []func(int) error{
+			func(seconds int) error {
+				if seconds < 0 {
+					return fmt.Errorf("SECURE_HSTS_SECONDS must not be negative, got %d", seconds)
+				}
+				return nil
+			},
+		},
+	})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_SECURE_HSTS_INCLUDE_SUBDOMAINS, Description: "Add includeSubDomains to the Strict-Transport-Security header"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_SECURE_HSTS_PRELOAD, Description: "Add preload to the Strict-Transport-Security header"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_SECURE_CONTENT_TYPE_NOSNIFF, Default: true, Description: "Set the X-Content-Type-Options: nosniff header"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_SECURE_REFERRER_POLICY, Default: DefaultReferrerPolicy, Description: "The Referrer-Policy header"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_SECURE_CROSS_ORIGIN_OPENER_POLICY, Default: DefaultCrossOriginOpenerPolicy, Description: "The Cross-Origin-Opener-Policy header"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_SECURE_PERMISSIONS_POLICY, Description: "The Permissions-Policy header"})
+	_ = DefineSetting(SettingSchema[map[string][]string]{Key: APPVAR_CSP, Description: "The directives of the Content-Security-Policy mapped to their sources"})
+	_ = DefineSetting(SettingSchema[bool]{Key: APPVAR_CSP_REPORT_ONLY, Description: "Report Content-Security-Policy violations without blocking them"})
+	_ = DefineSetting(SettingSchema[string]{Key: APPVAR_CSP_REPORT_URL, Default: DefaultCSPReportURL, Description: "The URL Content-Security-Policy violation reports are sent to"})
+	_ = DefineSetting(SettingSchema[cors.Config]{
+		Key:         APPVAR_CORS,
+		Description: "The CORS policy of all routes not matched by CORS_ROUTES",
+		Validators:  []func(cors.Config) error{cors.Config.Validate},
+	})
+	_ = DefineSetting(SettingSchema[map[string]cors.Config]{
+		Key:         APPVAR_CORS_ROUTES,
+		Description: "The CORS policies of route groups mapped to their path prefix",
+		Validators: []func(map[string]cors.Config) error{
+			func(routes map[string]cors.Config) error {
+				for prefix, config := range routes {
+					if !strings.HasPrefix(prefix, "/") {
+						return fmt.Errorf("CORS_ROUTES prefix %q must start with a slash", prefix)
+					}
+					if err := config.Validate(); err != nil {
+						return fmt.Errorf("CORS_ROUTES %q: %w", prefix, err)
+					}
+				}
+				return nil
+			},
+		},
+	})
+)
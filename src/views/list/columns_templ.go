@@ -727,6 +727,14 @@ skipConversionChecks:
 		fieldValue = c.process(r, defs, row, fieldValue.(VAL))
 	}
 
+	if c.process == nil {
+		if choicesField, ok := field.(attrs.CanChoices); ok {
+			if label, ok := choicesField.Choices().ChoiceLabel(r.Context(), fieldValue); ok {
+				return label
+			}
+		}
+	}
+
 	switch f := fieldValue.(type) {
 	case time.Time:
 		return trans.Time(r.Context(), f, trans.LONG_TIME_FORMAT)
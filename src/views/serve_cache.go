@@ -4,14 +4,26 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"hash/fnv"
-	"io"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Nigel2392/cache"
+	django "github.com/Nigel2392/go-django/src"
+	"github.com/Nigel2392/go-django/src/core/ctx"
 	"github.com/Nigel2392/go-django/src/core/logger"
+	"github.com/Nigel2392/go-django/src/core/metrics"
+	"github.com/Nigel2392/go-django/src/core/trace"
+	"github.com/Nigel2392/mux"
+	"github.com/Nigel2392/mux/middleware/authentication"
+	"github.com/alexedwards/scs/v2"
+	"github.com/justinas/nosurf"
 )
 
 func fnvhash(s string) string {
@@ -21,6 +33,30 @@ func fnvhash(s string) string {
 	return fmt.Sprintf("%x", sum)
 }
 
+var cacheRequestsTotal = metrics.Default.Counter(metrics.Opts{
+	Name:   "django_cache_requests_total",
+	Help:   "The number of cache lookups, by cache and result.",
+	Labels: []string{"cache", "result"},
+})
+
+// The time a response is cached for by [CacheView] and [CacheMiddleware]
+// if neither the options nor the response specify one.
+const DefaultCacheTimeout = 10 * time.Minute
+
+// The status codes of responses which are cached.
+var cacheableStatusCodes = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusMultipleChoices,
+	http.StatusMovedPermanently,
+	http.StatusPermanentRedirect,
+	http.StatusNotFound,
+	http.StatusGone,
+}
+
+// the backends used by the cache handlers, tags are invalidated in all of them
+var cacheTagBackends sync.Map // map[string][]string
+
 type byteArray []byte
 
 func (b byteArray) MarshalJSON() ([]byte, error) {
@@ -40,6 +76,10 @@ type CachedResponse struct {
 	ResponseStatusCode int         `json:"status_code"`
 	ResponseHeader     http.Header `json:"header"`
 	ResponseBody       byteArray   `json:"body"`
+
+	// The versions of the tags of the response when it was cached,
+	// the response is stale once any of the tags is invalidated.
+	TagVersions map[string]string `json:"tags,omitempty"`
 }
 
 func newCachedResponse() *CachedResponse {
@@ -59,112 +99,533 @@ func (w *responseWriter) Header() http.Header {
 }
 
 func (w *responseWriter) Write(data []byte) (int, error) {
+	if w.Status == 0 {
+		w.Status = http.StatusOK
+	}
 	return w.Body.Write(data)
 }
 
 func (w *responseWriter) WriteHeader(status int) {
-	w.Status = status
+	if w.Status == 0 {
+		w.Status = status
+	}
+}
+
+// CacheOptions configures the HTTP cache of [CacheView] and [CacheMiddleware].
+//
+// Responses are only cached for GET requests by anonymous users without an Authorization header
+// or a session cookie, HEAD requests are served from the cached GET response.
+//
+// A response is not cached if it sets cookies, if its status code is not cacheable,
+// if its Cache-Control header contains private, no-store or no-cache or if it varies on "*".
+// Responses which contain the CSRF token of the request are not cached either, the token
+// belongs to the CSRF cookie of the client, which nosurf sets outside of the cached response.
+// The same goes for responses which contain the Content-Security-Policy nonce of the request,
+// the policy header of later requests carries a new nonce.
+// The s-maxage and max-age directives of the Cache-Control header take precedence over [CacheOptions.Timeout].
+type CacheOptions struct {
+	// The time a response is cached for, defaults to [DefaultCacheTimeout].
+	Timeout time.Duration
+
+	// The name of the cache backend, the default backend is used if empty.
+	Backend string
+
+	// The prefix of the cache keys, it allows multiple caches to share a backend.
+	KeyPrefix string
+
+	// The request headers which are part of the cache key, in addition to the headers in the Vary header of the response.
+	Vary []string
+
+	// Tags returns the tags of the response to the request, see [AddCacheTags] and [InvalidateCacheTags].
+	Tags func(r *http.Request) []string
+
+	// The names of the session cookies, requests with any of them are not cached.
+	// Defaults to the cookie of the session manager in the [django.APPVAR_SESSION_MANAGER] setting.
+	SessionCookies []string
+}
+
+type httpCache struct {
+	timeout  time.Duration
+	backends []string
+	prefix   string
+	vary     []string
+	tags     func(r *http.Request) []string
+	cookies  []string
+
+	// the middleware warns once if it runs before the user is added to the request
+	middleware   bool
+	orderWarning sync.Once
+}
+
+func newHTTPCache(opts CacheOptions, backends ...string) *httpCache {
+	var c = &httpCache{
+		timeout:  opts.Timeout,
+		backends: backends,
+		prefix:   opts.KeyPrefix,
+		vary:     normalizeVary(opts.Vary),
+		tags:     opts.Tags,
+		cookies:  opts.SessionCookies,
+	}
+
+	if c.timeout == 0 {
+		c.timeout = DefaultCacheTimeout
+	}
+
+	if opts.Backend != "" {
+		c.backends = []string{opts.Backend}
+	}
+
+	cacheTagBackends.Store(strings.Join(c.backends, ","), c.backends)
+	return c
+}
+
+// Cache caches the response of a view for a given duration, a duration of 0 caches the response indefinitely.
+//
+// The response is cached in the first of the cache backends which exists, see [CacheOptions] for which responses are cached.
+func Cache(view http.Handler, duration time.Duration, cacheBackends ...string) http.Handler {
+	if duration == 0 {
+		duration = cache.Infinity
+	}
+
+	return newHTTPCache(CacheOptions{Timeout: duration}, cacheBackends...).handler(view)
+}
+
+// CacheView caches the responses of a view according to the options.
+func CacheView(view http.Handler, opts CacheOptions) http.Handler {
+	return newHTTPCache(opts).handler(view)
+}
+
+// CacheMiddleware returns a middleware which caches the responses of all views according to the options.
+//
+// The middleware should run after the user is added to the request. Requests with a session cookie
+// are never cached, so the pages of authenticated users are not cached if it runs before,
+// a warning is logged once if the auth app is installed and the request has no user.
+func CacheMiddleware(opts CacheOptions) func(next mux.Handler) mux.Handler {
+	var c = newHTTPCache(opts)
+	c.middleware = true
+	return func(next mux.Handler) mux.Handler {
+		return c.handler(next)
+	}
+}
+
+type cacheTagsContextKey struct{}
+
+type cacheTags struct {
+	mu   sync.Mutex
+	tags []string
+}
+
+// AddCacheTags tags the response to the request, the cached response is purged
+// when any of its tags is invalidated with [InvalidateCacheTags].
+//
+// It does nothing if the response is not cached by [CacheView] or [CacheMiddleware].
+func AddCacheTags(r *http.Request, tags ...string) {
+	var holder, ok = r.Context().Value(cacheTagsContextKey{}).(*cacheTags)
+	if !ok {
+		return
+	}
+
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	for _, tag := range tags {
+		if !slices.Contains(holder.tags, tag) {
+			holder.tags = append(holder.tags, tag)
+		}
+	}
 }
 
+// InvalidateCacheTags purges the cached responses with any of the tags from all backends used by the HTTP cache.
+//
+// The cached responses are not deleted, they are treated as stale when they are looked up.
+func InvalidateCacheTags(ctx context.Context, tags ...string) error {
+	var errs []error
+	cacheTagBackends.Range(func(_, value any) bool {
+		var backend = cache.GetCache(value.([]string)...)
+		for _, tag := range tags {
+			if err := backend.Set(ctx, cacheTagKey(tag), newTagVersion(), cache.Infinity); err != nil {
+				errs = append(errs, fmt.Errorf("failed to invalidate cache tag %q: %w", tag, err))
+			}
+		}
+		return true
+	})
+	return errors.Join(errs...)
+}
+
+func cacheTagKey(tag string) string {
+	return fmt.Sprintf("views.cache.tag.%s", tag)
+}
+
+func newTagVersion() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// buildRequestCacheKey returns the key of the cached response to a request without any vary headers.
 func buildRequestCacheKey(req *http.Request) string {
+	return buildCacheKey("", req, nil)
+}
+
+// buildCacheKey returns the key of the cached response to the request,
+// the values of the vary headers of the request are part of the key.
+//
+// HEAD requests share the key of GET requests.
+func buildCacheKey(prefix string, req *http.Request, vary []string) string {
 	var hash = fnv.New128a()
-	for key, values := range req.Header {
+	for _, key := range vary {
 		hash.Write([]byte(key))
-		for _, value := range values {
-			if value == "" {
+		hash.Write([]byte{0})
+		for _, value := range req.Header.Values(key) {
+			hash.Write([]byte(value))
+			hash.Write([]byte{0})
+		}
+	}
+
+	var method = req.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+
+	return fmt.Sprintf("views.cache.page.%s%s.%x.%s", prefix, method, hash.Sum(nil), fnvhash(req.Host+req.URL.RequestURI()))
+}
+
+// buildHeadersKey returns the key of the vary headers of the cached responses to the URL of the request.
+func buildHeadersKey(prefix string, req *http.Request) string {
+	return fmt.Sprintf("views.cache.headers.%s%s", prefix, fnvhash(req.Host+req.URL.RequestURI()))
+}
+
+// normalizeVary returns the canonical, sorted and unique header names.
+func normalizeVary(headers []string) []string {
+	var vary = make([]string, 0, len(headers))
+	for _, header := range headers {
+		for _, name := range strings.Split(header, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
 				continue
 			}
-			hash.Write([]byte(value))
+			vary = append(vary, http.CanonicalHeaderKey(name))
 		}
 	}
-	url := req.URL.String()
-	var sum = hash.Sum(nil)
-	return fmt.Sprintf("views.buildRequestCacheKey.%s.%x.%x", req.Method, sum, fnvhash(url))
+	slices.Sort(vary)
+	return slices.Compact(vary)
 }
 
-/*
-	TODO: FIX REQUEST CACHE IMPLEMENTATION
-*/
-// Cache caches the response of a view for a given duration.
-func Cache(view http.Handler, duration time.Duration, cacheBackends ...string) http.Handler {
-	if duration == 0 {
-		duration = cache.Infinity
+// cacheableRequest reports whether the response to the request can be served from or stored in the cache.
+//
+// Requests with a session cookie are never cached, the response could depend on the session
+// even if the user is anonymous, and the user may not have been added to the request yet.
+func (c *httpCache) cacheableRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
 	}
 
-	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		var cacheKey = buildRequestCacheKey(req)
-		var cacheBackend = cache.GetCache(cacheBackends...)
-
-		if !cacheBackend.Has(context.Background(), cacheKey) {
-			logger.Debugf(
-				"Key does not exist in cache: %s", cacheKey,
-			)
-			var buf = new(bytes.Buffer)
-			var cachedResponse = newCachedResponse()
-			var responseWriter = &responseWriter{
-				Headers: make(http.Header),
-				Body:    buf,
-			}
+	if r.Header.Get("Authorization") != "" {
+		return false
+	}
 
-			view.ServeHTTP(responseWriter, req)
+	for _, name := range c.sessionCookies() {
+		if _, err := r.Cookie(name); err == nil {
+			return false
+		}
+	}
 
-			cachedResponse.ResponseStatusCode = responseWriter.Status
-			cachedResponse.ResponseHeader = responseWriter.Headers
-			cachedResponse.ResponseBody = buf.Bytes()
+	var user = authentication.Retrieve(r)
+	if user == nil && c.middleware && django.Global != nil && django.AppInstalled("auth") {
+		c.orderWarning.Do(func() {
+			logger.Warnf("views.CacheMiddleware runs before the authentication middleware, only requests without a session cookie are cached")
+		})
+	}
 
-			logger.Debugf(
-				"Caching response: %s", cacheKey,
-			)
-			var err = cacheBackend.Set(context.Background(), cacheKey, cachedResponse, duration)
-			if err != nil {
-				logger.Errorf(
-					"Error caching response: %s", err,
-				)
-			}
+	return user == nil || !user.IsAuthenticated()
+}
 
-			w.WriteHeader(responseWriter.Status)
+// sessionCookies returns the configured session cookies, or the cookie of the session manager of the application.
+func (c *httpCache) sessionCookies() []string {
+	if c.cookies != nil {
+		return c.cookies
+	}
 
-			for key, values := range responseWriter.Headers {
-				for _, value := range values {
-					w.Header().Add(key, value)
-				}
-			}
+	if django.Global == nil {
+		return nil
+	}
 
-			_, err = io.Copy(w, buf)
-			if err != nil {
-				logger.Errorf(
-					"Error copying response: %s", err,
-				)
-			}
+	var manager, ok = django.ConfigGetOK[*scs.SessionManager](django.Global.Settings, django.APPVAR_SESSION_MANAGER)
+	if !ok || manager == nil {
+		return nil
+	}
+	return []string{manager.Cookie.Name}
+}
+
+// cacheTimeout returns the time the response can be cached for, it returns false if the response cannot be cached.
+func (c *httpCache) cacheTimeout(status int, header http.Header) (time.Duration, bool) {
+	if !slices.Contains(cacheableStatusCodes, status) {
+		return 0, false
+	}
+
+	if len(header.Values("Set-Cookie")) > 0 || slices.Contains(normalizeVary(header.Values("Vary")), "*") {
+		return 0, false
+	}
+
+	var maxAge, sharedMaxAge = -1, -1
+	for _, directive := range strings.Split(strings.Join(header.Values("Cache-Control"), ","), ",") {
+		var name, value, _ = strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "private", "no-store", "no-cache":
+			return 0, false
+		case "max-age":
+			maxAge, _ = strconv.Atoi(strings.Trim(value, `"`))
+		case "s-maxage":
+			sharedMaxAge, _ = strconv.Atoi(strings.Trim(value, `"`))
+		}
+	}
+
+	var timeout = c.timeout
+	switch {
+	case sharedMaxAge >= 0:
+		timeout = time.Duration(sharedMaxAge) * time.Second
+	case maxAge >= 0:
+		timeout = time.Duration(maxAge) * time.Second
+	}
+
+	return timeout, timeout > 0
+}
+
+// containsRequestTokens reports whether the body contains a token which is unique to the request.
+//
+// The CSRF token is only valid for the CSRF cookie of the client, and the Content-Security-Policy
+// nonce is only valid for the policy sent with the response, a response which contains either
+// of them cannot be served to other requests.
+func containsRequestTokens(r *http.Request, body []byte) bool {
+	return containsToken(body, nosurf.Token(r)) || containsToken(body, ctx.CSPNonce(r.Context()))
+}
+
+// containsToken reports whether the body contains the base64 encoded token.
+//
+// The token can be escaped when it is rendered, i.e. html/template escapes the "+" character,
+// so the longest alphanumeric part of the token is searched for instead.
+func containsToken(body []byte, token string) bool {
+	if token == "" {
+		return false
+	}
+
+	var longest = token
+	var parts = strings.FieldsFunc(token, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	})
+	if len(parts) > 0 {
+		longest = slices.MaxFunc(parts, func(a, b string) int {
+			return len(a) - len(b)
+		})
+	}
+
+	return bytes.Contains(body, []byte(longest))
+}
+
+// varyHeaders returns the vary headers stored for the URL of the request, or the configured vary headers.
+func (c *httpCache) varyHeaders(ctx context.Context, backend cache.TransactionalCache, r *http.Request) []string {
+	var value, err = backend.Get(ctx, buildHeadersKey(c.prefix, r))
+	if err != nil {
+		return c.vary
+	}
+
+	var headers, ok = value.([]string)
+	if !ok {
+		return c.vary
+	}
+	return headers
+}
+
+// lookup returns the cached response for the key, responses with invalidated tags are not returned.
+func (c *httpCache) lookup(ctx context.Context, backend cache.TransactionalCache, key string) (*CachedResponse, bool) {
+	var value, err = backend.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+
+	var cached, ok = value.(*CachedResponse)
+	if !ok || cached == nil {
+		return nil, false
+	}
+
+	for tag, version := range cached.TagVersions {
+		var current, err = backend.Get(ctx, cacheTagKey(tag))
+		if err != nil || current != version {
+			return nil, false
+		}
+	}
+
+	return cached, true
+}
+
+// tagVersions returns the current versions of the tags, tags without a version are assigned one.
+func (c *httpCache) tagVersions(ctx context.Context, backend cache.TransactionalCache, tags []string) (map[string]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	var versions = make(map[string]string, len(tags))
+	for _, tag := range tags {
+		var value, err = backend.Get(ctx, cacheTagKey(tag))
+		if version, ok := value.(string); err == nil && ok {
+			versions[tag] = version
+			continue
+		}
+
+		var version = newTagVersion()
+		if err := backend.Set(ctx, cacheTagKey(tag), version, cache.Infinity); err != nil {
+			return nil, err
+		}
+		versions[tag] = version
+	}
+	return versions, nil
+}
+
+// store caches the response under the vary headers of the response and the configured vary headers.
+//
+// An ETag and a Last-Modified header are added to the response if it has none.
+func (c *httpCache) store(ctx context.Context, backend cache.TransactionalCache, r *http.Request, response *CachedResponse, timeout time.Duration, tags []string) error {
+	var header = response.ResponseHeader
+	if header.Get("ETag") == "" {
+		header.Set("ETag", fmt.Sprintf(`"%s"`, fnvhash(string(response.ResponseBody))))
+	}
+
+	if header.Get("Last-Modified") == "" {
+		header.Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+	}
+
+	var versions, err = c.tagVersions(ctx, backend, tags)
+	if err != nil {
+		return err
+	}
+	response.TagVersions = versions
+
+	var vary = normalizeVary(append(slices.Clone(c.vary), header.Values("Vary")...))
+	if err := backend.Set(ctx, buildHeadersKey(c.prefix, r), vary, timeout); err != nil {
+		return err
+	}
+
+	return backend.Set(ctx, buildCacheKey(c.prefix, r, vary), response, timeout)
+}
+
+func (c *httpCache) handler(view http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !c.cacheableRequest(req) {
+			view.ServeHTTP(w, req)
 			return
 		}
 
-		logger.Debugf(
-			"Retrieving cached response: %s", cacheKey,
+		var (
+			ctx      = req.Context()
+			backend  = cache.GetCache(c.backends...)
+			cacheKey = buildCacheKey(c.prefix, req, c.varyHeaders(ctx, backend, req))
 		)
-		var response, err = cacheBackend.Get(context.Background(), cacheKey)
-		if err != nil {
-			logger.Errorf(
-				"Error getting cached response: %s", err,
-			)
-			view.ServeHTTP(w, req)
+
+		var _, span = trace.Start(ctx, "cache.get", "cache", "view")
+		var cached, hit = c.lookup(ctx, backend, cacheKey)
+		span.SetAttributes("cache.hit", hit)
+		span.End()
+
+		if hit {
+			cacheRequestsTotal.Inc("view", "hit")
+			logger.Debugf("Retrieving cached response: %s", cacheKey)
+			writeCachedResponse(w, req, cached)
 			return
 		}
 
-		var cached = response.(*CachedResponse)
-		w.WriteHeader(cached.ResponseStatusCode)
+		cacheRequestsTotal.Inc("view", "miss")
 
-		for key, values := range cached.ResponseHeader {
-			for _, value := range values {
-				w.Header().Add(key, value)
-			}
+		var tags = &cacheTags{}
+		if c.tags != nil {
+			tags.tags = c.tags(req)
 		}
 
-		_, err = w.Write(cached.ResponseBody)
-		if err != nil {
-			logger.Errorf(
-				"Error writing cached response: %s", err,
-			)
+		var buf = new(bytes.Buffer)
+		var rw = &responseWriter{
+			Headers: make(http.Header),
+			Body:    buf,
 		}
+
+		view.ServeHTTP(rw, req.WithContext(context.WithValue(ctx, cacheTagsContextKey{}, tags)))
+
+		var response = newCachedResponse()
+		response.ResponseStatusCode = rw.Status
+		response.ResponseHeader = rw.Headers
+		response.ResponseBody = buf.Bytes()
+
+		if response.ResponseStatusCode == 0 {
+			response.ResponseStatusCode = http.StatusOK
+		}
+
+		if timeout, ok := c.cacheTimeout(response.ResponseStatusCode, response.ResponseHeader); ok && req.Method == http.MethodGet && !containsRequestTokens(req, response.ResponseBody) {
+			logger.Debugf("Caching response: %s", cacheKey)
+
+			var _, span = trace.Start(ctx, "cache.set", "cache", "view")
+			var err = c.store(ctx, backend, req, response, timeout, tags.tags)
+			span.SetError(err)
+			span.End()
+
+			if err != nil {
+				logger.Errorf("Error caching response: %s", err)
+			}
+		}
+
+		writeCachedResponse(w, req, response)
 	})
 }
+
+// writeCachedResponse writes the response, a 304 Not Modified response is written instead if the
+// ETag or the Last-Modified header of the response match the conditional headers of the request.
+func writeCachedResponse(w http.ResponseWriter, r *http.Request, response *CachedResponse) {
+	var header = w.Header()
+	for key, values := range response.ResponseHeader {
+		header[key] = slices.Clone(values)
+	}
+
+	if response.ResponseStatusCode == http.StatusOK && notModified(r, response.ResponseHeader) {
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(response.ResponseStatusCode)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := w.Write(response.ResponseBody); err != nil {
+		logger.Errorf("Error writing cached response: %s", err)
+	}
+}
+
+// notModified reports whether the conditional headers of the request match the response,
+// If-None-Match takes precedence over If-Modified-Since.
+func notModified(r *http.Request, header http.Header) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		var etag = strings.TrimPrefix(header.Get("ETag"), "W/")
+		if etag == "" {
+			return false
+		}
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	var ims, err = http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.After(ims)
+}
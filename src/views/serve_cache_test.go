@@ -3,6 +3,7 @@ package views_test
 import (
 	"context"
 	"fmt"
+	"html/template"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -11,8 +12,10 @@ import (
 	"time"
 
 	"github.com/Nigel2392/cache"
+	"github.com/Nigel2392/go-django/src/core/ctx"
 	"github.com/Nigel2392/go-django/src/core/logger"
 	"github.com/Nigel2392/go-django/src/views"
+	"github.com/justinas/nosurf"
 
 	_ "unsafe"
 )
@@ -110,7 +113,7 @@ func TestCacheHit(t *testing.T) {
 	time.Sleep(time.Millisecond * 60)
 }
 
-// TestCacheDifferentMethods ensures that different HTTP methods are cached separately.
+// TestCacheDifferentMethods ensures that only GET requests are cached.
 func TestCacheDifferentMethods(t *testing.T) {
 	cacheBackend := cache.GetCache("memory")
 
@@ -129,9 +132,13 @@ func TestCacheDifferentMethods(t *testing.T) {
 	wPost := httptest.NewRecorder()
 	handler.ServeHTTP(wPost, reqPost)
 
+	if wPost.Body.String() != "POST response" {
+		t.Errorf("Expected POST request to be served by the view, got %s", wPost.Body.String())
+	}
+
 	cacheKeyPost := buildRequestCacheKey(reqPost)
-	if !cacheBackend.Has(context.Background(), cacheKeyPost) {
-		t.Error("Expected POST request to be cached")
+	if cacheBackend.Has(context.Background(), cacheKeyPost) {
+		t.Error("Expected POST request not to be cached")
 	}
 
 	// Check if GET and POST cache keys are different
@@ -164,3 +171,204 @@ func TestCacheExpiration(t *testing.T) {
 		t.Error("Expected cache to expire")
 	}
 }
+
+// TestCacheVary tests that responses are cached separately for the values of the Vary headers.
+func TestCacheVary(t *testing.T) {
+	var calls int
+	handler := views.CacheView(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Vary", "Accept-Language")
+		w.Write([]byte(r.Header.Get("Accept-Language")))
+	}), views.CacheOptions{Timeout: time.Millisecond * 50, Backend: "memory"})
+
+	for _, lang := range []string{"en", "nl", "en", "nl"} {
+		req := httptest.NewRequest("GET", "http://example.com/vary", nil)
+		req.Header.Set("Accept-Language", lang)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Body.String() != lang {
+			t.Errorf("Expected body to be %s, got %s", lang, w.Body.String())
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected the view to be called once per language, got %d calls", calls)
+	}
+
+	time.Sleep(time.Millisecond * 60)
+}
+
+// TestCacheSkipped tests that private responses and responses which set cookies are not cached.
+func TestCacheSkipped(t *testing.T) {
+	var tests = map[string]func(w http.ResponseWriter){
+		"private":  func(w http.ResponseWriter) { w.Header().Set("Cache-Control", "private, max-age=60") },
+		"no-store": func(w http.ResponseWriter) { w.Header().Set("Cache-Control", "no-store") },
+		"cookie":   func(w http.ResponseWriter) { http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"}) },
+		"error":    func(w http.ResponseWriter) { w.WriteHeader(http.StatusInternalServerError) },
+	}
+
+	for name, setup := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			handler := views.CacheView(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				setup(w)
+				w.Write([]byte("response"))
+			}), views.CacheOptions{Timeout: time.Millisecond * 50, Backend: "memory"})
+
+			for i := 0; i < 2; i++ {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://example.com/skip/"+name, nil))
+			}
+
+			if calls != 2 {
+				t.Errorf("Expected the response not to be cached, got %d calls", calls)
+			}
+		})
+	}
+}
+
+// TestCacheConditionalGet tests that a 304 response is sent for a matching ETag.
+func TestCacheConditionalGet(t *testing.T) {
+	handler := views.CacheView(mockHandler(http.StatusOK, "Conditional"), views.CacheOptions{Timeout: time.Millisecond * 50, Backend: "memory"})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/conditional", nil))
+
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Last-Modified") == "" {
+		t.Fatalf("Expected an ETag and a Last-Modified header, got %v", w.Header())
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/conditional", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected status %d without a body, got %d %q", http.StatusNotModified, w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "http://example.com/conditional", nil)
+	req.Header.Set("If-None-Match", `"other"`)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != "Conditional" {
+		t.Errorf("Expected the cached response, got %d %q", w.Code, w.Body.String())
+	}
+
+	time.Sleep(time.Millisecond * 60)
+}
+
+// TestCacheTags tests that invalidating a tag purges the responses with the tag.
+func TestCacheTags(t *testing.T) {
+	var calls int
+	handler := views.CacheMiddleware(views.CacheOptions{Timeout: time.Second, Backend: "memory"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		views.AddCacheTags(r, "page:1")
+		w.Write([]byte("Tagged"))
+	}))
+
+	var serve = func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://example.com/tagged", nil))
+	}
+
+	serve()
+	serve()
+	if calls != 1 {
+		t.Fatalf("Expected the response to be cached, got %d calls", calls)
+	}
+
+	if err := views.InvalidateCacheTags(context.Background(), "page:1"); err != nil {
+		t.Fatalf("Failed to invalidate the tag: %v", err)
+	}
+
+	serve()
+	serve()
+	if calls != 2 {
+		t.Errorf("Expected the response to be purged once, got %d calls", calls)
+	}
+}
+
+// TestCacheSessionCookie tests that requests with a session cookie are not cached.
+func TestCacheSessionCookie(t *testing.T) {
+	var calls int
+	handler := views.CacheView(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte("Session"))
+	}), views.CacheOptions{Timeout: time.Second, Backend: "memory", SessionCookies: []string{"session"}})
+
+	var serve = func(cookie bool) {
+		var r = httptest.NewRequest("GET", "http://example.com/session", nil)
+		if cookie {
+			r.AddCookie(&http.Cookie{Name: "session", Value: "token"})
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	serve(true)
+	serve(true)
+	if calls != 2 {
+		t.Fatalf("Expected requests with a session cookie not to be cached, got %d calls", calls)
+	}
+
+	serve(false)
+	serve(true)
+	serve(false)
+	if calls != 4 {
+		t.Errorf("Expected only the request without a session cookie to be cached, got %d calls", calls)
+	}
+}
+
+// TestCacheCSRFToken tests that responses which contain the CSRF token of the request are not cached.
+func TestCacheCSRFToken(t *testing.T) {
+	var tmpl = template.Must(template.New("form").Parse(`<input name="csrf_token" value="{{ . }}">`))
+	var tests = map[string]struct {
+		token bool
+		calls int
+	}{
+		"token":    {token: true, calls: 2},
+		"no-token": {token: false, calls: 1},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			handler := nosurf.New(views.CacheView(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if test.token {
+					tmpl.Execute(w, nosurf.Token(r))
+					return
+				}
+				w.Write([]byte("form"))
+			}), views.CacheOptions{Timeout: time.Second, Backend: "memory"}))
+
+			for i := 0; i < 2; i++ {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://example.com/csrf/"+name, nil))
+			}
+
+			if calls != test.calls {
+				t.Errorf("Expected %d calls, got %d", test.calls, calls)
+			}
+		})
+	}
+}
+
+// TestCacheCSPNonce tests that responses which contain the Content-Security-Policy nonce of the request are not cached.
+func TestCacheCSPNonce(t *testing.T) {
+	var calls int
+	handler := views.CacheView(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, `<script nonce="%s"></script>`, ctx.CSPNonce(r.Context()))
+	}), views.CacheOptions{Timeout: time.Second, Backend: "memory"})
+
+	for _, nonce := range []string{"bm9uY2Utb25lLW9uZQ==", "bm9uY2UtdHdvLXR3bw=="} {
+		var r = httptest.NewRequest("GET", "http://example.com/nonce", nil)
+		handler.ServeHTTP(httptest.NewRecorder(), r.WithContext(ctx.WithCSPNonce(r.Context(), nonce)))
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected responses with the nonce not to be cached, got %d calls", calls)
+	}
+}